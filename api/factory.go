@@ -6,6 +6,6 @@ import (
 
 func NewAPIServer(h Handler, sec SecurityHandler) (*Server, error) {
 	eh := WithErrorHandler(CustomErrorHandler())
-	mw := WithMiddleware(middleware.Authorize())
+	mw := WithMiddleware(middleware.Client(), middleware.Authorize())
 	return NewServer(h, sec, eh, mw)
 }
@@ -10,12 +10,107 @@ import (
 	"github.com/wolfsblu/recipe-manager/infra/config"
 )
 
+// operationPermissions are also what API tokens can be scoped to. Every
+// operation is either in here or in sessionOperations.
 var operationPermissions = map[operations.ID]permissions.Slug{
-	operations.AddRecipe:     permissions.CreateRecipe,
-	operations.DeleteRecipe:  permissions.DeleteRecipe,
-	operations.UpdateRecipe:  permissions.UpdateRecipe,
+	operations.BrowseRecipes: permissions.ListRecipes,
+	operations.SearchRecipes: permissions.ListRecipes,
 	operations.GetRecipes:    permissions.ListRecipes,
+	operations.GetTags:       permissions.ListRecipes,
 	operations.GetRecipeById: permissions.ViewRecipe,
+	operations.AddRecipe:     permissions.CreateRecipe,
+	operations.ImportRecipe:  permissions.CreateRecipe,
+	operations.UpdateRecipe:  permissions.UpdateRecipe,
+	operations.PatchRecipe:   permissions.UpdateRecipe,
+	operations.DeleteRecipe:  permissions.DeleteRecipe,
+
+	operations.GetUserProfile:        permissions.ViewProfile,
+	operations.UpdateNutrientTargets: permissions.UpdateProfile,
+
+	operations.GetMealSlots:           permissions.ListMealPlans,
+	operations.GetMealPlanTemplates:   permissions.ListMealPlans,
+	operations.GetMealPlan:            permissions.ViewMealPlan,
+	operations.GetMealPlanNutrition:   permissions.ViewMealPlan,
+	operations.CreateMealPlan:         permissions.CreateMealPlan,
+	operations.GenerateMealPlan:       permissions.CreateMealPlan,
+	operations.CreateMealPlanEntries:  permissions.CreateMealPlan,
+	operations.CreateMealPlanTemplate: permissions.CreateMealPlan,
+	operations.ApplyMealPlanTemplate:  permissions.CreateMealPlan,
+	operations.UpdateMealSlots:        permissions.UpdateMealPlan,
+	operations.UpdateMealPlanEntry:    permissions.UpdateMealPlan,
+	operations.MoveMealPlanEntry:      permissions.UpdateMealPlan,
+	operations.UpdateMealPlanTemplate: permissions.UpdateMealPlan,
+	operations.DeleteMealPlanEntry:    permissions.DeleteMealPlan,
+	operations.DeleteMealPlanTemplate: permissions.DeleteMealPlan,
+
+	operations.GetIngredients:   permissions.ListIngredients,
+	operations.ParseIngredients: permissions.ViewIngredient,
+	operations.AddIngredient:    permissions.CreateIngredient,
+	operations.UpdateIngredient: permissions.UpdateIngredient,
+	operations.DeleteIngredient: permissions.DeleteIngredient,
+
+	operations.GetUnits:     permissions.ListUnits,
+	operations.ConvertUnits: permissions.ViewUnit,
+	operations.AddUnit:      permissions.CreateUnit,
+	operations.UpdateUnit:   permissions.UpdateUnit,
+	operations.DeleteUnit:   permissions.DeleteUnit,
+
+	operations.GetShoppingLists:         permissions.ListShoppingLists,
+	operations.GetShoppingListById:      permissions.ViewShoppingList,
+	operations.CreateShoppingList:       permissions.CreateShoppingList,
+	operations.GenerateShoppingList:     permissions.CreateShoppingList,
+	operations.UpdateShoppingList:       permissions.UpdateShoppingList,
+	operations.AddShoppingListItem:      permissions.UpdateShoppingList,
+	operations.ReorderShoppingListItems: permissions.UpdateShoppingList,
+	operations.SyncShoppingList:         permissions.UpdateShoppingList,
+	operations.UpdateShoppingListItem:   permissions.UpdateShoppingList,
+	operations.DeleteShoppingListItem:   permissions.UpdateShoppingList,
+	operations.MoveShoppingListItem:     permissions.UpdateShoppingList,
+	operations.DeleteShoppingList:       permissions.DeleteShoppingList,
+
+	operations.GetStores:    permissions.ListStores,
+	operations.GetStoreById: permissions.ViewStore,
+	operations.AddStore:     permissions.CreateStore,
+	operations.UpdateStore:  permissions.UpdateStore,
+	operations.DeleteStore:  permissions.DeleteStore,
+
+	operations.GetHousehold:          permissions.ViewHousehold,
+	operations.UpdateHousehold:       permissions.UpdateHousehold,
+	operations.InviteHouseholdMember: permissions.UpdateHousehold,
+	operations.UpdateHouseholdMember: permissions.UpdateHousehold,
+	operations.RemoveHouseholdMember: permissions.UpdateHousehold,
+}
+
+// sessionOperations log in and manage the account and its security. They
+// need no permission, but are closed to API tokens, so a leaked token can't
+// be turned into a login or more tokens.
+var sessionOperations = map[operations.ID]bool{
+	operations.Login:                     true,
+	operations.Logout:                    true,
+	operations.Register:                  true,
+	operations.ConfirmUser:               true,
+	operations.UpdatePassword:            true,
+	operations.ResetPassword:             true,
+	operations.GetSessions:               true,
+	operations.DeleteSession:             true,
+	operations.DeleteOtherSessions:       true,
+	operations.EnrollTOTP:                true,
+	operations.ConfirmTOTP:               true,
+	operations.DisableTOTP:               true,
+	operations.LoginTOTP:                 true,
+	operations.GetPasskeys:               true,
+	operations.BeginPasskeyRegistration:  true,
+	operations.CreatePasskey:             true,
+	operations.DeletePasskey:             true,
+	operations.BeginPasskeyLogin:         true,
+	operations.LoginPasskey:              true,
+	operations.GetAPITokens:              true,
+	operations.CreateAPIToken:            true,
+	operations.DeleteAPIToken:            true,
+	operations.GetCalendarFeed:           true,
+	operations.CreateCalendarFeed:        true,
+	operations.DeleteCalendarFeed:        true,
+	operations.AcceptHouseholdInvitation: true,
 }
 
 func Authorize() middleware.Middleware {
@@ -34,6 +129,15 @@ func Authorize() middleware.Middleware {
 			return middleware.Response{}, domain.WrapError(domain.ErrAuthorization, fmt.Errorf("operation %s requires role %s", req.OperationID, requiredPermission))
 		}
 
+		// Operations missing from both lists are closed to everyone rather
+		// than open to API tokens
+		if !sessionOperations[operations.ID(req.OperationID)] {
+			return middleware.Response{}, domain.WrapError(domain.ErrAuthorization, fmt.Errorf("operation %s has no permission", req.OperationID))
+		}
+		if _, ok = req.Context.Value(config.CtxKeyAPIToken).(*domain.APIToken); ok {
+			return middleware.Response{}, domain.WrapError(domain.ErrAuthorization, fmt.Errorf("operation %s is not available to API tokens", req.OperationID))
+		}
+
 		return next(req)
 	}
 }
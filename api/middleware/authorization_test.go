@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/wolfsblu/recipe-manager/api/operations"
+)
+
+// TestOperationsAreAuthorized makes new operations fail here instead of
+// being closed to everyone, or worse open to every API token.
+func TestOperationsAreAuthorized(t *testing.T) {
+	spec, err := os.ReadFile("../openapi.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := regexp.MustCompile(`(?m)^\s+operationId:\s*(\w+)\s*$`).FindAllSubmatch(spec, -1)
+	if len(matches) == 0 {
+		t.Fatal("openapi.yml has no operations")
+	}
+	for _, match := range matches {
+		id := operations.ID(match[1])
+		_, hasPermission := operationPermissions[id]
+		if hasPermission == sessionOperations[id] {
+			t.Errorf("operation %s needs either a permission or to be a session operation", id)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ogen-go/ogen/middleware"
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/config"
+)
+
+// Client puts the device making the request into the context, so sessions
+// can be told apart by where they were started from.
+func Client() middleware.Middleware {
+	return func(req middleware.Request, next middleware.Next) (middleware.Response, error) {
+		req.Context = context.WithValue(req.Context, config.CtxKeyClient, SessionClient(req.Raw))
+		return next(req)
+	}
+}
+
+// SessionClient is the device making the request.
+func SessionClient(r *http.Request) domain.SessionClient {
+	return domain.SessionClient{
+		UserAgent: r.UserAgent(),
+		IPAddress: remoteAddress(r),
+	}
+}
+
+// remoteAddress prefers the address a reverse proxy forwarded the request
+// for. It is only shown to the user, so it doesn't matter that clients can
+// set the header themselves.
+func remoteAddress(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(first)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
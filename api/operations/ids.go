@@ -9,23 +9,100 @@ const (
 	AddRecipe     ID = "addRecipe"
 	GetRecipeById ID = "getRecipeById"
 	UpdateRecipe  ID = "updateRecipe"
+	PatchRecipe   ID = "patchRecipe"
 	DeleteRecipe  ID = "deleteRecipe"
+	SearchRecipes ID = "searchRecipes"
+	ImportRecipe  ID = "importRecipe"
+	GetTags       ID = "getTags"
 
 	// User
-	Login          ID = "login"
-	Logout         ID = "logout"
-	Register       ID = "register"
-	ConfirmUser    ID = "confirmUser"
-	UpdatePassword ID = "updatePassword"
-	ResetPassword  ID = "resetPassword"
-	GetUserProfile ID = "getUserProfile"
+	Login                 ID = "login"
+	Logout                ID = "logout"
+	Register              ID = "register"
+	ConfirmUser           ID = "confirmUser"
+	UpdatePassword        ID = "updatePassword"
+	ResetPassword         ID = "resetPassword"
+	GetUserProfile        ID = "getUserProfile"
+	UpdateNutrientTargets ID = "updateNutrientTargets"
+
+	// Account Security
+	GetSessions              ID = "getSessions"
+	DeleteSession            ID = "deleteSession"
+	DeleteOtherSessions      ID = "deleteOtherSessions"
+	EnrollTOTP               ID = "enrollTOTP"
+	ConfirmTOTP              ID = "confirmTOTP"
+	DisableTOTP              ID = "disableTOTP"
+	LoginTOTP                ID = "loginTOTP"
+	GetPasskeys              ID = "getPasskeys"
+	BeginPasskeyRegistration ID = "beginPasskeyRegistration"
+	CreatePasskey            ID = "createPasskey"
+	DeletePasskey            ID = "deletePasskey"
+	BeginPasskeyLogin        ID = "beginPasskeyLogin"
+	LoginPasskey             ID = "loginPasskey"
+	GetAPITokens             ID = "getAPITokens"
+	CreateAPIToken           ID = "createAPIToken"
+	DeleteAPIToken           ID = "deleteAPIToken"
+	GetCalendarFeed          ID = "getCalendarFeed"
+	CreateCalendarFeed       ID = "createCalendarFeed"
+	DeleteCalendarFeed       ID = "deleteCalendarFeed"
+
+	// Households
+	GetHousehold              ID = "getHousehold"
+	UpdateHousehold           ID = "updateHousehold"
+	InviteHouseholdMember     ID = "inviteHouseholdMember"
+	UpdateHouseholdMember     ID = "updateHouseholdMember"
+	RemoveHouseholdMember     ID = "removeHouseholdMember"
+	AcceptHouseholdInvitation ID = "acceptHouseholdInvitation"
 
 	// Meal Plan
-	GetMealPlan ID = "getMealPlan"
+	GetMealPlan            ID = "getMealPlan"
+	CreateMealPlan         ID = "createMealPlan"
+	GenerateMealPlan       ID = "generateMealPlan"
+	CreateMealPlanEntries  ID = "createMealPlanEntries"
+	GetMealPlanNutrition   ID = "getMealPlanNutrition"
+	GetMealSlots           ID = "getMealSlots"
+	UpdateMealSlots        ID = "updateMealSlots"
+	UpdateMealPlanEntry    ID = "updateMealPlanEntry"
+	DeleteMealPlanEntry    ID = "deleteMealPlanEntry"
+	MoveMealPlanEntry      ID = "moveMealPlanEntry"
+	GetMealPlanTemplates   ID = "getMealPlanTemplates"
+	CreateMealPlanTemplate ID = "createMealPlanTemplate"
+	UpdateMealPlanTemplate ID = "updateMealPlanTemplate"
+	DeleteMealPlanTemplate ID = "deleteMealPlanTemplate"
+	ApplyMealPlanTemplate  ID = "applyMealPlanTemplate"
 
 	// Ingredients
-	GetIngredients ID = "getIngredients"
+	GetIngredients   ID = "getIngredients"
+	AddIngredient    ID = "addIngredient"
+	ParseIngredients ID = "parseIngredients"
+	UpdateIngredient ID = "updateIngredient"
+	DeleteIngredient ID = "deleteIngredient"
 
 	// Units
-	GetUnits ID = "getUnits"
+	GetUnits     ID = "getUnits"
+	AddUnit      ID = "addUnit"
+	ConvertUnits ID = "convertUnits"
+	UpdateUnit   ID = "updateUnit"
+	DeleteUnit   ID = "deleteUnit"
+
+	// Shopping Lists
+	GetShoppingLists         ID = "getShoppingLists"
+	CreateShoppingList       ID = "createShoppingList"
+	GenerateShoppingList     ID = "generateShoppingList"
+	GetShoppingListById      ID = "getShoppingListById"
+	UpdateShoppingList       ID = "updateShoppingList"
+	DeleteShoppingList       ID = "deleteShoppingList"
+	AddShoppingListItem      ID = "addShoppingListItem"
+	ReorderShoppingListItems ID = "reorderShoppingListItems"
+	SyncShoppingList         ID = "syncShoppingList"
+	UpdateShoppingListItem   ID = "updateShoppingListItem"
+	DeleteShoppingListItem   ID = "deleteShoppingListItem"
+	MoveShoppingListItem     ID = "moveShoppingListItem"
+
+	// Stores
+	GetStores    ID = "getStores"
+	AddStore     ID = "addStore"
+	GetStoreById ID = "getStoreById"
+	UpdateStore  ID = "updateStore"
+	DeleteStore  ID = "deleteStore"
 )
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain/permissions"
+	"github.com/wolfsblu/recipe-manager/domain/security"
+)
+
+const (
+	// APITokenPrefix makes API tokens easy to recognize, for users as well as
+	// secret scanners.
+	APITokenPrefix        = "rm_"
+	maxAPITokenNameLength = 100
+	// apiTokenLastUsedInterval is how often the time a token was last used
+	// at is written.
+	apiTokenLastUsedInterval = time.Minute
+)
+
+// APIToken lets scripts and integrations call the API on behalf of a user.
+// The token itself is only stored hashed, its scopes are the permissions it
+// grants out of those the user has.
+type APIToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Scopes     []permissions.Slug
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+func (t APIToken) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+func (s *UserService) GetAPITokens(ctx context.Context, user *User) ([]APIToken, error) {
+	return s.store.GetAPITokens(ctx, user.ID)
+}
+
+// CreateAPIToken returns the new token along with its secret, which can't be
+// shown again.
+func (s *UserService) CreateAPIToken(ctx context.Context, user *User, name string, scopes []permissions.Slug, expiresAt *time.Time) (APIToken, string, error) {
+	name, scopes, err := s.validateAPIToken(user, name, scopes, expiresAt)
+	if err != nil {
+		return APIToken{}, "", err
+	}
+	secret := APITokenPrefix + security.GenerateToken(security.DefaultTokenLength)
+	token, err := s.store.CreateAPIToken(ctx, APIToken{
+		UserID:    user.ID,
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}, security.HashToken(secret))
+	if err != nil {
+		return APIToken{}, "", err
+	}
+	return token, secret, nil
+}
+
+func (s *UserService) DeleteAPIToken(ctx context.Context, user *User, id int64) error {
+	return s.store.DeleteAPIToken(ctx, user.ID, id)
+}
+
+// GetUserByAPIToken returns the owner of the token with only the permissions
+// its scopes grant.
+func (s *UserService) GetUserByAPIToken(ctx context.Context, secret string) (User, APIToken, error) {
+	token, err := s.store.GetAPITokenByHash(ctx, security.HashToken(secret))
+	if err != nil {
+		return User{}, APIToken{}, err
+	} else if token.Expired() {
+		return User{}, APIToken{}, ErrAPITokenNotFound
+	}
+
+	if token.LastUsedAt == nil || time.Since(*token.LastUsedAt) > apiTokenLastUsedInterval {
+		if err = s.store.UpdateAPITokenLastUsed(ctx, token.ID); err != nil {
+			return User{}, APIToken{}, err
+		}
+		now := time.Now()
+		token.LastUsedAt = &now
+	}
+
+	user, err := s.store.GetUserById(ctx, token.UserID)
+	if err != nil {
+		return User{}, APIToken{}, err
+	}
+	user.Role.Permissions = slices.DeleteFunc(user.Role.Permissions, func(permission Permission) bool {
+		return !slices.Contains(token.Scopes, permission.Slug)
+	})
+	return user, token, nil
+}
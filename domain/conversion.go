@@ -0,0 +1,107 @@
+package domain
+
+// UnitConversion converts an amount between two stored units. The ingredient
+// is only needed to convert between volume and mass.
+type UnitConversion struct {
+	Amount       float64
+	FromUnitID   int64
+	ToUnitID     int64
+	IngredientID *int64
+}
+
+func (u Unit) Convertible() bool {
+	return u.Dimension != "" && u.Factor > 0
+}
+
+// ConvertAmount converts amount from one unit into another. Volume and mass
+// convert into each other through the ingredient's density.
+func ConvertAmount(amount float64, from, to Unit, density *float64) (float64, error) {
+	if !from.Convertible() || !to.Convertible() {
+		return 0, ErrIncompatibleUnits
+	}
+
+	base := amount * from.Factor
+	switch {
+	case from.Dimension == to.Dimension:
+	case from.Dimension == UnitDimensionVolume && to.Dimension == UnitDimensionMass && density != nil:
+		base *= *density
+	case from.Dimension == UnitDimensionMass && to.Dimension == UnitDimensionVolume && density != nil:
+		base /= *density
+	default:
+		return 0, ErrIncompatibleUnits
+	}
+	return base / to.Factor, nil
+}
+
+// ConvertRecipe rewrites amounts given in units of another system into the
+// requested one. Units that belong to no system, like spoons, are kept.
+func ConvertRecipe(recipe Recipe, system UnitSystem, units []Unit) Recipe {
+	converted := recipe
+	converted.Steps = make([]RecipeStep, len(recipe.Steps))
+	for i, step := range recipe.Steps {
+		converted.Steps[i] = step
+		converted.Steps[i].Ingredients = make([]StepIngredient, len(step.Ingredients))
+		for j, ingredient := range step.Ingredients {
+			converted.Steps[i].Ingredients[j] = ConvertIngredient(ingredient, system, units)
+		}
+	}
+	return converted
+}
+
+func ConvertIngredient(ingredient StepIngredient, system UnitSystem, units []Unit) StepIngredient {
+	unit := ingredient.Unit
+	if !unit.Convertible() || unit.System == "" || unit.System == system {
+		return ingredient
+	}
+
+	for _, ladder := range unitLadders {
+		if ladder.system != system || ladder.dimension != unit.Dimension {
+			continue
+		}
+		if amount, target, ok := pickLadderUnit(ingredient.Amount*unit.Factor, ladder, units); ok {
+			ingredient.Amount = roundAmount(amount, canonicalUnitKey(target))
+			ingredient.Unit = target
+			return ingredient
+		}
+	}
+	return ingredient
+}
+
+// pickLadderUnit picks the largest unit of the ladder that still reads at
+// least its minimum amount, falling back to the smallest one available.
+func pickLadderUnit(base float64, ladder unitLadder, units []Unit) (float64, Unit, bool) {
+	var fallback *Unit
+	for i := len(ladder.steps) - 1; i >= 0; i-- {
+		step := ladder.steps[i]
+		target := findUnitByKey(units, step.key)
+		if target == nil || !target.Convertible() || target.Dimension != ladder.dimension {
+			continue
+		}
+		fallback = target
+		if amount := base / target.Factor; amount >= step.min {
+			return amount, *target, true
+		}
+	}
+	if fallback == nil {
+		return 0, Unit{}, false
+	}
+	return base / fallback.Factor, *fallback, true
+}
+
+func findUnitByID(units []Unit, id int64) *Unit {
+	for i := range units {
+		if units[i].ID == id {
+			return &units[i]
+		}
+	}
+	return nil
+}
+
+func findIngredientByID(ingredients []Ingredient, id int64) *Ingredient {
+	for i := range ingredients {
+		if ingredients[i].ID == id {
+			return &ingredients[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var conversionUnits = []Unit{
+	{ID: 1, Name: "Gram", Symbol: ptr("g"), Dimension: UnitDimensionMass, System: UnitSystemMetric, Factor: 1},
+	{ID: 2, Name: "Kilogram", Symbol: ptr("kg"), Dimension: UnitDimensionMass, System: UnitSystemMetric, Factor: 1000},
+	{ID: 3, Name: "Ounce", Symbol: ptr("oz"), Dimension: UnitDimensionMass, System: UnitSystemImperial, Factor: 28.349523125},
+	{ID: 4, Name: "Pound", Symbol: ptr("lb"), Dimension: UnitDimensionMass, System: UnitSystemImperial, Factor: 453.59237},
+	{ID: 5, Name: "Milliliter", Symbol: ptr("ml"), Dimension: UnitDimensionVolume, System: UnitSystemMetric, Factor: 1},
+	{ID: 6, Name: "Liter", Symbol: ptr("l"), Dimension: UnitDimensionVolume, System: UnitSystemMetric, Factor: 1000},
+	{ID: 7, Name: "Teaspoon", Symbol: ptr("tsp"), Dimension: UnitDimensionVolume, Factor: 4.92892159375},
+	{ID: 8, Name: "Tablespoon", Symbol: ptr("tbsp"), Dimension: UnitDimensionVolume, Factor: 14.78676478125},
+	{ID: 9, Name: "Cup", Symbol: ptr("c"), Dimension: UnitDimensionVolume, System: UnitSystemImperial, Factor: 236.5882365},
+	{ID: 10, Name: "Pinch"},
+}
+
+func TestConvertAmount(t *testing.T) {
+	flourDensity := 0.53
+
+	tests := []struct {
+		name    string
+		amount  float64
+		from    Unit
+		to      Unit
+		density *float64
+		want    float64
+		wantErr error
+	}{
+		{name: "Same dimension", amount: 200, from: conversionUnits[0], to: conversionUnits[1], want: 0.2},
+		{name: "Metric to imperial", amount: 1, from: conversionUnits[3], to: conversionUnits[0], want: 453.59237},
+		{name: "Volume to mass with density", amount: 1, from: conversionUnits[8], to: conversionUnits[0], density: &flourDensity, want: 125.392},
+		{name: "Mass to volume with density", amount: 125.392, from: conversionUnits[0], to: conversionUnits[8], density: &flourDensity, want: 1},
+		{name: "Volume to mass without density", amount: 1, from: conversionUnits[8], to: conversionUnits[0], wantErr: ErrIncompatibleUnits},
+		{name: "Unit without factor", amount: 1, from: conversionUnits[9], to: conversionUnits[0], wantErr: ErrIncompatibleUnits},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ConvertAmount(tc.amount, tc.from, tc.to, tc.density)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ConvertAmount() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if math.Abs(got-tc.want) > 0.001 {
+				t.Errorf("ConvertAmount() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConvertIngredient(t *testing.T) {
+	tests := []struct {
+		name       string
+		unit       Unit
+		amount     float64
+		system     UnitSystem
+		wantAmount float64
+		wantUnitID int64
+	}{
+		{name: "Cups to millilitres", unit: conversionUnits[8], amount: 1, system: UnitSystemMetric, wantAmount: 235, wantUnitID: 5},
+		{name: "Large volume to litres", unit: conversionUnits[8], amount: 6, system: UnitSystemMetric, wantAmount: 1.4, wantUnitID: 6},
+		{name: "Pounds to grams", unit: conversionUnits[3], amount: 2, system: UnitSystemMetric, wantAmount: 905, wantUnitID: 1},
+		{name: "Pounds to kilograms", unit: conversionUnits[3], amount: 5, system: UnitSystemMetric, wantAmount: 2.25, wantUnitID: 2},
+		{name: "Grams to ounces", unit: conversionUnits[0], amount: 100, system: UnitSystemImperial, wantAmount: 3.5, wantUnitID: 3},
+		{name: "Litres to cups", unit: conversionUnits[5], amount: 0.5, system: UnitSystemImperial, wantAmount: 2, wantUnitID: 9},
+		{name: "Small volume to spoons", unit: conversionUnits[4], amount: 10, system: UnitSystemImperial, wantAmount: 2, wantUnitID: 7},
+		{name: "Spoons belong to both systems", unit: conversionUnits[7], amount: 2, system: UnitSystemMetric, wantAmount: 2, wantUnitID: 8},
+		{name: "Already in target system", unit: conversionUnits[0], amount: 123, system: UnitSystemMetric, wantAmount: 123, wantUnitID: 1},
+		{name: "Unconvertible unit", unit: conversionUnits[9], amount: 1, system: UnitSystemMetric, wantAmount: 1, wantUnitID: 10},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ConvertIngredient(StepIngredient{Unit: tc.unit, Amount: tc.amount}, tc.system, conversionUnits)
+			if got.Amount != tc.wantAmount || got.Unit.ID != tc.wantUnitID {
+				t.Errorf("ConvertIngredient() = %v %s, want %v (unit %d)", got.Amount, got.Unit.Name, tc.wantAmount, tc.wantUnitID)
+			}
+		})
+	}
+}
+
+func TestValidateUnit(t *testing.T) {
+	service := &RecipeService{}
+
+	tests := []struct {
+		name    string
+		unit    Unit
+		wantErr error
+	}{
+		{name: "Plain unit", unit: Unit{Name: "Pinch"}},
+		{name: "Convertible unit", unit: conversionUnits[1]},
+		{name: "Missing name", unit: Unit{}, wantErr: ErrInvalidUnit},
+		{name: "Dimension without factor", unit: Unit{Name: "Gram", Dimension: UnitDimensionMass}, wantErr: ErrInvalidUnit},
+		{name: "Factor without dimension", unit: Unit{Name: "Gram", Factor: 1}, wantErr: ErrInvalidUnit},
+		{name: "Unknown dimension", unit: Unit{Name: "Kelvin", Dimension: "temperature", Factor: 1}, wantErr: ErrInvalidUnit},
+		{name: "Unknown system", unit: Unit{Name: "Shaku", Dimension: UnitDimensionLength, System: "shakkanho", Factor: 30.3}, wantErr: ErrInvalidUnit},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := service.validateUnit(tc.unit); !errors.Is(err, tc.wantErr) {
+				t.Errorf("validateUnit() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
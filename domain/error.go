@@ -27,6 +27,53 @@ var (
 	ErrUserNotFound               = &Error{Message: "user was not found"}
 	ErrInvalidIngredient          = &Error{Message: "invalid ingredient"}
 	ErrInvalidUnit                = &Error{Message: "invalid unit"}
+	ErrInvalidIngredientLines     = &Error{Message: "provide between 1 and 100 ingredient lines"}
+	ErrInvalidSearchQuery         = &Error{Message: "invalid search query"}
+	ErrInvalidCursor              = &Error{Message: "invalid pagination cursor"}
+	ErrInvalidListQuery           = &Error{Message: "invalid list query"}
+	ErrInvalidImportSource        = &Error{Message: "provide either a http(s) url or html to import"}
+	ErrFetchingRecipePage         = &Error{Message: "failed to fetch recipe page"}
+	ErrRecipeNotFoundInPage       = &Error{Message: "no schema.org recipe found on page"}
+	ErrInvalidRecipeScale         = &Error{Message: "servings and scale must be positive"}
+	ErrIncompatibleUnits          = &Error{Message: "units cannot be converted into each other"}
+	ErrInvalidDateRange           = &Error{Message: "invalid date range"}
+	ErrInvalidNutrientTargets     = &Error{Message: "invalid nutrient targets"}
+	ErrInvalidShoppingListItem    = &Error{Message: "invalid shopping list item"}
+	ErrInvalidStore               = &Error{Message: "invalid store"}
+	ErrStoreNotFound              = &Error{Message: "store was not found"}
+	ErrInvalidEventStream         = &Error{Message: "invalid event stream request"}
+	ErrInvalidShoppingListSync    = &Error{Message: "invalid shopping list sync"}
+	ErrInvalidSyncToken           = &Error{Message: "invalid sync token"}
+	ErrShoppingListItemNotFound   = &Error{Message: "shopping list item was not found"}
+	ErrMealPlanEntryNotFound      = &Error{Message: "meal plan entry was not found"}
+	ErrInvalidMove                = &Error{Message: "position must not be negative"}
+	ErrInvalidMealPlanEntry       = &Error{Message: "a meal plan entry needs a recipe or a note"}
+	ErrInvalidMealSlot            = &Error{Message: "invalid meal slot"}
+	ErrInvalidMealPlanGeneration  = &Error{Message: "invalid meal plan generation"}
+	ErrInvalidMealPlanTemplate    = &Error{Message: "invalid meal plan template"}
+	ErrMealPlanTemplateNotFound   = &Error{Message: "meal plan template was not found"}
+	ErrInvalidHousehold           = &Error{Message: "invalid household"}
+	ErrHouseholdNotFound          = &Error{Message: "household was not found"}
+	ErrHouseholdOwnerRequired     = &Error{Message: "a household needs at least one owner"}
+	ErrHouseholdMemberNotFound    = &Error{Message: "household member was not found"}
+	ErrInvitationNotFound         = &Error{Message: "household invitation was not found"}
+	ErrCalendarTokenNotFound      = &Error{Message: "calendar feed was not found"}
+	ErrSessionNotFound            = &Error{Message: "session was not found"}
+	ErrTwoFactorNotEnabled        = &Error{Message: "two-factor authentication is not enabled"}
+	ErrTwoFactorAlreadyEnabled    = &Error{Message: "two-factor authentication is already enabled"}
+	ErrInvalidTwoFactorCode       = &Error{Message: "invalid two-factor authentication code"}
+	ErrLoginChallengeNotFound     = &Error{Message: "login challenge was not found"}
+	ErrPasskeyNotFound            = &Error{Message: "passkey was not found"}
+	ErrPasskeyChallengeNotFound   = &Error{Message: "passkey challenge was not found or has expired"}
+	ErrInvalidPasskey             = &Error{Message: "passkey could not be verified"}
+	ErrInvalidPasskeyName         = &Error{Message: "passkey names can be at most 100 characters long"}
+	ErrSSONotConfigured           = &Error{Message: "single sign-on is not configured"}
+	ErrSSOLoginNotFound           = &Error{Message: "single sign-on login was not found or has expired"}
+	ErrSSOFailed                  = &Error{Message: "failed to log in with the identity provider"}
+	ErrSSOEmailNotVerified        = &Error{Message: "the identity provider did not verify the email address"}
+	ErrSSOAccountNotFound         = &Error{Message: "there is no account for this identity"}
+	ErrAPITokenNotFound           = &Error{Message: "API token was not found"}
+	ErrInvalidAPIToken            = &Error{Message: "API tokens need a name of at most 100 characters, scopes the user has and an expiry in the future"}
 )
 
 func (e *Error) Error() string {
@@ -0,0 +1,40 @@
+package domain
+
+import "context"
+
+type ShoppingListEventType string
+
+const (
+	ShoppingListItemCreated    ShoppingListEventType = "itemCreated"
+	ShoppingListItemUpdated    ShoppingListEventType = "itemUpdated"
+	ShoppingListItemDeleted    ShoppingListEventType = "itemDeleted"
+	ShoppingListItemsReordered ShoppingListEventType = "itemsReordered"
+	// ShoppingListEventReset tells a subscriber that events were missed and the
+	// list has to be fetched again.
+	ShoppingListEventReset ShoppingListEventType = "reset"
+)
+
+// ShoppingListEvent describes a change to the items of a list. Depending on
+// the type it carries the changed Item, the ItemID of a deleted item or all
+// Items in their new order. The ID is assigned by the bus and increases with
+// every published event.
+type ShoppingListEvent struct {
+	ID     int64
+	ListID int64
+	Type   ShoppingListEventType
+	Item   *ShoppingListItem
+	ItemID int64
+	Items  []ShoppingListItem
+}
+
+// ShoppingListEventBus passes changes of shopping lists on to everyone who is
+// looking at them.
+type ShoppingListEventBus interface {
+	Publish(event ShoppingListEvent)
+	// Subscribe returns the events of a list until ctx is done. Given the ID
+	// of the last event a subscriber saw, the events it missed are replayed
+	// first, or a reset is sent if they are no longer known.
+	Subscribe(ctx context.Context, listID int64, lastEventID *int64) <-chan ShoppingListEvent
+	// Remove ends the subscriptions to a deleted list.
+	Remove(listID int64)
+}
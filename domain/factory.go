@@ -14,8 +14,24 @@ func NewUserService(notifier NotificationSender, store UserStore) *UserService {
 	}
 }
 
-func NewShoppingService(store ShoppingStore) *ShoppingService {
+func NewHouseholdService(notifier NotificationSender, store HouseholdStore) *HouseholdService {
+	return &HouseholdService{
+		store:  store,
+		sender: notifier,
+	}
+}
+
+func NewShoppingService(store ShoppingStore, recipes *RecipeService, events ShoppingListEventBus) *ShoppingService {
 	return &ShoppingService{
-		store: store,
+		events:  events,
+		recipes: recipes,
+		store:   store,
+	}
+}
+
+func NewImportService(scraper RecipeScraper, store RecipeStore) *ImportService {
+	return &ImportService{
+		scraper: scraper,
+		store:   store,
 	}
 }
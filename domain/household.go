@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+type HouseholdRole string
+
+const (
+	HouseholdRoleOwner  HouseholdRole = "owner"
+	HouseholdRoleEditor HouseholdRole = "editor"
+	HouseholdRoleViewer HouseholdRole = "viewer"
+)
+
+const DefaultHouseholdName = "Household"
+
+// householdRoleRanks orders the roles, each role may do everything the roles
+// ranked below it may do.
+var householdRoleRanks = map[HouseholdRole]int{
+	HouseholdRoleViewer: 1,
+	HouseholdRoleEditor: 2,
+	HouseholdRoleOwner:  3,
+}
+
+func (r HouseholdRole) Valid() bool {
+	_, ok := householdRoleRanks[r]
+	return ok
+}
+
+// Includes reports whether the role grants at least the rights of other.
+func (r HouseholdRole) Includes(other HouseholdRole) bool {
+	return r.Valid() && householdRoleRanks[r] >= householdRoleRanks[other]
+}
+
+// Household shares recipes, meal plans and shopping lists between its members.
+// Every user is a member of exactly one household.
+type Household struct {
+	ID        int64
+	Name      string
+	Members   []HouseholdMember
+	CreatedAt time.Time
+}
+
+type HouseholdMember struct {
+	UserID int64
+	Email  string
+	Role   HouseholdRole
+}
+
+// Membership is the household of a user and the role they have in it.
+type Membership struct {
+	HouseholdID int64
+	Role        HouseholdRole
+}
+
+// Authorize fails unless the membership is in the given household with at
+// least the given role.
+func (m Membership) Authorize(householdID int64, role HouseholdRole) error {
+	if m.HouseholdID == 0 || m.HouseholdID != householdID || !m.Role.Includes(role) {
+		return ErrAuthorization
+	}
+	return nil
+}
+
+type HouseholdInvitation struct {
+	ID        int64
+	Household Household
+	Email     string
+	Role      HouseholdRole
+	Token     string
+	InvitedBy *User
+	CreatedAt time.Time
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMembershipAuthorize(t *testing.T) {
+	tests := []struct {
+		name        string
+		membership  Membership
+		householdID int64
+		role        HouseholdRole
+		wantErr     error
+	}{
+		{name: "Owner may edit", membership: Membership{HouseholdID: 1, Role: HouseholdRoleOwner}, householdID: 1, role: HouseholdRoleEditor},
+		{name: "Editor may edit", membership: Membership{HouseholdID: 1, Role: HouseholdRoleEditor}, householdID: 1, role: HouseholdRoleEditor},
+		{name: "Viewer may read", membership: Membership{HouseholdID: 1, Role: HouseholdRoleViewer}, householdID: 1, role: HouseholdRoleViewer},
+		{name: "Viewer may not edit", membership: Membership{HouseholdID: 1, Role: HouseholdRoleViewer}, householdID: 1, role: HouseholdRoleEditor, wantErr: ErrAuthorization},
+		{name: "Editor may not manage", membership: Membership{HouseholdID: 1, Role: HouseholdRoleEditor}, householdID: 1, role: HouseholdRoleOwner, wantErr: ErrAuthorization},
+		{name: "Other household", membership: Membership{HouseholdID: 1, Role: HouseholdRoleOwner}, householdID: 2, role: HouseholdRoleViewer, wantErr: ErrAuthorization},
+		{name: "No household", membership: Membership{}, householdID: 0, role: HouseholdRoleViewer, wantErr: ErrAuthorization},
+		{name: "Unknown role", membership: Membership{HouseholdID: 1, Role: "admin"}, householdID: 1, role: HouseholdRoleViewer, wantErr: ErrAuthorization},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.membership.Authorize(tc.householdID, tc.role); !errors.Is(err, tc.wantErr) {
+				t.Errorf("Authorize() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMemberChange(t *testing.T) {
+	service := &HouseholdService{}
+	household := Household{ID: 1, Members: []HouseholdMember{
+		{UserID: 1, Email: "owner@example.com", Role: HouseholdRoleOwner},
+		{UserID: 2, Email: "editor@example.com", Role: HouseholdRoleEditor},
+	}}
+	sharedOwnership := Household{ID: 1, Members: []HouseholdMember{
+		{UserID: 1, Email: "owner@example.com", Role: HouseholdRoleOwner},
+		{UserID: 2, Email: "second@example.com", Role: HouseholdRoleOwner},
+	}}
+
+	tests := []struct {
+		name      string
+		household Household
+		userID    int64
+		role      *HouseholdRole
+		wantErr   error
+	}{
+		{name: "Promote editor", household: household, userID: 2, role: ptr(HouseholdRoleOwner)},
+		{name: "Demote last owner", household: household, userID: 1, role: ptr(HouseholdRoleViewer), wantErr: ErrHouseholdOwnerRequired},
+		{name: "Demote one of two owners", household: sharedOwnership, userID: 1, role: ptr(HouseholdRoleEditor)},
+		{name: "Remove editor", household: household, userID: 2},
+		{name: "Remove last owner", household: household, userID: 1, wantErr: ErrHouseholdOwnerRequired},
+		{name: "Remove one of two owners", household: sharedOwnership, userID: 2},
+		{name: "Unknown member", household: household, userID: 3, wantErr: ErrHouseholdMemberNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := service.validateMemberChange(tc.household, tc.userID, tc.role); !errors.Is(err, tc.wantErr) {
+				t.Errorf("validateMemberChange() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInvitation(t *testing.T) {
+	service := &HouseholdService{}
+	household := Household{ID: 1, Members: []HouseholdMember{
+		{UserID: 1, Email: "owner@example.com", Role: HouseholdRoleOwner},
+	}}
+
+	tests := []struct {
+		name    string
+		email   string
+		role    HouseholdRole
+		wantErr error
+	}{
+		{name: "New member", email: "partner@example.com", role: HouseholdRoleEditor},
+		{name: "Invalid email", email: "partner", role: HouseholdRoleEditor, wantErr: ErrInvalidHousehold},
+		{name: "Unknown role", email: "partner@example.com", role: "admin", wantErr: ErrInvalidHousehold},
+		{name: "Existing member", email: "Owner@Example.com", role: HouseholdRoleViewer, wantErr: ErrInvalidHousehold},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := service.validateInvitation(household, tc.email, tc.role); !errors.Is(err, tc.wantErr) {
+				t.Errorf("validateInvitation() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
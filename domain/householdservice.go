@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const householdInvitationLifetime = 7 * 24 * time.Hour
+
+type HouseholdService struct {
+	sender NotificationSender
+	store  HouseholdStore
+}
+
+func (s *HouseholdService) Get(ctx context.Context, user *User) (Household, error) {
+	if err := user.Membership.Authorize(user.Membership.HouseholdID, HouseholdRoleViewer); err != nil {
+		return Household{}, err
+	}
+	return s.store.GetHouseholdByID(ctx, user.Membership.HouseholdID)
+}
+
+func (s *HouseholdService) Rename(ctx context.Context, user *User, name string) (Household, error) {
+	if err := user.Membership.Authorize(user.Membership.HouseholdID, HouseholdRoleOwner); err != nil {
+		return Household{}, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Household{}, ErrInvalidHousehold
+	}
+	return s.store.UpdateHousehold(ctx, user.Membership.HouseholdID, name)
+}
+
+// UpdateMember changes the role of a member, only owners may do so.
+func (s *HouseholdService) UpdateMember(ctx context.Context, user *User, member HouseholdMember) (Household, error) {
+	if err := user.Membership.Authorize(user.Membership.HouseholdID, HouseholdRoleOwner); err != nil {
+		return Household{}, err
+	}
+	if !member.Role.Valid() {
+		return Household{}, ErrInvalidHousehold
+	}
+
+	household, err := s.store.GetHouseholdByID(ctx, user.Membership.HouseholdID)
+	if err != nil {
+		return Household{}, err
+	}
+	if err = s.validateMemberChange(household, member.UserID, &member.Role); err != nil {
+		return Household{}, err
+	}
+
+	if err = s.store.UpdateHouseholdMember(ctx, household.ID, member); err != nil {
+		return Household{}, err
+	}
+	return s.store.GetHouseholdByID(ctx, household.ID)
+}
+
+// RemoveMember takes a user out of the household. Owners may remove anyone,
+// everyone else may only leave on their own. The removed user continues in a
+// new household of their own, everything they created stays shared.
+func (s *HouseholdService) RemoveMember(ctx context.Context, user *User, userID int64) error {
+	role := HouseholdRoleOwner
+	if userID == user.ID {
+		role = HouseholdRoleViewer
+	}
+	if err := user.Membership.Authorize(user.Membership.HouseholdID, role); err != nil {
+		return err
+	}
+
+	household, err := s.store.GetHouseholdByID(ctx, user.Membership.HouseholdID)
+	if err != nil {
+		return err
+	}
+	if err = s.validateMemberChange(household, userID, nil); err != nil {
+		return err
+	}
+
+	return s.store.RemoveHouseholdMember(ctx, household.ID, userID)
+}
+
+// Invite sends an invitation to join the household to the given email address.
+func (s *HouseholdService) Invite(ctx context.Context, user *User, email string, role HouseholdRole) error {
+	if err := user.Membership.Authorize(user.Membership.HouseholdID, HouseholdRoleOwner); err != nil {
+		return err
+	}
+
+	household, err := s.store.GetHouseholdByID(ctx, user.Membership.HouseholdID)
+	if err != nil {
+		return err
+	}
+	email = strings.TrimSpace(email)
+	if err = s.validateInvitation(household, email, role); err != nil {
+		return err
+	}
+
+	invitation, err := s.store.CreateHouseholdInvitation(ctx, HouseholdInvitation{
+		Household: household,
+		Email:     email,
+		Role:      role,
+		InvitedBy: user,
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		_ = s.sender.SendHouseholdInvitation(invitation)
+	}()
+	return nil
+}
+
+// AcceptInvitation moves the user into the household they were invited to.
+// When nobody else is left in their previous household, its recipes, meal
+// plan and shopping lists come along.
+func (s *HouseholdService) AcceptInvitation(ctx context.Context, user *User, token string) (Household, error) {
+	invitation, err := s.store.GetHouseholdInvitationByToken(ctx, token)
+	if err != nil {
+		return Household{}, err
+	}
+	if time.Since(invitation.CreatedAt) > householdInvitationLifetime || !strings.EqualFold(invitation.Email, user.Email) {
+		return Household{}, ErrInvitationNotFound
+	}
+
+	if invitation.Household.ID != user.Membership.HouseholdID {
+		previous, err := s.store.GetHouseholdByID(ctx, user.Membership.HouseholdID)
+		if err != nil {
+			return Household{}, err
+		}
+		if len(previous.Members) > 1 {
+			if err = s.validateMemberChange(previous, user.ID, nil); err != nil {
+				return Household{}, err
+			}
+		}
+	}
+
+	if err = s.store.AcceptHouseholdInvitation(ctx, invitation, user); err != nil {
+		return Household{}, err
+	}
+	return s.store.GetHouseholdByID(ctx, invitation.Household.ID)
+}
+
+func (s *HouseholdService) DeleteInvitationsOlderThan(ctx context.Context, olderThan time.Duration) error {
+	before := time.Now().Add(-olderThan)
+	return s.store.DeleteHouseholdInvitationsBefore(ctx, before)
+}
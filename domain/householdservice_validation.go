@@ -0,0 +1,40 @@
+package domain
+
+import "strings"
+
+// validateMemberChange makes sure the household keeps an owner when the given
+// member gets the new role, or leaves if role is nil.
+func (s *HouseholdService) validateMemberChange(household Household, userID int64, role *HouseholdRole) error {
+	found := false
+	owners := 0
+	for _, member := range household.Members {
+		if member.UserID == userID {
+			found = true
+			if role == nil || *role != HouseholdRoleOwner {
+				continue
+			}
+		} else if member.Role != HouseholdRoleOwner {
+			continue
+		}
+		owners++
+	}
+	if !found {
+		return ErrHouseholdMemberNotFound
+	}
+	if owners == 0 {
+		return ErrHouseholdOwnerRequired
+	}
+	return nil
+}
+
+func (s *HouseholdService) validateInvitation(household Household, email string, role HouseholdRole) error {
+	if !role.Valid() || !strings.Contains(email, "@") {
+		return ErrInvalidHousehold
+	}
+	for _, member := range household.Members {
+		if strings.EqualFold(member.Email, email) {
+			return ErrInvalidHousehold
+		}
+	}
+	return nil
+}
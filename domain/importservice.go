@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+type ImportService struct {
+	scraper RecipeScraper
+	store   RecipeStore
+}
+
+// Import scrapes a schema.org recipe and maps it onto a draft Recipe. The
+// draft is not stored; the caller reviews it, resolves the unresolved
+// ingredients and submits it through the regular recipe endpoints.
+func (s *ImportService) Import(ctx context.Context, user *User, source RecipeImportSource) (RecipeImport, error) {
+	if err := s.validateImportSource(source); err != nil {
+		return RecipeImport{}, err
+	}
+
+	scraped, err := s.scraper.Scrape(ctx, source)
+	if err != nil {
+		return RecipeImport{}, err
+	}
+	if strings.TrimSpace(scraped.Name) == "" {
+		return RecipeImport{}, ErrRecipeNotFoundInPage
+	}
+
+	units, err := s.store.GetUnits(ctx)
+	if err != nil {
+		return RecipeImport{}, err
+	}
+	ingredients, err := s.store.GetIngredients(ctx)
+	if err != nil {
+		return RecipeImport{}, err
+	}
+
+	result := RecipeImport{
+		Recipe: Recipe{
+			Steps: make([]RecipeStep, 0, len(scraped.Instructions)),
+			RecipeDetails: RecipeDetails{
+				Name:        strings.TrimSpace(scraped.Name),
+				Description: strings.TrimSpace(scraped.Description),
+				CreatedBy:   user,
+				Servings:    max(scraped.Servings, 1),
+				Minutes:     scraped.Minutes,
+			},
+		},
+		Unresolved: []UnresolvedIngredient{},
+	}
+	for _, image := range scraped.Images {
+		result.Recipe.Images = append(result.Recipe.Images, RecipeImage{URL: image})
+	}
+	for _, instructions := range scraped.Instructions {
+		result.Recipe.Steps = append(result.Recipe.Steps, RecipeStep{Instructions: instructions})
+	}
+	if len(result.Recipe.Steps) == 0 {
+		result.Recipe.Steps = append(result.Recipe.Steps, RecipeStep{})
+	}
+
+	// schema.org lists ingredients for the whole recipe, so they are all
+	// attached to the first step.
+	for _, line := range scraped.Ingredients {
+		parsed := ParseIngredientLine(line, units, ingredients)
+		if parsed.Unit == nil || parsed.Ingredient == nil || parsed.Amount <= 0 {
+			result.Unresolved = append(result.Unresolved, UnresolvedIngredient{
+				Line:       line,
+				Amount:     parsed.Amount,
+				Unit:       parsed.UnitText,
+				Ingredient: parsed.Name,
+			})
+			continue
+		}
+		result.Recipe.Steps[0].Ingredients = append(result.Recipe.Steps[0].Ingredients, StepIngredient{
+			Unit:       *parsed.Unit,
+			Amount:     parsed.Amount,
+			Ingredient: *parsed.Ingredient,
+		})
+	}
+	return result, nil
+}
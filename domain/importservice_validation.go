@@ -0,0 +1,11 @@
+package domain
+
+func (s *ImportService) validateImportSource(source RecipeImportSource) error {
+	if (source.URL == nil) == (len(source.HTML) == 0) {
+		return ErrInvalidImportSource
+	}
+	if source.URL != nil && source.URL.Scheme != "http" && source.URL.Scheme != "https" {
+		return ErrInvalidImportSource
+	}
+	return nil
+}
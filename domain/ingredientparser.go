@@ -0,0 +1,424 @@
+package domain
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParsedIngredient is the result of parsing a free text ingredient line.
+// Unit and Ingredient are only set when the text could be matched against
+// the known units and ingredients; Confidence ranges from 0 to 1.
+type ParsedIngredient struct {
+	Line       string
+	Amount     float64
+	MaxAmount  float64
+	UnitText   string
+	Unit       *Unit
+	Name       string
+	Ingredient *Ingredient
+	Note       string
+	Confidence float64
+}
+
+const minMatchSimilarity = 0.75
+
+var (
+	vulgarFractions = map[rune]string{
+		'¼': "1/4", '½': "1/2", '¾': "3/4",
+		'⅐': "1/7", '⅑': "1/9", '⅒': "1/10",
+		'⅓': "1/3", '⅔': "2/3",
+		'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5",
+		'⅙': "1/6", '⅚': "5/6",
+		'⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+	}
+
+	numberWords = map[string]float64{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "half": 0.5,
+		"ein": 1, "eine": 1, "einen": 1, "zwei": 2, "drei": 3, "vier": 4, "halbe": 0.5, "halber": 0.5,
+		"un": 1, "une": 1, "deux": 2, "trois": 3, "uno": 1, "una": 1, "dos": 2, "tres": 3,
+	}
+
+	rangeSeparators = map[string]bool{"-": true, "to": true, "or": true, "bis": true, "oder": true, "à": true, "a": true, "o": true}
+	fillerWords     = map[string]bool{"of": true, "von": true, "de": true, "d'": true, "du": true, "des": true}
+
+	// preparationWords are moved from the start of the name into the note,
+	// so "finely chopped onion" becomes "onion" with note "finely chopped".
+	preparationWords = map[string]bool{
+		"chopped": true, "diced": true, "minced": true, "sliced": true, "grated": true, "crushed": true,
+		"peeled": true, "melted": true, "softened": true, "beaten": true, "sifted": true, "shredded": true,
+		"finely": true, "roughly": true, "coarsely": true, "thinly": true, "freshly": true, "halved": true,
+		"gehackt": true, "gehackte": true, "gehackter": true, "gehacktes": true, "fein": true, "grob": true,
+		"gewürfelt": true, "gewürfelte": true, "gewürfelter": true, "gerieben": true, "geriebene": true,
+		"geriebener": true, "geschnitten": true, "geschnittene": true, "geschält": true, "geschälte": true,
+		"haché": true, "hachée": true, "émincé": true, "émincée": true, "râpé": true, "râpée": true,
+	}
+
+	// unitAliases maps how units are written in English, German, French and
+	// Spanish recipes onto a canonical unit key.
+	unitAliases = map[string][]string{
+		"g":       {"g", "gr", "gram", "grams", "gramm", "gramme", "grammes", "gramo", "gramos"},
+		"kg":      {"kg", "kilo", "kilos", "kilogram", "kilograms", "kilogramm", "kilogramme"},
+		"mg":      {"mg", "milligram", "milligrams", "milligramm"},
+		"ml":      {"ml", "milliliter", "milliliters", "millilitre", "millilitres", "mililitro", "mililitros"},
+		"cl":      {"cl", "centiliter", "centilitre"},
+		"dl":      {"dl", "deciliter", "decilitre"},
+		"l":       {"l", "liter", "liters", "litre", "litres", "litro", "litros"},
+		"tsp":     {"tsp", "tsps", "teaspoon", "teaspoons", "tl", "teelöffel", "c. à c.", "c.à.c.", "cuillère à café", "cuillères à café", "cdta", "cucharadita", "cucharaditas"},
+		"tbsp":    {"tbsp", "tbsps", "tbs", "tbl", "tablespoon", "tablespoons", "el", "esslöffel", "c. à s.", "c.à.s.", "cuillère à soupe", "cuillères à soupe", "cda", "cucharada", "cucharadas"},
+		"cup":     {"cup", "cups", "c", "tasse", "tassen", "taza", "tazas"},
+		"oz":      {"oz", "ounce", "ounces", "onza", "onzas"},
+		"fl oz":   {"fl oz", "fl. oz.", "fluid ounce", "fluid ounces"},
+		"lb":      {"lb", "lbs", "pound", "pounds", "pfund", "livre", "livres"},
+		"pt":      {"pt", "pint", "pints"},
+		"qt":      {"qt", "quart", "quarts"},
+		"gal":     {"gal", "gallon", "gallons"},
+		"pinch":   {"pinch", "pinches", "prise", "prisen", "pincée", "pincées", "pizca", "pizcas"},
+		"dash":    {"dash", "dashes", "schuss", "spritzer", "trait"},
+		"piece":   {"piece", "pieces", "pc", "pcs", "stück", "stk", "pièce", "pièces", "pieza", "piezas"},
+		"clove":   {"clove", "cloves", "zehe", "zehen", "gousse", "gousses", "diente", "dientes"},
+		"can":     {"can", "cans", "tin", "tins", "dose", "dosen", "boîte", "boîtes", "lata", "latas"},
+		"bunch":   {"bunch", "bunches", "bund", "bouquet", "manojo"},
+		"slice":   {"slice", "slices", "scheibe", "scheiben", "tranche", "tranches", "rebanada", "rebanadas"},
+		"handful": {"handful", "handfuls", "handvoll", "poignée", "puñado"},
+		"package": {"package", "packages", "pack", "packs", "packung", "päckchen", "pkg", "paquet", "paquete"},
+	}
+
+	// caseSensitiveUnits disambiguates the traditional T for tablespoon and
+	// t for teaspoon before aliases are compared case-insensitively.
+	caseSensitiveUnits = map[string]string{"T": "tbsp", "t": "tsp"}
+
+	unitAliasLookup   = buildUnitAliasLookup()
+	maxUnitAliasWords = 3
+
+	numberUnitPattern = regexp.MustCompile(`(\d)([^\d\s.,/\-–—])`)
+	decimalComma      = regexp.MustCompile(`(\d),(\d)`)
+	numericRange      = regexp.MustCompile(`(\d)\s*-\s*(\d)`)
+	parentheses       = regexp.MustCompile(`\(([^)]*)\)`)
+	quantityPattern   = regexp.MustCompile(`^(\d+\s+\d+/\d+|\d+/\d+|\d+(?:\.\d+)?)`)
+)
+
+func buildUnitAliasLookup() map[string]string {
+	lookup := make(map[string]string)
+	for key, aliases := range unitAliases {
+		for _, alias := range aliases {
+			lookup[alias] = key
+		}
+	}
+	return lookup
+}
+
+// ParseIngredientLine parses lines like "1 1/2 cups finely chopped onion
+// (about 2)" or "2–3 EL Olivenöl" and matches the result against the given
+// units and ingredients.
+func ParseIngredientLine(line string, units []Unit, ingredients []Ingredient) ParsedIngredient {
+	result := ParsedIngredient{Line: line}
+	text, notes := extractNotes(normalizeIngredientLine(line))
+
+	var amountScore float64
+	if amount, maxAmount, rest, ok := parseQuantityRange(text); ok {
+		result.Amount, result.MaxAmount = amount, maxAmount
+		text = rest
+		amountScore = 1
+	}
+
+	fields := strings.Fields(text)
+	unitKey, unitText, consumed := matchUnitAlias(fields)
+	var customUnit *Unit
+	if consumed == 0 && amountScore > 0 && len(fields) > 1 {
+		if customUnit = matchUnitByName(units, fields[0]); customUnit != nil {
+			unitText, consumed = fields[0], 1
+		}
+	}
+	fields = fields[consumed:]
+	if consumed > 0 && result.Amount == 0 {
+		// "pinch of salt" implies a single pinch.
+		result.Amount, result.MaxAmount = 1, 1
+		amountScore = 0.5
+	}
+	for len(fields) > 1 && fillerWords[strings.ToLower(fields[0])] {
+		fields = fields[1:]
+	}
+
+	name := strings.Join(fields, " ")
+	if before, after, found := strings.Cut(name, ","); found {
+		name = before
+		notes = append([]string{strings.TrimSpace(after)}, notes...)
+	}
+	nameFields := strings.Fields(name)
+	var preparation []string
+	for len(nameFields) > 1 && preparationWords[strings.ToLower(nameFields[0])] {
+		preparation = append(preparation, nameFields[0])
+		nameFields = nameFields[1:]
+	}
+	if len(preparation) > 0 {
+		notes = append([]string{strings.Join(preparation, " ")}, notes...)
+	}
+	result.Name = strings.Join(nameFields, " ")
+	result.Note = strings.Join(nonEmpty(notes), "; ")
+
+	var unitScore float64
+	result.UnitText = unitText
+	switch {
+	case customUnit != nil:
+		result.Unit, unitScore = customUnit, 1
+	case unitKey != "":
+		result.Unit, unitScore = matchKnownUnit(units, unitKey, unitText)
+	default:
+		// Countable ingredients like "2 eggs" have no unit; that is expected
+		// but still leaves the unit to be picked by the user.
+		unitScore = 0.5
+	}
+
+	var ingredientScore float64
+	result.Ingredient, ingredientScore = matchKnownIngredient(ingredients, result.Name)
+
+	confidence := 0.3*amountScore + 0.3*unitScore + 0.4*ingredientScore
+	result.Confidence = math.Round(confidence*100) / 100
+	return result
+}
+
+func normalizeIngredientLine(line string) string {
+	var sb strings.Builder
+	for _, r := range line {
+		if fraction, ok := vulgarFractions[r]; ok {
+			sb.WriteString(" " + fraction)
+			continue
+		}
+		switch r {
+		case '⁄':
+			sb.WriteRune('/')
+		case '–', '—', '‐', '−':
+			sb.WriteRune('-')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	text := decimalComma.ReplaceAllString(sb.String(), "$1.$2")
+	text = numericRange.ReplaceAllString(text, "$1 - $2")
+	text = numberUnitPattern.ReplaceAllString(text, "$1 $2")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func extractNotes(text string) (string, []string) {
+	var notes []string
+	for _, match := range parentheses.FindAllStringSubmatch(text, -1) {
+		notes = append(notes, strings.TrimSpace(match[1]))
+	}
+	text = parentheses.ReplaceAllString(text, " ")
+	return strings.Join(strings.Fields(text), " "), notes
+}
+
+func parseQuantityRange(text string) (float64, float64, string, bool) {
+	amount, rest, ok := parseQuantity(text)
+	if !ok {
+		return 0, 0, text, false
+	}
+	maxAmount := amount
+
+	fields := strings.SplitN(rest, " ", 2)
+	if len(fields) == 2 && rangeSeparators[strings.ToLower(fields[0])] {
+		if upper, remainder, ok := parseQuantity(fields[1]); ok && upper >= amount {
+			maxAmount = upper
+			rest = remainder
+		}
+	}
+	return amount, maxAmount, rest, true
+}
+
+func parseQuantity(text string) (float64, string, bool) {
+	if match := quantityPattern.FindString(text); match != "" {
+		var amount float64
+		for _, part := range strings.Fields(match) {
+			value, ok := parseNumber(part)
+			if !ok {
+				return 0, text, false
+			}
+			amount += value
+		}
+		return amount, strings.TrimSpace(text[len(match):]), true
+	}
+
+	word, rest, _ := strings.Cut(text, " ")
+	if value, ok := numberWords[strings.ToLower(word)]; ok && rest != "" {
+		return value, rest, true
+	}
+	return 0, text, false
+}
+
+func parseNumber(s string) (float64, bool) {
+	if numerator, denominator, ok := strings.Cut(s, "/"); ok {
+		n, err := strconv.ParseFloat(numerator, 64)
+		if err != nil {
+			return 0, false
+		}
+		d, err := strconv.ParseFloat(denominator, 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	return value, err == nil
+}
+
+// matchUnitAlias finds the longest unit alias at the start of fields and
+// returns its canonical key, the text as written and the number of fields
+// it spans.
+func matchUnitAlias(fields []string) (string, string, int) {
+	for n := min(maxUnitAliasWords, len(fields)-1); n > 0; n-- {
+		text := strings.Join(fields[:n], " ")
+		if key, ok := caseSensitiveUnits[strings.TrimSuffix(text, ".")]; ok {
+			return key, text, n
+		}
+		candidate := strings.ToLower(text)
+		if key, ok := unitAliasLookup[candidate]; ok {
+			return key, text, n
+		}
+		if key, ok := unitAliasLookup[strings.TrimSuffix(candidate, ".")]; ok {
+			return key, text, n
+		}
+	}
+	return "", "", 0
+}
+
+func matchKnownUnit(units []Unit, key, text string) (*Unit, float64) {
+	aliases := unitAliases[key]
+	for _, unit := range units {
+		for _, alias := range aliases {
+			if strings.EqualFold(unit.Name, alias) || (unit.Symbol != nil && strings.EqualFold(*unit.Symbol, alias)) {
+				return &unit, 1
+			}
+		}
+	}
+
+	var best *Unit
+	var bestScore float64
+	for _, unit := range units {
+		score := similarity(text, unit.Name)
+		if unit.Symbol != nil {
+			score = max(score, similarity(text, *unit.Symbol))
+		}
+		if score > bestScore {
+			best, bestScore = &unit, score
+		}
+	}
+	if bestScore < minMatchSimilarity {
+		return nil, 0
+	}
+	return best, bestScore
+}
+
+// matchUnitByName finds units that are not in the alias table, such as
+// units the users added themselves.
+func matchUnitByName(units []Unit, word string) *Unit {
+	word = strings.TrimSuffix(word, ".")
+	for _, unit := range units {
+		if strings.EqualFold(unit.Name, word) || strings.EqualFold(singular(strings.ToLower(word)), singular(strings.ToLower(unit.Name))) ||
+			(unit.Symbol != nil && strings.EqualFold(*unit.Symbol, word)) {
+			return &unit
+		}
+	}
+	return nil
+}
+
+func matchKnownIngredient(ingredients []Ingredient, name string) (*Ingredient, float64) {
+	if name == "" {
+		return nil, 0
+	}
+	target := singular(strings.ToLower(name))
+	targetWords := strings.Fields(target)
+
+	var best *Ingredient
+	var bestScore float64
+	for _, ingredient := range ingredients {
+		candidate := singular(strings.ToLower(ingredient.Name))
+		score := similarity(target, candidate)
+		// "red onion" should still find "Onion" when nothing closer exists.
+		if score < minMatchSimilarity && containsWords(targetWords, strings.Fields(candidate)) {
+			score = minMatchSimilarity + 0.1*float64(len(candidate))/float64(len(target))
+		}
+		if score > bestScore {
+			best, bestScore = &ingredient, score
+		}
+	}
+	if bestScore < minMatchSimilarity {
+		return nil, 0
+	}
+	return best, bestScore
+}
+
+func containsWords(haystack, needles []string) bool {
+	if len(needles) == 0 {
+		return false
+	}
+	for _, needle := range needles {
+		found := false
+		for _, word := range haystack {
+			if word == needle {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// singular strips common English plural endings from every word.
+func singular(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		switch {
+		case len(word) > 4 && strings.HasSuffix(word, "ies"):
+			words[i] = strings.TrimSuffix(word, "ies") + "y"
+		case len(word) > 4 && (strings.HasSuffix(word, "oes") || strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes")):
+			words[i] = strings.TrimSuffix(word, "es")
+		case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// similarity returns 1 minus the normalised edit distance between a and b,
+// ignoring case.
+func similarity(a, b string) float64 {
+	ra := []rune(strings.ToLower(strings.TrimSpace(a)))
+	rb := []rune(strings.ToLower(strings.TrimSpace(b)))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return 1 - float64(previous[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+func nonEmpty(values []string) []string {
+	result := values[:0]
+	for _, value := range values {
+		if strings.TrimFunc(value, unicode.IsSpace) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var (
+	testUnits = []Unit{
+		{ID: 1, Name: "Gram", Symbol: ptr("g")},
+		{ID: 2, Name: "Cup"},
+		{ID: 3, Name: "Tablespoon", Symbol: ptr("tbsp")},
+		{ID: 4, Name: "Teaspoon", Symbol: ptr("tsp")},
+		{ID: 5, Name: "Pinch"},
+		{ID: 6, Name: "Becher"},
+	}
+	testIngredients = []Ingredient{
+		{ID: 1, Name: "Onion"},
+		{ID: 2, Name: "Olive oil"},
+		{ID: 3, Name: "Olivenöl"},
+		{ID: 4, Name: "Flour"},
+		{ID: 5, Name: "Salt"},
+		{ID: 6, Name: "Egg"},
+		{ID: 7, Name: "Tomato"},
+		{ID: 8, Name: "Sahne"},
+	}
+)
+
+func TestParseIngredientLine(t *testing.T) {
+	tests := []struct {
+		name           string
+		line           string
+		wantAmount     float64
+		wantMaxAmount  float64
+		wantUnitID     int64
+		wantName       string
+		wantIngredient int64
+		wantNote       string
+	}{
+		{
+			name:           "Mixed fraction with preparation and parenthetical note",
+			line:           "1 1/2 cups finely chopped onion (about 2)",
+			wantAmount:     1.5,
+			wantMaxAmount:  1.5,
+			wantUnitID:     2,
+			wantName:       "onion",
+			wantIngredient: 1,
+			wantNote:       "finely chopped; about 2",
+		},
+		{
+			name:           "German range with en dash",
+			line:           "2–3 EL Olivenöl",
+			wantAmount:     2,
+			wantMaxAmount:  3,
+			wantUnitID:     3,
+			wantName:       "Olivenöl",
+			wantIngredient: 3,
+		},
+		{
+			name:           "Unicode vulgar fraction attached to number",
+			line:           "1½ tsp salt",
+			wantAmount:     1.5,
+			wantMaxAmount:  1.5,
+			wantUnitID:     4,
+			wantName:       "salt",
+			wantIngredient: 5,
+		},
+		{
+			name:           "Amount glued to metric unit with trailing note",
+			line:           "250g flour, sifted",
+			wantAmount:     250,
+			wantMaxAmount:  250,
+			wantUnitID:     1,
+			wantName:       "flour",
+			wantIngredient: 4,
+			wantNote:       "sifted",
+		},
+		{
+			name:           "Decimal comma",
+			line:           "0,5 kg Tomaten",
+			wantAmount:     0.5,
+			wantMaxAmount:  0.5,
+			wantName:       "Tomaten",
+			wantIngredient: 0,
+		},
+		{
+			name:           "Countable ingredient without unit",
+			line:           "2 large eggs",
+			wantAmount:     2,
+			wantMaxAmount:  2,
+			wantName:       "large eggs",
+			wantIngredient: 6,
+		},
+		{
+			name:           "Word amount with filler",
+			line:           "a pinch of salt",
+			wantAmount:     1,
+			wantMaxAmount:  1,
+			wantUnitID:     5,
+			wantName:       "salt",
+			wantIngredient: 5,
+		},
+		{
+			name:           "Unit without amount",
+			line:           "Prise Salz",
+			wantAmount:     1,
+			wantMaxAmount:  1,
+			wantUnitID:     5,
+			wantName:       "Salz",
+			wantIngredient: 5,
+		},
+		{
+			name:           "Traditional capital T for tablespoon",
+			line:           "2 T olive oil",
+			wantAmount:     2,
+			wantMaxAmount:  2,
+			wantUnitID:     3,
+			wantName:       "olive oil",
+			wantIngredient: 2,
+		},
+		{
+			name:           "Word range",
+			line:           "3 to 4 tomatoes",
+			wantAmount:     3,
+			wantMaxAmount:  4,
+			wantName:       "tomatoes",
+			wantIngredient: 7,
+		},
+		{
+			name:           "User defined unit",
+			line:           "1 Becher Sahne",
+			wantAmount:     1,
+			wantMaxAmount:  1,
+			wantUnitID:     6,
+			wantName:       "Sahne",
+			wantIngredient: 8,
+		},
+		{
+			name:           "No amount or unit",
+			line:           "salt and pepper to taste",
+			wantName:       "salt and pepper to taste",
+			wantIngredient: 5,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ParseIngredientLine(tc.line, testUnits, testIngredients)
+			if math.Abs(got.Amount-tc.wantAmount) > 1e-9 || math.Abs(got.MaxAmount-tc.wantMaxAmount) > 1e-9 {
+				t.Errorf("ParseIngredientLine() amount = %v-%v, want %v-%v", got.Amount, got.MaxAmount, tc.wantAmount, tc.wantMaxAmount)
+			}
+			var unitID int64
+			if got.Unit != nil {
+				unitID = got.Unit.ID
+			}
+			if unitID != tc.wantUnitID {
+				t.Errorf("ParseIngredientLine() unit = %d (%q), want %d", unitID, got.UnitText, tc.wantUnitID)
+			}
+			if got.Name != tc.wantName {
+				t.Errorf("ParseIngredientLine() name = %q, want %q", got.Name, tc.wantName)
+			}
+			var ingredientID int64
+			if got.Ingredient != nil {
+				ingredientID = got.Ingredient.ID
+			}
+			if ingredientID != tc.wantIngredient {
+				t.Errorf("ParseIngredientLine() ingredient = %d, want %d", ingredientID, tc.wantIngredient)
+			}
+			if got.Note != tc.wantNote {
+				t.Errorf("ParseIngredientLine() note = %q, want %q", got.Note, tc.wantNote)
+			}
+			if got.Confidence < 0 || got.Confidence > 1 {
+				t.Errorf("ParseIngredientLine() confidence = %v, want within [0, 1]", got.Confidence)
+			}
+		})
+	}
+}
+
+func TestParseIngredientLineConfidence(t *testing.T) {
+	exact := ParseIngredientLine("200 g flour", testUnits, testIngredients)
+	fuzzy := ParseIngredientLine("200 g flours", testUnits, testIngredients)
+	unknown := ParseIngredientLine("some dragon fruit", testUnits, testIngredients)
+
+	if exact.Confidence != 1 {
+		t.Errorf("exact match confidence = %v, want 1", exact.Confidence)
+	}
+	if !(unknown.Confidence < fuzzy.Confidence && fuzzy.Confidence <= exact.Confidence) {
+		t.Errorf("confidence ordering = %v, %v, %v, want increasing", unknown.Confidence, fuzzy.Confidence, exact.Confidence)
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// DefaultMealSlots are the slots every household starts with.
+var DefaultMealSlots = []MealSlot{
+	{Name: "Breakfast", Time: "08:00"},
+	{Name: "Lunch", Time: "12:30"},
+	{Name: "Dinner", Time: "18:30"},
+	{Name: "Snack"},
+}
+
+// MealSlot is a meal of the day like breakfast or dinner. Households arrange
+// their own slots, SortOrder is their position in the day. Time is when the
+// meal is usually eaten as "15:04", empty for slots without a set time.
+type MealSlot struct {
+	ID        int64
+	Name      string
+	SortOrder int64
+	Time      string
+}
+
+const mealSlotTimeLayout = "15:04"
+
+// At is when the meal of the slot starts on the given day. It reports false
+// for slots without a time.
+func (s MealSlot) At(day time.Time) (time.Time, bool) {
+	clock, err := time.Parse(mealSlotTimeLayout, s.Time)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location()), true
+}
+
+// moveMealPlanEntry takes the entry out of its slot and puts it at the given
+// position of the slot on the day at date, then numbers the entries of both
+// slots from the top starting at 0. A nil slotID keeps the entry in its slot.
+// Positions past the end put it at the bottom. It returns the entries whose
+// day, slot or position changed.
+func moveMealPlanEntry(entries []MealPlanEntry, entryID int64, date time.Time, slotID *int64, position int64) ([]MealPlanEntry, error) {
+	from := slices.IndexFunc(entries, func(entry MealPlanEntry) bool {
+		return entry.ID == entryID
+	})
+	if from < 0 {
+		return nil, ErrMealPlanEntryNotFound
+	}
+	entry := entries[from]
+	entry.Date = date
+	if slotID != nil {
+		entry.SlotID = slotID
+	}
+	changedSlot := !sameSlot(entry, entries[from])
+
+	var source, target []MealPlanEntry
+	for i, other := range entries {
+		switch {
+		case i == from:
+		case sameSlot(other, entry):
+			target = append(target, other)
+		case sameSlot(other, entries[from]):
+			source = append(source, other)
+		}
+	}
+	target = slices.Insert(target, int(min(position, int64(len(target)))), entry)
+
+	var moved []MealPlanEntry
+	for _, slot := range [][]MealPlanEntry{source, target} {
+		for i, other := range slot {
+			if other.SortOrder != int64(i) || (other.ID == entryID && changedSlot) {
+				other.SortOrder = int64(i)
+				moved = append(moved, other)
+			}
+		}
+	}
+	return moved, nil
+}
+
+// sameSlot tells if both entries are in the same slot on the same day, which
+// is what their sort order is counted in.
+func sameSlot(a, b MealPlanEntry) bool {
+	return a.Date.Format(time.DateOnly) == b.Date.Format(time.DateOnly) && equalIDs(a.SlotID, b.SlotID)
+}
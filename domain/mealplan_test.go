@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoveMealPlanEntry(t *testing.T) {
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	dinner := ptr(int64(3))
+	entries := []MealPlanEntry{
+		{ID: 1, Date: monday, SortOrder: 0},
+		{ID: 2, Date: monday, SortOrder: 1},
+		{ID: 3, Date: monday, SortOrder: 2},
+		{ID: 4, Date: tuesday, SortOrder: 0},
+		{ID: 5, Date: monday, SlotID: dinner, SortOrder: 0},
+	}
+
+	type position struct {
+		id        int64
+		date      time.Time
+		sortOrder int64
+		slotID    *int64
+	}
+	tests := []struct {
+		name     string
+		entryID  int64
+		date     time.Time
+		slotID   *int64
+		position int64
+		want     []position
+		wantErr  error
+	}{
+		{
+			name:    "Within the day",
+			entryID: 3, date: monday, position: 0,
+			want: []position{{3, monday, 0, nil}, {1, monday, 1, nil}, {2, monday, 2, nil}},
+		},
+		{
+			name:    "To another day",
+			entryID: 1, date: tuesday, position: 1,
+			want: []position{{2, monday, 0, nil}, {3, monday, 1, nil}, {1, tuesday, 1, nil}},
+		},
+		{
+			name:    "To the top of another day",
+			entryID: 2, date: tuesday, position: 0,
+			want: []position{{3, monday, 1, nil}, {2, tuesday, 0, nil}, {4, tuesday, 1, nil}},
+		},
+		{
+			name:    "To an empty day",
+			entryID: 4, date: tuesday.AddDate(0, 0, 1), position: 3,
+			want: []position{{4, tuesday.AddDate(0, 0, 1), 0, nil}},
+		},
+		{
+			name:    "To another slot",
+			entryID: 2, date: monday, slotID: dinner, position: 0,
+			want: []position{{3, monday, 1, nil}, {2, monday, 0, dinner}, {5, monday, 1, dinner}},
+		},
+		{
+			name:    "Keeps its slot",
+			entryID: 5, date: tuesday, position: 0,
+			want: []position{{5, tuesday, 0, dinner}},
+		},
+		{
+			name:    "Same position",
+			entryID: 2, date: monday, position: 1,
+		},
+		{
+			name:    "Unknown entry",
+			entryID: 9, date: monday,
+			wantErr: ErrMealPlanEntryNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			moved, err := moveMealPlanEntry(entries, tc.entryID, tc.date, tc.slotID, tc.position)
+			if err != tc.wantErr {
+				t.Fatalf("moveMealPlanEntry() error = %v, want %v", err, tc.wantErr)
+			}
+			if len(moved) != len(tc.want) {
+				t.Fatalf("moveMealPlanEntry() = %v, want %v", moved, tc.want)
+			}
+			for i, entry := range moved {
+				want := tc.want[i]
+				if entry.ID != want.id || !entry.Date.Equal(want.date) || entry.SortOrder != want.sortOrder || !equalIDs(entry.SlotID, want.slotID) {
+					t.Errorf("moveMealPlanEntry() entry %d = %d on %s at %d, want %d on %s at %d", i,
+						entry.ID, entry.Date.Format(time.DateOnly), entry.SortOrder,
+						want.id, want.date.Format(time.DateOnly), want.sortOrder)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMealSlots(t *testing.T) {
+	service := &RecipeService{}
+	tooMany := make([]MealSlot, maxMealSlots+1)
+	for i := range tooMany {
+		tooMany[i] = MealSlot{Name: "Slot"}
+	}
+
+	tests := []struct {
+		name    string
+		slots   []MealSlot
+		wantErr error
+	}{
+		{name: "Renamed and new", slots: []MealSlot{{ID: 1, Name: "Brunch"}, {Name: "Dinner"}}},
+		{name: "Time", slots: []MealSlot{{Name: "Dinner", Time: "18:30"}}},
+		{name: "Invalid time", slots: []MealSlot{{Name: "Dinner", Time: "6pm"}}, wantErr: ErrInvalidMealSlot},
+		{name: "None", slots: []MealSlot{}},
+		{name: "Blank name", slots: []MealSlot{{ID: 1, Name: " "}}, wantErr: ErrInvalidMealSlot},
+		{name: "Duplicate", slots: []MealSlot{{ID: 1, Name: "Lunch"}, {ID: 1, Name: "Dinner"}}, wantErr: ErrInvalidMealSlot},
+		{name: "Too many", slots: tooMany, wantErr: ErrInvalidMealSlot},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := service.validateMealSlots(tc.slots); err != tc.wantErr {
+				t.Errorf("validateMealSlots() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMealSlotAt(t *testing.T) {
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		slot   MealSlot
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "Time", slot: MealSlot{Time: "18:30"}, want: monday.Add(18*time.Hour + 30*time.Minute), wantOk: true},
+		{name: "No time", slot: MealSlot{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.slot.At(monday)
+			if ok != tc.wantOk || !got.Equal(tc.want) {
+				t.Errorf("At() = %v, %v, want %v, %v", got, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// MealPlanGeneration are the constraints to fill a date range with recipes
+// of the household. A recipe must have one of the IncludeTagIDs, if any, and
+// none of the ExcludeTagIDs. Recipes are not repeated within NoRepeatDays of
+// each other. The same Seed always yields the same plan for the same recipes.
+type MealPlanGeneration struct {
+	From                time.Time
+	Until               time.Time
+	SlotID              *int64
+	Servings            *int64
+	IncludeTagIDs       []int64
+	ExcludeTagIDs       []int64
+	WeekdayMaxMinutes   *int64
+	NoRepeatDays        int64
+	MaxIngredients      *int64
+	UseNutritionTargets bool
+	Seed                *int64
+}
+
+// MealPlanProposal is a generated plan that is not saved yet. Days no recipe
+// fits the constraints for are listed in UnfilledDates.
+type MealPlanProposal struct {
+	Seed          int64
+	Meals         []ProposedMeal
+	UnfilledDates []time.Time
+}
+
+type ProposedMeal struct {
+	Date     time.Time
+	SlotID   *int64
+	Servings *int64
+	Recipe   Recipe
+}
+
+// Entries turns the proposal into meal plan entries to save.
+func (p MealPlanProposal) Entries() []MealPlanEntry {
+	entries := make([]MealPlanEntry, len(p.Meals))
+	for i, meal := range p.Meals {
+		entries[i] = MealPlanEntry{
+			RecipeID: &meal.Recipe.ID,
+			SlotID:   meal.SlotID,
+			Date:     meal.Date,
+			Servings: meal.Servings,
+		}
+	}
+	return entries
+}
+
+// generateMealPlan picks a recipe for every day of the generation that has
+// nothing planned in its slot yet. The planned meals count towards repeats,
+// ingredients and nutrition. Recipes that pass the filters are ranked by how
+// far they take the plan over MaxIngredients distinct ingredients and then,
+// if asked for, by how close a serving brings the day to the nutrient
+// targets. Ties are broken by the seeded random source.
+func generateMealPlan(recipes []Recipe, planned []MealPlan, targets []NutrientTarget, generation MealPlanGeneration, seed int64) MealPlanProposal {
+	random := rand.New(rand.NewPCG(uint64(seed), 0))
+	proposal := MealPlanProposal{Seed: seed}
+
+	candidates := slices.DeleteFunc(slices.Clone(recipes), func(recipe Recipe) bool {
+		return !matchesTags(recipe, generation.IncludeTagIDs, generation.ExcludeTagIDs)
+	})
+	slices.SortFunc(candidates, func(a, b Recipe) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	lastPlanned := make(map[int64][]time.Time)
+	filled := make(map[string]bool)
+	dayMeals := make(map[string][]Recipe)
+	ingredients := make(map[int64]bool)
+	for _, day := range planned {
+		date := day.Date.Format(time.DateOnly)
+		for _, meal := range day.Meals {
+			inRange := !day.Date.Before(generation.From)
+			if inRange && equalIDs(meal.SlotID, generation.SlotID) {
+				filled[date] = true
+			}
+			if meal.Recipe == nil {
+				continue
+			}
+			lastPlanned[meal.Recipe.ID] = append(lastPlanned[meal.Recipe.ID], day.Date)
+			if inRange {
+				dayMeals[date] = append(dayMeals[date], *meal.Recipe)
+				addIngredientIDs(ingredients, *meal.Recipe)
+			}
+		}
+	}
+
+	for date := generation.From; !date.After(generation.Until); date = date.AddDate(0, 0, 1) {
+		if filled[date.Format(time.DateOnly)] {
+			continue
+		}
+
+		var best []Recipe
+		bestOverflow, bestDeviation := math.MaxInt, math.Inf(1)
+		for _, recipe := range candidates {
+			if !fitsDay(recipe, date, generation) || plannedWithin(lastPlanned[recipe.ID], date, generation.NoRepeatDays) {
+				continue
+			}
+			overflow := ingredientOverflow(ingredients, recipe, generation.MaxIngredients)
+			deviation := 0.0
+			if generation.UseNutritionTargets {
+				deviation = nutritionDeviation(append(slices.Clone(dayMeals[date.Format(time.DateOnly)]), recipe), targets)
+			}
+			switch {
+			case overflow < bestOverflow || (overflow == bestOverflow && deviation < bestDeviation):
+				best = []Recipe{recipe}
+				bestOverflow, bestDeviation = overflow, deviation
+			case overflow == bestOverflow && deviation == bestDeviation:
+				best = append(best, recipe)
+			}
+		}
+		if len(best) == 0 {
+			proposal.UnfilledDates = append(proposal.UnfilledDates, date)
+			continue
+		}
+
+		recipe := best[random.IntN(len(best))]
+		lastPlanned[recipe.ID] = append(lastPlanned[recipe.ID], date)
+		dayMeals[date.Format(time.DateOnly)] = append(dayMeals[date.Format(time.DateOnly)], recipe)
+		addIngredientIDs(ingredients, recipe)
+		proposal.Meals = append(proposal.Meals, ProposedMeal{
+			Date:     date,
+			SlotID:   generation.SlotID,
+			Servings: generation.Servings,
+			Recipe:   recipe,
+		})
+	}
+	return proposal
+}
+
+func matchesTags(recipe Recipe, include []int64, exclude []int64) bool {
+	included := len(include) == 0
+	for _, tag := range recipe.Tags {
+		if slices.Contains(exclude, tag.ID) {
+			return false
+		}
+		included = included || slices.Contains(include, tag.ID)
+	}
+	return included
+}
+
+// fitsDay applies the time limit on weekdays, weekends have none.
+func fitsDay(recipe Recipe, date time.Time, generation MealPlanGeneration) bool {
+	if generation.WeekdayMaxMinutes == nil || date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return true
+	}
+	return recipe.Minutes <= *generation.WeekdayMaxMinutes
+}
+
+func plannedWithin(dates []time.Time, date time.Time, days int64) bool {
+	for _, planned := range dates {
+		between := math.Abs(date.Sub(planned).Hours() / 24)
+		if between < float64(days) {
+			return true
+		}
+	}
+	return false
+}
+
+// ingredientOverflow is the number of distinct ingredients the recipe takes
+// the plan over the limit.
+func ingredientOverflow(used map[int64]bool, recipe Recipe, limit *int64) int {
+	if limit == nil {
+		return 0
+	}
+	added := make(map[int64]bool)
+	for _, step := range recipe.Steps {
+		for _, ingredient := range step.Ingredients {
+			if !used[ingredient.Ingredient.ID] {
+				added[ingredient.Ingredient.ID] = true
+			}
+		}
+	}
+	return max(0, len(used)+len(added)-int(*limit))
+}
+
+func addIngredientIDs(ids map[int64]bool, recipe Recipe) {
+	for _, step := range recipe.Steps {
+		for _, ingredient := range step.Ingredients {
+			ids[ingredient.Ingredient.ID] = true
+		}
+	}
+}
+
+// nutritionDeviation sums how far a serving of each recipe puts the day off
+// every target, relative to the target.
+func nutritionDeviation(recipes []Recipe, targets []NutrientTarget) float64 {
+	var sum nutrientSum
+	for _, recipe := range recipes {
+		for _, nutrient := range recipe.Nutrition().PerServing {
+			sum.add(nutrient.Nutrient, nutrient.Amount)
+		}
+	}
+	deviation := 0.0
+	for _, progress := range compareTargets(sum.list(1), targets) {
+		if progress.Target > 0 {
+			deviation += math.Abs(progress.Amount-progress.Target) / progress.Target
+		}
+	}
+	return deviation
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerateMealPlan(t *testing.T) {
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	friday := monday.AddDate(0, 0, 4)
+	gram := conversionUnits[0]
+	energy := Nutrient{ID: 1, Name: "Energy", Unit: "kcal"}
+	vegetarian, meat := Tag{ID: 1, Name: "Vegetarian"}, Tag{ID: 2, Name: "Meat"}
+
+	flour := Ingredient{ID: 1, Name: "Flour", ReferenceAmount: 100, Nutrients: []IngredientNutrient{{Nutrient: energy, Amount: 364}}}
+	milk := Ingredient{ID: 2, Name: "Milk", ReferenceAmount: 100}
+	beef := Ingredient{ID: 3, Name: "Beef", ReferenceAmount: 100}
+	recipe := func(id int64, minutes int64, tag Tag, ingredients ...StepIngredient) Recipe {
+		return Recipe{
+			ID:            id,
+			Tags:          []Tag{tag},
+			Steps:         []RecipeStep{{Ingredients: ingredients}},
+			RecipeDetails: RecipeDetails{Servings: 1, Minutes: minutes},
+		}
+	}
+	recipes := []Recipe{
+		recipe(3, 20, meat, StepIngredient{Ingredient: beef, Unit: gram, Amount: 200}),
+		recipe(1, 15, vegetarian, StepIngredient{Ingredient: flour, Unit: gram, Amount: 100}),
+		recipe(2, 90, vegetarian, StepIngredient{Ingredient: flour, Unit: gram, Amount: 250}, StepIngredient{Ingredient: milk, Unit: gram, Amount: 200}),
+	}
+	plannedMeat := []MealPlan{{Date: monday, Meals: []PlannedMeal{{EntryID: 1, Recipe: &recipes[0]}}}}
+	plannedQuick := []MealPlan{{Date: monday, Meals: []PlannedMeal{{EntryID: 1, Recipe: &recipes[1]}}}}
+
+	tests := []struct {
+		name         string
+		generation   MealPlanGeneration
+		planned      []MealPlan
+		targets      []NutrientTarget
+		want         []int64
+		wantUnfilled []time.Time
+	}{
+		{
+			name:       "Include tags",
+			generation: MealPlanGeneration{From: monday, Until: monday, IncludeTagIDs: []int64{meat.ID}},
+			want:       []int64{3},
+		},
+		{
+			name:       "Exclude tags",
+			generation: MealPlanGeneration{From: monday, Until: monday, ExcludeTagIDs: []int64{vegetarian.ID}},
+			want:       []int64{3},
+		},
+		{
+			name:       "Time limit on weekdays only",
+			generation: MealPlanGeneration{From: friday, Until: friday.AddDate(0, 0, 1), IncludeTagIDs: []int64{vegetarian.ID}, WeekdayMaxMinutes: ptr(int64(30)), NoRepeatDays: 2},
+			want:       []int64{1, 2},
+		},
+		{
+			name:         "No repeats",
+			generation:   MealPlanGeneration{From: monday, Until: monday.AddDate(0, 0, 2), IncludeTagIDs: []int64{meat.ID}, NoRepeatDays: 2},
+			want:         []int64{3, 3},
+			wantUnfilled: []time.Time{monday.AddDate(0, 0, 1)},
+		},
+		{
+			name:         "Skips planned days",
+			generation:   MealPlanGeneration{From: monday, Until: monday.AddDate(0, 0, 1), IncludeTagIDs: []int64{meat.ID}, NoRepeatDays: 2},
+			planned:      plannedMeat,
+			wantUnfilled: []time.Time{monday.AddDate(0, 0, 1)},
+		},
+		{
+			name:       "Fills other slots of planned days",
+			generation: MealPlanGeneration{From: monday, Until: monday, SlotID: ptr(int64(3)), IncludeTagIDs: []int64{meat.ID}},
+			planned:    plannedMeat,
+			want:       []int64{3},
+		},
+		{
+			name:       "Prefers planned ingredients",
+			generation: MealPlanGeneration{From: monday, Until: monday, SlotID: ptr(int64(3)), MaxIngredients: ptr(int64(1))},
+			planned:    plannedQuick,
+			want:       []int64{1},
+		},
+		{
+			name:       "Nutrient targets",
+			generation: MealPlanGeneration{From: monday, Until: monday, UseNutritionTargets: true},
+			targets:    []NutrientTarget{{Nutrient: energy, Amount: 900}},
+			want:       []int64{2},
+		},
+		{
+			name:         "Nothing fits",
+			generation:   MealPlanGeneration{From: monday, Until: monday, IncludeTagIDs: []int64{meat.ID}, ExcludeTagIDs: []int64{meat.ID}},
+			wantUnfilled: []time.Time{monday},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			proposal := generateMealPlan(recipes, tc.planned, tc.targets, tc.generation, 1)
+			var got []int64
+			for _, meal := range proposal.Meals {
+				got = append(got, meal.Recipe.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("generateMealPlan() recipes = %v, want %v", got, tc.want)
+			}
+			if !slices.Equal(proposal.UnfilledDates, tc.wantUnfilled) {
+				t.Errorf("generateMealPlan() unfilled = %v, want %v", proposal.UnfilledDates, tc.wantUnfilled)
+			}
+		})
+	}
+
+	t.Run("Same seed", func(t *testing.T) {
+		generation := MealPlanGeneration{From: monday, Until: monday.AddDate(0, 0, 27), SlotID: ptr(int64(3)), Servings: ptr(int64(4))}
+		first := generateMealPlan(recipes, nil, nil, generation, 42)
+		// The order the recipes are loaded in doesn't matter
+		second := generateMealPlan(append(slices.Clone(recipes[1:]), recipes[0]), nil, nil, generation, 42)
+		if first.Seed != 42 || len(first.Meals) != 28 {
+			t.Fatalf("generateMealPlan() = seed %d with %d meals, want seed 42 with 28", first.Seed, len(first.Meals))
+		}
+		entries := first.Entries()
+		for i, meal := range second.Meals {
+			if meal.Recipe.ID != first.Meals[i].Recipe.ID {
+				t.Fatalf("generateMealPlan() differs on %s for the same seed", meal.Date.Format(time.DateOnly))
+			}
+			if *entries[i].RecipeID != meal.Recipe.ID || *entries[i].SlotID != 3 || *entries[i].Servings != 4 || !entries[i].Date.Equal(meal.Date) {
+				t.Errorf("Entries()[%d] = %+v, want meal %+v", i, entries[i], meal)
+			}
+		}
+	})
+}
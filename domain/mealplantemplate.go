@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// mealPlanTemplateWeeks is how many weeks ahead recurring templates are kept
+// on the meal plan, counting the current one.
+const mealPlanTemplateWeeks = 4
+
+// MealPlanTemplate is a named week of entries like "every Friday dinner:
+// pizza". Applying it to a week puts its entries on the meal plan on their
+// weekdays. Recurring templates are applied to the coming weeks on their own.
+type MealPlanTemplate struct {
+	ID          int64
+	HouseholdID int64
+	UserID      int64
+	Name        string
+	Recurring   bool
+	Entries     []MealPlanTemplateEntry
+}
+
+type MealPlanTemplateEntry struct {
+	ID       int64
+	Weekday  time.Weekday
+	SlotID   *int64
+	RecipeID *int64
+	Note     *string
+	Servings *int64
+}
+
+// Occurrences are the meal plan entries of the template in the weeks
+// starting on the given Mondays, leaving out the days before from.
+func (t MealPlanTemplate) Occurrences(weeks []time.Time, from time.Time) []MealPlanEntry {
+	var entries []MealPlanEntry
+	for _, week := range weeks {
+		for _, entry := range t.Entries {
+			date := week.AddDate(0, 0, (int(entry.Weekday)+6)%7)
+			if date.Before(from) {
+				continue
+			}
+			entries = append(entries, MealPlanEntry{
+				HouseholdID:     t.HouseholdID,
+				UserID:          t.UserID,
+				RecipeID:        entry.RecipeID,
+				SlotID:          entry.SlotID,
+				Note:            entry.Note,
+				Date:            date,
+				Servings:        entry.Servings,
+				TemplateEntryID: &entry.ID,
+			})
+		}
+	}
+	return entries
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfWeek is the Monday of the week the day is in.
+func startOfWeek(day time.Time) time.Time {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// weeksBetween lists the Mondays of the weeks from the one of from up to
+// and including the one of until.
+func weeksBetween(from time.Time, until time.Time) []time.Time {
+	var weeks []time.Time
+	for week := startOfWeek(from); !week.After(until); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMealPlanTemplateOccurrences(t *testing.T) {
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	template := MealPlanTemplate{
+		HouseholdID: 1,
+		UserID:      2,
+		Entries: []MealPlanTemplateEntry{
+			{ID: 1, Weekday: time.Monday, Note: ptr("Porridge")},
+			{ID: 2, Weekday: time.Friday, RecipeID: ptr(int64(10)), SlotID: ptr(int64(3))},
+			{ID: 3, Weekday: time.Sunday, Note: ptr("Leftovers")},
+		},
+	}
+
+	type occurrence struct {
+		entryID int64
+		date    time.Time
+	}
+	tests := []struct {
+		name  string
+		weeks []time.Time
+		from  time.Time
+		want  []occurrence
+	}{
+		{
+			name:  "One week",
+			weeks: []time.Time{monday},
+			from:  monday,
+			want:  []occurrence{{1, monday}, {2, monday.AddDate(0, 0, 4)}, {3, monday.AddDate(0, 0, 6)}},
+		},
+		{
+			name:  "Leaves out days before from",
+			weeks: []time.Time{monday, monday.AddDate(0, 0, 7)},
+			from:  monday.AddDate(0, 0, 5),
+			want:  []occurrence{{3, monday.AddDate(0, 0, 6)}, {1, monday.AddDate(0, 0, 7)}, {2, monday.AddDate(0, 0, 11)}, {3, monday.AddDate(0, 0, 13)}},
+		},
+		{
+			name: "No weeks",
+			from: monday,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []occurrence
+			for _, entry := range template.Occurrences(tc.weeks, tc.from) {
+				if entry.HouseholdID != 1 || entry.UserID != 2 {
+					t.Errorf("Occurrences() entry of household %d by %d, want 1 by 2", entry.HouseholdID, entry.UserID)
+				}
+				got = append(got, occurrence{*entry.TemplateEntryID, entry.Date})
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWeeksBetween(t *testing.T) {
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		from  time.Time
+		until time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "Same day",
+			from:  monday.AddDate(0, 0, 2),
+			until: monday.AddDate(0, 0, 2),
+			want:  []time.Time{monday},
+		},
+		{
+			name:  "Sunday to Monday",
+			from:  monday.AddDate(0, 0, -1),
+			until: monday,
+			want:  []time.Time{monday.AddDate(0, 0, -7), monday},
+		},
+		{
+			name:  "Time of day",
+			from:  monday.Add(23 * time.Hour),
+			until: monday.AddDate(0, 0, 13),
+			want:  []time.Time{monday, monday.AddDate(0, 0, 7)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := weeksBetween(tc.from, tc.until); !slices.Equal(got, tc.want) {
+				t.Errorf("weeksBetween() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
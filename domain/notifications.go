@@ -3,4 +3,5 @@ package domain
 type NotificationSender interface {
 	SendPasswordReset(token PasswordResetToken) error
 	SendUserRegistration(registration UserRegistration) error
+	SendHouseholdInvitation(invitation HouseholdInvitation) error
 }
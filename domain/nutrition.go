@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+const DefaultNutrientReferenceAmount = 100
+
+// defaultReferenceUnit is used for ingredients without a reference unit,
+// their nutrients are given per ReferenceAmount grams.
+var defaultReferenceUnit = Unit{Name: "Gram", Dimension: UnitDimensionMass, System: UnitSystemMetric, Factor: 1}
+
+type NutritionWarningReason string
+
+const (
+	NutritionWarningMissingNutrients  NutritionWarningReason = "missingNutrients"
+	NutritionWarningUnconvertibleUnit NutritionWarningReason = "unconvertibleUnit"
+)
+
+// NutritionWarning marks an ingredient that was left out of the totals.
+type NutritionWarning struct {
+	Ingredient Ingredient
+	Unit       Unit
+	Reason     NutritionWarningReason
+}
+
+type IngredientNutrition struct {
+	Ingredient Ingredient
+	Nutrients  []IngredientNutrient
+}
+
+type RecipeNutrition struct {
+	Total       []IngredientNutrient
+	PerServing  []IngredientNutrient
+	Ingredients []IngredientNutrition
+	Warnings    []NutritionWarning
+}
+
+// NutrientTarget is the amount of a nutrient a user aims for per day.
+type NutrientTarget struct {
+	Nutrient Nutrient
+	Amount   float64
+}
+
+// NutrientProgress compares a nutrient amount against the daily target.
+type NutrientProgress struct {
+	Nutrient Nutrient
+	Amount   float64
+	Target   float64
+	Percent  float64
+}
+
+type DailyNutrition struct {
+	Date      time.Time
+	Nutrients []IngredientNutrient
+	Targets   []NutrientProgress
+	Warnings  []NutritionWarning
+}
+
+type MealPlanNutrition struct {
+	From         time.Time
+	Until        time.Time
+	Days         []DailyNutrition
+	Total        []IngredientNutrient
+	DailyAverage []IngredientNutrient
+	Targets      []NutrientProgress
+}
+
+// Nutrition adds up the nutrients of all step ingredients. Ingredients that
+// appear in several steps are summed into one entry of the breakdown.
+func (r Recipe) Nutrition() RecipeNutrition {
+	nutrition := RecipeNutrition{
+		Ingredients: []IngredientNutrition{},
+		Warnings:    []NutritionWarning{},
+	}
+
+	var total nutrientSum
+	byIngredient := make(map[int64]*nutrientSum)
+	var order []Ingredient
+	for _, step := range r.Steps {
+		for _, stepIngredient := range step.Ingredients {
+			ingredient := stepIngredient.Ingredient
+			if len(ingredient.Nutrients) == 0 {
+				nutrition.Warnings = append(nutrition.Warnings, NutritionWarning{
+					Ingredient: ingredient,
+					Unit:       stepIngredient.Unit,
+					Reason:     NutritionWarningMissingNutrients,
+				})
+				continue
+			}
+
+			factor, err := ingredient.nutrientFactor(stepIngredient.Amount, stepIngredient.Unit)
+			if err != nil {
+				nutrition.Warnings = append(nutrition.Warnings, NutritionWarning{
+					Ingredient: ingredient,
+					Unit:       stepIngredient.Unit,
+					Reason:     NutritionWarningUnconvertibleUnit,
+				})
+				continue
+			}
+
+			sum, ok := byIngredient[ingredient.ID]
+			if !ok {
+				sum = &nutrientSum{}
+				byIngredient[ingredient.ID] = sum
+				order = append(order, ingredient)
+			}
+			for _, nutrient := range ingredient.Nutrients {
+				sum.add(nutrient.Nutrient, nutrient.Amount*factor)
+				total.add(nutrient.Nutrient, nutrient.Amount*factor)
+			}
+		}
+	}
+
+	for _, ingredient := range order {
+		nutrition.Ingredients = append(nutrition.Ingredients, IngredientNutrition{
+			Ingredient: ingredient,
+			Nutrients:  byIngredient[ingredient.ID].list(1),
+		})
+	}
+	nutrition.Total = total.list(1)
+	nutrition.PerServing = total.list(float64(max(1, r.Servings)))
+	return nutrition
+}
+
+// nutrientFactor is the number of reference quantities in the given amount.
+func (i Ingredient) nutrientFactor(amount float64, unit Unit) (float64, error) {
+	referenceUnit := defaultReferenceUnit
+	if i.ReferenceUnit != nil {
+		referenceUnit = *i.ReferenceUnit
+	}
+	referenceAmount := i.ReferenceAmount
+	if referenceAmount <= 0 {
+		referenceAmount = DefaultNutrientReferenceAmount
+	}
+
+	converted, err := ConvertAmount(amount, unit, referenceUnit, i.Density)
+	if err != nil {
+		return 0, err
+	}
+	return converted / referenceAmount, nil
+}
+
+// SummarizeMealPlanNutrition sums the planned recipes for every day between
+// from and until. Each planned recipe counts with its planned servings, or a
+// single serving if none were planned. Entries without a recipe are skipped.
+// The range is compared against the targets through its daily average, empty
+// days included.
+func SummarizeMealPlanNutrition(mealPlan []MealPlan, targets []NutrientTarget, from, until time.Time) MealPlanNutrition {
+	byDate := make(map[string][]PlannedMeal, len(mealPlan))
+	for _, day := range mealPlan {
+		date := day.Date.Format(time.DateOnly)
+		for _, planned := range day.Meals {
+			if planned.Recipe != nil {
+				byDate[date] = append(byDate[date], planned)
+			}
+		}
+	}
+
+	summary := MealPlanNutrition{
+		From:  from,
+		Until: until,
+		Days:  []DailyNutrition{},
+	}
+	var total nutrientSum
+	for date := from; !date.After(until); date = date.AddDate(0, 0, 1) {
+		var sum nutrientSum
+		day := DailyNutrition{
+			Date:     date,
+			Warnings: []NutritionWarning{},
+		}
+		for _, planned := range byDate[date.Format(time.DateOnly)] {
+			nutrition := planned.Recipe.Nutrition()
+			// The share of the recipe that was planned, whether or not it was
+			// already scaled to the planned servings
+			portions := 1.0
+			if planned.Servings != nil {
+				portions = float64(*planned.Servings)
+			}
+			share := portions / float64(max(1, planned.Recipe.Servings))
+			for _, nutrient := range nutrition.Total {
+				amount := nutrient.Amount * share
+				sum.add(nutrient.Nutrient, amount)
+				total.add(nutrient.Nutrient, amount)
+			}
+			day.Warnings = append(day.Warnings, nutrition.Warnings...)
+		}
+		day.Nutrients = sum.list(1)
+		day.Targets = compareTargets(day.Nutrients, targets)
+		summary.Days = append(summary.Days, day)
+	}
+
+	summary.Total = total.list(1)
+	summary.DailyAverage = total.list(float64(max(1, len(summary.Days))))
+	summary.Targets = compareTargets(summary.DailyAverage, targets)
+	return summary
+}
+
+func compareTargets(nutrients []IngredientNutrient, targets []NutrientTarget) []NutrientProgress {
+	result := make([]NutrientProgress, len(targets))
+	for i, target := range targets {
+		result[i] = NutrientProgress{
+			Nutrient: target.Nutrient,
+			Target:   target.Amount,
+		}
+		for _, nutrient := range nutrients {
+			if nutrient.Nutrient.ID == target.Nutrient.ID {
+				result[i].Amount = nutrient.Amount
+				break
+			}
+		}
+		if target.Amount > 0 {
+			result[i].Percent = trimPrecision(result[i].Amount / target.Amount * 100)
+		}
+	}
+	return result
+}
+
+type nutrientSum struct {
+	nutrients map[int64]IngredientNutrient
+}
+
+func (s *nutrientSum) add(nutrient Nutrient, amount float64) {
+	if s.nutrients == nil {
+		s.nutrients = make(map[int64]IngredientNutrient)
+	}
+	sum := s.nutrients[nutrient.ID]
+	sum.Nutrient = nutrient
+	sum.Amount += amount
+	s.nutrients[nutrient.ID] = sum
+}
+
+// list returns the sums divided by divisor, ordered by nutrient name.
+func (s *nutrientSum) list(divisor float64) []IngredientNutrient {
+	result := make([]IngredientNutrient, 0, len(s.nutrients))
+	for _, sum := range s.nutrients {
+		sum.Amount = trimPrecision(sum.Amount / divisor)
+		result = append(result, sum)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Nutrient.Name < result[j].Nutrient.Name
+	})
+	return result
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecipeNutrition(t *testing.T) {
+	energy := Nutrient{ID: 1, Name: "Energy", Unit: "kcal"}
+	protein := Nutrient{ID: 2, Name: "Protein", Unit: "g"}
+	milkDensity := 1.03
+
+	flour := Ingredient{ID: 1, Name: "Flour", ReferenceAmount: 100, Nutrients: []IngredientNutrient{
+		{Nutrient: energy, Amount: 364},
+		{Nutrient: protein, Amount: 10},
+	}}
+	milk := Ingredient{ID: 2, Name: "Milk", Density: &milkDensity, ReferenceAmount: 100, Nutrients: []IngredientNutrient{
+		{Nutrient: energy, Amount: 64},
+		{Nutrient: protein, Amount: 3.4},
+	}}
+	egg := Ingredient{ID: 3, Name: "Egg", ReferenceAmount: 1, ReferenceUnit: &Unit{ID: 20, Name: "Piece", Dimension: UnitDimensionCount, Factor: 1}, Nutrients: []IngredientNutrient{
+		{Nutrient: energy, Amount: 78},
+		{Nutrient: protein, Amount: 6.3},
+	}}
+	water := Ingredient{ID: 4, Name: "Water", ReferenceAmount: 100}
+	piece := Unit{ID: 20, Name: "Piece", Dimension: UnitDimensionCount, Factor: 1}
+
+	tests := []struct {
+		name            string
+		servings        int64
+		ingredients     [][]StepIngredient
+		wantTotal       map[string]float64
+		wantPerServing  map[string]float64
+		wantIngredients int
+		wantWarnings    []NutritionWarningReason
+	}{
+		{
+			name:           "Grams",
+			servings:       1,
+			ingredients:    [][]StepIngredient{{{Ingredient: flour, Unit: conversionUnits[0], Amount: 250}}},
+			wantTotal:      map[string]float64{"Energy": 910, "Protein": 25},
+			wantPerServing: map[string]float64{"Energy": 910, "Protein": 25}, wantIngredients: 1,
+		},
+		{
+			name:           "Kilograms",
+			servings:       1,
+			ingredients:    [][]StepIngredient{{{Ingredient: flour, Unit: conversionUnits[1], Amount: 0.5}}},
+			wantTotal:      map[string]float64{"Energy": 1820, "Protein": 50},
+			wantPerServing: map[string]float64{"Energy": 1820, "Protein": 50}, wantIngredients: 1,
+		},
+		{
+			name:           "Volume with density",
+			servings:       1,
+			ingredients:    [][]StepIngredient{{{Ingredient: milk, Unit: conversionUnits[5], Amount: 0.5}}},
+			wantTotal:      map[string]float64{"Energy": 329.6, "Protein": 17.51},
+			wantPerServing: map[string]float64{"Energy": 329.6, "Protein": 17.51}, wantIngredients: 1,
+		},
+		{
+			name:           "Count reference unit",
+			servings:       1,
+			ingredients:    [][]StepIngredient{{{Ingredient: egg, Unit: piece, Amount: 2}}},
+			wantTotal:      map[string]float64{"Energy": 156, "Protein": 12.6},
+			wantPerServing: map[string]float64{"Energy": 156, "Protein": 12.6}, wantIngredients: 1,
+		},
+		{
+			name:     "Per serving",
+			servings: 4,
+			ingredients: [][]StepIngredient{{
+				{Ingredient: flour, Unit: conversionUnits[0], Amount: 400},
+				{Ingredient: egg, Unit: piece, Amount: 2},
+			}},
+			wantTotal:      map[string]float64{"Energy": 1612, "Protein": 52.6},
+			wantPerServing: map[string]float64{"Energy": 403, "Protein": 13.15}, wantIngredients: 2,
+		},
+		{
+			name:     "Same ingredient in two steps",
+			servings: 2,
+			ingredients: [][]StepIngredient{
+				{{Ingredient: flour, Unit: conversionUnits[0], Amount: 100}},
+				{{Ingredient: flour, Unit: conversionUnits[0], Amount: 50}},
+			},
+			wantTotal:      map[string]float64{"Energy": 546, "Protein": 15},
+			wantPerServing: map[string]float64{"Energy": 273, "Protein": 7.5}, wantIngredients: 1,
+		},
+		{
+			name:     "Missing nutrients",
+			servings: 1,
+			ingredients: [][]StepIngredient{{
+				{Ingredient: flour, Unit: conversionUnits[0], Amount: 100},
+				{Ingredient: water, Unit: conversionUnits[4], Amount: 200},
+			}},
+			wantTotal:      map[string]float64{"Energy": 364, "Protein": 10},
+			wantPerServing: map[string]float64{"Energy": 364, "Protein": 10}, wantIngredients: 1,
+			wantWarnings: []NutritionWarningReason{NutritionWarningMissingNutrients},
+		},
+		{
+			name:     "Unconvertible unit",
+			servings: 1,
+			ingredients: [][]StepIngredient{{
+				{Ingredient: flour, Unit: conversionUnits[8], Amount: 1},
+				{Ingredient: flour, Unit: conversionUnits[9], Amount: 1},
+			}},
+			wantTotal:      map[string]float64{},
+			wantPerServing: map[string]float64{},
+			wantWarnings:   []NutritionWarningReason{NutritionWarningUnconvertibleUnit, NutritionWarningUnconvertibleUnit},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recipe := Recipe{RecipeDetails: RecipeDetails{Servings: tc.servings}}
+			for _, ingredients := range tc.ingredients {
+				recipe.Steps = append(recipe.Steps, RecipeStep{Ingredients: ingredients})
+			}
+
+			got := recipe.Nutrition()
+			assertNutrients(t, "total", got.Total, tc.wantTotal)
+			assertNutrients(t, "per serving", got.PerServing, tc.wantPerServing)
+			if len(got.Ingredients) != tc.wantIngredients {
+				t.Errorf("Nutrition() ingredients = %d, want %d", len(got.Ingredients), tc.wantIngredients)
+			}
+			if len(got.Warnings) != len(tc.wantWarnings) {
+				t.Fatalf("Nutrition() warnings = %v, want %v", got.Warnings, tc.wantWarnings)
+			}
+			for i, warning := range got.Warnings {
+				if warning.Reason != tc.wantWarnings[i] {
+					t.Errorf("Nutrition() warning %d = %s, want %s", i, warning.Reason, tc.wantWarnings[i])
+				}
+			}
+		})
+	}
+}
+
+func assertNutrients(t *testing.T, label string, got []IngredientNutrient, want map[string]float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("Nutrition() %s = %v, want %v", label, got, want)
+		return
+	}
+	for _, nutrient := range got {
+		if nutrient.Amount != want[nutrient.Nutrient.Name] {
+			t.Errorf("Nutrition() %s %s = %v, want %v", label, nutrient.Nutrient.Name, nutrient.Amount, want[nutrient.Nutrient.Name])
+		}
+	}
+}
+
+func TestSummarizeMealPlanNutrition(t *testing.T) {
+	energy := Nutrient{ID: 1, Name: "Energy", Unit: "kcal"}
+	protein := Nutrient{ID: 2, Name: "Protein", Unit: "g"}
+	flour := Ingredient{ID: 1, Name: "Flour", ReferenceAmount: 100, Nutrients: []IngredientNutrient{
+		{Nutrient: energy, Amount: 400},
+		{Nutrient: protein, Amount: 10},
+	}}
+	salt := Ingredient{ID: 2, Name: "Salt", ReferenceAmount: 100}
+
+	// 800 kcal and 20 g protein for 4 servings
+	bread := Recipe{
+		RecipeDetails: RecipeDetails{Servings: 4},
+		Steps: []RecipeStep{{Ingredients: []StepIngredient{
+			{Ingredient: flour, Unit: conversionUnits[0], Amount: 200},
+			{Ingredient: salt, Unit: conversionUnits[0], Amount: 5},
+		}}},
+	}
+	// Scaled to 2 servings the way GetMealPlan returns it
+	halfBread := scaleRecipe(bread, RecipeScale{Servings: ptr(int64(2))}, conversionUnits)
+
+	monday := time.Date(2025, 10, 13, 0, 0, 0, 0, time.UTC)
+	tuesday, thursday := monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 3)
+	mealPlan := []MealPlan{
+		{Date: monday, Meals: []PlannedMeal{{Recipe: &bread}, {Recipe: &halfBread, Servings: ptr(int64(2))}, {Note: ptr("Eat out")}}},
+		{Date: tuesday, Meals: []PlannedMeal{{Recipe: &bread, Servings: ptr(int64(4))}}},
+		// Planned servings count the same when the recipe wasn't scaled
+		{Date: thursday, Meals: []PlannedMeal{{Recipe: &bread, Servings: ptr(int64(2))}}},
+	}
+	targets := []NutrientTarget{{Nutrient: energy, Amount: 2000}}
+
+	got := SummarizeMealPlanNutrition(mealPlan, targets, monday, monday.AddDate(0, 0, 3))
+
+	if len(got.Days) != 4 {
+		t.Fatalf("SummarizeMealPlanNutrition() days = %d, want 4", len(got.Days))
+	}
+
+	tests := []struct {
+		name string
+		got  []IngredientNutrient
+		want map[string]float64
+	}{
+		{name: "Single serving and planned servings", got: got.Days[0].Nutrients, want: map[string]float64{"Energy": 600, "Protein": 15}},
+		{name: "All planned servings", got: got.Days[1].Nutrients, want: map[string]float64{"Energy": 800, "Protein": 20}},
+		{name: "Empty day", got: got.Days[2].Nutrients, want: map[string]float64{}},
+		{name: "Planned servings of unscaled recipe", got: got.Days[3].Nutrients, want: map[string]float64{"Energy": 400, "Protein": 10}},
+		{name: "Total", got: got.Total, want: map[string]float64{"Energy": 1800, "Protein": 45}},
+		{name: "Daily average", got: got.DailyAverage, want: map[string]float64{"Energy": 450, "Protein": 11.25}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assertNutrients(t, tc.name, tc.got, tc.want)
+		})
+	}
+
+	if len(got.Days[0].Warnings) != 2 {
+		t.Errorf("SummarizeMealPlanNutrition() warnings = %v, want 2", got.Days[0].Warnings)
+	}
+	if progress := got.Days[1].Targets; len(progress) != 1 || progress[0].Percent != 40 {
+		t.Errorf("SummarizeMealPlanNutrition() day targets = %v, want 40%%", progress)
+	}
+	if progress := got.Targets; len(progress) != 1 || progress[0].Amount != 450 || progress[0].Percent != 22.5 {
+		t.Errorf("SummarizeMealPlanNutrition() range targets = %v, want 450 at 22.5%%", progress)
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// Cursor marks the last item of a page. Key records the sort key the cursor
+// was created for, so it cannot be replayed against a different ordering.
+type Cursor struct {
+	Key   string `json:"k"`
+	Value string `json:"v"`
+	ID    int64  `json:"i"`
+}
+
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func normalizePageSize(limit int64) (int64, error) {
+	if limit < 0 {
+		return 0, ErrInvalidListQuery
+	}
+	if limit == 0 {
+		return DefaultPageSize, nil
+	}
+	return min(limit, MaxPageSize), nil
+}
@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	PasskeyCeremonyRegistration = "registration"
+	PasskeyCeremonyLogin        = "login"
+	// PasskeyChallengeLifetime is how long the browser has to finish a
+	// ceremony, it is also the timeout handed to the authenticator.
+	PasskeyChallengeLifetime = 5 * time.Minute
+	defaultPasskeyName       = "Passkey"
+	maxPasskeyNameLength     = 100
+)
+
+// Passkey is a WebAuthn credential a user can log in with instead of their
+// password.
+type Passkey struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Credential webauthn.Credential
+	// UnknownFlags is set for passkeys saved before their flags were, they
+	// take on the flags of their next login.
+	UnknownFlags bool
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+// PasskeyChallenge is the state of a ceremony between handing out its options
+// and the browser sending back the response. Login challenges don't belong to
+// a user, the passkey tells who logs in.
+type PasskeyChallenge struct {
+	ID        int64
+	Token     string
+	Ceremony  string
+	UserID    int64
+	Session   webauthn.SessionData
+	CreatedAt time.Time
+}
+
+func (c PasskeyChallenge) ExpiresAt() time.Time {
+	return c.CreatedAt.Add(PasskeyChallengeLifetime)
+}
+
+// PasskeyCreationOptions are what navigator.credentials.create() needs to
+// make a passkey for the user.
+type PasskeyCreationOptions struct {
+	Token     string
+	PublicKey protocol.PublicKeyCredentialCreationOptions
+}
+
+// PasskeyRequestOptions are what navigator.credentials.get() needs to log in
+// with any passkey the authenticator has for the site.
+type PasskeyRequestOptions struct {
+	Token     string
+	PublicKey protocol.PublicKeyCredentialRequestOptions
+}
+
+// NewWebAuthn sets up the relying party passkeys are created for from the
+// address the app is served at. Passkeys have to be discoverable and verify
+// the user, attestation isn't asked for.
+func NewWebAuthn(baseURL string) (*webauthn.WebAuthn, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	} else if u.Scheme == "" || u.Host == "" {
+		return nil, errors.New("base url needs a scheme and a host")
+	}
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    PasskeyChallengeLifetime,
+		TimeoutUVD: PasskeyChallengeLifetime,
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:                  u.Hostname(),
+		RPDisplayName:         AppName,
+		RPOrigins:             []string{u.Scheme + "://" + u.Host},
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+}
+
+func (s *UserService) GetPasskeys(ctx context.Context, user *User) ([]Passkey, error) {
+	return s.store.GetPasskeys(ctx, user.ID)
+}
+
+func (s *UserService) DeletePasskey(ctx context.Context, user *User, id int64) error {
+	return s.store.DeletePasskey(ctx, user.ID, id)
+}
+
+// BeginPasskeyRegistration starts adding a passkey to the account of the
+// user. Passkeys the user already has are excluded, so authenticators don't
+// create a second one.
+func (s *UserService) BeginPasskeyRegistration(ctx context.Context, wa *webauthn.WebAuthn, user *User) (PasskeyCreationOptions, error) {
+	passkeys, err := s.store.GetPasskeys(ctx, user.ID)
+	if err != nil {
+		return PasskeyCreationOptions{}, err
+	}
+	account := passkeyUser{id: user.ID, email: user.Email, passkeys: passkeys}
+	excluded := webauthn.Credentials(account.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := wa.BeginRegistration(account, webauthn.WithExclusions(excluded))
+	if err != nil {
+		return PasskeyCreationOptions{}, err
+	}
+	challenge, err := s.store.CreatePasskeyChallenge(ctx, PasskeyCeremonyRegistration, user.ID, *session)
+	if err != nil {
+		return PasskeyCreationOptions{}, err
+	}
+	return PasskeyCreationOptions{
+		Token:     challenge.Token,
+		PublicKey: creation.Response,
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the new credential and saves it.
+func (s *UserService) FinishPasskeyRegistration(ctx context.Context, wa *webauthn.WebAuthn, user *User, token, name string, response *protocol.ParsedCredentialCreationData) (Passkey, error) {
+	name, err := s.validatePasskeyName(name)
+	if err != nil {
+		return Passkey{}, err
+	}
+	challenge, err := s.takePasskeyChallenge(ctx, token, PasskeyCeremonyRegistration)
+	if err != nil {
+		return Passkey{}, err
+	} else if challenge.UserID != user.ID {
+		return Passkey{}, ErrPasskeyChallengeNotFound
+	}
+
+	credential, err := wa.CreateCredential(passkeyUser{id: user.ID, email: user.Email}, challenge.Session, response)
+	if err != nil {
+		return Passkey{}, ErrInvalidPasskey
+	}
+	return s.store.CreatePasskey(ctx, user.ID, name, *credential)
+}
+
+func (s *UserService) BeginPasskeyLogin(ctx context.Context, wa *webauthn.WebAuthn) (PasskeyRequestOptions, error) {
+	assertion, session, err := wa.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return PasskeyRequestOptions{}, err
+	}
+	challenge, err := s.store.CreatePasskeyChallenge(ctx, PasskeyCeremonyLogin, 0, *session)
+	if err != nil {
+		return PasskeyRequestOptions{}, err
+	}
+	return PasskeyRequestOptions{
+		Token:     challenge.Token,
+		PublicKey: assertion.Response,
+	}, nil
+}
+
+// FinishPasskeyLogin verifies the assertion and returns the user to start a
+// session for. Authenticators verify the user themselves, so logging in with
+// a passkey doesn't ask for a TOTP code.
+func (s *UserService) FinishPasskeyLogin(ctx context.Context, wa *webauthn.WebAuthn, token string, response *protocol.ParsedCredentialAssertionData) (User, error) {
+	challenge, err := s.takePasskeyChallenge(ctx, token, PasskeyCeremonyLogin)
+	if err != nil {
+		return User{}, err
+	}
+
+	// Unknown passkeys are invalid credentials, only errors of the store
+	// itself are passed on.
+	var passkey Passkey
+	var lookupErr error
+	findPasskey := func(credentialID, userHandle []byte) (webauthn.User, error) {
+		passkey, lookupErr = s.store.GetPasskeyByCredentialID(ctx, credentialID)
+		if lookupErr != nil {
+			return nil, lookupErr
+		} else if !bytes.Equal(userHandle, passkeyUserHandle(passkey.UserID)) {
+			return nil, ErrInvalidCredentials
+		}
+		if passkey.UnknownFlags {
+			passkey.Credential.Flags = webauthn.NewCredentialFlags(response.Response.AuthenticatorData.Flags)
+		}
+		return passkeyUser{id: passkey.UserID, passkeys: []Passkey{passkey}}, nil
+	}
+	// A signature counter that didn't move forward hints at a cloned
+	// authenticator.
+	_, credential, err := wa.ValidatePasskeyLogin(findPasskey, challenge.Session, response)
+	if lookupErr != nil && lookupErr != ErrPasskeyNotFound {
+		return User{}, lookupErr
+	} else if err != nil || credential.Authenticator.CloneWarning {
+		return User{}, ErrInvalidCredentials
+	}
+	if err = s.store.UpdatePasskeyUsage(ctx, passkey.ID, *credential); err != nil {
+		return User{}, err
+	}
+
+	user, err := s.store.GetUserById(ctx, passkey.UserID)
+	if err != nil {
+		return User{}, err
+	} else if !user.Confirmed {
+		return User{}, ErrUnconfirmedUser
+	}
+	return user, nil
+}
+
+func (s *UserService) DeleteExpiredPasskeyChallenges(ctx context.Context) error {
+	return s.store.DeletePasskeyChallengesBefore(ctx, time.Now().Add(-PasskeyChallengeLifetime))
+}
+
+// takePasskeyChallenge removes the challenge, so every ceremony can only be
+// finished once.
+func (s *UserService) takePasskeyChallenge(ctx context.Context, token, ceremony string) (PasskeyChallenge, error) {
+	challenge, err := s.store.TakePasskeyChallenge(ctx, token, ceremony)
+	if err != nil {
+		return PasskeyChallenge{}, err
+	} else if time.Now().After(challenge.ExpiresAt()) {
+		return PasskeyChallenge{}, ErrPasskeyChallengeNotFound
+	}
+	return challenge, nil
+}
+
+// passkeyUserHandle identifies the user to authenticators. It must not
+// contain personal information, so it is the ID rather than the email.
+func passkeyUserHandle(userID int64) []byte {
+	return []byte(strconv.FormatInt(userID, 10))
+}
+
+// passkeyUser is the account a ceremony is about, as go-webauthn sees it.
+type passkeyUser struct {
+	id       int64
+	email    string
+	passkeys []Passkey
+}
+
+func (u passkeyUser) WebAuthnID() []byte {
+	return passkeyUserHandle(u.id)
+}
+
+func (u passkeyUser) WebAuthnName() string {
+	return u.email
+}
+
+func (u passkeyUser) WebAuthnDisplayName() string {
+	return u.email
+}
+
+func (u passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.passkeys))
+	for i, passkey := range u.passkeys {
+		credentials[i] = passkey.Credential
+	}
+	return credentials
+}
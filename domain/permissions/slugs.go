@@ -53,3 +53,18 @@ const (
 	UpdateShoppingList Slug = "can_update_shopping_list"
 	ViewShoppingList   Slug = "can_view_shopping_list"
 )
+
+// Stores
+const (
+	CreateStore Slug = "can_create_store"
+	DeleteStore Slug = "can_delete_store"
+	ListStores  Slug = "can_list_stores"
+	UpdateStore Slug = "can_update_store"
+	ViewStore   Slug = "can_view_store"
+)
+
+// Households
+const (
+	UpdateHousehold Slug = "can_update_household"
+	ViewHousehold   Slug = "can_view_household"
+)
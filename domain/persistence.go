@@ -3,20 +3,57 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/wolfsblu/recipe-manager/domain/roles"
 )
 
 type RecipeStore interface {
-	BrowseRecipes(ctx context.Context) ([]Recipe, error)
 	CreateRecipe(ctx context.Context, recipe Recipe) (Recipe, error)
 	DeleteRecipe(ctx context.Context, id int64) error
-	GetMealPlan(ctx context.Context, user *User, from time.Time, until time.Time) ([]MealPlan, error)
+	GetMealPlan(ctx context.Context, householdID int64, from time.Time, until time.Time) ([]MealPlan, error)
+	GetNutrientTargets(ctx context.Context, userID int64) ([]NutrientTarget, error)
+	// CreateMealPlan adds the entry to the bottom of its slot, its SortOrder
+	// is assigned in the same statement so concurrent entries can't collide.
 	CreateMealPlan(ctx context.Context, entry MealPlanEntry) error
-	DeleteMealPlan(ctx context.Context, userID int64, recipeID int64, date time.Time) error
+	// CreateMealPlanEntries adds all entries like CreateMealPlan in one
+	// transaction.
+	CreateMealPlanEntries(ctx context.Context, entries []MealPlanEntry) error
+	// UpdateMealPlanEntry puts the entry at the bottom of its new slot if the
+	// slot changes.
+	UpdateMealPlanEntry(ctx context.Context, entry MealPlanEntry) error
+	DeleteMealPlanEntry(ctx context.Context, householdID int64, entryID int64) error
+	// MoveMealPlanEntry passes the household's entries on the entry's day and
+	// the given date to move, sorted by date, slot and position, and saves the
+	// entries it returns in one transaction.
+	MoveMealPlanEntry(ctx context.Context, householdID int64, entryID int64, date time.Time, move func([]MealPlanEntry) ([]MealPlanEntry, error)) error
+	GetMealSlots(ctx context.Context, householdID int64) ([]MealSlot, error)
+	// SaveMealSlots makes the household's slots match the given ones and
+	// returns them.
+	SaveMealSlots(ctx context.Context, householdID int64, slots []MealSlot) ([]MealSlot, error)
+	GetMealPlanTemplates(ctx context.Context, householdID int64) ([]MealPlanTemplate, error)
+	GetRecurringMealPlanTemplates(ctx context.Context) ([]MealPlanTemplate, error)
+	GetMealPlanTemplate(ctx context.Context, householdID int64, templateID int64) (MealPlanTemplate, error)
+	CreateMealPlanTemplate(ctx context.Context, template MealPlanTemplate) (MealPlanTemplate, error)
+	// UpdateMealPlanTemplate saves the template and replaces the entries it
+	// added from the given day on that nobody changed with its new ones, in
+	// every week it was applied to.
+	UpdateMealPlanTemplate(ctx context.Context, template MealPlanTemplate, from time.Time) (MealPlanTemplate, error)
+	// DeleteMealPlanTemplate removes the template along with the entries it
+	// added from the given day on that nobody changed.
+	DeleteMealPlanTemplate(ctx context.Context, householdID int64, templateID int64, from time.Time) error
+	// ApplyMealPlanTemplate adds the occurrences of the template in the weeks
+	// from the given day on. Days that already have an occurrence of a
+	// template entry, or had one that was changed or removed, are skipped.
+	ApplyMealPlanTemplate(ctx context.Context, template MealPlanTemplate, weeks []time.Time, from time.Time) error
 	GetIngredients(ctx context.Context) ([]Ingredient, error)
+	ListIngredients(ctx context.Context, after *Cursor, limit int64) ([]Ingredient, error)
 	GetUnits(ctx context.Context) ([]Unit, error)
 	GetTags(ctx context.Context) ([]Tag, error)
 	GetRecipeById(ctx context.Context, user *User, id int64) (Recipe, error)
-	GetRecipesByUser(ctx context.Context, user *User) ([]Recipe, error)
+	GetRecipesByHousehold(ctx context.Context, householdID int64) ([]Recipe, error)
+	ListRecipes(ctx context.Context, query RecipeListQuery, after *Cursor) ([]Recipe, error)
+	SearchRecipes(ctx context.Context, query RecipeSearchQuery) ([]RecipeSearchResult, error)
 	UpdateRecipe(ctx context.Context, recipe Recipe) (Recipe, error)
 	CreateIngredient(ctx context.Context, ingredient Ingredient) (Ingredient, error)
 	UpdateIngredient(ctx context.Context, ingredient Ingredient) (Ingredient, error)
@@ -28,6 +65,71 @@ type RecipeStore interface {
 
 type UserStore interface {
 	ConfirmRegistration(ctx context.Context, user *User) error
+	// CreateCalendarToken saves the hash of the user's new calendar token,
+	// replacing the one they had.
+	CreateCalendarToken(ctx context.Context, userID int64, hash string) (CalendarToken, error)
+	DeleteCalendarToken(ctx context.Context, userID int64) error
+	GetCalendarToken(ctx context.Context, userID int64) (CalendarToken, error)
+	GetUserByCalendarTokenHash(ctx context.Context, hash string) (User, error)
+	// CreateSession saves a new session under the hash of its token.
+	CreateSession(ctx context.Context, userID int64, hash string, client SessionClient) (Session, error)
+	DeleteSession(ctx context.Context, userID int64, id int64) error
+	DeleteSessionByTokenHash(ctx context.Context, hash string) error
+	// DeleteSessions ends all sessions of the user except the one with keepID.
+	DeleteSessions(ctx context.Context, userID int64, keepID int64) error
+	DeleteSessionsBefore(ctx context.Context, before time.Time) error
+	GetSessionByTokenHash(ctx context.Context, hash string) (Session, error)
+	GetSessions(ctx context.Context, userID int64) ([]Session, error)
+	UpdateSessionLastSeen(ctx context.Context, id int64) error
+	// ConfirmTOTP turns on two-factor authentication for the user and replaces
+	// their recovery codes with the given hashes.
+	ConfirmTOTP(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error
+	DeleteTOTP(ctx context.Context, userID int64) error
+	GetTOTP(ctx context.Context, userID int64) (TOTP, error)
+	SaveTOTP(ctx context.Context, userID int64, secret string) error
+	// UpdateTOTPLastUsedStep fails with ErrInvalidTwoFactorCode unless the
+	// step comes after the last one that was used.
+	UpdateTOTPLastUsedStep(ctx context.Context, userID int64, step int64) error
+	DeleteRecoveryCode(ctx context.Context, id int64) error
+	GetRecoveryCodes(ctx context.Context, userID int64) ([]RecoveryCode, error)
+	CreateLoginChallenge(ctx context.Context, userID int64) (LoginChallenge, error)
+	DeleteLoginChallenge(ctx context.Context, id int64) error
+	DeleteLoginChallengesBefore(ctx context.Context, before time.Time) error
+	GetLoginChallenge(ctx context.Context, token string) (LoginChallenge, error)
+	UpdateLoginChallengeAttempts(ctx context.Context, id int64, since time.Time, maxAttempts int64) error
+	CreatePasskey(ctx context.Context, userID int64, name string, credential webauthn.Credential) (Passkey, error)
+	DeletePasskey(ctx context.Context, userID int64, id int64) error
+	GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (Passkey, error)
+	GetPasskeys(ctx context.Context, userID int64) ([]Passkey, error)
+	// UpdatePasskeyUsage saves the sign count and flags of a login with the
+	// passkey.
+	UpdatePasskeyUsage(ctx context.Context, id int64, credential webauthn.Credential) error
+	// CreatePasskeyChallenge saves the challenge of a ceremony, userID is zero
+	// for logins.
+	CreatePasskeyChallenge(ctx context.Context, ceremony string, userID int64, session webauthn.SessionData) (PasskeyChallenge, error)
+	DeletePasskeyChallengesBefore(ctx context.Context, before time.Time) error
+	// TakePasskeyChallenge removes the challenge of the ceremony and returns it.
+	TakePasskeyChallenge(ctx context.Context, token, ceremony string) (PasskeyChallenge, error)
+	// CreateSSOLogin saves the nonce and PKCE verifier of a login under a new
+	// state.
+	CreateSSOLogin(ctx context.Context, nonce, codeVerifier string) (SSOLogin, error)
+	DeleteSSOLoginsBefore(ctx context.Context, before time.Time) error
+	// TakeSSOLogin removes the login with the state and returns it.
+	TakeSSOLogin(ctx context.Context, state string) (SSOLogin, error)
+	CreateUserIdentity(ctx context.Context, userID int64, identity ExternalIdentity) error
+	GetUserByIdentity(ctx context.Context, issuer, subject string) (User, error)
+	// ProvisionUser creates a confirmed user with the role that logs in with
+	// the identity.
+	ProvisionUser(ctx context.Context, userDetails UserDetails, roleID roles.ID, identity ExternalIdentity) (User, error)
+	// ClaimUnconfirmedUser confirms the user and replaces their password hash,
+	// ending any sessions they have.
+	ClaimUnconfirmedUser(ctx context.Context, user *User, passwordHash string) error
+	// CreateAPIToken saves the token under the hash of its secret.
+	CreateAPIToken(ctx context.Context, token APIToken, hash string) (APIToken, error)
+	DeleteAPIToken(ctx context.Context, userID int64, id int64) error
+	GetAPITokenByHash(ctx context.Context, hash string) (APIToken, error)
+	GetAPITokens(ctx context.Context, userID int64) ([]APIToken, error)
+	UpdateAPITokenLastUsed(ctx context.Context, id int64) error
 	CreatePasswordResetToken(ctx context.Context, user *User) (PasswordResetToken, error)
 	DeletePasswordResetsBefore(ctx context.Context, before time.Time) error
 	DeleteRegistrationsBefore(ctx context.Context, before time.Time) error
@@ -35,17 +137,42 @@ type UserStore interface {
 	GetRegistrationByToken(ctx context.Context, token string) (UserRegistration, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserById(ctx context.Context, id int64) (User, error)
+	GetNutrientTargets(ctx context.Context, userID int64) ([]NutrientTarget, error)
+	UpdateNutrientTargets(ctx context.Context, userID int64, targets []NutrientTarget) ([]NutrientTarget, error)
 	RegisterUser(ctx context.Context, userDetails UserDetails) (User, UserRegistration, error)
 	UpdatePasswordByToken(ctx context.Context, token, hashedPassword string) error
 }
 
+type HouseholdStore interface {
+	GetHouseholdByID(ctx context.Context, id int64) (Household, error)
+	UpdateHousehold(ctx context.Context, id int64, name string) (Household, error)
+	UpdateHouseholdMember(ctx context.Context, householdID int64, member HouseholdMember) error
+	RemoveHouseholdMember(ctx context.Context, householdID int64, userID int64) error
+	CreateHouseholdInvitation(ctx context.Context, invitation HouseholdInvitation) (HouseholdInvitation, error)
+	GetHouseholdInvitationByToken(ctx context.Context, token string) (HouseholdInvitation, error)
+	DeleteHouseholdInvitationsBefore(ctx context.Context, before time.Time) error
+	AcceptHouseholdInvitation(ctx context.Context, invitation HouseholdInvitation, user *User) error
+}
+
 type ShoppingStore interface {
-	GetShoppingListsByUser(ctx context.Context, userID int64) ([]ShoppingList, error)
+	GetShoppingListsByHousehold(ctx context.Context, householdID int64) ([]ShoppingList, error)
 	GetShoppingListByID(ctx context.Context, listID int64) (ShoppingList, error)
-	CreateShoppingList(ctx context.Context, userID int64, name string) (ShoppingList, error)
+	// CreateShoppingList creates the list together with its items.
+	CreateShoppingList(ctx context.Context, householdID int64, userID int64, list ShoppingList) (ShoppingList, error)
 	UpdateShoppingList(ctx context.Context, listID int64, name string) (ShoppingList, error)
 	DeleteShoppingList(ctx context.Context, listID int64) error
 	CreateShoppingListItem(ctx context.Context, listID int64, item ShoppingListItem) (ShoppingListItem, error)
 	UpdateShoppingListItem(ctx context.Context, itemID int64, item ShoppingListItem) (ShoppingListItem, error)
 	DeleteShoppingListItem(ctx context.Context, itemID int64) error
+	SaveShoppingListItems(ctx context.Context, listID int64, items []ShoppingListItem) error
+	// SyncShoppingListItems saves the changes computed by apply from the
+	// current list in one transaction and returns the list afterwards. The
+	// transaction is rolled back if apply fails.
+	SyncShoppingListItems(ctx context.Context, listID int64, apply func(ShoppingList) (ShoppingListChanges, error)) (ShoppingList, error)
+	GetShoppingListChangesSince(ctx context.Context, listID int64, revision int64) (ShoppingListDelta, error)
+	GetStoresByHousehold(ctx context.Context, householdID int64) ([]Store, error)
+	GetStoreByID(ctx context.Context, storeID int64) (Store, error)
+	CreateStore(ctx context.Context, householdID int64, userID int64, store Store) (Store, error)
+	UpdateStore(ctx context.Context, storeID int64, store Store) (Store, error)
+	DeleteStore(ctx context.Context, storeID int64) error
 }
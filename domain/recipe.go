@@ -26,34 +26,108 @@ type RecipeStep struct {
 }
 
 type Recipe struct {
-	ID     int64
-	Tags   []Tag
-	Images []RecipeImage
-	Steps  []RecipeStep
+	ID          int64
+	HouseholdID int64
+	Tags        []Tag
+	Images      []RecipeImage
+	Steps       []RecipeStep
+	CreatedAt   time.Time
 	RecipeDetails
 }
 
+type RecipeSortKey string
+
+const (
+	RecipeSortByName      RecipeSortKey = "name"
+	RecipeSortByCreatedAt RecipeSortKey = "createdAt"
+	RecipeSortByMinutes   RecipeSortKey = "minutes"
+)
+
+type RecipeFilter struct {
+	CreatedBy            *int64
+	HouseholdID          *int64
+	TagIDs               []int64
+	MaxMinutes           *int64
+	MinServings          *int64
+	MaxServings          *int64
+	IncludeIngredientIDs []int64
+	ExcludeIngredientIDs []int64
+}
+
+type RecipeListQuery struct {
+	Filter RecipeFilter
+	SortBy RecipeSortKey
+	Order  SortOrder
+	Cursor string
+	Limit  int64
+}
+
+type IngredientListQuery struct {
+	Cursor string
+	Limit  int64
+}
+
+type RecipeSearchQuery struct {
+	Text   string
+	Limit  int64
+	Offset int64
+}
+
+type RecipeSearchResult struct {
+	Recipe          Recipe
+	HighlightedName string
+	Snippet         string
+	Rank            float64
+}
+
 type RecipeImage struct {
 	ID  int64
 	URL *url.URL
 }
 
 type MealPlan struct {
-	Date    time.Time
-	Recipes []Recipe
+	Date  time.Time
+	Meals []PlannedMeal
 }
 
+// PlannedMeal is an entry on the meal plan with its recipe, if it has one.
+// Servings overrides the recipe's own servings for that entry when set.
+type PlannedMeal struct {
+	EntryID         int64
+	SlotID          *int64
+	Recipe          *Recipe
+	Note            *string
+	Servings        *int64
+	TemplateEntryID *int64
+}
+
+// MealPlanEntry plans a recipe or just a note like "eat out" for the
+// household, UserID is the member who added it. Entries can be put into one
+// of the household's meal slots. TemplateEntryID is set on entries a template
+// added for as long as nobody changes them.
 type MealPlanEntry struct {
-	UserID    int64
-	RecipeID  int64
-	Date      time.Time
-	SortOrder int64
+	ID              int64
+	HouseholdID     int64
+	UserID          int64
+	RecipeID        *int64
+	SlotID          *int64
+	Note            *string
+	Date            time.Time
+	SortOrder       int64
+	Servings        *int64
+	TemplateEntryID *int64
 }
 
 type Ingredient struct {
-	ID        int64
-	Name      string
-	Nutrients []IngredientNutrient
+	ID   int64
+	Name string
+	// Density in grams per millilitre, used to convert between volume and mass.
+	Density *float64
+	// Nutrients are given per ReferenceAmount of ReferenceUnit, or per
+	// ReferenceAmount grams when no reference unit is set.
+	ReferenceAmount float64
+	ReferenceUnit   *Unit
+	Nutrients       []IngredientNutrient
 }
 
 type Nutrient struct {
@@ -67,10 +141,32 @@ type IngredientNutrient struct {
 	Amount   float64
 }
 
+type UnitDimension string
+
+const (
+	UnitDimensionMass   UnitDimension = "mass"
+	UnitDimensionVolume UnitDimension = "volume"
+	UnitDimensionCount  UnitDimension = "count"
+	UnitDimensionLength UnitDimension = "length"
+)
+
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+// Unit is convertible when it has a dimension and a factor. Factor is the
+// amount of the dimension's base unit (g, ml, piece or cm) in one of this
+// unit. System is empty for units used with both systems, like spoons.
 type Unit struct {
-	ID     int64
-	Name   string
-	Symbol *string
+	ID        int64
+	Name      string
+	Symbol    *string
+	Dimension UnitDimension
+	System    UnitSystem
+	Factor    float64
 }
 
 type Tag struct {
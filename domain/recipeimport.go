@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+)
+
+// RecipeImportSource is either a page to fetch or HTML the user uploaded.
+// BaseURL resolves relative image links in uploaded HTML.
+type RecipeImportSource struct {
+	URL     *url.URL
+	HTML    []byte
+	BaseURL *url.URL
+}
+
+// ScrapedRecipe is the raw schema.org/Recipe data found on a page, before
+// ingredient lines have been parsed and matched.
+type ScrapedRecipe struct {
+	Name         string
+	Description  string
+	Servings     int64
+	Minutes      int64
+	Instructions []string
+	Ingredients  []string
+	Images       []*url.URL
+}
+
+type RecipeScraper interface {
+	Scrape(ctx context.Context, source RecipeImportSource) (ScrapedRecipe, error)
+}
+
+// UnresolvedIngredient is an ingredient line whose unit or ingredient could
+// not be matched and needs to be confirmed by the user.
+type UnresolvedIngredient struct {
+	Line       string
+	Amount     float64
+	Unit       string
+	Ingredient string
+}
+
+type RecipeImport struct {
+	Recipe     Recipe
+	Unresolved []UnresolvedIngredient
+}
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
 	"time"
 )
 
@@ -11,60 +14,418 @@ type RecipeService struct {
 }
 
 func (s *RecipeService) Add(ctx context.Context, r Recipe) (Recipe, error) {
+	membership := r.CreatedBy.Membership
+	if err := membership.Authorize(membership.HouseholdID, HouseholdRoleEditor); err != nil {
+		return Recipe{}, err
+	}
+	r.HouseholdID = membership.HouseholdID
+
 	if err := s.validateRecipe(ctx, r); err != nil {
 		return Recipe{}, err
 	}
 	return s.store.CreateRecipe(ctx, r)
 }
 
-func (s *RecipeService) Browse(ctx context.Context) ([]Recipe, error) {
-	return s.store.BrowseRecipes(ctx)
+func (s *RecipeService) Browse(ctx context.Context, query RecipeListQuery) (Page[Recipe], error) {
+	query.Filter.CreatedBy = nil
+	query.Filter.HouseholdID = nil
+	return s.listRecipes(ctx, query)
 }
 
+// GetMealPlan returns the household's planned meals with the ingredients of
+// their recipes scaled to the servings planned for each entry.
 func (s *RecipeService) GetMealPlan(ctx context.Context, user *User, from time.Time, until time.Time) ([]MealPlan, error) {
-	return s.store.GetMealPlan(ctx, user, from, until)
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+
+	mealPlan, err := s.store.GetMealPlan(ctx, householdID, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := s.store.GetUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, day := range mealPlan {
+		for i, planned := range day.Meals {
+			if planned.Recipe == nil || planned.Servings == nil || *planned.Servings == planned.Recipe.Servings {
+				continue
+			}
+			scaled := scaleRecipe(*planned.Recipe, RecipeScale{Servings: planned.Servings}, units)
+			day.Meals[i].Recipe = &scaled
+		}
+	}
+	return mealPlan, nil
 }
 
-func (s *RecipeService) CreateMealPlan(ctx context.Context, user *User, recipeID int64, date time.Time) error {
-	existingMealPlan, err := s.store.GetMealPlan(ctx, user, date, date)
+// GetMealPlanNutrition sums up the nutrition of the meal plan per day and over
+// the whole range and compares it against the user's daily targets.
+func (s *RecipeService) GetMealPlanNutrition(ctx context.Context, user *User, from time.Time, until time.Time) (MealPlanNutrition, error) {
+	if err := s.validateDateRange(from, until); err != nil {
+		return MealPlanNutrition{}, err
+	}
+
+	mealPlan, err := s.GetMealPlan(ctx, user, from, until)
+	if err != nil {
+		return MealPlanNutrition{}, err
+	}
+
+	targets, err := s.store.GetNutrientTargets(ctx, user.ID)
 	if err != nil {
+		return MealPlanNutrition{}, err
+	}
+	return SummarizeMealPlanNutrition(mealPlan, targets, from, until), nil
+}
+
+// CreateMealPlan adds the recipe or note of the entry to the bottom of its
+// slot on its day.
+func (s *RecipeService) CreateMealPlan(ctx context.Context, user *User, entry MealPlanEntry) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	entry.HouseholdID = householdID
+	entry.UserID = user.ID
+	if err := s.validateMealPlanEntry(ctx, entry); err != nil {
+		return err
+	}
+	return s.store.CreateMealPlan(ctx, entry)
+}
+
+// CreateMealPlanEntries saves several entries at once, like the meals of an
+// accepted proposal. Either all of them are added or none.
+func (s *RecipeService) CreateMealPlanEntries(ctx context.Context, user *User, entries []MealPlanEntry) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	if len(entries) == 0 || len(entries) > maxMealPlanEntries {
+		return ErrInvalidMealPlanEntry
+	}
+	for i := range entries {
+		entries[i].HouseholdID = householdID
+		entries[i].UserID = user.ID
+		if err := s.validateMealPlanEntry(ctx, entries[i]); err != nil {
+			return err
+		}
+	}
+	return s.store.CreateMealPlanEntries(ctx, entries)
+}
+
+// GenerateMealPlan proposes recipes of the household for the days of the
+// range that have nothing planned in the slot yet. Nothing is saved, the
+// proposal can be accepted through CreateMealPlanEntries. Without a seed a
+// random one is picked and returned with the proposal.
+func (s *RecipeService) GenerateMealPlan(ctx context.Context, user *User, generation MealPlanGeneration) (MealPlanProposal, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return MealPlanProposal{}, err
+	}
+	if err := s.validateMealPlanGeneration(ctx, householdID, generation); err != nil {
+		return MealPlanProposal{}, err
+	}
+
+	recipes, err := s.store.GetRecipesByHousehold(ctx, householdID)
+	if err != nil {
+		return MealPlanProposal{}, err
+	}
+	// Meals planned shortly before the range count as repeats as well
+	planned, err := s.store.GetMealPlan(ctx, householdID, generation.From.AddDate(0, 0, -int(generation.NoRepeatDays)), generation.Until)
+	if err != nil {
+		return MealPlanProposal{}, err
+	}
+	var targets []NutrientTarget
+	if generation.UseNutritionTargets {
+		if targets, err = s.store.GetNutrientTargets(ctx, user.ID); err != nil {
+			return MealPlanProposal{}, err
+		}
+	}
+
+	seed := rand.Int64()
+	if generation.Seed != nil {
+		seed = *generation.Seed
+	}
+	return generateMealPlan(recipes, planned, targets, generation, seed), nil
+}
+
+// UpdateMealPlanEntry changes what an entry plans, its day and position stay
+// the same.
+func (s *RecipeService) UpdateMealPlanEntry(ctx context.Context, user *User, entryID int64, entry MealPlanEntry) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	entry.ID = entryID
+	entry.HouseholdID = householdID
+	if err := s.validateMealPlanEntry(ctx, entry); err != nil {
+		return err
+	}
+	return s.store.UpdateMealPlanEntry(ctx, entry)
+}
+
+func (s *RecipeService) DeleteMealPlanEntry(ctx context.Context, user *User, entryID int64) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
 		return err
 	}
+	return s.store.DeleteMealPlanEntry(ctx, householdID, entryID)
+}
+
+func (s *RecipeService) GetMealSlots(ctx context.Context, user *User) ([]MealSlot, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.store.GetMealSlots(ctx, householdID)
+}
 
-	var sortOrder int64 = 0
-	if len(existingMealPlan) > 0 && len(existingMealPlan[0].Recipes) > 0 {
-		sortOrder = int64(len(existingMealPlan[0].Recipes))
+// SaveMealSlots replaces the slots of the household with the given ones in
+// their order. Slots with an ID are renamed, the others created. Entries in
+// a slot that is left out remain on the meal plan without a slot.
+func (s *RecipeService) SaveMealSlots(ctx context.Context, user *User, slots []MealSlot) ([]MealSlot, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleOwner); err != nil {
+		return nil, err
 	}
+	if err := s.validateMealSlots(slots); err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		slots[i].SortOrder = int64(i)
+	}
+	return s.store.SaveMealSlots(ctx, householdID, slots)
+}
 
-	return s.store.CreateMealPlan(ctx, MealPlanEntry{
-		UserID:    user.ID,
-		RecipeID:  recipeID,
-		Date:      date,
-		SortOrder: sortOrder,
+// MoveMealPlanEntry puts an entry at the given position of a slot, counted
+// from the top, which may be on another day than the one it is planned for.
+// Without a slot the entry stays in the one it is in.
+func (s *RecipeService) MoveMealPlanEntry(ctx context.Context, user *User, entryID int64, date time.Time, slotID *int64, position int64) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	if position < 0 {
+		return ErrInvalidMove
+	}
+	if err := s.validateMealSlotID(ctx, householdID, slotID); err != nil {
+		return err
+	}
+	return s.store.MoveMealPlanEntry(ctx, householdID, entryID, date, func(entries []MealPlanEntry) ([]MealPlanEntry, error) {
+		return moveMealPlanEntry(entries, entryID, date, slotID, position)
 	})
 }
 
-func (s *RecipeService) DeleteMealPlan(ctx context.Context, user *User, recipeID int64, date time.Time) error {
-	return s.store.DeleteMealPlan(ctx, user.ID, recipeID, date)
+func (s *RecipeService) GetMealPlanTemplates(ctx context.Context, user *User) ([]MealPlanTemplate, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.store.GetMealPlanTemplates(ctx, householdID)
+}
+
+// CreateMealPlanTemplate saves a new template of the household. A recurring
+// template is put on the meal plan of the coming weeks right away.
+func (s *RecipeService) CreateMealPlanTemplate(ctx context.Context, user *User, template MealPlanTemplate) (MealPlanTemplate, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return MealPlanTemplate{}, err
+	}
+	template.HouseholdID = householdID
+	template.UserID = user.ID
+	if err := s.validateMealPlanTemplate(ctx, template); err != nil {
+		return MealPlanTemplate{}, err
+	}
+
+	created, err := s.store.CreateMealPlanTemplate(ctx, template)
+	if err != nil {
+		return MealPlanTemplate{}, err
+	}
+	return created, s.applyRecurringMealPlanTemplate(ctx, created)
+}
+
+// UpdateMealPlanTemplate replaces the template with the given one. Entries
+// with an ID are changed, the others added and the ones left out removed.
+// The changes carry over to the entries the template added from today on,
+// unless they were changed on the meal plan itself.
+func (s *RecipeService) UpdateMealPlanTemplate(ctx context.Context, user *User, templateID int64, template MealPlanTemplate) (MealPlanTemplate, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return MealPlanTemplate{}, err
+	}
+	template.ID = templateID
+	template.HouseholdID = householdID
+	if err := s.validateMealPlanTemplate(ctx, template); err != nil {
+		return MealPlanTemplate{}, err
+	}
+
+	updated, err := s.store.UpdateMealPlanTemplate(ctx, template, today())
+	if err != nil {
+		return MealPlanTemplate{}, err
+	}
+	return updated, s.applyRecurringMealPlanTemplate(ctx, updated)
+}
+
+// DeleteMealPlanTemplate removes the template and the entries it added from
+// today on that weren't changed since.
+func (s *RecipeService) DeleteMealPlanTemplate(ctx context.Context, user *User, templateID int64) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	return s.store.DeleteMealPlanTemplate(ctx, householdID, templateID, today())
+}
+
+// ApplyMealPlanTemplate puts the template on the meal plan of every week
+// from the one of from to the one of until. Days the template was already
+// applied to keep their entries.
+func (s *RecipeService) ApplyMealPlanTemplate(ctx context.Context, user *User, templateID int64, from time.Time, until time.Time) error {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	if err := s.validateDateRange(from, until); err != nil {
+		return err
+	}
+
+	template, err := s.store.GetMealPlanTemplate(ctx, householdID, templateID)
+	if err != nil {
+		return err
+	}
+	weeks := weeksBetween(from, until)
+	return s.store.ApplyMealPlanTemplate(ctx, template, weeks, weeks[0])
+}
+
+// ApplyRecurringMealPlanTemplates keeps the recurring templates of all
+// households on the meal plan of the coming weeks.
+func (s *RecipeService) ApplyRecurringMealPlanTemplates(ctx context.Context) error {
+	templates, err := s.store.GetRecurringMealPlanTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, template := range templates {
+		errs = append(errs, s.applyRecurringMealPlanTemplate(ctx, template))
+	}
+	return errors.Join(errs...)
+}
+
+// applyRecurringMealPlanTemplate applies a recurring template to the current
+// week from today on and the weeks after it.
+func (s *RecipeService) applyRecurringMealPlanTemplate(ctx context.Context, template MealPlanTemplate) error {
+	if !template.Recurring {
+		return nil
+	}
+	from := today()
+	weeks := weeksBetween(from, from.AddDate(0, 0, 7*(mealPlanTemplateWeeks-1)))
+	return s.store.ApplyMealPlanTemplate(ctx, template, weeks, from)
 }
 
 func (s *RecipeService) Delete(ctx context.Context, user *User, id int64) error {
-	if err := s.validateRecipeOwnership(ctx, user, id); err != nil {
+	if err := s.validateRecipeMembership(ctx, user, id); err != nil {
 		return err
 	}
 	return s.store.DeleteRecipe(ctx, id)
 }
 
-func (s *RecipeService) GetByUser(ctx context.Context, user *User) ([]Recipe, error) {
-	return s.store.GetRecipesByUser(ctx, user)
+// GetByUser lists the recipes of the user's household, no matter which member
+// created them.
+func (s *RecipeService) GetByUser(ctx context.Context, user *User, query RecipeListQuery) (Page[Recipe], error) {
+	query.Filter.CreatedBy = nil
+	query.Filter.HouseholdID = &user.Membership.HouseholdID
+	return s.listRecipes(ctx, query)
+}
+
+func (s *RecipeService) listRecipes(ctx context.Context, query RecipeListQuery) (Page[Recipe], error) {
+	query, after, err := s.normalizeRecipeListQuery(query)
+	if err != nil {
+		return Page[Recipe]{}, err
+	}
+
+	pageSize := query.Limit
+	query.Limit++
+	recipes, err := s.store.ListRecipes(ctx, query, after)
+	if err != nil {
+		return Page[Recipe]{}, err
+	}
+
+	page := Page[Recipe]{Items: recipes}
+	if int64(len(recipes)) > pageSize {
+		page.Items = recipes[:pageSize]
+		last := page.Items[pageSize-1]
+		page.NextCursor = Cursor{
+			Key:   string(query.SortBy),
+			Value: recipeSortValue(last, query.SortBy),
+			ID:    last.ID,
+		}.Encode()
+	}
+	return page, nil
+}
+
+func (s *RecipeService) Search(ctx context.Context, query RecipeSearchQuery) ([]RecipeSearchResult, error) {
+	query, err := s.normalizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.SearchRecipes(ctx, query)
 }
 
 func (s *RecipeService) GetById(ctx context.Context, user *User, id int64) (Recipe, error) {
 	return s.store.GetRecipeById(ctx, user, id)
 }
 
-func (s *RecipeService) GetIngredients(ctx context.Context) ([]Ingredient, error) {
-	return s.store.GetIngredients(ctx)
+// GetScaledById returns the recipe with its ingredient amounts scaled to the
+// requested servings or factor.
+func (s *RecipeService) GetScaledById(ctx context.Context, user *User, id int64, scale RecipeScale) (Recipe, error) {
+	if err := s.validateRecipeScale(scale); err != nil {
+		return Recipe{}, err
+	}
+
+	recipe, err := s.store.GetRecipeById(ctx, user, id)
+	if err != nil || scale.IsZero() {
+		return recipe, err
+	}
+
+	units, err := s.store.GetUnits(ctx)
+	if err != nil {
+		return Recipe{}, err
+	}
+
+	return scaleRecipe(recipe, scale, units), nil
+}
+
+func (s *RecipeService) GetIngredients(ctx context.Context, query IngredientListQuery) (Page[Ingredient], error) {
+	pageSize, err := normalizePageSize(query.Limit)
+	if err != nil {
+		return Page[Ingredient]{}, err
+	}
+	after, err := DecodeCursor(query.Cursor)
+	if err != nil {
+		return Page[Ingredient]{}, err
+	}
+	if after != nil && after.Key != ingredientCursorKey {
+		return Page[Ingredient]{}, ErrInvalidCursor
+	}
+
+	ingredients, err := s.store.ListIngredients(ctx, after, pageSize+1)
+	if err != nil {
+		return Page[Ingredient]{}, err
+	}
+
+	page := Page[Ingredient]{Items: ingredients}
+	if int64(len(ingredients)) > pageSize {
+		page.Items = ingredients[:pageSize]
+		last := page.Items[pageSize-1]
+		page.NextCursor = Cursor{
+			Key:   ingredientCursorKey,
+			Value: last.Name,
+			ID:    last.ID,
+		}.Encode()
+	}
+	return page, nil
 }
 
 func (s *RecipeService) GetUnits(ctx context.Context) ([]Unit, error) {
@@ -75,8 +436,9 @@ func (s *RecipeService) GetTags(ctx context.Context) ([]Tag, error) {
 	return s.store.GetTags(ctx)
 }
 
+// UpdateRecipe saves the recipe on behalf of the member set as CreatedBy.
 func (s *RecipeService) UpdateRecipe(ctx context.Context, recipe Recipe) (Recipe, error) {
-	if err := s.validateRecipeOwnership(ctx, recipe.CreatedBy, recipe.ID); err != nil {
+	if err := s.validateRecipeMembership(ctx, recipe.CreatedBy, recipe.ID); err != nil {
 		return Recipe{}, err
 	}
 
@@ -87,6 +449,27 @@ func (s *RecipeService) UpdateRecipe(ctx context.Context, recipe Recipe) (Recipe
 	return s.store.UpdateRecipe(ctx, recipe)
 }
 
+func (s *RecipeService) ParseIngredients(ctx context.Context, lines []string) ([]ParsedIngredient, error) {
+	if err := s.validateIngredientLines(lines); err != nil {
+		return nil, err
+	}
+
+	units, err := s.store.GetUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ingredients, err := s.store.GetIngredients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make([]ParsedIngredient, len(lines))
+	for i, line := range lines {
+		parsed[i] = ParseIngredientLine(line, units, ingredients)
+	}
+	return parsed, nil
+}
+
 func (s *RecipeService) AddIngredient(ctx context.Context, ingredient Ingredient) (Ingredient, error) {
 	if err := s.validateIngredient(ingredient); err != nil {
 		return Ingredient{}, err
@@ -122,6 +505,43 @@ func (s *RecipeService) UpdateUnit(ctx context.Context, unit Unit) (Unit, error)
 	return unit, nil
 }
 
+func (s *RecipeService) ConvertAmount(ctx context.Context, conversion UnitConversion) (float64, error) {
+	units, err := s.store.GetUnits(ctx)
+	if err != nil {
+		return 0, err
+	}
+	from, to := findUnitByID(units, conversion.FromUnitID), findUnitByID(units, conversion.ToUnitID)
+	if from == nil || to == nil {
+		return 0, ErrInvalidUnit
+	}
+
+	var density *float64
+	if conversion.IngredientID != nil {
+		ingredients, err := s.store.GetIngredients(ctx)
+		if err != nil {
+			return 0, err
+		}
+		ingredient := findIngredientByID(ingredients, *conversion.IngredientID)
+		if ingredient == nil {
+			return 0, ErrInvalidIngredient
+		}
+		density = ingredient.Density
+	}
+
+	return ConvertAmount(conversion.Amount, *from, *to, density)
+}
+
 func (s *RecipeService) DeleteUnit(ctx context.Context, id int64) error {
 	return s.store.DeleteUnit(ctx, id)
 }
+
+func recipeSortValue(recipe Recipe, sortBy RecipeSortKey) string {
+	switch sortBy {
+	case RecipeSortByMinutes:
+		return strconv.FormatInt(recipe.Minutes, 10)
+	case RecipeSortByCreatedAt:
+		return recipe.CreatedAt.UTC().Format(time.DateTime)
+	default:
+		return recipe.Name
+	}
+}
@@ -2,29 +2,193 @@ package domain
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	ingredientCursorKey = "name"
+
+	maxIngredientLines = 100
+
+	maxScaleFactor    = 100
+	maxScaledServings = 1000
+
+	maxDateRange = 366 * 24 * time.Hour
+
+	maxMealPlanNoteLength = 500
+	maxMealSlots          = 20
+	maxMealPlanEntries    = 500
+	maxNoRepeatDays       = 366
+
+	maxMealPlanTemplateNameLength = 100
+	maxMealPlanTemplateEntries    = 100
 )
 
 func (s *RecipeService) validateRecipe(ctx context.Context, r Recipe) error {
 	return nil
 }
 
-func (s *RecipeService) validateRecipeOwnership(ctx context.Context, user *User, recipeID int64) error {
+// validateRecipeMembership allows editors of the recipe's household to change it.
+func (s *RecipeService) validateRecipeMembership(ctx context.Context, user *User, recipeID int64) error {
 	recipe, err := s.store.GetRecipeById(ctx, user, recipeID)
 	if err != nil {
 		return err
 	}
 
-	if recipe.CreatedBy.ID != user.ID {
-		return ErrAuthorization
-	}
-
-	return nil
+	return user.Membership.Authorize(recipe.HouseholdID, HouseholdRoleEditor)
 }
 
 func (s *RecipeService) validateIngredient(ingredient Ingredient) error {
 	if ingredient.Name == "" {
 		return ErrInvalidIngredient
 	}
+	if ingredient.Density != nil && *ingredient.Density <= 0 {
+		return ErrInvalidIngredient
+	}
+	if ingredient.ReferenceAmount <= 0 {
+		return ErrInvalidIngredient
+	}
+	return nil
+}
+
+func (s *RecipeService) validateDateRange(from, until time.Time) error {
+	if until.Before(from) || until.Sub(from) > maxDateRange {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// validateMealPlanEntry requires an entry to plan a recipe, a note or both.
+// Its slot has to be one of the household's.
+func (s *RecipeService) validateMealPlanEntry(ctx context.Context, entry MealPlanEntry) error {
+	if entry.Note != nil && (strings.TrimSpace(*entry.Note) == "" || len(*entry.Note) > maxMealPlanNoteLength) {
+		return ErrInvalidMealPlanEntry
+	}
+	if entry.RecipeID == nil && entry.Note == nil {
+		return ErrInvalidMealPlanEntry
+	}
+	if err := s.validateRecipeScale(RecipeScale{Servings: entry.Servings}); err != nil {
+		return err
+	}
+	return s.validateMealSlotID(ctx, entry.HouseholdID, entry.SlotID)
+}
+
+func (s *RecipeService) validateMealPlanGeneration(ctx context.Context, householdID int64, generation MealPlanGeneration) error {
+	if err := s.validateDateRange(generation.From, generation.Until); err != nil {
+		return err
+	}
+	if generation.NoRepeatDays < 0 || generation.NoRepeatDays > maxNoRepeatDays {
+		return ErrInvalidMealPlanGeneration
+	}
+	if generation.WeekdayMaxMinutes != nil && *generation.WeekdayMaxMinutes < 0 {
+		return ErrInvalidMealPlanGeneration
+	}
+	if generation.MaxIngredients != nil && *generation.MaxIngredients < 1 {
+		return ErrInvalidMealPlanGeneration
+	}
+	if err := s.validateRecipeScale(RecipeScale{Servings: generation.Servings}); err != nil {
+		return err
+	}
+	return s.validateMealSlotID(ctx, householdID, generation.SlotID)
+}
+
+// validateMealPlanTemplate checks the template's entries like meal plan
+// entries of its household, on a day of the week.
+func (s *RecipeService) validateMealPlanTemplate(ctx context.Context, template MealPlanTemplate) error {
+	if strings.TrimSpace(template.Name) == "" || len(template.Name) > maxMealPlanTemplateNameLength {
+		return ErrInvalidMealPlanTemplate
+	}
+	if len(template.Entries) > maxMealPlanTemplateEntries {
+		return ErrInvalidMealPlanTemplate
+	}
+	ids := make(map[int64]bool, len(template.Entries))
+	for _, entry := range template.Entries {
+		if entry.Weekday < time.Sunday || entry.Weekday > time.Saturday {
+			return ErrInvalidMealPlanTemplate
+		}
+		if entry.ID != 0 {
+			if ids[entry.ID] {
+				return ErrInvalidMealPlanTemplate
+			}
+			ids[entry.ID] = true
+		}
+		err := s.validateMealPlanEntry(ctx, MealPlanEntry{
+			HouseholdID: template.HouseholdID,
+			RecipeID:    entry.RecipeID,
+			SlotID:      entry.SlotID,
+			Note:        entry.Note,
+			Servings:    entry.Servings,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RecipeService) validateMealSlotID(ctx context.Context, householdID int64, slotID *int64) error {
+	if slotID == nil {
+		return nil
+	}
+
+	slots, err := s.store.GetMealSlots(ctx, householdID)
+	if err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		if slot.ID == *slotID {
+			return nil
+		}
+	}
+	return ErrInvalidMealSlot
+}
+
+func (s *RecipeService) validateMealSlots(slots []MealSlot) error {
+	if len(slots) > maxMealSlots {
+		return ErrInvalidMealSlot
+	}
+	ids := make(map[int64]bool, len(slots))
+	for _, slot := range slots {
+		if strings.TrimSpace(slot.Name) == "" {
+			return ErrInvalidMealSlot
+		}
+		if slot.Time != "" {
+			if _, err := time.Parse(mealSlotTimeLayout, slot.Time); err != nil {
+				return ErrInvalidMealSlot
+			}
+		}
+		if slot.ID != 0 {
+			if ids[slot.ID] {
+				return ErrInvalidMealSlot
+			}
+			ids[slot.ID] = true
+		}
+	}
+	return nil
+}
+
+func (s *RecipeService) validateIngredientLines(lines []string) error {
+	if len(lines) == 0 || len(lines) > maxIngredientLines {
+		return ErrInvalidIngredientLines
+	}
+	return nil
+}
+
+func (s *RecipeService) validateRecipeScale(scale RecipeScale) error {
+	if scale.Servings != nil && (*scale.Servings <= 0 || *scale.Servings > maxScaledServings) {
+		return ErrInvalidRecipeScale
+	}
+	if scale.Factor != nil && (*scale.Factor <= 0 || *scale.Factor > maxScaleFactor) {
+		return ErrInvalidRecipeScale
+	}
+	if scale.System != "" && !validUnitSystem(scale.System) {
+		return ErrInvalidRecipeScale
+	}
 	return nil
 }
 
@@ -32,5 +196,94 @@ func (s *RecipeService) validateUnit(unit Unit) error {
 	if unit.Name == "" {
 		return ErrInvalidUnit
 	}
+	if unit.System != "" && !validUnitSystem(unit.System) {
+		return ErrInvalidUnit
+	}
+	switch unit.Dimension {
+	case "":
+		if unit.Factor != 0 || unit.System != "" {
+			return ErrInvalidUnit
+		}
+	case UnitDimensionMass, UnitDimensionVolume, UnitDimensionCount, UnitDimensionLength:
+		if unit.Factor <= 0 {
+			return ErrInvalidUnit
+		}
+	default:
+		return ErrInvalidUnit
+	}
+	return nil
+}
+
+func validUnitSystem(system UnitSystem) bool {
+	return system == UnitSystemMetric || system == UnitSystemImperial
+}
+
+func (s *RecipeService) normalizeSearchQuery(query RecipeSearchQuery) (RecipeSearchQuery, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" || query.Offset < 0 || query.Limit < 0 {
+		return RecipeSearchQuery{}, ErrInvalidSearchQuery
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultSearchLimit
+	}
+	query.Limit = min(query.Limit, maxSearchLimit)
+	return query, nil
+}
+
+func (s *RecipeService) normalizeRecipeListQuery(query RecipeListQuery) (RecipeListQuery, *Cursor, error) {
+	limit, err := normalizePageSize(query.Limit)
+	if err != nil {
+		return RecipeListQuery{}, nil, err
+	}
+	query.Limit = limit
+
+	switch query.SortBy {
+	case "":
+		query.SortBy = RecipeSortByName
+	case RecipeSortByName, RecipeSortByCreatedAt, RecipeSortByMinutes:
+	default:
+		return RecipeListQuery{}, nil, ErrInvalidListQuery
+	}
+
+	switch query.Order {
+	case "":
+		query.Order = SortAscending
+	case SortAscending, SortDescending:
+	default:
+		return RecipeListQuery{}, nil, ErrInvalidListQuery
+	}
+
+	filter := query.Filter
+	if (filter.MaxMinutes != nil && *filter.MaxMinutes < 0) ||
+		(filter.MinServings != nil && filter.MaxServings != nil && *filter.MinServings > *filter.MaxServings) {
+		return RecipeListQuery{}, nil, ErrInvalidListQuery
+	}
+
+	after, err := DecodeCursor(query.Cursor)
+	if err != nil {
+		return RecipeListQuery{}, nil, err
+	}
+	if after != nil {
+		if err = validateRecipeCursor(after, query.SortBy); err != nil {
+			return RecipeListQuery{}, nil, err
+		}
+	}
+	return query, after, nil
+}
+
+func validateRecipeCursor(cursor *Cursor, sortBy RecipeSortKey) error {
+	if cursor.Key != string(sortBy) {
+		return ErrInvalidCursor
+	}
+	switch sortBy {
+	case RecipeSortByMinutes:
+		if _, err := strconv.ParseInt(cursor.Value, 10, 64); err != nil {
+			return ErrInvalidCursor
+		}
+	case RecipeSortByCreatedAt:
+		if _, err := time.Parse(time.DateTime, cursor.Value); err != nil {
+			return ErrInvalidCursor
+		}
+	}
 	return nil
 }
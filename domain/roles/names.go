@@ -0,0 +1,16 @@
+package roles
+
+import "strings"
+
+// names are the names of the roles in the roles table.
+var names = map[string]ID{
+	"administrator": Administrator,
+	"moderator":     Moderator,
+	"user":          User,
+}
+
+// Parse returns the role with the name, ignoring case.
+func Parse(name string) (ID, bool) {
+	id, ok := names[strings.ToLower(strings.TrimSpace(name))]
+	return id, ok
+}
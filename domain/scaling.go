@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+// RecipeScale asks for a recipe either for a number of servings or by a plain
+// multiplier. Servings wins when both are set. System additionally converts
+// the scaled amounts into metric or imperial units.
+type RecipeScale struct {
+	Servings *int64
+	Factor   *float64
+	System   UnitSystem
+}
+
+func (s RecipeScale) IsZero() bool {
+	return s.Servings == nil && s.Factor == nil && s.System == ""
+}
+
+// unitStep is one rung of a unit ladder, e.g. tsp → tbsp → cup. Size is the
+// amount of the ladder's smallest unit, Min the smallest amount worth showing
+// in this unit before falling back to the previous rung.
+type unitStep struct {
+	key  string
+	size float64
+	min  float64
+}
+
+// unitLadder lists the units cooks step through as amounts grow. The first
+// ladder of a system and dimension is the one amounts are converted into.
+type unitLadder struct {
+	system    UnitSystem
+	dimension UnitDimension
+	steps     []unitStep
+}
+
+var unitLadders = []unitLadder{
+	{system: UnitSystemMetric, dimension: UnitDimensionMass, steps: []unitStep{{key: "mg", size: 1}, {key: "g", size: 1000, min: 0.1}, {key: "kg", size: 1000000, min: 1}}},
+	{system: UnitSystemMetric, dimension: UnitDimensionVolume, steps: []unitStep{{key: "ml", size: 1}, {key: "l", size: 1000, min: 1}}},
+	{system: UnitSystemImperial, dimension: UnitDimensionVolume, steps: []unitStep{{key: "tsp", size: 1}, {key: "tbsp", size: 3, min: 1}, {key: "cup", size: 48, min: 0.25}}},
+	{system: UnitSystemImperial, dimension: UnitDimensionMass, steps: []unitStep{{key: "oz", size: 1}, {key: "lb", size: 16, min: 1}}},
+	{system: UnitSystemImperial, dimension: UnitDimensionVolume, steps: []unitStep{{key: "fl oz", size: 1}, {key: "pt", size: 16, min: 1}, {key: "qt", size: 32, min: 1}, {key: "gal", size: 128, min: 1}}},
+}
+
+// countableUnits can't be split in a kitchen, so scaled amounts are rounded
+// to whole numbers and never drop below one.
+var countableUnits = map[string]bool{
+	"pinch":   true,
+	"dash":    true,
+	"piece":   true,
+	"clove":   true,
+	"can":     true,
+	"bunch":   true,
+	"slice":   true,
+	"handful": true,
+	"package": true,
+}
+
+// fractionDenominators are the fractions cooks actually measure with for
+// spoon and cup based units.
+var fractionDenominators = map[string][]float64{
+	"tsp":   {2, 4, 8},
+	"tbsp":  {2, 3, 4},
+	"cup":   {2, 3, 4},
+	"fl oz": {2},
+	"oz":    {2, 4},
+	"lb":    {4},
+	"pt":    {2},
+	"qt":    {4},
+	"gal":   {4},
+}
+
+// ScaleRecipe multiplies every step ingredient by factor. Amounts are rounded
+// to a precision that suits their unit and moved up or down a unit ladder
+// when one of the given units reads better, e.g. 48 tsp becomes 1 cup.
+func ScaleRecipe(recipe Recipe, factor float64, units []Unit) Recipe {
+	scaled := recipe
+	scaled.Servings = max(1, int64(math.Round(float64(recipe.Servings)*factor)))
+	scaled.Steps = make([]RecipeStep, len(recipe.Steps))
+	for i, step := range recipe.Steps {
+		scaled.Steps[i] = step
+		scaled.Steps[i].Ingredients = make([]StepIngredient, len(step.Ingredients))
+		for j, ingredient := range step.Ingredients {
+			scaled.Steps[i].Ingredients[j] = ScaleIngredient(ingredient, factor, units)
+		}
+	}
+	return scaled
+}
+
+func ScaleIngredient(ingredient StepIngredient, factor float64, units []Unit) StepIngredient {
+	ingredient.Amount, ingredient.Unit = promoteUnit(ingredient.Amount*factor, ingredient.Unit, units)
+	ingredient.Amount = roundAmount(ingredient.Amount, canonicalUnitKey(ingredient.Unit))
+	return ingredient
+}
+
+// scaleRecipe resolves the requested scale against the recipe's own servings.
+func scaleRecipe(recipe Recipe, scale RecipeScale, units []Unit) Recipe {
+	if scale.Servings != nil {
+		factor := float64(*scale.Servings) / float64(max(1, recipe.Servings))
+		recipe = ScaleRecipe(recipe, factor, units)
+		recipe.Servings = *scale.Servings
+	} else if scale.Factor != nil {
+		recipe = ScaleRecipe(recipe, *scale.Factor, units)
+	}
+	if scale.System != "" {
+		recipe = ConvertRecipe(recipe, scale.System, units)
+	}
+	return recipe
+}
+
+func promoteUnit(amount float64, unit Unit, units []Unit) (float64, Unit) {
+	key := canonicalUnitKey(unit)
+	ladder, from := findUnitStep(key)
+	if ladder == nil {
+		return amount, unit
+	}
+
+	base := amount * ladder[from].size
+	for i := len(ladder) - 1; i >= 0; i-- {
+		step := ladder[i]
+		if base/step.size < step.min {
+			continue
+		}
+		if i == from {
+			return amount, unit
+		}
+		if target := findUnitByKey(units, step.key); target != nil {
+			return base / step.size, *target
+		}
+	}
+	return amount, unit
+}
+
+func findUnitStep(key string) ([]unitStep, int) {
+	for _, ladder := range unitLadders {
+		for i, step := range ladder.steps {
+			if step.key == key {
+				return ladder.steps, i
+			}
+		}
+	}
+	return nil, 0
+}
+
+func findUnitByKey(units []Unit, key string) *Unit {
+	for i := range units {
+		if canonicalUnitKey(units[i]) == key {
+			return &units[i]
+		}
+	}
+	return nil
+}
+
+// canonicalUnitKey maps a stored unit onto the alias table used by the
+// ingredient parser, or returns an empty string for units it doesn't know.
+func canonicalUnitKey(unit Unit) string {
+	if unit.Symbol != nil {
+		if key, ok := caseSensitiveUnits[*unit.Symbol]; ok {
+			return key
+		}
+		if key, ok := unitAliasLookup[strings.ToLower(*unit.Symbol)]; ok {
+			return key
+		}
+	}
+	if key, ok := unitAliasLookup[strings.ToLower(unit.Name)]; ok {
+		return key
+	}
+	return ""
+}
+
+func roundAmount(amount float64, key string) float64 {
+	if amount <= 0 {
+		return amount
+	}
+	if countableUnits[key] {
+		return max(1, math.Round(amount))
+	}
+	if denominators, ok := fractionDenominators[key]; ok {
+		return roundToFraction(amount, denominators)
+	}
+
+	var step float64
+	switch key {
+	case "g", "ml":
+		switch {
+		case amount < 10:
+			step = 0.5
+		case amount < 100:
+			step = 1
+		case amount < 1000:
+			step = 5
+		default:
+			step = 10
+		}
+	case "mg":
+		step = 1
+	case "kg", "l":
+		step = 0.05
+	case "cl", "dl":
+		step = 0.5
+	default:
+		step = 0.01
+	}
+	return trimPrecision(max(step, math.Round(amount/step)*step))
+}
+
+// trimPrecision drops floating point noise such as 1.1500000000000001.
+func trimPrecision(amount float64) float64 {
+	return math.Round(amount*1000) / 1000
+}
+
+func roundToFraction(amount float64, denominators []float64) float64 {
+	best := amount
+	bestDiff := math.Inf(1)
+	for _, d := range denominators {
+		candidate := max(1/d, math.Round(amount*d)/d)
+		if diff := math.Abs(candidate - amount); diff < bestDiff {
+			best, bestDiff = candidate, diff
+		}
+	}
+	return trimPrecision(best)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestScaleIngredient(t *testing.T) {
+	units := []Unit{
+		{ID: 1, Name: "Gram", Symbol: ptr("g")},
+		{ID: 2, Name: "Kilogram", Symbol: ptr("kg")},
+		{ID: 3, Name: "Teaspoon", Symbol: ptr("tsp")},
+		{ID: 4, Name: "Tablespoon", Symbol: ptr("tbsp")},
+		{ID: 5, Name: "Cup"},
+		{ID: 6, Name: "Pinch"},
+		{ID: 7, Name: "Milliliter", Symbol: ptr("ml")},
+		{ID: 8, Name: "Becher"},
+	}
+
+	tests := []struct {
+		name       string
+		unit       Unit
+		amount     float64
+		factor     float64
+		wantAmount float64
+		wantUnitID int64
+	}{
+		{name: "Pinches stay whole", unit: units[5], amount: 1, factor: 1.5, wantAmount: 2, wantUnitID: 6},
+		{name: "Pinches never drop to zero", unit: units[5], amount: 1, factor: 0.25, wantAmount: 1, wantUnitID: 6},
+		{name: "Small gram amounts keep half grams", unit: units[0], amount: 3, factor: 1.5, wantAmount: 4.5, wantUnitID: 1},
+		{name: "Grams round to whole numbers", unit: units[0], amount: 33, factor: 1.5, wantAmount: 50, wantUnitID: 1},
+		{name: "Grams round to fives", unit: units[0], amount: 125, factor: 1.5, wantAmount: 190, wantUnitID: 1},
+		{name: "Grams promote to kilograms", unit: units[0], amount: 500, factor: 3, wantAmount: 1.5, wantUnitID: 2},
+		{name: "Kilograms demote to grams", unit: units[1], amount: 1, factor: 0.25, wantAmount: 250, wantUnitID: 1},
+		{name: "Teaspoons promote to tablespoons", unit: units[2], amount: 2, factor: 3, wantAmount: 2, wantUnitID: 4},
+		{name: "Tablespoons promote to cups", unit: units[3], amount: 4, factor: 2, wantAmount: 0.5, wantUnitID: 5},
+		{name: "Tablespoons demote to teaspoons", unit: units[3], amount: 1, factor: 0.5, wantAmount: 1.5, wantUnitID: 3},
+		{name: "Cups round to thirds", unit: units[4], amount: 1, factor: 1.0 / 3, wantAmount: 0.333, wantUnitID: 5},
+		{name: "Missing target unit keeps original", unit: units[6], amount: 500, factor: 4, wantAmount: 2000, wantUnitID: 7},
+		{name: "Unknown units round to two decimals", unit: units[7], amount: 1, factor: 1.0 / 3, wantAmount: 0.33, wantUnitID: 8},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ScaleIngredient(StepIngredient{Unit: tc.unit, Amount: tc.amount}, tc.factor, units)
+			if got.Amount != tc.wantAmount || got.Unit.ID != tc.wantUnitID {
+				t.Errorf("ScaleIngredient() = %v %s, want %v (unit %d)", got.Amount, got.Unit.Name, tc.wantAmount, tc.wantUnitID)
+			}
+		})
+	}
+}
+
+func TestScaleRecipeServings(t *testing.T) {
+	recipe := Recipe{
+		RecipeDetails: RecipeDetails{Servings: 4},
+		Steps: []RecipeStep{{
+			Ingredients: []StepIngredient{{Unit: Unit{ID: 1, Name: "Gram", Symbol: ptr("g")}, Amount: 200}},
+		}},
+	}
+
+	servings := int64(6)
+	got := scaleRecipe(recipe, RecipeScale{Servings: &servings}, nil)
+	if got.Servings != 6 {
+		t.Errorf("scaleRecipe() servings = %d, want 6", got.Servings)
+	}
+	if amount := got.Steps[0].Ingredients[0].Amount; amount != 300 {
+		t.Errorf("scaleRecipe() amount = %v, want 300", amount)
+	}
+	if amount := recipe.Steps[0].Ingredients[0].Amount; amount != 200 {
+		t.Errorf("scaleRecipe() modified the original recipe, amount = %v", amount)
+	}
+}
@@ -2,6 +2,7 @@ package security
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 )
 
@@ -14,3 +15,10 @@ func GenerateToken(length int) string {
 	}
 	return hex.EncodeToString(b)
 }
+
+// HashToken is for looking up tokens without storing them. They are random,
+// so unlike passwords they don't need a slow hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,78 @@
+package security
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The SHA-1 test vectors of RFC 6238 appendix B, cut to six digits
+func TestTOTPCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			got, err := TOTPCode(secret, TOTPStep(time.Unix(tc.unix, 0)))
+			if err != nil {
+				t.Fatalf("TOTPCode() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("TOTPCode() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1760000000, 0)
+	code := func(offset time.Duration) string {
+		code, err := TOTPCode(secret, TOTPStep(now.Add(offset)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name   string
+		code   string
+		wantOk bool
+	}{
+		{name: "Current", code: code(0), wantOk: true},
+		{name: "Previous period", code: code(-TOTPPeriod), wantOk: true},
+		{name: "Next period", code: code(TOTPPeriod), wantOk: true},
+		{name: "Too old", code: code(-3 * TOTPPeriod)},
+		{name: "Wrong length", code: code(0)[1:]},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := ValidateTOTP(secret, tc.code, now); ok != tc.wantOk {
+				t.Errorf("ValidateTOTP(%s) = %v, want %v", tc.code, ok, tc.wantOk)
+			}
+		})
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	got := TOTPProvisioningURI("Recipe Manager", "user@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(got, "otpauth://totp/Recipe%20Manager:user@example.com?") || !strings.Contains(got, "secret=JBSWY3DPEHPK3PXP") {
+		t.Errorf("TOTPProvisioningURI() = %s", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Package webauthntest provides a software authenticator to run the WebAuthn
+// ceremonies against in tests, like a browser with a passkey provider would.
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+var ErrNoCredential = errors.New("authenticator has no credential for the relying party")
+
+// Authenticator creates ES256 discoverable credentials and answers challenges
+// with them. It always reports the user as present and verified and counts
+// signatures.
+type Authenticator struct {
+	// Origin is what the client reports as the origin of the page.
+	Origin      string
+	credentials []*credential
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{Origin: origin}
+}
+
+// Create makes a new credential, as navigator.credentials.create() would, and
+// returns the response the way the server parses it.
+func (a *Authenticator) Create(options protocol.PublicKeyCredentialCreationOptions) (*protocol.ParsedCredentialCreationData, error) {
+	userHandle, _ := options.User.ID.(protocol.URLEncodedBase64)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return nil, err
+	}
+	cred := &credential{id: id, rpID: options.RelyingParty.ID, userHandle: bytes.Clone(userHandle), key: key}
+	a.credentials = append(a.credentials, cred)
+
+	point, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: point[1:33],
+		YCoord: point[33:],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var attested bytes.Buffer
+	attested.Write(make([]byte, 16))
+	_ = binary.Write(&attested, binary.BigEndian, uint16(len(id)))
+	attested.Write(id)
+	attested.Write(publicKey)
+	flags := protocol.FlagUserPresent | protocol.FlagUserVerified | protocol.FlagAttestedCredentialData
+	attestationObject, err := webauthncbor.Marshal(struct {
+		Format    string         `cbor:"fmt"`
+		Statement map[string]any `cbor:"attStmt"`
+		AuthData  []byte         `cbor:"authData"`
+	}{
+		Format:    "none",
+		Statement: map[string]any{},
+		AuthData:  a.authenticatorData(cred, flags, attested.Bytes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := protocol.CredentialCreationResponse{
+		PublicKeyCredential: publicKeyCredential(cred),
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{
+				ClientDataJSON: a.clientData(protocol.CreateCeremony, options.Challenge),
+			},
+			AttestationObject: attestationObject,
+		},
+	}
+	return response.Parse()
+}
+
+// Get signs the challenge with the most recent credential for the relying
+// party, as navigator.credentials.get() would without allowed credentials.
+func (a *Authenticator) Get(options protocol.PublicKeyCredentialRequestOptions) (*protocol.ParsedCredentialAssertionData, error) {
+	var cred *credential
+	for _, c := range a.credentials {
+		if c.rpID == options.RelyingPartyID {
+			cred = c
+		}
+	}
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+
+	cred.signCount++
+	clientData := a.clientData(protocol.AssertCeremony, options.Challenge)
+	authData := a.authenticatorData(cred, protocol.FlagUserPresent|protocol.FlagUserVerified, nil)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	response := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: publicKeyCredential(cred),
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{
+				ClientDataJSON: clientData,
+			},
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        bytes.Clone(cred.userHandle),
+		},
+	}
+	return response.Parse()
+}
+
+func (a *Authenticator) clientData(ceremony protocol.CeremonyType, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	return data
+}
+
+func (a *Authenticator) authenticatorData(cred *credential, flags protocol.AuthenticatorFlags, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(cred.rpID))
+	data := append(rpIDHash[:], byte(flags))
+	data = binary.BigEndian.AppendUint32(data, cred.signCount)
+	return append(data, attested...)
+}
+
+func publicKeyCredential(cred *credential) protocol.PublicKeyCredential {
+	return protocol.PublicKeyCredential{
+		Credential: protocol.Credential{
+			ID:   base64.RawURLEncoding.EncodeToString(cred.id),
+			Type: string(protocol.PublicKeyCredentialType),
+		},
+		RawID: bytes.Clone(cred.id),
+	}
+}
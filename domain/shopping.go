@@ -1,17 +1,420 @@
 package domain
 
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultShoppingListName = "Shopping List"
+
 type ShoppingList struct {
-	ID     int64
-	UserID int64
-	Name   string
-	Items  []ShoppingListItem
+	ID          int64
+	HouseholdID int64
+	UserID      int64
+	Name        string
+	Items       []ShoppingListItem
+	// Revision is raised with every change to the items of the list
+	Revision int64
+	// StoreID and Sections are only set once the list is arranged for a store
+	StoreID  *int64
+	Sections []ShoppingListSection
+}
+
+// ShoppingListSection holds the items found in one section of a store. Items
+// in no known section are grouped without a Section.
+type ShoppingListSection struct {
+	Section *StoreSection
+	Items   []ShoppingListItem
 }
 
+// ShoppingListItem is either free text or refers to an ingredient with an
+// optional amount and unit. The text fields of the latter always describe
+// the structured values, so both kinds of items read the same.
 type ShoppingListItem struct {
-	ID         int64
-	Ingredient string
-	Quantity   *string
-	Unit       *string
-	Done       bool
-	SortOrder  int64
+	ID           int64
+	Ingredient   string
+	Quantity     *string
+	Unit         *string
+	IngredientID *int64
+	UnitID       *int64
+	Amount       *float64
+	Recipes      []Recipe
+	SectionID    *int64
+	Done         bool
+	SortOrder    int64
+	// Version is raised whenever the item is changed, UpdatedAt is the time
+	// of the latest change. Both are used to resolve conflicting changes.
+	Version   int64
+	UpdatedAt time.Time
+	// ClientID is the ID a client gave the item when it was created offline
+	ClientID *string
+}
+
+// Store is the layout of a shop, its sections are listed in the order they
+// are walked. Each ingredient is found in at most one section of a store.
+type Store struct {
+	ID          int64
+	HouseholdID int64
+	UserID      int64
+	Name        string
+	Sections    []StoreSection
+}
+
+type StoreSection struct {
+	ID            int64
+	Name          string
+	SortOrder     int64
+	IngredientIDs []int64
+}
+
+// MealPlanShopping selects the planned days to shop for. The items are added
+// to the list with ListID, or to a new list called Name if it is nil.
+type MealPlanShopping struct {
+	From   time.Time
+	Until  time.Time
+	ListID *int64
+	Name   string
+}
+
+// CollectMealPlanItems turns the ingredients of all planned recipes into
+// shopping list items. Amounts of the same ingredient are summed up whenever
+// their units convert into each other.
+func CollectMealPlanItems(mealPlan []MealPlan, units []Unit) []ShoppingListItem {
+	var items []ShoppingListItem
+	for _, day := range mealPlan {
+		for _, planned := range day.Meals {
+			if planned.Recipe == nil {
+				continue
+			}
+			source := Recipe{ID: planned.Recipe.ID, RecipeDetails: RecipeDetails{Name: planned.Recipe.Name}}
+			for _, step := range planned.Recipe.Steps {
+				for _, ingredient := range step.Ingredients {
+					items = addShoppingListItem(items, newShoppingListItem(ingredient, source), units, ingredient.Ingredient.Density)
+				}
+			}
+		}
+	}
+	for i := range items {
+		items[i].normalize(units)
+	}
+	return items
+}
+
+// ArrangeForStore sorts the items by the sections of the store they are found
+// in, keeping the list order within a section. The section of an item is
+// inferred from its ingredient, items without a known section come last.
+func (l *ShoppingList) ArrangeForStore(store Store) {
+	sections := append([]StoreSection{}, store.Sections...)
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].SortOrder < sections[j].SortOrder
+	})
+	sectionByIngredient := make(map[int64]int)
+	for i, section := range sections {
+		for _, id := range section.IngredientIDs {
+			sectionByIngredient[id] = i
+		}
+	}
+
+	items := append([]ShoppingListItem{}, l.Items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SortOrder < items[j].SortOrder
+	})
+	grouped := make([][]ShoppingListItem, len(sections)+1)
+	for _, item := range items {
+		index := len(sections)
+		if item.IngredientID != nil {
+			if i, ok := sectionByIngredient[*item.IngredientID]; ok {
+				index = i
+				item.SectionID = &sections[i].ID
+			}
+		}
+		grouped[index] = append(grouped[index], item)
+	}
+
+	l.StoreID = &store.ID
+	l.Items = make([]ShoppingListItem, 0, len(items))
+	l.Sections = []ShoppingListSection{}
+	for i, group := range grouped {
+		if len(group) == 0 {
+			continue
+		}
+		var section *StoreSection
+		if i < len(sections) {
+			section = &sections[i]
+		}
+		l.Items = append(l.Items, group...)
+		l.Sections = append(l.Sections, ShoppingListSection{Section: section, Items: group})
+	}
+}
+
+func addShoppingListItem(items []ShoppingListItem, item ShoppingListItem, units []Unit, density *float64) []ShoppingListItem {
+	for i := range items {
+		if items[i].combine(item, units, density) {
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func newShoppingListItem(ingredient StepIngredient, source Recipe) ShoppingListItem {
+	amount := ingredient.Amount
+	return ShoppingListItem{
+		Ingredient:   ingredient.Ingredient.Name,
+		IngredientID: &ingredient.Ingredient.ID,
+		UnitID:       &ingredient.Unit.ID,
+		Amount:       &amount,
+		Recipes:      []Recipe{source},
+	}
+}
+
+// mergeShoppingListItems returns the items to save for the additions. An
+// addition that is already open on the list is added to that item when the
+// amounts can be combined, otherwise it becomes an item of its own so that
+// no amount gets lost. Ingredients the user wrote down as free text are left
+// as they are, just like items that are already done.
+func mergeShoppingListItems(items, additions []ShoppingListItem, units []Unit, ingredients []Ingredient, nextSortOrder int64) []ShoppingListItem {
+	merged := append([]ShoppingListItem{}, items...)
+	changed := make(map[int]bool)
+	for _, addition := range additions {
+		combined, freeText := -1, false
+		for i := range merged {
+			if merged[i].Done || !merged[i].sameIngredient(addition) {
+				continue
+			}
+			if merged[i].combine(addition, units, ingredientDensity(ingredients, addition.IngredientID)) {
+				combined = i
+				break
+			}
+			freeText = freeText || merged[i].IngredientID == nil
+		}
+
+		switch {
+		case combined >= 0:
+			merged[combined].normalize(units)
+			changed[combined] = true
+		case !freeText:
+			addition.SortOrder = nextSortOrder
+			nextSortOrder++
+			merged = append(merged, addition)
+			changed[len(merged)-1] = true
+		}
+	}
+
+	result := make([]ShoppingListItem, 0, len(changed))
+	for i, item := range merged {
+		if changed[i] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// sortedShoppingListItems returns a copy of the items from top to bottom.
+func sortedShoppingListItems(items []ShoppingListItem) []ShoppingListItem {
+	sorted := append([]ShoppingListItem{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SortOrder < sorted[j].SortOrder
+	})
+	return sorted
+}
+
+// moveShoppingListItem takes the item out of the sorted items and puts it
+// back at the given position. Positions past the end put it at the bottom.
+func moveShoppingListItem(items []ShoppingListItem, itemID int64, position int64) ([]ShoppingListItem, error) {
+	from := slices.IndexFunc(items, func(item ShoppingListItem) bool {
+		return item.ID == itemID
+	})
+	if from < 0 {
+		return nil, ErrShoppingListItemNotFound
+	}
+	item := items[from]
+	result := slices.Delete(slices.Clone(items), from, from+1)
+	return slices.Insert(result, int(min(position, int64(len(result)))), item), nil
+}
+
+// orderShoppingListItems puts the items with the given IDs on top in that
+// order, the others follow in their current order. Unknown IDs are skipped,
+// since the items may have been deleted in the meantime.
+func orderShoppingListItems(items []ShoppingListItem, itemIDs []int64) []ShoppingListItem {
+	order := make([]ShoppingListItemRef, len(itemIDs))
+	for i := range itemIDs {
+		order[i] = ShoppingListItemRef{ID: &itemIDs[i]}
+	}
+	return reorderShoppingListItems(items, order, func(ref ShoppingListItemRef) int {
+		return slices.IndexFunc(items, func(item ShoppingListItem) bool {
+			return item.ID == *ref.ID
+		})
+	})
+}
+
+// renumberShoppingListItems numbers the items from the top starting at 0
+// and returns those whose position changed.
+func renumberShoppingListItems(items []ShoppingListItem) []ShoppingListItem {
+	var moved []ShoppingListItem
+	for i := range items {
+		if items[i].SortOrder != int64(i) {
+			items[i].SortOrder = int64(i)
+			moved = append(moved, items[i])
+		}
+	}
+	return moved
+}
+
+// changedShoppingListItems returns the items of after that are not in before
+// or differ from the item with the same ID, ignoring their position.
+func changedShoppingListItems(before, after []ShoppingListItem) []ShoppingListItem {
+	previous := make(map[int64]ShoppingListItem, len(before))
+	for _, item := range before {
+		previous[item.ID] = item
+	}
+	var changed []ShoppingListItem
+	for _, item := range after {
+		if old, ok := previous[item.ID]; !ok || !old.equal(item) {
+			changed = append(changed, item)
+		}
+	}
+	return changed
+}
+
+// movedShoppingListItems reports whether an item that is on both lists
+// changed its position.
+func movedShoppingListItems(before, after []ShoppingListItem) bool {
+	previous := make(map[int64]int64, len(before))
+	for _, item := range before {
+		previous[item.ID] = item.SortOrder
+	}
+	for _, item := range after {
+		if sortOrder, ok := previous[item.ID]; ok && sortOrder != item.SortOrder {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *ShoppingListItem) equal(other ShoppingListItem) bool {
+	if i.Ingredient != other.Ingredient || i.Done != other.Done ||
+		!equalValues(i.Quantity, other.Quantity) || !equalValues(i.Unit, other.Unit) ||
+		!equalIDs(i.IngredientID, other.IngredientID) || !equalIDs(i.UnitID, other.UnitID) ||
+		!equalValues(i.Amount, other.Amount) || len(i.Recipes) != len(other.Recipes) {
+		return false
+	}
+	for j := range i.Recipes {
+		if i.Recipes[j].ID != other.Recipes[j].ID {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *ShoppingListItem) sameIngredient(other ShoppingListItem) bool {
+	if i.IngredientID != nil && other.IngredientID != nil {
+		return *i.IngredientID == *other.IngredientID
+	}
+	return strings.EqualFold(strings.TrimSpace(i.Ingredient), strings.TrimSpace(other.Ingredient))
+}
+
+// combine adds the amount of other to the item if both refer to the same
+// ingredient in units that convert into each other.
+func (i *ShoppingListItem) combine(other ShoppingListItem, units []Unit, density *float64) bool {
+	if i.IngredientID == nil || other.IngredientID == nil || *i.IngredientID != *other.IngredientID {
+		return false
+	}
+	if i.Amount == nil || other.Amount == nil {
+		if i.Amount != nil || other.Amount != nil || !equalIDs(i.UnitID, other.UnitID) {
+			return false
+		}
+		i.Recipes = mergeRecipeReferences(i.Recipes, other.Recipes)
+		return true
+	}
+
+	amount := *other.Amount
+	if !equalIDs(i.UnitID, other.UnitID) {
+		if i.UnitID == nil || other.UnitID == nil {
+			return false
+		}
+		from, to := findUnitByID(units, *other.UnitID), findUnitByID(units, *i.UnitID)
+		if from == nil || to == nil {
+			return false
+		}
+		converted, err := ConvertAmount(amount, *from, *to, density)
+		if err != nil {
+			return false
+		}
+		amount = converted
+	}
+
+	sum := *i.Amount + amount
+	i.Amount = &sum
+	i.Recipes = mergeRecipeReferences(i.Recipes, other.Recipes)
+	return true
+}
+
+// normalize moves the amount into a fitting unit, rounds it and writes the
+// structured values into the text fields.
+func (i *ShoppingListItem) normalize(units []Unit) {
+	var unit *Unit
+	if i.UnitID != nil {
+		unit = findUnitByID(units, *i.UnitID)
+	}
+
+	if i.Amount != nil {
+		amount := trimPrecision(*i.Amount)
+		if unit != nil {
+			var promoted Unit
+			amount, promoted = promoteUnit(amount, *unit, units)
+			amount = roundAmount(amount, canonicalUnitKey(promoted))
+			unit = &promoted
+			i.UnitID = &promoted.ID
+		}
+		i.Amount = &amount
+		quantity := strconv.FormatFloat(amount, 'f', -1, 64)
+		i.Quantity = &quantity
+	}
+	if unit != nil {
+		text := unit.Name
+		if unit.Symbol != nil && *unit.Symbol != "" {
+			text = *unit.Symbol
+		}
+		i.Unit = &text
+	}
+}
+
+func mergeRecipeReferences(recipes, others []Recipe) []Recipe {
+	for _, other := range others {
+		found := false
+		for _, recipe := range recipes {
+			if recipe.ID == other.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			recipes = append(recipes, other)
+		}
+	}
+	return recipes
+}
+
+func ingredientDensity(ingredients []Ingredient, id *int64) *float64 {
+	if id == nil {
+		return nil
+	}
+	if ingredient := findIngredientByID(ingredients, *id); ingredient != nil {
+		return ingredient.Density
+	}
+	return nil
+}
+
+func equalIDs(a, b *int64) bool {
+	return equalValues(a, b)
+}
+
+func equalValues[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
@@ -0,0 +1,407 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCollectMealPlanItems(t *testing.T) {
+	milkDensity := 1.03
+	flour := Ingredient{ID: 1, Name: "Flour"}
+	milk := Ingredient{ID: 2, Name: "Milk", Density: &milkDensity}
+	egg := Ingredient{ID: 3, Name: "Egg"}
+	piece := Unit{ID: 20, Name: "Piece", Dimension: UnitDimensionCount, Factor: 1}
+	units := append(conversionUnits, piece)
+
+	pancakes := Recipe{ID: 1, RecipeDetails: RecipeDetails{Name: "Pancakes"}, Steps: []RecipeStep{
+		{Ingredients: []StepIngredient{
+			{Ingredient: flour, Unit: conversionUnits[0], Amount: 250},
+			{Ingredient: milk, Unit: conversionUnits[4], Amount: 500},
+		}},
+		{Ingredients: []StepIngredient{{Ingredient: egg, Unit: piece, Amount: 2}}},
+	}}
+	bread := Recipe{ID: 2, RecipeDetails: RecipeDetails{Name: "Bread"}, Steps: []RecipeStep{{Ingredients: []StepIngredient{
+		{Ingredient: flour, Unit: conversionUnits[1], Amount: 1},
+		{Ingredient: milk, Unit: conversionUnits[0], Amount: 100},
+		{Ingredient: egg, Unit: conversionUnits[0], Amount: 50},
+	}}}}
+	mealPlan := []MealPlan{
+		{Meals: []PlannedMeal{{Recipe: &pancakes}, {Note: ptr("Leftovers")}, {Recipe: &bread}}},
+		{Meals: []PlannedMeal{{Recipe: &pancakes}}},
+	}
+
+	got := CollectMealPlanItems(mealPlan, units)
+
+	want := []struct {
+		ingredientID int64
+		amount       float64
+		unitID       int64
+		quantity     string
+		unit         string
+		recipeIDs    []int64
+	}{
+		{ingredientID: 1, amount: 1.5, unitID: 2, quantity: "1.5", unit: "kg", recipeIDs: []int64{1, 2}},
+		{ingredientID: 2, amount: 1.1, unitID: 6, quantity: "1.1", unit: "l", recipeIDs: []int64{1, 2}},
+		{ingredientID: 3, amount: 4, unitID: 20, quantity: "4", unit: "Piece", recipeIDs: []int64{1}},
+		{ingredientID: 3, amount: 50, unitID: 1, quantity: "50", unit: "g", recipeIDs: []int64{2}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CollectMealPlanItems() = %v, want %d items", got, len(want))
+	}
+	for i, w := range want {
+		item := got[i]
+		if *item.IngredientID != w.ingredientID || *item.Amount != w.amount || *item.UnitID != w.unitID {
+			t.Errorf("CollectMealPlanItems()[%d] = %v (unit %d) of %d, want %v (unit %d) of %d", i, *item.Amount, *item.UnitID, *item.IngredientID, w.amount, w.unitID, w.ingredientID)
+		}
+		if *item.Quantity != w.quantity || *item.Unit != w.unit {
+			t.Errorf("CollectMealPlanItems()[%d] text = %s %s, want %s %s", i, *item.Quantity, *item.Unit, w.quantity, w.unit)
+		}
+		if len(item.Recipes) != len(w.recipeIDs) {
+			t.Fatalf("CollectMealPlanItems()[%d] recipes = %v, want %v", i, item.Recipes, w.recipeIDs)
+		}
+		for j, id := range w.recipeIDs {
+			if item.Recipes[j].ID != id {
+				t.Errorf("CollectMealPlanItems()[%d] recipe %d = %d, want %d", i, j, item.Recipes[j].ID, id)
+			}
+		}
+	}
+}
+
+func TestMergeShoppingListItems(t *testing.T) {
+	flour := Ingredient{ID: 1, Name: "Flour"}
+	milk := Ingredient{ID: 2, Name: "Milk"}
+	ingredients := []Ingredient{flour, milk}
+	additions := CollectMealPlanItems([]MealPlan{{Meals: []PlannedMeal{{Recipe: &Recipe{ID: 1, Steps: []RecipeStep{{Ingredients: []StepIngredient{
+		{Ingredient: flour, Unit: conversionUnits[0], Amount: 500},
+		{Ingredient: milk, Unit: conversionUnits[4], Amount: 250},
+	}}}}}}}}, conversionUnits)
+
+	tests := []struct {
+		name      string
+		items     []ShoppingListItem
+		wantItems []ShoppingListItem
+	}{
+		{
+			name: "Empty list",
+			wantItems: []ShoppingListItem{
+				{Ingredient: "Flour", Amount: ptr(500.0), UnitID: ptr(int64(1)), Quantity: ptr("500"), SortOrder: 0},
+				{Ingredient: "Milk", Amount: ptr(250.0), UnitID: ptr(int64(5)), Quantity: ptr("250"), SortOrder: 1},
+			},
+		},
+		{
+			name:  "Same unit is summed",
+			items: []ShoppingListItem{{ID: 1, Ingredient: "Flour", IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(200.0)}},
+			wantItems: []ShoppingListItem{
+				{ID: 1, Ingredient: "Flour", Amount: ptr(700.0), UnitID: ptr(int64(1)), Quantity: ptr("700")},
+				{Ingredient: "Milk", Amount: ptr(250.0), UnitID: ptr(int64(5)), Quantity: ptr("250"), SortOrder: 1},
+			},
+		},
+		{
+			name:  "Converted into the unit on the list",
+			items: []ShoppingListItem{{ID: 1, Ingredient: "Milk", IngredientID: ptr(int64(2)), UnitID: ptr(int64(6)), Amount: ptr(1.0), SortOrder: 3}},
+			wantItems: []ShoppingListItem{
+				{ID: 1, Ingredient: "Milk", Amount: ptr(1.25), UnitID: ptr(int64(6)), Quantity: ptr("1.25"), SortOrder: 3},
+				{Ingredient: "Flour", Amount: ptr(500.0), UnitID: ptr(int64(1)), Quantity: ptr("500"), SortOrder: 4},
+			},
+		},
+		{
+			name:  "Incompatible unit is added separately",
+			items: []ShoppingListItem{{ID: 1, Ingredient: "Flour", IngredientID: ptr(int64(1)), UnitID: ptr(int64(9)), Amount: ptr(2.0)}},
+			wantItems: []ShoppingListItem{
+				{Ingredient: "Flour", Amount: ptr(500.0), UnitID: ptr(int64(1)), Quantity: ptr("500"), SortOrder: 1},
+				{Ingredient: "Milk", Amount: ptr(250.0), UnitID: ptr(int64(5)), Quantity: ptr("250"), SortOrder: 2},
+			},
+		},
+		{
+			name:  "Free text item is kept",
+			items: []ShoppingListItem{{ID: 1, Ingredient: "flour"}},
+			wantItems: []ShoppingListItem{
+				{Ingredient: "Milk", Amount: ptr(250.0), UnitID: ptr(int64(5)), Quantity: ptr("250"), SortOrder: 1},
+			},
+		},
+		{
+			name:  "Done items are left alone",
+			items: []ShoppingListItem{{ID: 1, Ingredient: "Flour", IngredientID: ptr(int64(1)), UnitID: ptr(int64(2)), Amount: ptr(1.0), Done: true}},
+			wantItems: []ShoppingListItem{
+				{Ingredient: "Flour", Amount: ptr(500.0), UnitID: ptr(int64(1)), Quantity: ptr("500"), SortOrder: 1},
+				{Ingredient: "Milk", Amount: ptr(250.0), UnitID: ptr(int64(5)), Quantity: ptr("250"), SortOrder: 2},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &ShoppingService{}
+			got := mergeShoppingListItems(tc.items, additions, conversionUnits, ingredients, service.calculateNextSortOrder(tc.items))
+			if len(got) != len(tc.wantItems) {
+				t.Fatalf("mergeShoppingListItems() = %d items, want %d", len(got), len(tc.wantItems))
+			}
+			for i, want := range tc.wantItems {
+				item := got[i]
+				if item.ID != want.ID || item.Ingredient != want.Ingredient || *item.Amount != *want.Amount || *item.UnitID != *want.UnitID || *item.Quantity != *want.Quantity || item.SortOrder != want.SortOrder {
+					t.Errorf("mergeShoppingListItems()[%d] = %+v %v %s, want %+v %v %s", i, item, *item.Amount, *item.Quantity, want, *want.Amount, *want.Quantity)
+				}
+				if len(item.Recipes) != 1 {
+					t.Errorf("mergeShoppingListItems()[%d] recipes = %v, want the planned recipe", i, item.Recipes)
+				}
+			}
+		})
+	}
+}
+
+func TestShoppingListItemCombine(t *testing.T) {
+	flourDensity := 0.53
+
+	tests := []struct {
+		name       string
+		item       ShoppingListItem
+		other      ShoppingListItem
+		density    *float64
+		want       bool
+		wantAmount float64
+		wantUnitID int64
+	}{
+		{
+			name:  "Same unit",
+			item:  ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(200.0)},
+			other: ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(50.0)},
+			want:  true, wantAmount: 250, wantUnitID: 1,
+		},
+		{
+			name:  "Compatible unit",
+			item:  ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(2)), Amount: ptr(1.0)},
+			other: ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(500.0)},
+			want:  true, wantAmount: 1.5, wantUnitID: 2,
+		},
+		{
+			name:    "Volume to mass with density",
+			item:    ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(100.0)},
+			other:   ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(5)), Amount: ptr(100.0)},
+			density: &flourDensity,
+			want:    true, wantAmount: 155, wantUnitID: 1,
+		},
+		{
+			name:  "Incompatible unit",
+			item:  ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(100.0)},
+			other: ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(5)), Amount: ptr(100.0)},
+			want:  false, wantAmount: 100, wantUnitID: 1,
+		},
+		{
+			name:  "Different ingredient",
+			item:  ShoppingListItem{IngredientID: ptr(int64(1)), UnitID: ptr(int64(1)), Amount: ptr(100.0)},
+			other: ShoppingListItem{IngredientID: ptr(int64(2)), UnitID: ptr(int64(1)), Amount: ptr(100.0)},
+			want:  false, wantAmount: 100, wantUnitID: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			item := tc.item
+			if got := item.combine(tc.other, conversionUnits, tc.density); got != tc.want {
+				t.Fatalf("combine() = %v, want %v", got, tc.want)
+			}
+			item.normalize(conversionUnits)
+			if *item.Amount != tc.wantAmount || *item.UnitID != tc.wantUnitID {
+				t.Errorf("combine() = %v (unit %d), want %v (unit %d)", *item.Amount, *item.UnitID, tc.wantAmount, tc.wantUnitID)
+			}
+		})
+	}
+}
+
+func TestChangedShoppingListItems(t *testing.T) {
+	before := []ShoppingListItem{
+		{ID: 1, Ingredient: "Flour", IngredientID: ptr(int64(1)), Amount: ptr(200.0), Recipes: []Recipe{{ID: 1}}},
+		{ID: 2, Ingredient: "Milk", IngredientID: ptr(int64(2)), Amount: ptr(0.5)},
+		{ID: 3, Ingredient: "Salt"},
+	}
+
+	tests := []struct {
+		name  string
+		after []ShoppingListItem
+		want  []int64
+	}{
+		{name: "Unchanged", after: before},
+		{name: "New item", after: append(append([]ShoppingListItem{}, before...), ShoppingListItem{ID: 4, Ingredient: "Eggs"}), want: []int64{4}},
+		{name: "Changed amount", after: []ShoppingListItem{before[0], {ID: 2, Ingredient: "Milk", IngredientID: ptr(int64(2)), Amount: ptr(1.0)}, before[2]}, want: []int64{2}},
+		{name: "Added recipe", after: []ShoppingListItem{{ID: 1, Ingredient: "Flour", IngredientID: ptr(int64(1)), Amount: ptr(200.0), Recipes: []Recipe{{ID: 1}, {ID: 2}}}, before[1], before[2]}, want: []int64{1}},
+		{name: "Removed item", after: before[:2]},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := changedShoppingListItems(before, tc.after)
+			if len(got) != len(tc.want) {
+				t.Fatalf("changedShoppingListItems() = %v, want items %v", got, tc.want)
+			}
+			for i, item := range got {
+				if item.ID != tc.want[i] {
+					t.Errorf("changedShoppingListItems() item %d = %d, want %d", i, item.ID, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMoveShoppingListItem(t *testing.T) {
+	items := []ShoppingListItem{
+		{ID: 1, Ingredient: "Flour", SortOrder: 0},
+		{ID: 2, Ingredient: "Milk", SortOrder: 1},
+		{ID: 3, Ingredient: "Salt", SortOrder: 2},
+		{ID: 4, Ingredient: "Eggs", SortOrder: 3},
+	}
+
+	tests := []struct {
+		name      string
+		itemID    int64
+		position  int64
+		want      []int64
+		wantMoved []int64
+		wantErr   error
+	}{
+		{name: "Down", itemID: 1, position: 2, want: []int64{2, 3, 1, 4}, wantMoved: []int64{2, 3, 1}},
+		{name: "Up", itemID: 4, position: 1, want: []int64{1, 4, 2, 3}, wantMoved: []int64{4, 2, 3}},
+		{name: "Same position", itemID: 2, position: 1, want: []int64{1, 2, 3, 4}},
+		{name: "Past the end", itemID: 2, position: 10, want: []int64{1, 3, 4, 2}, wantMoved: []int64{3, 4, 2}},
+		{name: "Unknown item", itemID: 5, position: 0, wantErr: ErrShoppingListItemNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := moveShoppingListItem(items, tc.itemID, tc.position)
+			if err != tc.wantErr {
+				t.Fatalf("moveShoppingListItem() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if ids := shoppingListItemIDs(got); !slices.Equal(ids, tc.want) {
+				t.Errorf("moveShoppingListItem() = %v, want %v", ids, tc.want)
+			}
+			if moved := shoppingListItemIDs(renumberShoppingListItems(got)); !slices.Equal(moved, tc.wantMoved) {
+				t.Errorf("renumberShoppingListItems() = %v, want %v", moved, tc.wantMoved)
+			}
+			if items[0].ID != 1 || items[0].SortOrder != 0 {
+				t.Errorf("moveShoppingListItem() changed the given items")
+			}
+		})
+	}
+}
+
+func TestOrderShoppingListItems(t *testing.T) {
+	items := []ShoppingListItem{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+
+	tests := []struct {
+		name    string
+		itemIDs []int64
+		want    []int64
+	}{
+		{name: "All items", itemIDs: []int64{4, 3, 2, 1}, want: []int64{4, 3, 2, 1}},
+		{name: "Some items", itemIDs: []int64{3, 1}, want: []int64{3, 1, 2, 4}},
+		{name: "Unknown and repeated items", itemIDs: []int64{5, 2, 2}, want: []int64{2, 1, 3, 4}},
+		{name: "No items", want: []int64{1, 2, 3, 4}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shoppingListItemIDs(orderShoppingListItems(items, tc.itemIDs)); !slices.Equal(got, tc.want) {
+				t.Errorf("orderShoppingListItems() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func shoppingListItemIDs(items []ShoppingListItem) []int64 {
+	var ids []int64
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestArrangeForStore(t *testing.T) {
+	store := Store{ID: 1, Sections: []StoreSection{
+		{ID: 11, Name: "Dairy", SortOrder: 1, IngredientIDs: []int64{2}},
+		{ID: 10, Name: "Produce", SortOrder: 0, IngredientIDs: []int64{3, 4}},
+		{ID: 12, Name: "Frozen", SortOrder: 2},
+	}}
+	list := ShoppingList{Items: []ShoppingListItem{
+		{ID: 1, Ingredient: "Milk", IngredientID: ptr(int64(2)), SortOrder: 0},
+		{ID: 2, Ingredient: "Batteries", SortOrder: 1},
+		{ID: 3, Ingredient: "Apples", IngredientID: ptr(int64(3)), SortOrder: 2},
+		{ID: 4, Ingredient: "Flour", IngredientID: ptr(int64(1)), SortOrder: 3},
+		{ID: 5, Ingredient: "Carrots", IngredientID: ptr(int64(4)), SortOrder: 4},
+	}}
+
+	list.ArrangeForStore(store)
+
+	wantItems := []int64{3, 5, 1, 2, 4}
+	if len(list.Items) != len(wantItems) {
+		t.Fatalf("ArrangeForStore() = %d items, want %d", len(list.Items), len(wantItems))
+	}
+	for i, id := range wantItems {
+		if list.Items[i].ID != id {
+			t.Errorf("ArrangeForStore() item %d = %d, want %d", i, list.Items[i].ID, id)
+		}
+	}
+
+	wantSections := []struct {
+		sectionID *int64
+		items     int
+	}{
+		{sectionID: ptr(int64(10)), items: 2},
+		{sectionID: ptr(int64(11)), items: 1},
+		{sectionID: nil, items: 2},
+	}
+	if len(list.Sections) != len(wantSections) {
+		t.Fatalf("ArrangeForStore() = %d sections, want %d", len(list.Sections), len(wantSections))
+	}
+	for i, want := range wantSections {
+		section := list.Sections[i]
+		if (section.Section == nil) != (want.sectionID == nil) || (section.Section != nil && section.Section.ID != *want.sectionID) {
+			t.Errorf("ArrangeForStore() section %d = %v, want %v", i, section.Section, want.sectionID)
+		}
+		if len(section.Items) != want.items {
+			t.Errorf("ArrangeForStore() section %d = %d items, want %d", i, len(section.Items), want.items)
+		}
+		for _, item := range section.Items {
+			if !equalIDs(item.SectionID, want.sectionID) {
+				t.Errorf("ArrangeForStore() item %d section = %v, want %v", item.ID, item.SectionID, want.sectionID)
+			}
+		}
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	service := &ShoppingService{}
+
+	tests := []struct {
+		name    string
+		store   Store
+		wantErr bool
+	}{
+		{name: "Without sections", store: Store{Name: "Corner Market"}},
+		{name: "With sections", store: Store{Name: "Corner Market", Sections: []StoreSection{
+			{ID: 1, Name: "Produce", IngredientIDs: []int64{1, 2}},
+			{Name: "Dairy", IngredientIDs: []int64{3}},
+		}}},
+		{name: "Missing name", store: Store{Name: " "}, wantErr: true},
+		{name: "Missing section name", store: Store{Name: "Corner Market", Sections: []StoreSection{{}}}, wantErr: true},
+		{name: "Duplicate section", store: Store{Name: "Corner Market", Sections: []StoreSection{
+			{ID: 1, Name: "Produce"},
+			{ID: 1, Name: "Dairy"},
+		}}, wantErr: true},
+		{name: "Ingredient in two sections", store: Store{Name: "Corner Market", Sections: []StoreSection{
+			{Name: "Produce", IngredientIDs: []int64{1}},
+			{Name: "Dairy", IngredientIDs: []int64{1}},
+		}}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.validateStore(tc.store)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateStore() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -2,14 +2,22 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type ShoppingService struct {
-	store ShoppingStore
+	events  ShoppingListEventBus
+	recipes *RecipeService
+	store   ShoppingStore
 }
 
+// GetByUser returns the shopping lists of the user's household.
 func (s *ShoppingService) GetByUser(ctx context.Context, user *User) ([]ShoppingList, error) {
-	return s.store.GetShoppingListsByUser(ctx, user.ID)
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.store.GetShoppingListsByHousehold(ctx, householdID)
 }
 
 func (s *ShoppingService) GetByID(ctx context.Context, user *User, listID int64) (ShoppingList, error) {
@@ -18,19 +26,39 @@ func (s *ShoppingService) GetByID(ctx context.Context, user *User, listID int64)
 		return ShoppingList{}, err
 	}
 
-	if err := s.validateShoppingListOwnership(ctx, user, &list); err != nil {
+	if err := s.validateShoppingListMembership(user, &list, HouseholdRoleViewer); err != nil {
 		return ShoppingList{}, err
 	}
 
 	return list, nil
 }
 
+// GetByIDForStore returns the list with its items arranged by the layout of
+// the given store.
+func (s *ShoppingService) GetByIDForStore(ctx context.Context, user *User, listID int64, storeID int64) (ShoppingList, error) {
+	list, err := s.GetByID(ctx, user, listID)
+	if err != nil {
+		return ShoppingList{}, err
+	}
+	store, err := s.GetStore(ctx, user, storeID)
+	if err != nil {
+		return ShoppingList{}, err
+	}
+
+	list.ArrangeForStore(store)
+	return list, nil
+}
+
 func (s *ShoppingService) Create(ctx context.Context, user *User, name string) (ShoppingList, error) {
-	return s.store.CreateShoppingList(ctx, user.ID, name)
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return ShoppingList{}, err
+	}
+	return s.store.CreateShoppingList(ctx, householdID, user.ID, ShoppingList{Name: name})
 }
 
 func (s *ShoppingService) Update(ctx context.Context, user *User, listID int64, name string) (ShoppingList, error) {
-	if err := s.validateShoppingListOwnershipByID(ctx, user, listID); err != nil {
+	if err := s.validateShoppingListMembershipByID(ctx, user, listID); err != nil {
 		return ShoppingList{}, err
 	}
 
@@ -38,37 +66,389 @@ func (s *ShoppingService) Update(ctx context.Context, user *User, listID int64,
 }
 
 func (s *ShoppingService) Delete(ctx context.Context, user *User, listID int64) error {
-	if err := s.validateShoppingListOwnershipByID(ctx, user, listID); err != nil {
+	if err := s.validateShoppingListMembershipByID(ctx, user, listID); err != nil {
 		return err
 	}
 
-	return s.store.DeleteShoppingList(ctx, listID)
+	if err := s.store.DeleteShoppingList(ctx, listID); err != nil {
+		return err
+	}
+	s.events.Remove(listID)
+	return nil
 }
 
 func (s *ShoppingService) AddItem(ctx context.Context, user *User, listID int64, item ShoppingListItem) (ShoppingListItem, error) {
-	list, err := s.GetByID(ctx, user, listID)
+	list, err := s.store.GetShoppingListByID(ctx, listID)
 	if err != nil {
 		return ShoppingListItem{}, err
 	}
+	if err = s.validateShoppingListMembership(user, &list, HouseholdRoleEditor); err != nil {
+		return ShoppingListItem{}, err
+	}
+
+	units, ingredients, err := s.getUnitsAndIngredients(ctx)
+	if err != nil {
+		return ShoppingListItem{}, err
+	}
+	if item, err = s.prepareItem(item, units, ingredients); err != nil {
+		return ShoppingListItem{}, err
+	}
+
+	// Adding an ingredient that is already open on the list raises its amount
+	density := ingredientDensity(ingredients, item.IngredientID)
+	for _, existing := range list.Items {
+		if !existing.Done && existing.combine(item, units, density) {
+			existing.normalize(units)
+			updated, err := s.store.UpdateShoppingListItem(ctx, existing.ID, existing)
+			if err != nil {
+				return ShoppingListItem{}, err
+			}
+			s.publishItem(listID, ShoppingListItemUpdated, updated)
+			return updated, nil
+		}
+	}
 
 	item.SortOrder = s.calculateNextSortOrder(list.Items)
-	return s.store.CreateShoppingListItem(ctx, listID, item)
+	created, err := s.store.CreateShoppingListItem(ctx, listID, item)
+	if err != nil {
+		return ShoppingListItem{}, err
+	}
+	s.publishItem(listID, ShoppingListItemCreated, created)
+	return created, nil
+}
+
+// AddFromMealPlan puts the ingredients of the recipes planned in the given
+// range on a shopping list, scaled to the planned servings.
+func (s *ShoppingService) AddFromMealPlan(ctx context.Context, user *User, request MealPlanShopping) (ShoppingList, error) {
+	if err := s.recipes.validateDateRange(request.From, request.Until); err != nil {
+		return ShoppingList{}, err
+	}
+
+	mealPlan, err := s.recipes.GetMealPlan(ctx, user, request.From, request.Until)
+	if err != nil {
+		return ShoppingList{}, err
+	}
+	units, ingredients, err := s.getUnitsAndIngredients(ctx)
+	if err != nil {
+		return ShoppingList{}, err
+	}
+
+	list := ShoppingList{Name: request.Name}
+	if request.ListID != nil {
+		list, err = s.store.GetShoppingListByID(ctx, *request.ListID)
+		if err == nil {
+			err = s.validateShoppingListMembership(user, &list, HouseholdRoleEditor)
+		}
+	} else {
+		err = user.Membership.Authorize(user.Membership.HouseholdID, HouseholdRoleEditor)
+	}
+	if err != nil {
+		return ShoppingList{}, err
+	}
+
+	additions := CollectMealPlanItems(mealPlan, units)
+	items := mergeShoppingListItems(list.Items, additions, units, ingredients, s.calculateNextSortOrder(list.Items))
+	if request.ListID == nil {
+		// The new list is created together with its items, so that a failure
+		// doesn't leave an empty list behind
+		list.Items = items
+		return s.store.CreateShoppingList(ctx, user.Membership.HouseholdID, user.ID, list)
+	}
+	if err = s.store.SaveShoppingListItems(ctx, list.ID, items); err != nil {
+		return ShoppingList{}, err
+	}
+	saved, err := s.store.GetShoppingListByID(ctx, list.ID)
+	if err != nil {
+		return ShoppingList{}, err
+	}
+	s.publishItemChanges(list, saved)
+	return saved, nil
 }
 
 func (s *ShoppingService) UpdateItem(ctx context.Context, user *User, listID int64, itemID int64, item ShoppingListItem) (ShoppingListItem, error) {
-	if err := s.validateShoppingListOwnershipByID(ctx, user, listID); err != nil {
+	if err := s.validateShoppingListItemMembership(ctx, user, listID, itemID); err != nil {
 		return ShoppingListItem{}, err
 	}
 
-	return s.store.UpdateShoppingListItem(ctx, itemID, item)
+	units, ingredients, err := s.getUnitsAndIngredients(ctx)
+	if err != nil {
+		return ShoppingListItem{}, err
+	}
+	if item, err = s.prepareItem(item, units, ingredients); err != nil {
+		return ShoppingListItem{}, err
+	}
+	updated, err := s.store.UpdateShoppingListItem(ctx, itemID, item)
+	if err != nil {
+		return ShoppingListItem{}, err
+	}
+	s.publishItem(listID, ShoppingListItemUpdated, updated)
+	return updated, nil
 }
 
 func (s *ShoppingService) DeleteItem(ctx context.Context, user *User, listID int64, itemID int64) error {
-	if err := s.validateShoppingListOwnershipByID(ctx, user, listID); err != nil {
+	if err := s.validateShoppingListItemMembership(ctx, user, listID, itemID); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteShoppingListItem(ctx, itemID); err != nil {
 		return err
 	}
+	s.events.Publish(ShoppingListEvent{ListID: listID, Type: ShoppingListItemDeleted, ItemID: itemID})
+	return nil
+}
+
+// MoveItem puts an item at the given position of its list, counted from the
+// top. The other items close the gap it leaves and make room for it.
+func (s *ShoppingService) MoveItem(ctx context.Context, user *User, listID int64, itemID int64, position int64) (ShoppingList, error) {
+	if position < 0 {
+		return ShoppingList{}, ErrInvalidMove
+	}
+	if err := s.validateShoppingListMembershipByID(ctx, user, listID); err != nil {
+		return ShoppingList{}, err
+	}
+	return s.reorderItems(ctx, listID, func(items []ShoppingListItem) ([]ShoppingListItem, error) {
+		return moveShoppingListItem(items, itemID, position)
+	})
+}
+
+// ReorderItems puts the items with the given IDs on top of the list in that
+// order, the items left out keep their order below them.
+func (s *ShoppingService) ReorderItems(ctx context.Context, user *User, listID int64, itemIDs []int64) (ShoppingList, error) {
+	if err := s.validateShoppingListMembershipByID(ctx, user, listID); err != nil {
+		return ShoppingList{}, err
+	}
+	return s.reorderItems(ctx, listID, func(items []ShoppingListItem) ([]ShoppingListItem, error) {
+		return orderShoppingListItems(items, itemIDs), nil
+	})
+}
 
-	return s.store.DeleteShoppingListItem(ctx, itemID)
+// Sync applies the operations a client recorded offline in one transaction
+// and returns what changed since the client's last sync. Without operations
+// it only pulls the changes, which viewers are allowed to do as well.
+func (s *ShoppingService) Sync(ctx context.Context, user *User, listID int64, sync ShoppingListSync) (ShoppingListSyncResult, error) {
+	token, err := DecodeSyncToken(sync.SyncToken)
+	if err != nil {
+		return ShoppingListSyncResult{}, err
+	}
+	if token != nil && token.ListID != listID {
+		return ShoppingListSyncResult{}, ErrInvalidSyncToken
+	}
+	if err = s.validateShoppingListSync(sync); err != nil {
+		return ShoppingListSyncResult{}, err
+	}
+
+	role := HouseholdRoleViewer
+	if len(sync.Operations) > 0 {
+		role = HouseholdRoleEditor
+	}
+	list, err := s.store.GetShoppingListByID(ctx, listID)
+	if err != nil {
+		return ShoppingListSyncResult{}, err
+	}
+	if err = s.validateShoppingListMembership(user, &list, role); err != nil {
+		return ShoppingListSyncResult{}, err
+	}
+
+	var results []ShoppingListOperationResult
+	if len(sync.Operations) > 0 {
+		units, ingredients, err := s.getUnitsAndIngredients(ctx)
+		if err != nil {
+			return ShoppingListSyncResult{}, err
+		}
+		prepare := func(item ShoppingListItem) (ShoppingListItem, error) {
+			return s.prepareItem(item, units, ingredients)
+		}
+
+		now := time.Now()
+		var before ShoppingList
+		list, err = s.store.SyncShoppingListItems(ctx, listID, func(current ShoppingList) (ShoppingListChanges, error) {
+			before = current
+			var changes ShoppingListChanges
+			changes, results = s.applyOperations(current, sync.Operations, now, prepare)
+			return changes, nil
+		})
+		if err != nil {
+			return ShoppingListSyncResult{}, err
+		}
+		resolveCreatedItemIDs(list, sync.Operations, results)
+		s.publishItemChanges(before, list)
+	}
+
+	result := ShoppingListSyncResult{
+		List:           list,
+		Full:           true,
+		DeletedItemIDs: []int64{},
+		SyncToken:      SyncToken{ListID: listID, Revision: list.Revision}.Encode(),
+		Results:        results,
+	}
+	if token != nil && token.Revision <= list.Revision {
+		delta, err := s.store.GetShoppingListChangesSince(ctx, listID, token.Revision)
+		if err != nil {
+			return ShoppingListSyncResult{}, err
+		}
+		result.List.Items = delta.Items
+		result.Full = false
+		result.DeletedItemIDs = delta.DeletedItemIDs
+		result.SyncToken = SyncToken{ListID: listID, Revision: delta.Revision}.Encode()
+	}
+	return result, nil
+}
+
+// Subscribe streams the changes to the items of a list the user can see.
+func (s *ShoppingService) Subscribe(ctx context.Context, user *User, listID int64, lastEventID *int64) (<-chan ShoppingListEvent, error) {
+	if _, err := s.GetByID(ctx, user, listID); err != nil {
+		return nil, err
+	}
+	return s.events.Subscribe(ctx, listID, lastEventID), nil
+}
+
+// GetStores returns the stores of the user's household.
+func (s *ShoppingService) GetStores(ctx context.Context, user *User) ([]Store, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.store.GetStoresByHousehold(ctx, householdID)
+}
+
+func (s *ShoppingService) GetStore(ctx context.Context, user *User, storeID int64) (Store, error) {
+	store, err := s.store.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if err := s.validateStoreMembership(user, &store, HouseholdRoleViewer); err != nil {
+		return Store{}, err
+	}
+
+	return store, nil
+}
+
+func (s *ShoppingService) CreateStore(ctx context.Context, user *User, store Store) (Store, error) {
+	householdID := user.Membership.HouseholdID
+	if err := user.Membership.Authorize(householdID, HouseholdRoleEditor); err != nil {
+		return Store{}, err
+	}
+	if err := s.validateStore(store); err != nil {
+		return Store{}, err
+	}
+
+	return s.store.CreateStore(ctx, householdID, user.ID, numberStoreSections(store))
+}
+
+func (s *ShoppingService) UpdateStore(ctx context.Context, user *User, storeID int64, store Store) (Store, error) {
+	if err := s.validateStoreMembershipByID(ctx, user, storeID); err != nil {
+		return Store{}, err
+	}
+	if err := s.validateStore(store); err != nil {
+		return Store{}, err
+	}
+
+	return s.store.UpdateStore(ctx, storeID, numberStoreSections(store))
+}
+
+func (s *ShoppingService) DeleteStore(ctx context.Context, user *User, storeID int64) error {
+	if err := s.validateStoreMembershipByID(ctx, user, storeID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteStore(ctx, storeID)
+}
+
+// numberStoreSections orders the sections the way they were passed in.
+func numberStoreSections(store Store) Store {
+	sections := make([]StoreSection, len(store.Sections))
+	for i, section := range store.Sections {
+		section.SortOrder = int64(i)
+		sections[i] = section
+	}
+	store.Sections = sections
+	return store
+}
+
+// prepareItem validates the item and fills in the text of structured items.
+func (s *ShoppingService) prepareItem(item ShoppingListItem, units []Unit, ingredients []Ingredient) (ShoppingListItem, error) {
+	if item.IngredientID != nil {
+		ingredient := findIngredientByID(ingredients, *item.IngredientID)
+		if ingredient == nil {
+			return ShoppingListItem{}, ErrInvalidShoppingListItem
+		}
+		item.Ingredient = ingredient.Name
+	}
+	if item.UnitID != nil && findUnitByID(units, *item.UnitID) == nil {
+		return ShoppingListItem{}, ErrInvalidShoppingListItem
+	}
+	if err := s.validateShoppingListItem(item); err != nil {
+		return ShoppingListItem{}, err
+	}
+
+	item.normalize(units)
+	return item, nil
+}
+
+func (s *ShoppingService) getUnitsAndIngredients(ctx context.Context) ([]Unit, []Ingredient, error) {
+	units, err := s.recipes.store.GetUnits(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ingredients, err := s.recipes.store.GetIngredients(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return units, ingredients, nil
+}
+
+// reorderItems renumbers the items of the list in the order returned by
+// order, which gets them sorted from top to bottom, in one transaction.
+func (s *ShoppingService) reorderItems(ctx context.Context, listID int64, order func([]ShoppingListItem) ([]ShoppingListItem, error)) (ShoppingList, error) {
+	var before ShoppingList
+	list, err := s.store.SyncShoppingListItems(ctx, listID, func(current ShoppingList) (ShoppingListChanges, error) {
+		before = current
+		items, err := order(sortedShoppingListItems(current.Items))
+		if err != nil {
+			return ShoppingListChanges{}, err
+		}
+		return ShoppingListChanges{Moved: renumberShoppingListItems(items)}, nil
+	})
+	if err != nil {
+		return ShoppingList{}, err
+	}
+	s.publishItemChanges(before, list)
+	return list, nil
+}
+
+func (s *ShoppingService) publishItem(listID int64, eventType ShoppingListEventType, item ShoppingListItem) {
+	s.events.Publish(ShoppingListEvent{ListID: listID, Type: eventType, Item: &item})
+}
+
+// publishItemChanges compares the items of a list before and after a batch
+// of changes and publishes the items that were created, updated or deleted,
+// followed by the new order if it changed.
+func (s *ShoppingService) publishItemChanges(before, after ShoppingList) {
+	existing := make(map[int64]bool, len(before.Items))
+	for _, item := range before.Items {
+		existing[item.ID] = true
+	}
+	remaining := make(map[int64]bool, len(after.Items))
+	for _, item := range after.Items {
+		remaining[item.ID] = true
+	}
+
+	for _, item := range before.Items {
+		if !remaining[item.ID] {
+			s.events.Publish(ShoppingListEvent{ListID: after.ID, Type: ShoppingListItemDeleted, ItemID: item.ID})
+		}
+	}
+	for _, item := range changedShoppingListItems(before.Items, after.Items) {
+		eventType := ShoppingListItemCreated
+		if existing[item.ID] {
+			eventType = ShoppingListItemUpdated
+		}
+		s.publishItem(after.ID, eventType, item)
+	}
+	if movedShoppingListItems(before.Items, after.Items) {
+		s.events.Publish(ShoppingListEvent{ListID: after.ID, Type: ShoppingListItemsReordered, Items: after.Items})
+	}
 }
 
 func (s *ShoppingService) calculateNextSortOrder(items []ShoppingListItem) int64 {
@@ -2,20 +2,97 @@ package domain
 
 import (
 	"context"
+	"slices"
+	"strings"
 )
 
-func (s *ShoppingService) validateShoppingListOwnership(ctx context.Context, user *User, list *ShoppingList) error {
-	if list.UserID != user.ID {
-		return ErrAuthorization
+func (s *ShoppingService) validateShoppingListMembership(user *User, list *ShoppingList, role HouseholdRole) error {
+	return user.Membership.Authorize(list.HouseholdID, role)
+}
+
+// validateShoppingListMembershipByID allows editors of the list's household
+// to change it.
+func (s *ShoppingService) validateShoppingListMembershipByID(ctx context.Context, user *User, listID int64) error {
+	list, err := s.store.GetShoppingListByID(ctx, listID)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	return s.validateShoppingListMembership(user, &list, HouseholdRoleEditor)
 }
 
-func (s *ShoppingService) validateShoppingListOwnershipByID(ctx context.Context, user *User, listID int64) error {
+// validateShoppingListItemMembership allows editors of the list's household
+// to change the item if it is on that list, which is also the list its
+// changes are published to.
+func (s *ShoppingService) validateShoppingListItemMembership(ctx context.Context, user *User, listID int64, itemID int64) error {
 	list, err := s.store.GetShoppingListByID(ctx, listID)
 	if err != nil {
 		return err
 	}
+	if err = s.validateShoppingListMembership(user, &list, HouseholdRoleEditor); err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(list.Items, func(item ShoppingListItem) bool { return item.ID == itemID }) {
+		return ErrShoppingListItemNotFound
+	}
+	return nil
+}
+
+func (s *ShoppingService) validateShoppingListItem(item ShoppingListItem) error {
+	if strings.TrimSpace(item.Ingredient) == "" {
+		return ErrInvalidShoppingListItem
+	}
+	if item.Amount != nil && *item.Amount <= 0 {
+		return ErrInvalidShoppingListItem
+	}
+	return nil
+}
 
-	return s.validateShoppingListOwnership(ctx, user, &list)
+func (s *ShoppingService) validateShoppingListSync(sync ShoppingListSync) error {
+	if len(sync.Operations) > MaxShoppingListOperations {
+		return ErrInvalidShoppingListSync
+	}
+	return nil
+}
+
+func (s *ShoppingService) validateStoreMembership(user *User, store *Store, role HouseholdRole) error {
+	return user.Membership.Authorize(store.HouseholdID, role)
+}
+
+// validateStoreMembershipByID allows editors of the store's household to
+// change it.
+func (s *ShoppingService) validateStoreMembershipByID(ctx context.Context, user *User, storeID int64) error {
+	store, err := s.store.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return err
+	}
+
+	return s.validateStoreMembership(user, &store, HouseholdRoleEditor)
+}
+
+func (s *ShoppingService) validateStore(store Store) error {
+	if strings.TrimSpace(store.Name) == "" {
+		return ErrInvalidStore
+	}
+
+	sectionIDs := make(map[int64]bool)
+	ingredientIDs := make(map[int64]bool)
+	for _, section := range store.Sections {
+		if strings.TrimSpace(section.Name) == "" {
+			return ErrInvalidStore
+		}
+		if section.ID != 0 {
+			if sectionIDs[section.ID] {
+				return ErrInvalidStore
+			}
+			sectionIDs[section.ID] = true
+		}
+		for _, id := range section.IngredientIDs {
+			if ingredientIDs[id] {
+				return ErrInvalidStore
+			}
+			ingredientIDs[id] = true
+		}
+	}
+	return nil
 }
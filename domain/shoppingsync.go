@@ -0,0 +1,293 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const MaxShoppingListOperations = 500
+
+type ShoppingListOperationType string
+
+const (
+	ShoppingListOperationCreate  ShoppingListOperationType = "create"
+	ShoppingListOperationToggle  ShoppingListOperationType = "toggle"
+	ShoppingListOperationEdit    ShoppingListOperationType = "edit"
+	ShoppingListOperationDelete  ShoppingListOperationType = "delete"
+	ShoppingListOperationReorder ShoppingListOperationType = "reorder"
+)
+
+type ShoppingListOperationStatus string
+
+const (
+	ShoppingListOperationApplied ShoppingListOperationStatus = "applied"
+	// ShoppingListOperationConflict means the item was changed after the
+	// operation was recorded and the newer change was kept.
+	ShoppingListOperationConflict ShoppingListOperationStatus = "conflict"
+	ShoppingListOperationNotFound ShoppingListOperationStatus = "notFound"
+	ShoppingListOperationInvalid  ShoppingListOperationStatus = "invalid"
+)
+
+// ShoppingListItemRef points to an item by its ID, or by its ClientID if it
+// was created offline and the client has not learned its ID yet.
+type ShoppingListItemRef struct {
+	ID       *int64
+	ClientID *string
+}
+
+// ShoppingListOperation is a change a client recorded while it was offline.
+// BaseVersion is the version of the item the change was made to, Values
+// holds the new item for creates and edits, Done the state of a toggle and
+// Order the items of a reorder from top to bottom.
+type ShoppingListOperation struct {
+	Type        ShoppingListOperationType
+	Item        ShoppingListItemRef
+	BaseVersion int64
+	Timestamp   time.Time
+	Values      ShoppingListItem
+	Done        bool
+	Order       []ShoppingListItemRef
+}
+
+type ShoppingListOperationResult struct {
+	Status ShoppingListOperationStatus
+	ItemID *int64
+}
+
+// ShoppingListSync is a batch of offline operations. The SyncToken of the
+// previous sync limits the response to what changed since then.
+type ShoppingListSync struct {
+	SyncToken  string
+	Operations []ShoppingListOperation
+}
+
+// ShoppingListSyncResult holds the canonical list after a sync. If Full is
+// not set, its items are only those changed since the given sync token and
+// DeletedItemIDs lists the items removed since.
+type ShoppingListSyncResult struct {
+	List           ShoppingList
+	Full           bool
+	DeletedItemIDs []int64
+	SyncToken      string
+	Results        []ShoppingListOperationResult
+}
+
+// ShoppingListChanges are the item changes a sync persists. Updated items
+// have new values, Moved items only a new SortOrder.
+type ShoppingListChanges struct {
+	Created []ShoppingListItem
+	Updated []ShoppingListItem
+	Moved   []ShoppingListItem
+	Deleted []int64
+}
+
+// ShoppingListDelta holds the items of a list changed after a revision.
+type ShoppingListDelta struct {
+	Revision       int64
+	Items          []ShoppingListItem
+	DeletedItemIDs []int64
+}
+
+// SyncToken marks the revision of a list a client has seen.
+type SyncToken struct {
+	ListID   int64 `json:"l"`
+	Revision int64 `json:"r"`
+}
+
+func (t SyncToken) Encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeSyncToken(token string) (*SyncToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidSyncToken
+	}
+	var syncToken SyncToken
+	if err = json.Unmarshal(data, &syncToken); err != nil {
+		return nil, ErrInvalidSyncToken
+	}
+	return &syncToken, nil
+}
+
+// applyOperations replays offline operations on the list in the order they
+// were recorded. Operations of the batch never conflict with each other. A
+// change to an item that was changed elsewhere since the client saw it is
+// applied only if it was made later, so the last writer wins and ties keep
+// the server state. Changes to deleted items are dropped. Timestamps in the
+// future are treated as now, so a skewed clock can't win every conflict.
+func (s *ShoppingService) applyOperations(list ShoppingList, operations []ShoppingListOperation, now time.Time, prepare func(ShoppingListItem) (ShoppingListItem, error)) (ShoppingListChanges, []ShoppingListOperationResult) {
+	items := sortedShoppingListItems(list.Items)
+	original := make(map[int64]ShoppingListItem, len(items))
+	for _, item := range items {
+		original[item.ID] = item
+	}
+
+	var changes ShoppingListChanges
+	results := make([]ShoppingListOperationResult, len(operations))
+	edited := make(map[int64]bool)
+	reordered := false
+	nextSortOrder := s.calculateNextSortOrder(items)
+
+	find := func(ref ShoppingListItemRef) int {
+		for i := range items {
+			if ref.ID != nil && items[i].ID == *ref.ID && items[i].ID != 0 {
+				return i
+			}
+			if ref.ClientID != nil && items[i].ClientID != nil && *items[i].ClientID == *ref.ClientID {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for n, operation := range operations {
+		timestamp := operation.Timestamp
+		if timestamp.IsZero() || timestamp.After(now) {
+			timestamp = now
+		}
+
+		if operation.Type == ShoppingListOperationCreate {
+			if operation.Item.ClientID == nil || *operation.Item.ClientID == "" {
+				results[n].Status = ShoppingListOperationInvalid
+				continue
+			}
+			// A batch that is sent again must not add its items twice
+			if i := find(ShoppingListItemRef{ClientID: operation.Item.ClientID}); i >= 0 {
+				results[n] = ShoppingListOperationResult{Status: ShoppingListOperationApplied, ItemID: shoppingListItemID(items[i])}
+				continue
+			}
+			item, err := prepare(operation.Values)
+			if err != nil {
+				results[n].Status = ShoppingListOperationInvalid
+				continue
+			}
+			item.ID = 0
+			item.ClientID = operation.Item.ClientID
+			item.Version = 1
+			item.UpdatedAt = timestamp
+			item.SortOrder = nextSortOrder
+			nextSortOrder++
+			items = append(items, item)
+			results[n].Status = ShoppingListOperationApplied
+			continue
+		}
+
+		if operation.Type == ShoppingListOperationReorder {
+			items = reorderShoppingListItems(items, operation.Order, find)
+			reordered = true
+			results[n].Status = ShoppingListOperationApplied
+			continue
+		}
+
+		i := find(operation.Item)
+		if i < 0 {
+			results[n].Status = ShoppingListOperationNotFound
+			continue
+		}
+		item := &items[i]
+		results[n].ItemID = shoppingListItemID(*item)
+		if item.ID != 0 && !edited[item.ID] && operation.BaseVersion != item.Version && !timestamp.After(item.UpdatedAt) {
+			results[n].Status = ShoppingListOperationConflict
+			continue
+		}
+
+		switch operation.Type {
+		case ShoppingListOperationToggle:
+			item.Done = operation.Done
+		case ShoppingListOperationEdit:
+			values, err := prepare(operation.Values)
+			if err != nil {
+				results[n].Status = ShoppingListOperationInvalid
+				continue
+			}
+			item.Ingredient = values.Ingredient
+			item.Quantity = values.Quantity
+			item.Unit = values.Unit
+			item.IngredientID = values.IngredientID
+			item.UnitID = values.UnitID
+			item.Amount = values.Amount
+		case ShoppingListOperationDelete:
+			if item.ID != 0 {
+				changes.Deleted = append(changes.Deleted, item.ID)
+			}
+			items = append(items[:i], items[i+1:]...)
+			results[n].Status = ShoppingListOperationApplied
+			continue
+		default:
+			results[n].Status = ShoppingListOperationInvalid
+			continue
+		}
+		if timestamp.After(item.UpdatedAt) {
+			item.UpdatedAt = timestamp
+		}
+		if item.ID != 0 {
+			edited[item.ID] = true
+		}
+		results[n].Status = ShoppingListOperationApplied
+	}
+
+	if reordered {
+		for i := range items {
+			items[i].SortOrder = int64(i)
+		}
+	}
+	for _, item := range items {
+		switch {
+		case item.ID == 0:
+			changes.Created = append(changes.Created, item)
+		case edited[item.ID]:
+			changes.Updated = append(changes.Updated, item)
+		case item.SortOrder != original[item.ID].SortOrder:
+			changes.Moved = append(changes.Moved, item)
+		}
+	}
+	return changes, results
+}
+
+// resolveCreatedItemIDs fills in the IDs of the items created by the sync,
+// which are only known once they are saved.
+func resolveCreatedItemIDs(list ShoppingList, operations []ShoppingListOperation, results []ShoppingListOperationResult) {
+	for n, operation := range operations {
+		if operation.Type != ShoppingListOperationCreate || results[n].ItemID != nil || results[n].Status != ShoppingListOperationApplied {
+			continue
+		}
+		for _, item := range list.Items {
+			if item.ClientID != nil && *item.ClientID == *operation.Item.ClientID {
+				results[n].ItemID = &item.ID
+				break
+			}
+		}
+	}
+}
+
+// reorderShoppingListItems puts the referenced items on top in the given
+// order, the others follow in their current order.
+func reorderShoppingListItems(items []ShoppingListItem, order []ShoppingListItemRef, find func(ShoppingListItemRef) int) []ShoppingListItem {
+	placed := make([]bool, len(items))
+	result := make([]ShoppingListItem, 0, len(items))
+	for _, ref := range order {
+		if i := find(ref); i >= 0 && !placed[i] {
+			placed[i] = true
+			result = append(result, items[i])
+		}
+	}
+	for i, item := range items {
+		if !placed[i] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func shoppingListItemID(item ShoppingListItem) *int64 {
+	if item.ID == 0 {
+		return nil
+	}
+	return &item.ID
+}
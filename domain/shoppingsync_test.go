@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyOperations(t *testing.T) {
+	service := &ShoppingService{}
+	start := time.Date(2025, 10, 21, 9, 0, 0, 0, time.UTC)
+	now := start.Add(time.Hour)
+	list := ShoppingList{ID: 1, Items: []ShoppingListItem{
+		{ID: 1, Ingredient: "Milk", Version: 2, UpdatedAt: start.Add(10 * time.Minute), SortOrder: 0},
+		{ID: 2, Ingredient: "Eggs", Version: 1, UpdatedAt: start, SortOrder: 1},
+		{ID: 3, Ingredient: "Bread", Version: 1, UpdatedAt: start, SortOrder: 2, ClientID: ptr("bread")},
+	}}
+	prepare := func(item ShoppingListItem) (ShoppingListItem, error) {
+		if item.Ingredient == "" {
+			return ShoppingListItem{}, ErrInvalidShoppingListItem
+		}
+		return item, nil
+	}
+	byID := func(id int64) ShoppingListItemRef { return ShoppingListItemRef{ID: &id} }
+	byClientID := func(id string) ShoppingListItemRef { return ShoppingListItemRef{ClientID: &id} }
+
+	tests := []struct {
+		name        string
+		operations  []ShoppingListOperation
+		want        []ShoppingListOperationStatus
+		wantCreated int
+		wantUpdated []int64
+		wantMoved   []int64
+		wantDeleted []int64
+	}{
+		{
+			name:        "Create",
+			operations:  []ShoppingListOperation{{Type: ShoppingListOperationCreate, Item: byClientID("apples"), Values: ShoppingListItem{Ingredient: "Apples"}, Timestamp: start}},
+			want:        []ShoppingListOperationStatus{ShoppingListOperationApplied},
+			wantCreated: 1,
+		},
+		{
+			name:       "Create sent again",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationCreate, Item: byClientID("bread"), Values: ShoppingListItem{Ingredient: "Bread"}, Timestamp: start}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationApplied},
+		},
+		{
+			name:       "Create without client ID",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationCreate, Values: ShoppingListItem{Ingredient: "Apples"}, Timestamp: start}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationInvalid},
+		},
+		{
+			name:        "Toggle current version",
+			operations:  []ShoppingListOperation{{Type: ShoppingListOperationToggle, Item: byID(2), BaseVersion: 1, Done: true, Timestamp: start}},
+			want:        []ShoppingListOperationStatus{ShoppingListOperationApplied},
+			wantUpdated: []int64{2},
+		},
+		{
+			name:        "Stale edit made later",
+			operations:  []ShoppingListOperation{{Type: ShoppingListOperationEdit, Item: byID(1), BaseVersion: 1, Values: ShoppingListItem{Ingredient: "Oat milk"}, Timestamp: start.Add(20 * time.Minute)}},
+			want:        []ShoppingListOperationStatus{ShoppingListOperationApplied},
+			wantUpdated: []int64{1},
+		},
+		{
+			name:       "Stale edit made earlier",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationEdit, Item: byID(1), BaseVersion: 1, Values: ShoppingListItem{Ingredient: "Oat milk"}, Timestamp: start.Add(5 * time.Minute)}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationConflict},
+		},
+		{
+			name:       "Stale edit made at the same time",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationToggle, Item: byID(1), BaseVersion: 1, Done: true, Timestamp: start.Add(10 * time.Minute)}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationConflict},
+		},
+		{
+			name:       "Invalid edit",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationEdit, Item: byID(2), BaseVersion: 1, Timestamp: start}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationInvalid},
+		},
+		{
+			name:        "Delete",
+			operations:  []ShoppingListOperation{{Type: ShoppingListOperationDelete, Item: byID(3), BaseVersion: 1, Timestamp: start}},
+			want:        []ShoppingListOperationStatus{ShoppingListOperationApplied},
+			wantDeleted: []int64{3},
+		},
+		{
+			name:       "Stale delete",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationDelete, Item: byID(1), BaseVersion: 1, Timestamp: start}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationConflict},
+		},
+		{
+			name:       "Deleted item",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationToggle, Item: byID(99), BaseVersion: 1, Done: true, Timestamp: start}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationNotFound},
+		},
+		{
+			name: "Changes of the same batch",
+			operations: []ShoppingListOperation{
+				{Type: ShoppingListOperationEdit, Item: byID(2), BaseVersion: 1, Values: ShoppingListItem{Ingredient: "Eggs"}, Timestamp: start},
+				{Type: ShoppingListOperationToggle, Item: byID(2), BaseVersion: 1, Done: true, Timestamp: start},
+			},
+			want:        []ShoppingListOperationStatus{ShoppingListOperationApplied, ShoppingListOperationApplied},
+			wantUpdated: []int64{2},
+		},
+		{
+			name: "Create and toggle",
+			operations: []ShoppingListOperation{
+				{Type: ShoppingListOperationCreate, Item: byClientID("apples"), Values: ShoppingListItem{Ingredient: "Apples"}, Timestamp: start},
+				{Type: ShoppingListOperationToggle, Item: byClientID("apples"), Done: true, Timestamp: start},
+			},
+			want:        []ShoppingListOperationStatus{ShoppingListOperationApplied, ShoppingListOperationApplied},
+			wantCreated: 1,
+		},
+		{
+			name: "Create and delete",
+			operations: []ShoppingListOperation{
+				{Type: ShoppingListOperationCreate, Item: byClientID("apples"), Values: ShoppingListItem{Ingredient: "Apples"}, Timestamp: start},
+				{Type: ShoppingListOperationDelete, Item: byClientID("apples"), Timestamp: start},
+			},
+			want: []ShoppingListOperationStatus{ShoppingListOperationApplied, ShoppingListOperationApplied},
+		},
+		{
+			name:       "Reorder",
+			operations: []ShoppingListOperation{{Type: ShoppingListOperationReorder, Order: []ShoppingListItemRef{byID(3), byID(1), byID(99)}, Timestamp: start}},
+			want:       []ShoppingListOperationStatus{ShoppingListOperationApplied},
+			wantMoved:  []int64{3, 1, 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, results := service.applyOperations(list, tc.operations, now, prepare)
+
+			if len(results) != len(tc.want) {
+				t.Fatalf("applyOperations() results = %v, want %v", results, tc.want)
+			}
+			for i, result := range results {
+				if result.Status != tc.want[i] {
+					t.Errorf("applyOperations() result %d = %s, want %s", i, result.Status, tc.want[i])
+				}
+			}
+			if len(changes.Created) != tc.wantCreated {
+				t.Errorf("applyOperations() created = %d, want %d", len(changes.Created), tc.wantCreated)
+			}
+			assertItemIDs(t, "updated", changes.Updated, tc.wantUpdated)
+			assertItemIDs(t, "moved", changes.Moved, tc.wantMoved)
+			if !slices.Equal(changes.Deleted, tc.wantDeleted) {
+				t.Errorf("applyOperations() deleted = %v, want %v", changes.Deleted, tc.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestApplyOperationsValues(t *testing.T) {
+	service := &ShoppingService{}
+	start := time.Date(2025, 10, 21, 9, 0, 0, 0, time.UTC)
+	list := ShoppingList{ID: 1, Items: []ShoppingListItem{
+		{ID: 1, Ingredient: "Milk", Version: 1, UpdatedAt: start, SortOrder: 4},
+	}}
+	prepare := func(item ShoppingListItem) (ShoppingListItem, error) { return item, nil }
+
+	changes, _ := service.applyOperations(list, []ShoppingListOperation{
+		{Type: ShoppingListOperationToggle, Item: ShoppingListItemRef{ID: ptr(int64(1))}, BaseVersion: 1, Done: true, Timestamp: start.Add(48 * time.Hour)},
+		{Type: ShoppingListOperationCreate, Item: ShoppingListItemRef{ClientID: ptr("eggs")}, Values: ShoppingListItem{Ingredient: "Eggs"}, Timestamp: start},
+	}, start.Add(time.Hour), prepare)
+
+	if item := changes.Updated[0]; !item.Done || !item.UpdatedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("applyOperations() updated = %v at %v, want done at the time of the sync", item.Done, item.UpdatedAt)
+	}
+	if item := changes.Created[0]; item.SortOrder != 5 || *item.ClientID != "eggs" {
+		t.Errorf("applyOperations() created = %d %v, want sort order 5 for eggs", item.SortOrder, item.ClientID)
+	}
+}
+
+func assertItemIDs(t *testing.T, label string, items []ShoppingListItem, want []int64) {
+	t.Helper()
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	if len(ids) != len(want) || (len(want) > 0 && !slices.Equal(ids, want)) {
+		t.Errorf("applyOperations() %s = %v, want %v", label, ids, want)
+	}
+}
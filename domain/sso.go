@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain/roles"
+	"github.com/wolfsblu/recipe-manager/domain/security"
+)
+
+const (
+	// SSOLoginLifetime is how long users have to log in at the identity
+	// provider before they have to start over.
+	SSOLoginLifetime = 10 * time.Minute
+	// defaultLocale is what users created by single sign-on start with, it
+	// matches the default of the users table.
+	defaultLocale = "en"
+)
+
+// IdentityProvider is where users log in for single sign-on. It tells who
+// logged in once the provider sent the user back with a code.
+type IdentityProvider interface {
+	// AuthorizationURL is where the user is sent to log in. The code
+	// challenge for PKCE is derived from the verifier.
+	AuthorizationURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange redeems the code and returns the identity from the validated
+	// ID token.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (ExternalIdentity, error)
+}
+
+// ExternalIdentity is an account at an identity provider. Issuer and subject
+// identify it for good, the email can change at the provider.
+type ExternalIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// SSOProvisioning decides what happens to identities without an account. When
+// it is enabled they get a new account with the role.
+type SSOProvisioning struct {
+	Enabled bool
+	Role    roles.ID
+}
+
+// SSOLogin is the state of a login between sending the user to the identity
+// provider and them coming back.
+type SSOLogin struct {
+	ID           int64
+	State        string
+	Nonce        string
+	CodeVerifier string
+	CreatedAt    time.Time
+}
+
+func (l SSOLogin) ExpiresAt() time.Time {
+	return l.CreatedAt.Add(SSOLoginLifetime)
+}
+
+// BeginSSOLogin returns where to send the user to log in and the state the
+// provider hands back, which the browser has to present again.
+func (s *UserService) BeginSSOLogin(ctx context.Context, provider IdentityProvider) (string, string, error) {
+	if provider == nil {
+		return "", "", ErrSSONotConfigured
+	}
+	login, err := s.store.CreateSSOLogin(ctx, security.GenerateToken(security.DefaultTokenLength), security.GenerateToken(security.DefaultTokenLength))
+	if err != nil {
+		return "", "", err
+	}
+	redirectURL, err := provider.AuthorizationURL(ctx, login.State, login.Nonce, login.CodeVerifier)
+	if err != nil {
+		return "", "", WrapError(ErrSSOFailed, err)
+	}
+	return redirectURL, login.State, nil
+}
+
+// FinishSSOLogin returns the user to start a session for. Identities that
+// logged in before belong to their user. Otherwise they are linked to the
+// account with the same email, as long as the provider verified it, or get a
+// new account when provisioning is enabled. Unconfirmed accounts get a new
+// random password when they are linked. The provider took care of the login,
+// so it doesn't ask for a TOTP code.
+func (s *UserService) FinishSSOLogin(ctx context.Context, provider IdentityProvider, provisioning SSOProvisioning, state, code string) (User, error) {
+	if provider == nil {
+		return User{}, ErrSSONotConfigured
+	}
+	login, err := s.store.TakeSSOLogin(ctx, state)
+	if err != nil {
+		return User{}, err
+	} else if time.Now().After(login.ExpiresAt()) {
+		return User{}, ErrSSOLoginNotFound
+	}
+	identity, err := provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return User{}, WrapError(ErrSSOFailed, err)
+	}
+
+	user, err := s.store.GetUserByIdentity(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		if !user.Confirmed {
+			return User{}, ErrUnconfirmedUser
+		}
+		return user, nil
+	} else if err != ErrUserNotFound {
+		return User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return User{}, ErrSSOEmailNotVerified
+	}
+	user, err = s.store.GetUserByEmail(ctx, identity.Email)
+	if errors.Is(err, ErrUserNotFound) {
+		return s.provisionSSOUser(ctx, provisioning, identity)
+	} else if err != nil {
+		return User{}, err
+	}
+
+	// The provider verified the email, just like the registration mail would
+	// have. Whoever registered the account never did, so the password they
+	// chose and any session they have must not outlive the link.
+	if !user.Confirmed {
+		hash, err := security.CreateHash(security.GenerateToken(security.DefaultTokenLength), security.DefaultHashParams)
+		if err != nil {
+			return User{}, WrapError(ErrUpdatingPassword, err)
+		}
+		user.Confirmed = true
+		if err = s.store.ClaimUnconfirmedUser(ctx, &user, hash); err != nil {
+			return User{}, err
+		}
+	}
+	if err = s.store.CreateUserIdentity(ctx, user.ID, identity); err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (s *UserService) DeleteExpiredSSOLogins(ctx context.Context) error {
+	return s.store.DeleteSSOLoginsBefore(ctx, time.Now().Add(-SSOLoginLifetime))
+}
+
+// provisionSSOUser creates a confirmed account for the identity. Its password
+// is random and never handed out, users who want one can reset it.
+func (s *UserService) provisionSSOUser(ctx context.Context, provisioning SSOProvisioning, identity ExternalIdentity) (User, error) {
+	if !provisioning.Enabled {
+		return User{}, ErrSSOAccountNotFound
+	}
+	hash, err := security.CreateHash(security.GenerateToken(security.DefaultTokenLength), security.DefaultHashParams)
+	if err != nil {
+		return User{}, WrapError(ErrCreatingUser, err)
+	}
+	return s.store.ProvisionUser(ctx, UserDetails{
+		Email:        identity.Email,
+		PasswordHash: hash,
+		Locale:       defaultLocale,
+	}, provisioning.Role, identity)
+}
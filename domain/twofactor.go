@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain/security"
+)
+
+// AppName is how the app introduces itself to authenticator apps and
+// passkey providers.
+const AppName = "Recipe Manager"
+
+const (
+	// recoveryCodeCount is how many recovery codes users get when they turn
+	// on two-factor authentication. Every code works once.
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 5
+	// LoginChallengeLifetime is how long users have to enter their code after
+	// their password checked out.
+	LoginChallengeLifetime = 5 * time.Minute
+	// maxLoginChallengeAttempts is how many codes a user may try across all
+	// of their challenges within the lifetime of a challenge. Logging in
+	// again hands out a new challenge, but not new attempts.
+	maxLoginChallengeAttempts = 5
+)
+
+// TOTP is the authenticator app of a user. It only guards the login once the
+// user confirmed it with a code. LastUsedStep is the period of the last code
+// that was accepted, codes can't be used twice.
+type TOTP struct {
+	Secret       string
+	Confirmed    bool
+	LastUsedStep int64
+}
+
+// TOTPEnrollment is what authenticator apps need to add the account.
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type RecoveryCode struct {
+	ID   int64
+	Hash string
+}
+
+// LoginChallenge is handed out instead of a session when the password of a
+// user with two-factor authentication checks out. Its token and a code from
+// the authenticator app or a recovery code finish the login.
+type LoginChallenge struct {
+	ID        int64
+	UserID    int64
+	Token     string
+	Attempts  int64
+	CreatedAt time.Time
+}
+
+func (c LoginChallenge) ExpiresAt() time.Time {
+	return c.CreatedAt.Add(LoginChallengeLifetime)
+}
+
+func (s *UserService) IsTwoFactorEnabled(ctx context.Context, user *User) (bool, error) {
+	totp, err := s.store.GetTOTP(ctx, user.ID)
+	if err == ErrTwoFactorNotEnabled {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return totp.Confirmed, nil
+}
+
+// EnrollTOTP creates a new secret for the user's authenticator app. It only
+// takes effect once ConfirmTOTP was called with a code generated from it.
+func (s *UserService) EnrollTOTP(ctx context.Context, user *User) (TOTPEnrollment, error) {
+	enabled, err := s.IsTwoFactorEnabled(ctx, user)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	} else if enabled {
+		return TOTPEnrollment{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	if err = s.store.SaveTOTP(ctx, user.ID, secret); err != nil {
+		return TOTPEnrollment{}, err
+	}
+	return TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(AppName, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP turns on two-factor authentication when the code matches the
+// enrolled secret. It returns the recovery codes of the user, they are only
+// stored hashed and can't be shown again.
+func (s *UserService) ConfirmTOTP(ctx context.Context, user *User, code string) ([]string, error) {
+	totp, err := s.store.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	} else if totp.Confirmed {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, ok := security.ValidateTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		token := security.GenerateToken(recoveryCodeLength)
+		codes[i] = token[:recoveryCodeLength] + "-" + token[recoveryCodeLength:]
+		if hashes[i], err = security.CreateHash(token, security.DefaultHashParams); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.store.ConfirmTOTP(ctx, user.ID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns off two-factor authentication, which takes a code just
+// like logging in does.
+func (s *UserService) DisableTOTP(ctx context.Context, user *User, code string) error {
+	if err := s.verifySecondFactor(ctx, user.ID, code); err != nil {
+		return err
+	}
+	return s.store.DeleteTOTP(ctx, user.ID)
+}
+
+func (s *UserService) CreateLoginChallenge(ctx context.Context, user *User) (LoginChallenge, error) {
+	return s.store.CreateLoginChallenge(ctx, user.ID)
+}
+
+// CompleteLoginChallenge checks the code for the challenge and returns the
+// user to start a session for. Challenges are gone once they were passed or
+// expired, failed ones are kept until they expire to count against the user.
+func (s *UserService) CompleteLoginChallenge(ctx context.Context, token string, code string) (User, error) {
+	challenge, err := s.store.GetLoginChallenge(ctx, token)
+	if err != nil {
+		return User{}, err
+	}
+	if time.Now().After(challenge.ExpiresAt()) {
+		if err = s.store.DeleteLoginChallenge(ctx, challenge.ID); err != nil {
+			return User{}, err
+		}
+		return User{}, ErrLoginChallengeNotFound
+	}
+
+	// The attempt is counted before the code is checked, so that parallel
+	// requests can't try more codes than allowed
+	since := time.Now().Add(-LoginChallengeLifetime)
+	if err = s.store.UpdateLoginChallengeAttempts(ctx, challenge.ID, since, maxLoginChallengeAttempts); err != nil {
+		return User{}, err
+	}
+	if err = s.verifySecondFactor(ctx, challenge.UserID, code); err != nil {
+		return User{}, err
+	}
+
+	if err = s.store.DeleteLoginChallenge(ctx, challenge.ID); err != nil {
+		return User{}, err
+	}
+	return s.store.GetUserById(ctx, challenge.UserID)
+}
+
+func (s *UserService) DeleteExpiredLoginChallenges(ctx context.Context) error {
+	return s.store.DeleteLoginChallengesBefore(ctx, time.Now().Add(-LoginChallengeLifetime))
+}
+
+// verifySecondFactor accepts a code of the user's authenticator app that
+// wasn't used before or one of their recovery codes, which is used up by it.
+func (s *UserService) verifySecondFactor(ctx context.Context, userID int64, code string) error {
+	totp, err := s.store.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	} else if !totp.Confirmed {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == security.TOTPDigits {
+		step, ok := security.ValidateTOTP(totp.Secret, code, time.Now())
+		if !ok || step <= totp.LastUsedStep {
+			return ErrInvalidTwoFactorCode
+		}
+		return s.store.UpdateTOTPLastUsedStep(ctx, userID, step)
+	}
+
+	recoveryCodes, err := s.store.GetRecoveryCodes(ctx, userID)
+	if err != nil {
+		return err
+	}
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	for _, recoveryCode := range recoveryCodes {
+		if ok, err := security.ComparePasswordAndHash(code, recoveryCode.Hash); err == nil && ok {
+			return s.store.DeleteRecoveryCode(ctx, recoveryCode.ID)
+		}
+	}
+	return ErrInvalidTwoFactorCode
+}
@@ -25,9 +25,10 @@ type Role struct {
 }
 
 type User struct {
-	ID        int64
-	Confirmed bool
-	Role      Role
+	ID         int64
+	Confirmed  bool
+	Role       Role
+	Membership Membership
 	UserDetails
 }
 
@@ -37,6 +38,42 @@ type PasswordResetToken struct {
 	CreatedAt time.Time
 }
 
+// CalendarToken is the secret in the address of the user's meal plan feed.
+// Calendar apps can't log in, so anyone with the token can read the meal plan
+// until the user revokes it. Only its hash is stored, the Token is empty
+// unless it was just created.
+type CalendarToken struct {
+	Token     string
+	CreatedAt time.Time
+}
+
+// SessionLifetime is how long a login lasts before the user has to log in
+// again.
+const SessionLifetime = 7 * 24 * time.Hour
+
+// SessionClient is the device a session was started from, it lets users tell
+// their sessions apart.
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session is a login of the user. The token is kept in the session cookie,
+// deleting the session logs the device out. Only its hash is stored, the
+// Token is empty unless the session was just created or looked up by it.
+type Session struct {
+	ID         int64
+	UserID     int64
+	Token      string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	SessionClient
+}
+
+func (s Session) Expired() bool {
+	return time.Since(s.CreatedAt) > SessionLifetime
+}
+
 type UserRegistration struct {
 	User      *User
 	Token     string
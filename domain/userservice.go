@@ -7,6 +7,10 @@ import (
 	"github.com/wolfsblu/recipe-manager/domain/security"
 )
 
+// sessionLastSeenInterval is how often the time a session was last used at
+// is updated.
+const sessionLastSeenInterval = 5 * time.Minute
+
 type UserService struct {
 	sender NotificationSender
 	store  UserStore
@@ -24,6 +28,87 @@ func (s *UserService) ConfirmUserByToken(ctx context.Context, token string) erro
 	return s.store.ConfirmRegistration(ctx, user)
 }
 
+func (s *UserService) GetCalendarToken(ctx context.Context, user *User) (CalendarToken, error) {
+	return s.store.GetCalendarToken(ctx, user.ID)
+}
+
+// CreateCalendarToken gives the user a new address for their meal plan feed,
+// the old one stops working. The token can't be shown again afterwards.
+func (s *UserService) CreateCalendarToken(ctx context.Context, user *User) (CalendarToken, error) {
+	secret := security.GenerateToken(security.DefaultTokenLength)
+	token, err := s.store.CreateCalendarToken(ctx, user.ID, security.HashToken(secret))
+	if err != nil {
+		return CalendarToken{}, err
+	}
+	token.Token = secret
+	return token, nil
+}
+
+func (s *UserService) DeleteCalendarToken(ctx context.Context, user *User) error {
+	return s.store.DeleteCalendarToken(ctx, user.ID)
+}
+
+func (s *UserService) GetUserByCalendarToken(ctx context.Context, token string) (User, error) {
+	return s.store.GetUserByCalendarTokenHash(ctx, security.HashToken(token))
+}
+
+func (s *UserService) CreateSession(ctx context.Context, user *User, client SessionClient) (Session, error) {
+	secret := security.GenerateToken(security.DefaultTokenLength)
+	session, err := s.store.CreateSession(ctx, user.ID, security.HashToken(secret), client)
+	if err != nil {
+		return Session{}, err
+	}
+	session.Token = secret
+	return session, nil
+}
+
+// GetUserBySession finds the user logged in with the session token and
+// notes that the session was used.
+func (s *UserService) GetUserBySession(ctx context.Context, token string) (User, Session, error) {
+	session, err := s.store.GetSessionByTokenHash(ctx, security.HashToken(token))
+	if err != nil {
+		return User{}, Session{}, err
+	} else if session.Expired() {
+		return User{}, Session{}, ErrSessionNotFound
+	}
+	session.Token = token
+
+	// Writing on every request is not needed to tell recent sessions apart
+	if time.Since(session.LastSeenAt) > sessionLastSeenInterval {
+		if err = s.store.UpdateSessionLastSeen(ctx, session.ID); err != nil {
+			return User{}, Session{}, err
+		}
+		session.LastSeenAt = time.Now()
+	}
+
+	user, err := s.store.GetUserById(ctx, session.UserID)
+	if err != nil {
+		return User{}, Session{}, err
+	}
+	return user, session, nil
+}
+
+func (s *UserService) GetSessions(ctx context.Context, user *User) ([]Session, error) {
+	return s.store.GetSessions(ctx, user.ID)
+}
+
+func (s *UserService) DeleteSession(ctx context.Context, user *User, id int64) error {
+	return s.store.DeleteSession(ctx, user.ID, id)
+}
+
+func (s *UserService) DeleteSessionByToken(ctx context.Context, token string) error {
+	return s.store.DeleteSessionByTokenHash(ctx, security.HashToken(token))
+}
+
+// DeleteOtherSessions logs the user out everywhere but in the current session.
+func (s *UserService) DeleteOtherSessions(ctx context.Context, user *User, current Session) error {
+	return s.store.DeleteSessions(ctx, user.ID, current.ID)
+}
+
+func (s *UserService) DeleteExpiredSessions(ctx context.Context) error {
+	return s.store.DeleteSessionsBefore(ctx, time.Now().Add(-SessionLifetime))
+}
+
 func (s *UserService) DeletePasswordResetsOlderThan(ctx context.Context, olderThan time.Duration) error {
 	before := time.Now().Add(-olderThan)
 	return s.store.DeleteRegistrationsBefore(ctx, before)
@@ -42,6 +127,18 @@ func (s *UserService) GetUserById(ctx context.Context, id int64) (User, error) {
 	return s.store.GetUserById(ctx, id)
 }
 
+func (s *UserService) GetNutrientTargets(ctx context.Context, user *User) ([]NutrientTarget, error) {
+	return s.store.GetNutrientTargets(ctx, user.ID)
+}
+
+// UpdateNutrientTargets replaces all daily nutrient targets of the user.
+func (s *UserService) UpdateNutrientTargets(ctx context.Context, user *User, targets []NutrientTarget) ([]NutrientTarget, error) {
+	if err := s.validateNutrientTargets(targets); err != nil {
+		return nil, err
+	}
+	return s.store.UpdateNutrientTargets(ctx, user.ID, targets)
+}
+
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (User, error) {
 	return s.store.GetUserByEmail(ctx, email)
 }
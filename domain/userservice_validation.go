@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wolfsblu/recipe-manager/domain/permissions"
+)
+
+func (s *UserService) validateNutrientTargets(targets []NutrientTarget) error {
+	seen := make(map[int64]bool, len(targets))
+	for _, target := range targets {
+		if target.Amount <= 0 || seen[target.Nutrient.ID] {
+			return ErrInvalidNutrientTargets
+		}
+		seen[target.Nutrient.ID] = true
+	}
+	return nil
+}
+
+func (s *UserService) validatePasskeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return defaultPasskeyName, nil
+	} else if utf8.RuneCountInString(name) > maxPasskeyNameLength {
+		return "", ErrInvalidPasskeyName
+	}
+	return name, nil
+}
+
+// validateAPIToken only lets tokens have scopes the user is granted, a token
+// can't do more than its user.
+func (s *UserService) validateAPIToken(user *User, name string, scopes []permissions.Slug, expiresAt *time.Time) (string, []permissions.Slug, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPITokenNameLength || len(scopes) == 0 {
+		return "", nil, ErrInvalidAPIToken
+	} else if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, ErrInvalidAPIToken
+	}
+
+	var valid []permissions.Slug
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(user.Role.Permissions, func(p Permission) bool { return p.Slug == scope }) {
+			return "", nil, ErrInvalidAPIToken
+		} else if !slices.Contains(valid, scope) {
+			valid = append(valid, scope)
+		}
+	}
+	return name, valid, nil
+}
@@ -3,5 +3,8 @@ package config
 type contextKey string
 
 const (
-	CtxKeyUser = contextKey("User")
+	CtxKeyUser     = contextKey("User")
+	CtxKeySession  = contextKey("Session")
+	CtxKeyClient   = contextKey("Client")
+	CtxKeyAPIToken = contextKey("APIToken")
 )
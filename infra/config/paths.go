@@ -1,7 +1,9 @@
 package config
 
 const (
-	APIPathPrefix    = "/api"
-	ImagesPathPrefix = "/images"
-	UploadPathPrefix = "/upload"
+	APIPathPrefix      = "/api"
+	CalendarPathPrefix = APIPathPrefix + "/calendar"
+	ImagesPathPrefix   = "/images"
+	SSOPathPrefix      = APIPathPrefix + "/sso"
+	UploadPathPrefix   = "/upload"
 )
@@ -42,6 +42,26 @@ func MustGet(key string) string {
 	return value
 }
 
+// Get returns the fallback when the variable isn't set.
+func Get(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func GetBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("env variable '%s' with value '%s' is not a boolean\n", key, value)
+	}
+	return boolValue
+}
+
 func MustGetInt(key string) int {
 	value, ok := os.LookupEnv(key)
 	if !ok {
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+)
+
+const (
+	historySize      = 256
+	subscriberBuffer = 64
+	// idleTimeout is how long the events of a list without subscribers are
+	// kept for them to resume.
+	idleTimeout = 10 * time.Minute
+)
+
+// ShoppingListBus keeps the recent events of every list in memory so that
+// subscribers can resume after a reconnect. Event IDs start at the time the
+// bus was created, which lets it tell IDs from before a restart apart. Lists
+// nobody subscribed to for a while are forgotten.
+type ShoppingListBus struct {
+	mu      sync.Mutex
+	firstID int64
+	lastID  int64
+	// forgottenID is the ID of the newest event of the forgotten lists. Lists
+	// seen again start from it, which resets their old subscribers.
+	forgottenID int64
+	sweptAt     time.Time
+	lists       map[int64]*listEvents
+	subscribers map[int64]map[chan domain.ShoppingListEvent]struct{}
+}
+
+type listEvents struct {
+	history []domain.ShoppingListEvent
+	// evictedID is the ID of the newest event dropped from the history
+	evictedID int64
+	// touched is when the list last had an event or a subscriber
+	touched time.Time
+}
+
+func NewShoppingListBus() *ShoppingListBus {
+	start := time.Now().UnixMicro()
+	return &ShoppingListBus{
+		firstID:     start,
+		lastID:      start,
+		sweptAt:     time.Now(),
+		lists:       make(map[int64]*listEvents),
+		subscribers: make(map[int64]map[chan domain.ShoppingListEvent]struct{}),
+	}
+}
+
+func (b *ShoppingListBus) Publish(event domain.ShoppingListEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+
+	now := time.Now()
+	b.forgetIdleLists(now)
+	list := b.list(event.ListID)
+	list.touched = now
+	if len(list.history) == historySize {
+		list.evictedID = list.history[0].ID
+		list.history = list.history[1:]
+	}
+	list.history = append(list.history, event)
+
+	for ch := range b.subscribers[event.ListID] {
+		select {
+		case ch <- event:
+		default:
+			// The subscriber can't keep up, closing the stream makes it
+			// reconnect and catch up from its last event.
+			b.unsubscribe(event.ListID, ch)
+		}
+	}
+}
+
+func (b *ShoppingListBus) Subscribe(ctx context.Context, listID int64, lastEventID *int64) <-chan domain.ShoppingListEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.forgetIdleLists(now)
+	b.list(listID).touched = now
+	missed := b.missedEvents(listID, lastEventID)
+	ch := make(chan domain.ShoppingListEvent, max(subscriberBuffer, len(missed)))
+	for _, event := range missed {
+		ch <- event
+	}
+
+	if b.subscribers[listID] == nil {
+		b.subscribers[listID] = make(map[chan domain.ShoppingListEvent]struct{})
+	}
+	b.subscribers[listID][ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.unsubscribe(listID, ch)
+	}()
+	return ch
+}
+
+// Remove ends the subscriptions to a deleted list and forgets its events.
+func (b *ShoppingListBus) Remove(listID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[listID] {
+		b.unsubscribe(listID, ch)
+	}
+	if list, ok := b.lists[listID]; ok {
+		b.forget(listID, list)
+	}
+}
+
+// missedEvents returns the events after lastEventID, or a reset if some of
+// them are no longer in the history.
+func (b *ShoppingListBus) missedEvents(listID int64, lastEventID *int64) []domain.ShoppingListEvent {
+	if lastEventID == nil {
+		return nil
+	}
+	list := b.list(listID)
+	if *lastEventID < b.firstID || *lastEventID < list.evictedID || *lastEventID > b.lastID {
+		return []domain.ShoppingListEvent{{ID: b.lastID, ListID: listID, Type: domain.ShoppingListEventReset}}
+	}
+
+	var missed []domain.ShoppingListEvent
+	for _, event := range list.history {
+		if event.ID > *lastEventID {
+			missed = append(missed, event)
+		}
+	}
+	return missed
+}
+
+func (b *ShoppingListBus) list(listID int64) *listEvents {
+	list, ok := b.lists[listID]
+	if !ok {
+		list = &listEvents{evictedID: b.forgottenID, touched: time.Now()}
+		b.lists[listID] = list
+	}
+	return list
+}
+
+// forgetIdleLists drops the lists without subscribers that were untouched
+// for the idle timeout. The lists are only checked once per timeout.
+func (b *ShoppingListBus) forgetIdleLists(now time.Time) {
+	if now.Sub(b.sweptAt) < idleTimeout {
+		return
+	}
+	b.sweptAt = now
+	for listID, list := range b.lists {
+		if b.subscribers[listID] == nil && now.Sub(list.touched) >= idleTimeout {
+			b.forget(listID, list)
+		}
+	}
+}
+
+func (b *ShoppingListBus) forget(listID int64, list *listEvents) {
+	newestID := list.evictedID
+	if len(list.history) > 0 {
+		newestID = list.history[len(list.history)-1].ID
+	}
+	b.forgottenID = max(b.forgottenID, newestID)
+	delete(b.lists, listID)
+}
+
+func (b *ShoppingListBus) unsubscribe(listID int64, ch chan domain.ShoppingListEvent) {
+	subscribers := b.subscribers[listID]
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+	delete(subscribers, ch)
+	if list, ok := b.lists[listID]; ok {
+		list.touched = time.Now()
+	}
+	if len(subscribers) == 0 {
+		delete(b.subscribers, listID)
+	}
+	close(ch)
+}
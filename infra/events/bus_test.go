@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+)
+
+func TestShoppingListBusResume(t *testing.T) {
+	bus := NewShoppingListBus()
+	for i := int64(1); i <= 3; i++ {
+		bus.Publish(domain.ShoppingListEvent{ListID: 1, Type: domain.ShoppingListItemDeleted, ItemID: i})
+	}
+	bus.Publish(domain.ShoppingListEvent{ListID: 2, Type: domain.ShoppingListItemDeleted, ItemID: 4})
+	first := bus.firstID
+
+	tests := []struct {
+		name        string
+		lastEventID *int64
+		want        []domain.ShoppingListEventType
+		wantItemIDs []int64
+	}{
+		{name: "New subscriber"},
+		{name: "Missed events", lastEventID: ptr(first + 1), want: []domain.ShoppingListEventType{domain.ShoppingListItemDeleted, domain.ShoppingListItemDeleted}, wantItemIDs: []int64{2, 3}},
+		{name: "Up to date", lastEventID: ptr(first + 4)},
+		{name: "Before restart", lastEventID: ptr(first - 10), want: []domain.ShoppingListEventType{domain.ShoppingListEventReset}, wantItemIDs: []int64{0}},
+		{name: "Unknown event", lastEventID: ptr(first + 100), want: []domain.ShoppingListEventType{domain.ShoppingListEventReset}, wantItemIDs: []int64{0}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch := bus.Subscribe(ctx, 1, tc.lastEventID)
+			if len(ch) != len(tc.want) {
+				t.Fatalf("Subscribe() replayed %d events, want %d", len(ch), len(tc.want))
+			}
+			for i := range tc.want {
+				event := <-ch
+				if event.Type != tc.want[i] || event.ItemID != tc.wantItemIDs[i] {
+					t.Errorf("Subscribe() event %d = %s %d, want %s %d", i, event.Type, event.ItemID, tc.want[i], tc.wantItemIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShoppingListBusEvictedHistory(t *testing.T) {
+	bus := NewShoppingListBus()
+	for range historySize + 1 {
+		bus.Publish(domain.ShoppingListEvent{ListID: 1, Type: domain.ShoppingListItemCreated})
+	}
+
+	ch := bus.Subscribe(context.Background(), 1, ptr(bus.firstID))
+	if event := <-ch; event.Type != domain.ShoppingListEventReset || event.ID != bus.lastID {
+		t.Errorf("Subscribe() = %s %d, want reset %d", event.Type, event.ID, bus.lastID)
+	}
+}
+
+func TestShoppingListBusPublish(t *testing.T) {
+	bus := NewShoppingListBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := bus.Subscribe(ctx, 1, nil)
+	other := bus.Subscribe(ctx, 2, nil)
+
+	bus.Publish(domain.ShoppingListEvent{ListID: 1, Type: domain.ShoppingListItemCreated})
+	if event := <-ch; event.Type != domain.ShoppingListItemCreated || event.ID != bus.firstID+1 {
+		t.Errorf("Publish() delivered %s %d, want %s %d", event.Type, event.ID, domain.ShoppingListItemCreated, bus.firstID+1)
+	}
+	if len(other) != 0 {
+		t.Errorf("Publish() delivered %d events to another list", len(other))
+	}
+
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Error("Subscribe() channel still open after the context is done")
+	}
+}
+
+func TestShoppingListBusForgetsIdleLists(t *testing.T) {
+	bus := NewShoppingListBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus.Subscribe(ctx, 1, nil)
+	bus.Publish(domain.ShoppingListEvent{ListID: 1, Type: domain.ShoppingListItemCreated})
+	bus.Publish(domain.ShoppingListEvent{ListID: 2, Type: domain.ShoppingListItemCreated})
+	seen := bus.lastID
+
+	idle := time.Now().Add(-idleTimeout)
+	bus.sweptAt = idle
+	for _, list := range bus.lists {
+		list.touched = idle
+	}
+	bus.Publish(domain.ShoppingListEvent{ListID: 3, Type: domain.ShoppingListItemCreated})
+	if _, ok := bus.lists[1]; !ok {
+		t.Error("Publish() forgot a list with subscribers")
+	}
+	if _, ok := bus.lists[2]; ok {
+		t.Error("Publish() kept an idle list without subscribers")
+	}
+
+	// Subscribers of the forgotten list can't tell what they missed
+	ch := bus.Subscribe(ctx, 2, ptr(seen-1))
+	if event := <-ch; event.Type != domain.ShoppingListEventReset {
+		t.Errorf("Subscribe() to a forgotten list = %s, want reset", event.Type)
+	}
+	if ch = bus.Subscribe(ctx, 2, ptr(seen)); len(ch) != 0 {
+		t.Errorf("Subscribe() to a forgotten list after its last event replayed %d events, want 0", len(ch))
+	}
+}
+
+func TestShoppingListBusRemove(t *testing.T) {
+	bus := NewShoppingListBus()
+	ch := bus.Subscribe(context.Background(), 1, nil)
+	bus.Publish(domain.ShoppingListEvent{ListID: 1, Type: domain.ShoppingListItemCreated})
+
+	bus.Remove(1)
+	<-ch
+	if _, ok := <-ch; ok {
+		t.Error("Subscribe() channel still open after the list was removed")
+	}
+	if len(bus.lists) != 0 || len(bus.subscribers) != 0 {
+		t.Errorf("Remove() kept %d lists and %d subscribed lists", len(bus.lists), len(bus.subscribers))
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/ical"
+)
+
+const (
+	calendarDaysBefore  = 28
+	calendarDaysAfter   = 91
+	defaultMealDuration = 30 * time.Minute
+)
+
+// CalendarHandler serves the meal plan of a user as an iCalendar feed. Calendar
+// apps can't log in, so the user is found by the secret token in the address
+// instead of the session cookie.
+type CalendarHandler struct {
+	baseURL string
+	Recipes *domain.RecipeService
+	Users   *domain.UserService
+}
+
+func NewCalendarHandler(recipes *domain.RecipeService, users *domain.UserService) *CalendarHandler {
+	return &CalendarHandler{
+		baseURL: strings.TrimSuffix(env.MustGet("BASE_URL"), "/"),
+		Recipes: recipes,
+		Users:   users,
+	}
+}
+
+func (h *CalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutSuffix(r.PathValue("feed"), ".ics")
+	if !ok {
+		writeError(w, domain.ErrCalendarTokenNotFound)
+		return
+	}
+	user, err := h.Users.GetUserByCalendarToken(r.Context(), token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	mealPlan, err := h.Recipes.GetMealPlan(r.Context(), &user, today.AddDate(0, 0, -calendarDaysBefore), today.AddDate(0, 0, calendarDaysAfter))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	slots, err := h.Recipes.GetMealSlots(r.Context(), &user)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err = h.toCalendar(mealPlan, slots).Write(w, now); err != nil {
+		log.Println(fmt.Errorf("failed to write calendar feed: %w", err))
+	}
+}
+
+// toCalendar turns every meal into an event. Meals in a slot with a time
+// start then and last as long as the recipe takes, all others take up the
+// whole day.
+func (h *CalendarHandler) toCalendar(mealPlan []domain.MealPlan, slots []domain.MealSlot) ical.Calendar {
+	slotsByID := make(map[int64]domain.MealSlot, len(slots))
+	for _, slot := range slots {
+		slotsByID[slot.ID] = slot
+	}
+	host := h.baseURL
+	if u, err := url.Parse(h.baseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	calendar := ical.Calendar{Name: "Meal Plan"}
+	for _, day := range mealPlan {
+		for _, meal := range day.Meals {
+			event := ical.Event{
+				UID:    fmt.Sprintf("meal-plan-entry-%d@%s", meal.EntryID, host),
+				Start:  day.Date,
+				AllDay: true,
+			}
+			if meal.SlotID != nil {
+				if start, ok := slotsByID[*meal.SlotID].At(day.Date); ok {
+					event.Start, event.AllDay = start, false
+					event.Duration = defaultMealDuration
+				}
+			}
+
+			var description []string
+			if meal.Recipe != nil {
+				event.Summary = meal.Recipe.Name
+				event.URL = fmt.Sprintf("%s/recipes/%d", h.baseURL, meal.Recipe.ID)
+				if meal.Recipe.Minutes > 0 {
+					description = append(description, fmt.Sprintf("%d minutes", meal.Recipe.Minutes))
+					event.Duration = time.Duration(meal.Recipe.Minutes) * time.Minute
+				}
+			}
+			if meal.Note != nil {
+				if event.Summary == "" {
+					event.Summary = *meal.Note
+				} else {
+					description = append(description, *meal.Note)
+				}
+			}
+			if event.URL != "" {
+				description = append(description, event.URL)
+			}
+			event.Description = strings.Join(description, "\n")
+			calendar.Events = append(calendar.Events, event)
+		}
+	}
+	return calendar
+}
@@ -5,20 +5,20 @@ import (
 	"time"
 
 	"github.com/gorilla/securecookie"
+	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/infra/env"
 )
 
 const AuthCookieName = "SESSID"
 
-func createSessionCookie(userId int64) (string, error) {
-	payload, err := encryptUserId(userId)
+func createSessionCookie(session domain.Session) (string, error) {
+	payload, err := encryptSessionToken(session.Token)
 	if err != nil {
 		return "", err
 	}
-	expiry := 7 * 24 * time.Hour // One week
 	cookie := http.Cookie{
 		HttpOnly: true,
-		MaxAge:   int(expiry / time.Second),
+		MaxAge:   int(domain.SessionLifetime / time.Second),
 		Name:     AuthCookieName,
 		Path:     "/",
 		SameSite: http.SameSiteDefaultMode,
@@ -40,31 +40,31 @@ func expireSessionCookie() string {
 	return cookie.String()
 }
 
-func getUserFromSessionCookie(cookieValue string) (int64, error) {
-	return decryptUserId(cookieValue)
+func getSessionTokenFromCookie(cookieValue string) (string, error) {
+	return decryptSessionToken(cookieValue)
 }
 
-func encryptUserId(userId int64) (string, error) {
+func encryptSessionToken(token string) (string, error) {
 	var s = securecookie.New(
 		[]byte(env.MustGet("COOKIE_HASH_KEY")),
 		[]byte(env.MustGet("COOKIE_BLOCK_KEY")),
 	)
-	encoded, err := s.Encode(AuthCookieName, userId)
+	encoded, err := s.Encode(AuthCookieName, token)
 	if err != nil {
 		return "", err
 	}
 	return encoded, nil
 }
 
-func decryptUserId(cookieValue string) (int64, error) {
-	var userId int64
+func decryptSessionToken(cookieValue string) (string, error) {
+	var token string
 	var s = securecookie.New(
 		[]byte(env.MustGet("COOKIE_HASH_KEY")),
 		[]byte(env.MustGet("COOKIE_BLOCK_KEY")),
 	)
-	err := s.Decode(AuthCookieName, cookieValue, &userId)
+	err := s.Decode(AuthCookieName, cookieValue, &token)
 	if err != nil {
-		return -1, err
+		return "", err
 	}
-	return userId, nil
+	return token, nil
 }
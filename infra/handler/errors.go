@@ -22,10 +22,59 @@ var errorStatusCodeMap = map[*domain.Error]int{
 	domain.ErrDeletingPasswordResetToken: http.StatusInternalServerError,
 	domain.ErrDeletingRegistration:       http.StatusInternalServerError,
 	domain.ErrInvalidCredentials:         http.StatusUnauthorized,
+	domain.ErrInvalidIngredientLines:     http.StatusBadRequest,
+	domain.ErrInvalidIngredient:          http.StatusBadRequest,
+	domain.ErrInvalidUnit:                http.StatusBadRequest,
+	domain.ErrInvalidRecipeScale:         http.StatusBadRequest,
+	domain.ErrIncompatibleUnits:          http.StatusUnprocessableEntity,
+	domain.ErrInvalidDateRange:           http.StatusBadRequest,
+	domain.ErrInvalidNutrientTargets:     http.StatusBadRequest,
+	domain.ErrInvalidShoppingListItem:    http.StatusBadRequest,
+	domain.ErrInvalidStore:               http.StatusBadRequest,
+	domain.ErrInvalidEventStream:         http.StatusBadRequest,
+	domain.ErrInvalidShoppingListSync:    http.StatusBadRequest,
+	domain.ErrInvalidSyncToken:           http.StatusBadRequest,
+	domain.ErrShoppingListItemNotFound:   http.StatusNotFound,
+	domain.ErrMealPlanEntryNotFound:      http.StatusNotFound,
+	domain.ErrInvalidMove:                http.StatusBadRequest,
+	domain.ErrInvalidMealPlanEntry:       http.StatusBadRequest,
+	domain.ErrInvalidMealPlanGeneration:  http.StatusBadRequest,
+	domain.ErrInvalidMealPlanTemplate:    http.StatusBadRequest,
+	domain.ErrMealPlanTemplateNotFound:   http.StatusNotFound,
+	domain.ErrInvalidMealSlot:            http.StatusBadRequest,
+	domain.ErrInvalidHousehold:           http.StatusBadRequest,
+	domain.ErrHouseholdNotFound:          http.StatusNotFound,
+	domain.ErrHouseholdOwnerRequired:     http.StatusConflict,
+	domain.ErrHouseholdMemberNotFound:    http.StatusNotFound,
+	domain.ErrInvitationNotFound:         http.StatusNotFound,
+	domain.ErrCalendarTokenNotFound:      http.StatusNotFound,
+	domain.ErrSessionNotFound:            http.StatusNotFound,
+	domain.ErrTwoFactorNotEnabled:        http.StatusBadRequest,
+	domain.ErrTwoFactorAlreadyEnabled:    http.StatusConflict,
+	domain.ErrInvalidTwoFactorCode:       http.StatusUnauthorized,
+	domain.ErrLoginChallengeNotFound:     http.StatusUnauthorized,
+	domain.ErrPasskeyNotFound:            http.StatusNotFound,
+	domain.ErrPasskeyChallengeNotFound:   http.StatusBadRequest,
+	domain.ErrInvalidPasskey:             http.StatusBadRequest,
+	domain.ErrInvalidPasskeyName:         http.StatusBadRequest,
+	domain.ErrSSONotConfigured:           http.StatusNotFound,
+	domain.ErrSSOLoginNotFound:           http.StatusBadRequest,
+	domain.ErrSSOFailed:                  http.StatusBadGateway,
+	domain.ErrSSOEmailNotVerified:        http.StatusForbidden,
+	domain.ErrSSOAccountNotFound:         http.StatusForbidden,
+	domain.ErrAPITokenNotFound:           http.StatusNotFound,
+	domain.ErrInvalidAPIToken:            http.StatusBadRequest,
+	domain.ErrInvalidSearchQuery:         http.StatusBadRequest,
+	domain.ErrInvalidCursor:              http.StatusBadRequest,
+	domain.ErrInvalidListQuery:           http.StatusBadRequest,
+	domain.ErrInvalidImportSource:        http.StatusBadRequest,
+	domain.ErrFetchingRecipePage:         http.StatusBadGateway,
+	domain.ErrRecipeNotFoundInPage:       http.StatusUnprocessableEntity,
 	domain.ErrPasswordResetTokenNotFound: http.StatusUnauthorized,
 	domain.ErrRecipeNotFound:             http.StatusNotFound,
 	domain.ErrRegistrationNotFound:       http.StatusNotFound,
 	domain.ErrStartingTransaction:        http.StatusInternalServerError,
+	domain.ErrStoreNotFound:              http.StatusNotFound,
 	domain.ErrUnconfirmedUser:            http.StatusForbidden,
 	domain.ErrUnhandled:                  http.StatusInternalServerError,
 	domain.ErrUpdatingPassword:           http.StatusInternalServerError,
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/api"
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/handler/mapper"
+)
+
+const eventHeartbeatInterval = 30 * time.Second
+
+// ShoppingListEventHandler streams the changes to a shopping list as
+// server-sent events. It lives next to the generated API server since ogen
+// has no support for event streams.
+type ShoppingListEventHandler struct {
+	mapper   *mapper.APIMapper
+	Shopping *domain.ShoppingService
+	Users    *domain.UserService
+}
+
+type shoppingListEventData struct {
+	Type   domain.ShoppingListEventType `json:"type"`
+	ListID int64                        `json:"listId"`
+	Item   *api.ReadShoppingListItem    `json:"item,omitempty"`
+	ItemID *int64                       `json:"itemId,omitempty"`
+	Items  []api.ReadShoppingListItem   `json:"items,omitempty"`
+}
+
+func NewShoppingListEventHandler(shopping *domain.ShoppingService, users *domain.UserService) *ShoppingListEventHandler {
+	return &ShoppingListEventHandler{
+		mapper:   mapper.NewAPIMapper(env.MustGet("BASE_URL")),
+		Shopping: shopping,
+		Users:    users,
+	}
+}
+
+func (h *ShoppingListEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, err := sessionToken(r)
+	if err != nil {
+		writeError(w, domain.ErrAuthentication)
+		return
+	}
+	user, _, err := h.Users.GetUserBySession(r.Context(), token)
+	if err != nil {
+		writeError(w, domain.ErrAuthentication)
+		return
+	}
+	listID, err := strconv.ParseInt(r.PathValue("shoppingListId"), 10, 64)
+	if err != nil {
+		writeError(w, domain.ErrInvalidEventStream)
+		return
+	}
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		writeError(w, domain.ErrInvalidEventStream)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, domain.ErrUnhandled)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	events, err := h.Shopping.Subscribe(ctx, &user, listID, lastEventID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := h.writeEvent(w, event); err != nil {
+				log.Println(fmt.Errorf("failed to write shopping list event: %w", err))
+				return
+			}
+		case <-heartbeat.C:
+			// Streams outlive the request that authorized them, so they end
+			// once the session is revoked or the user lost access to the list
+			if !h.authorized(ctx, token, listID) {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (h *ShoppingListEventHandler) authorized(ctx context.Context, token string, listID int64) bool {
+	user, _, err := h.Users.GetUserBySession(ctx, token)
+	if err != nil {
+		return false
+	}
+	_, err = h.Shopping.GetByID(ctx, &user, listID)
+	return err == nil
+}
+
+func sessionToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(AuthCookieName)
+	if err != nil {
+		return "", err
+	}
+	return getSessionTokenFromCookie(cookie.Value)
+}
+
+func (h *ShoppingListEventHandler) writeEvent(w http.ResponseWriter, event domain.ShoppingListEvent) error {
+	data := shoppingListEventData{
+		Type:   event.Type,
+		ListID: event.ListID,
+	}
+	if event.Item != nil {
+		item, err := h.mapper.ToShoppingListItem(*event.Item)
+		if err != nil {
+			return err
+		}
+		data.Item = item
+	}
+	if event.Type == domain.ShoppingListItemDeleted {
+		data.ItemID = &event.ItemID
+	}
+	if event.Items != nil {
+		items, err := h.mapper.ToShoppingListItems(event.Items)
+		if err != nil {
+			return err
+		}
+		data.Items = items
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+	return err
+}
+
+// parseLastEventID reads the ID browsers send when they reconnect. Clients
+// that open a new stream can pass it as query parameter instead.
+func parseLastEventID(r *http.Request) (*int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var domainErr = domain.ErrUnhandled
+	if !errors.As(err, &domainErr) {
+		log.Println(fmt.Errorf("unhandled error: %w", err))
+		domainErr = domain.ErrUnhandled
+	}
+
+	payload, _ := json.Marshal(api.Error{Message: domainErr.Message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(mapDomainErrorToStatusCode(domainErr))
+	_, _ = w.Write(payload)
+}
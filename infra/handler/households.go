@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/wolfsblu/recipe-manager/api"
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/config"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/handler/mapper"
+)
+
+type HouseholdHandler struct {
+	mapper     *mapper.APIMapper
+	Households *domain.HouseholdService
+}
+
+func NewHouseholdHandler(service *domain.HouseholdService) *HouseholdHandler {
+	return &HouseholdHandler{
+		mapper:     mapper.NewAPIMapper(env.MustGet("BASE_URL")),
+		Households: service,
+	}
+}
+
+func (h *HouseholdHandler) GetHousehold(ctx context.Context) (*api.ReadHousehold, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	household, err := h.Households.Get(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToHousehold(household, user.ID), nil
+}
+
+func (h *HouseholdHandler) UpdateHousehold(ctx context.Context, req *api.WriteHousehold) (*api.ReadHousehold, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	household, err := h.Households.Rename(ctx, user, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToHousehold(household, user.ID), nil
+}
+
+func (h *HouseholdHandler) UpdateHouseholdMember(ctx context.Context, req *api.WriteHouseholdMember, params api.UpdateHouseholdMemberParams) (*api.ReadHousehold, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	household, err := h.Households.UpdateMember(ctx, user, domain.HouseholdMember{
+		UserID: params.UserId,
+		Role:   domain.HouseholdRole(req.Role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToHousehold(household, user.ID), nil
+}
+
+func (h *HouseholdHandler) RemoveHouseholdMember(ctx context.Context, params api.RemoveHouseholdMemberParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Households.RemoveMember(ctx, user, params.UserId)
+}
+
+func (h *HouseholdHandler) InviteHouseholdMember(ctx context.Context, req *api.WriteHouseholdInvitation) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Households.Invite(ctx, user, req.Email, domain.HouseholdRole(req.Role))
+}
+
+func (h *HouseholdHandler) AcceptHouseholdInvitation(ctx context.Context, req *api.Token) (*api.ReadHousehold, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	household, err := h.Households.AcceptInvitation(ctx, user, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToHousehold(household, user.ID), nil
+}
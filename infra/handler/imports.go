@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/wolfsblu/recipe-manager/api"
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/config"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/handler/mapper"
+)
+
+type ImportHandler struct {
+	mapper  *mapper.APIMapper
+	Imports *domain.ImportService
+}
+
+func NewImportHandler(service *domain.ImportService) *ImportHandler {
+	return &ImportHandler{
+		mapper:  mapper.NewAPIMapper(env.MustGet("BASE_URL")),
+		Imports: service,
+	}
+}
+
+func (h *ImportHandler) ImportRecipe(ctx context.Context, req *api.RecipeImportSource) (*api.RecipeImport, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	result, err := h.Imports.Import(ctx, user, h.mapper.FromRecipeImportSource(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToRecipeImport(result)
+}
@@ -1,8 +1,14 @@
 package mapper
 
 import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/wolfsblu/recipe-manager/api"
 	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/domain/permissions"
 )
 
 func (m *APIMapper) FromWriteRecipe(req *api.WriteRecipe) domain.Recipe {
@@ -75,16 +81,47 @@ func (m *APIMapper) FromWriteIngredient(req *api.WriteIngredient) domain.Ingredi
 	for i, nutrient := range req.Nutrients {
 		nutrients[i] = m.FromWriteIngredientNutrient(nutrient)
 	}
-	return domain.Ingredient{
-		Name:      req.Name,
-		Nutrients: nutrients,
+	ingredient := domain.Ingredient{
+		Name:            req.Name,
+		Density:         optFloat64(req.Density),
+		ReferenceAmount: req.ReferenceAmount.Or(domain.DefaultNutrientReferenceAmount),
+		Nutrients:       nutrients,
+	}
+	if id, ok := req.ReferenceUnitId.Get(); ok {
+		ingredient.ReferenceUnit = &domain.Unit{ID: id}
+	}
+	return ingredient
+}
+
+func (m *APIMapper) FromWriteNutrientTargets(req []api.WriteNutrientTarget) []domain.NutrientTarget {
+	targets := make([]domain.NutrientTarget, len(req))
+	for i, target := range req {
+		targets[i] = domain.NutrientTarget{
+			Nutrient: domain.Nutrient{
+				ID: target.ID,
+			},
+			Amount: target.Amount,
+		}
 	}
+	return targets
 }
 
 func (m *APIMapper) FromWriteUnit(req *api.WriteUnit) domain.Unit {
 	return domain.Unit{
-		Name:   req.Name,
-		Symbol: FromOptNilString(req.Symbol),
+		Name:      req.Name,
+		Symbol:    FromOptNilString(req.Symbol),
+		Dimension: domain.UnitDimension(req.Dimension.Or("")),
+		System:    domain.UnitSystem(req.System.Or("")),
+		Factor:    req.Factor.Or(0),
+	}
+}
+
+func (m *APIMapper) FromConvertUnitsParams(params api.ConvertUnitsParams) domain.UnitConversion {
+	return domain.UnitConversion{
+		Amount:       params.Amount,
+		FromUnitID:   params.From,
+		ToUnitID:     params.To,
+		IngredientID: optInt64(params.Ingredient),
 	}
 }
 
@@ -97,9 +134,289 @@ func FromOptNilString(s api.OptNilString) *string {
 
 func (m *APIMapper) FromWriteShoppingListItem(req *api.WriteShoppingListItem) domain.ShoppingListItem {
 	return domain.ShoppingListItem{
-		Ingredient: req.Ingredient,
-		Quantity:   FromOptNilString(req.Quantity),
-		Unit:       FromOptNilString(req.Unit),
-		Done:       req.Done.Value,
+		Ingredient:   req.Ingredient.Or(""),
+		Quantity:     FromOptNilString(req.Quantity),
+		Unit:         FromOptNilString(req.Unit),
+		IngredientID: optInt64(req.IngredientId),
+		UnitID:       optInt64(req.UnitId),
+		Amount:       optFloat64(req.Amount),
+		Done:         req.Done.Value,
+	}
+}
+
+func (m *APIMapper) FromWriteShoppingListSync(req *api.WriteShoppingListSync) domain.ShoppingListSync {
+	sync := domain.ShoppingListSync{
+		SyncToken:  req.SyncToken.Or(""),
+		Operations: make([]domain.ShoppingListOperation, len(req.Operations)),
 	}
+	for i, operation := range req.Operations {
+		sync.Operations[i] = domain.ShoppingListOperation{
+			Type:        domain.ShoppingListOperationType(operation.Type),
+			Item:        fromShoppingListItemRef(operation.Item.Or(api.ShoppingListItemRef{})),
+			BaseVersion: operation.BaseVersion.Or(0),
+			Timestamp:   operation.Timestamp,
+			Done:        operation.Done.Or(false),
+			Order:       make([]domain.ShoppingListItemRef, len(operation.Order)),
+		}
+		if values, ok := operation.Values.Get(); ok {
+			sync.Operations[i].Values = m.FromWriteShoppingListItem(&values)
+		}
+		for j, ref := range operation.Order {
+			sync.Operations[i].Order[j] = fromShoppingListItemRef(ref)
+		}
+	}
+	return sync
+}
+
+func fromShoppingListItemRef(ref api.ShoppingListItemRef) domain.ShoppingListItemRef {
+	result := domain.ShoppingListItemRef{ID: optInt64(ref.ID)}
+	if clientID, ok := ref.ClientId.Get(); ok {
+		result.ClientID = &clientID
+	}
+	return result
+}
+
+func (m *APIMapper) FromWriteStore(req *api.WriteStore) domain.Store {
+	store := domain.Store{
+		Name:     req.Name,
+		Sections: make([]domain.StoreSection, len(req.Sections)),
+	}
+	for i, section := range req.Sections {
+		store.Sections[i] = domain.StoreSection{
+			ID:            section.ID.Or(0),
+			Name:          section.Name,
+			IngredientIDs: section.IngredientIds,
+		}
+	}
+	return store
+}
+
+func (m *APIMapper) FromWriteMealPlan(req *api.WriteMealPlan) domain.MealPlanEntry {
+	entry := m.FromWriteMealPlanEntry(&api.WriteMealPlanEntry{
+		RecipeId: req.RecipeId,
+		SlotId:   req.SlotId,
+		Note:     req.Note,
+		Servings: req.Servings,
+	})
+	entry.Date = req.Date
+	return entry
+}
+
+func (m *APIMapper) FromWriteMealPlanEntry(req *api.WriteMealPlanEntry) domain.MealPlanEntry {
+	entry := domain.MealPlanEntry{
+		RecipeID: optInt64(req.RecipeId),
+		SlotID:   optInt64(req.SlotId),
+		Servings: optInt64(req.Servings),
+	}
+	if note, ok := req.Note.Get(); ok {
+		entry.Note = &note
+	}
+	return entry
+}
+
+func (m *APIMapper) FromWriteMealSlots(req *api.WriteMealSlots) []domain.MealSlot {
+	slots := make([]domain.MealSlot, len(req.Slots))
+	for i, slot := range req.Slots {
+		slots[i] = domain.MealSlot{
+			ID:   slot.ID.Or(0),
+			Name: slot.Name,
+			Time: slot.Time.Or(""),
+		}
+	}
+	return slots
+}
+
+func (m *APIMapper) FromWriteMealPlanShopping(req *api.WriteMealPlanShopping) domain.MealPlanShopping {
+	return domain.MealPlanShopping{
+		From:   req.From,
+		Until:  req.Until,
+		ListID: optInt64(req.ShoppingListId),
+		Name:   req.Name.Or(domain.DefaultShoppingListName),
+	}
+}
+
+func (m *APIMapper) FromWriteMealPlanEntries(req *api.WriteMealPlanEntries) []domain.MealPlanEntry {
+	entries := make([]domain.MealPlanEntry, len(req.Entries))
+	for i := range req.Entries {
+		entries[i] = m.FromWriteMealPlan(&req.Entries[i])
+	}
+	return entries
+}
+
+func (m *APIMapper) FromWriteMealPlanTemplate(req *api.WriteMealPlanTemplate) domain.MealPlanTemplate {
+	template := domain.MealPlanTemplate{
+		Name:      req.Name,
+		Recurring: req.Recurring.Or(false),
+		Entries:   make([]domain.MealPlanTemplateEntry, len(req.Entries)),
+	}
+	for i, entry := range req.Entries {
+		template.Entries[i] = domain.MealPlanTemplateEntry{
+			ID:       entry.ID.Or(0),
+			Weekday:  fromWeekday(entry.Weekday),
+			SlotID:   optInt64(entry.SlotId),
+			RecipeID: optInt64(entry.RecipeId),
+			Servings: optInt64(entry.Servings),
+		}
+		if note, ok := entry.Note.Get(); ok {
+			template.Entries[i].Note = &note
+		}
+	}
+	return template
+}
+
+func (m *APIMapper) FromWriteMealPlanGeneration(req *api.WriteMealPlanGeneration) domain.MealPlanGeneration {
+	return domain.MealPlanGeneration{
+		From:                req.From,
+		Until:               req.Until,
+		SlotID:              optInt64(req.SlotId),
+		Servings:            optInt64(req.Servings),
+		IncludeTagIDs:       req.IncludeTags,
+		ExcludeTagIDs:       req.ExcludeTags,
+		WeekdayMaxMinutes:   optInt64(req.WeekdayMaxMinutes),
+		NoRepeatDays:        req.NoRepeatDays.Or(0),
+		MaxIngredients:      optInt64(req.MaxIngredients),
+		UseNutritionTargets: req.UseNutritionTargets.Or(false),
+		Seed:                optInt64(req.Seed),
+	}
+}
+
+func (m *APIMapper) FromRecipeListParams(params api.BrowseRecipesParams) domain.RecipeListQuery {
+	return domain.RecipeListQuery{
+		Filter: domain.RecipeFilter{
+			TagIDs:               params.Tags,
+			MaxMinutes:           optInt64(params.MaxMinutes),
+			MinServings:          optInt64(params.MinServings),
+			MaxServings:          optInt64(params.MaxServings),
+			IncludeIngredientIDs: params.IncludeIngredients,
+			ExcludeIngredientIDs: params.ExcludeIngredients,
+		},
+		SortBy: domain.RecipeSortKey(params.Sort.Or("")),
+		Order:  domain.SortOrder(params.Order.Or("")),
+		Cursor: params.Cursor.Or(""),
+		Limit:  params.Limit.Or(0),
+	}
+}
+
+func (m *APIMapper) FromRecipeScaleParams(params api.GetRecipeByIdParams) domain.RecipeScale {
+	return domain.RecipeScale{
+		Servings: optInt64(params.Servings),
+		Factor:   optFloat64(params.Scale),
+		System:   domain.UnitSystem(params.Units.Or("")),
+	}
+}
+
+func fromWeekday(weekday api.Weekday) time.Weekday {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), string(weekday)) {
+			return day
+		}
+	}
+	return -1
+}
+
+func optInt64(value api.OptInt64) *int64 {
+	if v, ok := value.Get(); ok {
+		return &v
+	}
+	return nil
+}
+
+func optFloat64(value api.OptFloat64) *float64 {
+	if v, ok := value.Get(); ok {
+		return &v
+	}
+	return nil
+}
+
+func optDateTime(value api.OptDateTime) *time.Time {
+	if v, ok := value.Get(); ok {
+		return &v
+	}
+	return nil
+}
+
+func (m *APIMapper) FromWriteAPIToken(req *api.WriteAPIToken) (string, []permissions.Slug, *time.Time) {
+	scopes := make([]permissions.Slug, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = permissions.Slug(scope)
+	}
+	return req.Name, scopes, optDateTime(req.ExpiresAt)
+}
+
+func (m *APIMapper) FromRecipeImportSource(req *api.RecipeImportSource) domain.RecipeImportSource {
+	var source domain.RecipeImportSource
+	if u, ok := req.URL.Get(); ok {
+		source.URL = &u
+	}
+	if u, ok := req.BaseUrl.Get(); ok {
+		source.BaseURL = &u
+	}
+	if html, ok := req.HTML.Get(); ok {
+		source.HTML = []byte(html)
+	}
+	return source
+}
+
+func (m *APIMapper) FromPasskeyAttestation(req api.PasskeyAttestation) (*protocol.ParsedCredentialCreationData, error) {
+	response := protocol.CredentialCreationResponse{
+		PublicKeyCredential: m.fromPublicKeyCredential(req.ID),
+	}
+	var err error
+	if response.AttestationResponse.ClientDataJSON, err = decodeBase64URL(req.Response.ClientDataJSON); err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+	if response.AttestationResponse.AttestationObject, err = decodeBase64URL(req.Response.AttestationObject); err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+	parsed, err := response.Parse()
+	if err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+	return parsed, nil
+}
+
+func (m *APIMapper) FromPasskeyAssertion(req api.PasskeyAssertion) (*protocol.ParsedCredentialAssertionData, error) {
+	response := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: m.fromPublicKeyCredential(req.ID),
+	}
+	fields := []struct {
+		value string
+		dst   *protocol.URLEncodedBase64
+	}{
+		{req.Response.ClientDataJSON, &response.AssertionResponse.ClientDataJSON},
+		{req.Response.AuthenticatorData, &response.AssertionResponse.AuthenticatorData},
+		{req.Response.Signature, &response.AssertionResponse.Signature},
+		{req.Response.UserHandle.Or(""), &response.AssertionResponse.UserHandle},
+	}
+	for _, field := range fields {
+		value, err := decodeBase64URL(field.value)
+		if err != nil {
+			return nil, domain.ErrInvalidCredentials
+		}
+		*field.dst = value
+	}
+	parsed, err := response.Parse()
+	if err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	return parsed, nil
+}
+
+// fromPublicKeyCredential fills in what the browser sends about every
+// credential. Only the ID is part of the API, the raw ID is the same bytes.
+func (m *APIMapper) fromPublicKeyCredential(id string) protocol.PublicKeyCredential {
+	rawID, _ := decodeBase64URL(id)
+	return protocol.PublicKeyCredential{
+		Credential: protocol.Credential{
+			ID:   strings.TrimRight(id, "="),
+			Type: string(protocol.PublicKeyCredentialType),
+		},
+		RawID: rawID,
+	}
+}
+
+// decodeBase64URL decodes the binary values of WebAuthn JSON, which leave out
+// the padding.
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
 }
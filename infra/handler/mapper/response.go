@@ -1,11 +1,15 @@
 package mapper
 
 import (
+	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/wolfsblu/recipe-manager/api"
 	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/config"
 )
 
 func (m *APIMapper) ToIngredientNutrient(nutrient domain.IngredientNutrient) api.IngredientNutrient {
@@ -22,11 +26,66 @@ func (m *APIMapper) ToIngredient(ingredient domain.Ingredient) *api.Ingredient {
 	for i, nutrient := range ingredient.Nutrients {
 		nutrients[i] = m.ToIngredientNutrient(nutrient)
 	}
-	return &api.Ingredient{
-		ID:        ingredient.ID,
-		Name:      ingredient.Name,
-		Nutrients: nutrients,
+	result := &api.Ingredient{
+		ID:              ingredient.ID,
+		Name:            ingredient.Name,
+		ReferenceAmount: ingredient.ReferenceAmount,
+		Nutrients:       nutrients,
 	}
+	if ingredient.Density != nil {
+		result.Density = api.NewOptFloat64(*ingredient.Density)
+	}
+	if ingredient.ReferenceUnit != nil {
+		result.ReferenceUnitId = api.NewOptInt64(ingredient.ReferenceUnit.ID)
+	}
+	return result
+}
+
+func (m *APIMapper) ToIngredientNutrients(nutrients []domain.IngredientNutrient) []api.IngredientNutrient {
+	result := make([]api.IngredientNutrient, len(nutrients))
+	for i, nutrient := range nutrients {
+		result[i] = m.ToIngredientNutrient(nutrient)
+	}
+	return result
+}
+
+func (m *APIMapper) ToRecipeNutrition(nutrition domain.RecipeNutrition) api.RecipeNutrition {
+	ingredients := make([]api.IngredientNutrition, len(nutrition.Ingredients))
+	for i, ingredient := range nutrition.Ingredients {
+		ingredients[i] = api.IngredientNutrition{
+			IngredientId: ingredient.Ingredient.ID,
+			Name:         ingredient.Ingredient.Name,
+			Nutrients:    m.ToIngredientNutrients(ingredient.Nutrients),
+		}
+	}
+	return api.RecipeNutrition{
+		Total:       m.ToIngredientNutrients(nutrition.Total),
+		PerServing:  m.ToIngredientNutrients(nutrition.PerServing),
+		Ingredients: ingredients,
+		Warnings:    m.ToNutritionWarnings(nutrition.Warnings),
+	}
+}
+
+func (m *APIMapper) ToParsedIngredients(parsed []domain.ParsedIngredient) []api.ParsedIngredient {
+	result := make([]api.ParsedIngredient, len(parsed))
+	for i, ingredient := range parsed {
+		result[i] = api.ParsedIngredient{
+			Line:       ingredient.Line,
+			Amount:     ingredient.Amount,
+			MaxAmount:  ingredient.MaxAmount,
+			UnitText:   ingredient.UnitText,
+			Name:       ingredient.Name,
+			Note:       ingredient.Note,
+			Confidence: ingredient.Confidence,
+		}
+		if ingredient.Unit != nil {
+			result[i].Unit = api.NewOptReadUnit(*m.ToUnit(*ingredient.Unit))
+		}
+		if ingredient.Ingredient != nil {
+			result[i].Ingredient = api.NewOptIngredient(*m.ToIngredient(*ingredient.Ingredient))
+		}
+	}
+	return result
 }
 
 func (m *APIMapper) ToReadStepIngredient(ingredient domain.StepIngredient) api.ReadStepIngredient {
@@ -54,6 +113,17 @@ func (m *APIMapper) ToReadRecipeStep(step domain.RecipeStep) (api.ReadRecipeStep
 	}, nil
 }
 
+func (m *APIMapper) ToIngredientPage(page domain.Page[domain.Ingredient]) (*api.IngredientListHeaders, error) {
+	ingredients, err := m.ToIngredients(page.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &api.IngredientListHeaders{
+		XNextCursor: toOptCursor(page.NextCursor),
+		Response:    ingredients,
+	}, nil
+}
+
 func (m *APIMapper) ToIngredients(ingredients []domain.Ingredient) ([]api.Ingredient, error) {
 	result := make([]api.Ingredient, len(ingredients))
 	for i, ingredient := range ingredients {
@@ -63,21 +133,328 @@ func (m *APIMapper) ToIngredients(ingredients []domain.Ingredient) ([]api.Ingred
 }
 
 func (m *APIMapper) ToMealPlan(mealPlan domain.MealPlan) (api.ReadMealPlan, error) {
-	recipes := make([]api.ReadRecipe, len(mealPlan.Recipes))
-	for i, recipe := range mealPlan.Recipes {
-		response, err := m.ToReadRecipe(recipe)
+	recipes := make([]api.ReadRecipe, 0, len(mealPlan.Meals))
+	entries := make([]api.ReadMealPlanEntry, len(mealPlan.Meals))
+	for i, meal := range mealPlan.Meals {
+		entries[i] = api.ReadMealPlanEntry{ID: meal.EntryID}
+		if meal.SlotID != nil {
+			entries[i].SlotId = api.NewOptInt64(*meal.SlotID)
+		}
+		if meal.Note != nil {
+			entries[i].Note = api.NewOptString(*meal.Note)
+		}
+		if meal.Servings != nil {
+			entries[i].Servings = api.NewOptInt64(*meal.Servings)
+		}
+		if meal.TemplateEntryID != nil {
+			entries[i].TemplateEntryId = api.NewOptInt64(*meal.TemplateEntryID)
+		}
+		if meal.Recipe == nil {
+			continue
+		}
+		response, err := m.ToReadRecipe(*meal.Recipe)
 		if err != nil {
 			return api.ReadMealPlan{}, err
 		}
-		recipes[i] = *response
+		recipes = append(recipes, *response)
+		entries[i].RecipeId = api.NewOptInt64(meal.Recipe.ID)
+		entries[i].Recipe = api.NewOptReadRecipe(*response)
 	}
 
 	return api.ReadMealPlan{
 		Date:    mealPlan.Date.Format(time.DateOnly),
 		Recipes: recipes,
+		Entries: entries,
+	}, nil
+}
+
+func (m *APIMapper) ToMealPlanTemplate(template domain.MealPlanTemplate) *api.ReadMealPlanTemplate {
+	entries := make([]api.ReadMealPlanTemplateEntry, len(template.Entries))
+	for i, entry := range template.Entries {
+		entries[i] = api.ReadMealPlanTemplateEntry{
+			ID:      entry.ID,
+			Weekday: api.Weekday(strings.ToLower(entry.Weekday.String())),
+		}
+		if entry.SlotID != nil {
+			entries[i].SlotId = api.NewOptInt64(*entry.SlotID)
+		}
+		if entry.RecipeID != nil {
+			entries[i].RecipeId = api.NewOptInt64(*entry.RecipeID)
+		}
+		if entry.Note != nil {
+			entries[i].Note = api.NewOptString(*entry.Note)
+		}
+		if entry.Servings != nil {
+			entries[i].Servings = api.NewOptInt64(*entry.Servings)
+		}
+	}
+	return &api.ReadMealPlanTemplate{
+		ID:        template.ID,
+		Name:      template.Name,
+		Recurring: template.Recurring,
+		Entries:   entries,
+	}
+}
+
+func (m *APIMapper) ToMealPlanTemplates(templates []domain.MealPlanTemplate) []api.ReadMealPlanTemplate {
+	result := make([]api.ReadMealPlanTemplate, len(templates))
+	for i, template := range templates {
+		result[i] = *m.ToMealPlanTemplate(template)
+	}
+	return result
+}
+
+func (m *APIMapper) ToMealPlanProposal(proposal domain.MealPlanProposal) (*api.ReadMealPlanProposal, error) {
+	meals := make([]api.ProposedMeal, len(proposal.Meals))
+	for i, meal := range proposal.Meals {
+		recipe, err := m.ToReadRecipe(meal.Recipe)
+		if err != nil {
+			return nil, err
+		}
+		meals[i] = api.ProposedMeal{
+			Date:   meal.Date,
+			Recipe: *recipe,
+		}
+		if meal.SlotID != nil {
+			meals[i].SlotId = api.NewOptInt64(*meal.SlotID)
+		}
+		if meal.Servings != nil {
+			meals[i].Servings = api.NewOptInt64(*meal.Servings)
+		}
+	}
+	return &api.ReadMealPlanProposal{
+		Seed:          proposal.Seed,
+		Meals:         meals,
+		UnfilledDates: proposal.UnfilledDates,
 	}, nil
 }
 
+func (m *APIMapper) ToMealSlots(slots []domain.MealSlot) []api.MealSlot {
+	result := make([]api.MealSlot, len(slots))
+	for i, slot := range slots {
+		result[i] = api.MealSlot{
+			ID:   slot.ID,
+			Name: slot.Name,
+		}
+		if slot.Time != "" {
+			result[i].Time = api.NewOptString(slot.Time)
+		}
+	}
+	return result
+}
+
+// ToCalendarFeed only has the address of a feed that was just created, the
+// token in it isn't known otherwise.
+func (m *APIMapper) ToCalendarFeed(token domain.CalendarToken) (*api.CalendarFeed, error) {
+	feed := &api.CalendarFeed{CreatedAt: token.CreatedAt}
+	if token.Token == "" {
+		return feed, nil
+	}
+	feedURL, err := url.Parse(fmt.Sprintf("%s%s/%s.ics", strings.TrimSuffix(m.baseURL, "/"), config.CalendarPathPrefix, token.Token))
+	if err != nil {
+		return nil, err
+	}
+	feed.URL = api.NewOptURI(*feedURL)
+	return feed, nil
+}
+
+func (m *APIMapper) ToSessions(sessions []domain.Session, current *domain.Session) []api.Session {
+	result := make([]api.Session, len(sessions))
+	for i, session := range sessions {
+		result[i] = api.Session{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    current != nil && session.ID == current.ID,
+		}
+	}
+	return result
+}
+
+func (m *APIMapper) ToPasskeys(passkeys []domain.Passkey) []api.Passkey {
+	result := make([]api.Passkey, len(passkeys))
+	for i, passkey := range passkeys {
+		result[i] = m.ToPasskey(passkey)
+	}
+	return result
+}
+
+func (m *APIMapper) ToPasskey(passkey domain.Passkey) api.Passkey {
+	result := api.Passkey{
+		ID:        passkey.ID,
+		Name:      passkey.Name,
+		CreatedAt: passkey.CreatedAt,
+	}
+	if passkey.LastUsedAt != nil {
+		result.LastUsedAt = api.NewOptDateTime(*passkey.LastUsedAt)
+	}
+	return result
+}
+
+func (m *APIMapper) ToAPITokens(tokens []domain.APIToken) []api.APIToken {
+	result := make([]api.APIToken, len(tokens))
+	for i, token := range tokens {
+		result[i] = m.ToAPIToken(token)
+	}
+	return result
+}
+
+func (m *APIMapper) ToAPIToken(token domain.APIToken) api.APIToken {
+	result := api.APIToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    make([]api.PermissionSlug, len(token.Scopes)),
+		CreatedAt: token.CreatedAt,
+	}
+	for i, scope := range token.Scopes {
+		result.Scopes[i] = api.PermissionSlug(scope)
+	}
+	if token.ExpiresAt != nil {
+		result.ExpiresAt = api.NewOptDateTime(*token.ExpiresAt)
+	}
+	if token.LastUsedAt != nil {
+		result.LastUsedAt = api.NewOptDateTime(*token.LastUsedAt)
+	}
+	return result
+}
+
+func (m *APIMapper) ToNewAPIToken(token domain.APIToken, secret string) *api.NewAPIToken {
+	result := m.ToAPIToken(token)
+	return &api.NewAPIToken{
+		ID:         result.ID,
+		Name:       result.Name,
+		Scopes:     result.Scopes,
+		CreatedAt:  result.CreatedAt,
+		ExpiresAt:  result.ExpiresAt,
+		LastUsedAt: result.LastUsedAt,
+		Token:      secret,
+	}
+}
+
+func (m *APIMapper) ToPasskeyCreationOptions(options domain.PasskeyCreationOptions) *api.PasskeyCreationOptions {
+	publicKey := options.PublicKey
+	params := make([]api.PublicKeyCredentialCreationOptionsPubKeyCredParamsItem, len(publicKey.Parameters))
+	for i, param := range publicKey.Parameters {
+		params[i] = api.PublicKeyCredentialCreationOptionsPubKeyCredParamsItem{
+			Type: string(param.Type),
+			Alg:  int64(param.Algorithm),
+		}
+	}
+	userHandle, _ := publicKey.User.ID.(protocol.URLEncodedBase64)
+	return &api.PasskeyCreationOptions{
+		Token: options.Token,
+		PublicKey: api.PublicKeyCredentialCreationOptions{
+			Rp: api.PublicKeyCredentialCreationOptionsRp{
+				ID:   publicKey.RelyingParty.ID,
+				Name: publicKey.RelyingParty.Name,
+			},
+			User: api.PublicKeyCredentialCreationOptionsUser{
+				ID:          userHandle.String(),
+				Name:        publicKey.User.Name,
+				DisplayName: publicKey.User.DisplayName,
+			},
+			Challenge:          publicKey.Challenge.String(),
+			PubKeyCredParams:   params,
+			Timeout:            int64(publicKey.Timeout),
+			ExcludeCredentials: m.toPublicKeyCredentialDescriptors(publicKey.CredentialExcludeList),
+			AuthenticatorSelection: api.PublicKeyCredentialCreationOptionsAuthenticatorSelection{
+				ResidentKey:        string(publicKey.AuthenticatorSelection.ResidentKey),
+				RequireResidentKey: publicKey.AuthenticatorSelection.RequireResidentKey != nil && *publicKey.AuthenticatorSelection.RequireResidentKey,
+				UserVerification:   string(publicKey.AuthenticatorSelection.UserVerification),
+			},
+			Attestation: string(publicKey.Attestation),
+		},
+	}
+}
+
+func (m *APIMapper) ToPasskeyRequestOptions(options domain.PasskeyRequestOptions) *api.PasskeyRequestOptions {
+	publicKey := options.PublicKey
+	return &api.PasskeyRequestOptions{
+		Token: options.Token,
+		PublicKey: api.PublicKeyCredentialRequestOptions{
+			Challenge:        publicKey.Challenge.String(),
+			RpId:             publicKey.RelyingPartyID,
+			Timeout:          int64(publicKey.Timeout),
+			UserVerification: string(publicKey.UserVerification),
+			AllowCredentials: m.toPublicKeyCredentialDescriptors(publicKey.AllowedCredentials),
+		},
+	}
+}
+
+func (m *APIMapper) toPublicKeyCredentialDescriptors(descriptors []protocol.CredentialDescriptor) []api.PublicKeyCredentialDescriptor {
+	result := make([]api.PublicKeyCredentialDescriptor, len(descriptors))
+	for i, descriptor := range descriptors {
+		result[i] = api.PublicKeyCredentialDescriptor{
+			Type: string(descriptor.Type),
+			ID:   descriptor.CredentialID.String(),
+		}
+	}
+	return result
+}
+
+func (m *APIMapper) ToNutrientTargets(targets []domain.NutrientTarget) []api.NutrientTarget {
+	result := make([]api.NutrientTarget, len(targets))
+	for i, target := range targets {
+		result[i] = api.NutrientTarget{
+			ID:     target.Nutrient.ID,
+			Name:   target.Nutrient.Name,
+			Unit:   target.Nutrient.Unit,
+			Amount: target.Amount,
+		}
+	}
+	return result
+}
+
+func (m *APIMapper) ToNutrientProgress(progress []domain.NutrientProgress) []api.NutrientProgress {
+	result := make([]api.NutrientProgress, len(progress))
+	for i, p := range progress {
+		result[i] = api.NutrientProgress{
+			ID:      p.Nutrient.ID,
+			Name:    p.Nutrient.Name,
+			Unit:    p.Nutrient.Unit,
+			Amount:  p.Amount,
+			Target:  p.Target,
+			Percent: p.Percent,
+		}
+	}
+	return result
+}
+
+func (m *APIMapper) ToNutritionWarnings(warnings []domain.NutritionWarning) []api.NutritionWarning {
+	result := make([]api.NutritionWarning, len(warnings))
+	for i, warning := range warnings {
+		result[i] = api.NutritionWarning{
+			IngredientId: warning.Ingredient.ID,
+			Name:         warning.Ingredient.Name,
+			UnitId:       warning.Unit.ID,
+			Reason:       api.NutritionWarningReason(warning.Reason),
+		}
+	}
+	return result
+}
+
+func (m *APIMapper) ToMealPlanNutrition(nutrition domain.MealPlanNutrition) *api.MealPlanNutrition {
+	days := make([]api.DailyNutrition, len(nutrition.Days))
+	for i, day := range nutrition.Days {
+		days[i] = api.DailyNutrition{
+			Date:      day.Date,
+			Nutrients: m.ToIngredientNutrients(day.Nutrients),
+			Targets:   m.ToNutrientProgress(day.Targets),
+			Warnings:  m.ToNutritionWarnings(day.Warnings),
+		}
+	}
+	return &api.MealPlanNutrition{
+		From:         nutrition.From,
+		Until:        nutrition.Until,
+		Days:         days,
+		Total:        m.ToIngredientNutrients(nutrition.Total),
+		DailyAverage: m.ToIngredientNutrients(nutrition.DailyAverage),
+		Targets:      m.ToNutrientProgress(nutrition.Targets),
+	}
+}
+
 func (m *APIMapper) ToMealPlans(mealPlans []domain.MealPlan) ([]api.ReadMealPlan, error) {
 	result := make([]api.ReadMealPlan, len(mealPlans))
 	for i, mealPlan := range mealPlans {
@@ -115,6 +492,7 @@ func (m *APIMapper) ToReadRecipe(recipe domain.Recipe) (*api.ReadRecipe, error)
 		Images:      images,
 		Tags:        tags,
 		Steps:       steps,
+		Nutrition:   m.ToRecipeNutrition(recipe.Nutrition()),
 	}, nil
 }
 
@@ -130,12 +508,48 @@ func (m *APIMapper) ToRecipes(recipes []domain.Recipe) ([]api.ReadRecipe, error)
 	return result, nil
 }
 
+func (m *APIMapper) ToRecipePage(page domain.Page[domain.Recipe]) (*api.RecipeListHeaders, error) {
+	recipes, err := m.ToRecipes(page.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RecipeListHeaders{
+		XNextCursor: toOptCursor(page.NextCursor),
+		Response:    recipes,
+	}, nil
+}
+
+func (m *APIMapper) ToRecipeSearchResults(results []domain.RecipeSearchResult) ([]api.RecipeSearchResult, error) {
+	mapped := make([]api.RecipeSearchResult, len(results))
+	for i, result := range results {
+		recipe, err := m.ToReadRecipe(result.Recipe)
+		if err != nil {
+			return nil, err
+		}
+		mapped[i] = api.RecipeSearchResult{
+			Recipe:          *recipe,
+			HighlightedName: result.HighlightedName,
+			Snippet:         result.Snippet,
+			Rank:            result.Rank,
+		}
+	}
+	return mapped, nil
+}
+
 func (m *APIMapper) ToUnit(unit domain.Unit) *api.ReadUnit {
-	return &api.ReadUnit{
+	result := &api.ReadUnit{
 		ID:     unit.ID,
 		Name:   unit.Name,
 		Symbol: ToNilString(unit.Symbol),
 	}
+	if unit.Convertible() {
+		result.Dimension = api.NewOptUnitDimension(api.UnitDimension(unit.Dimension))
+		result.Factor = api.NewOptFloat64(unit.Factor)
+	}
+	if unit.System != "" {
+		result.System = api.NewOptUnitSystem(api.UnitSystem(unit.System))
+	}
+	return result
 }
 
 func (m *APIMapper) ToUnits(units []domain.Unit) []api.ReadUnit {
@@ -180,14 +594,38 @@ func ToNilString(s *string) api.NilString {
 }
 
 func (m *APIMapper) ToShoppingListItem(item domain.ShoppingListItem) (*api.ReadShoppingListItem, error) {
-	return &api.ReadShoppingListItem{
+	result := &api.ReadShoppingListItem{
 		ID:         item.ID,
 		Ingredient: item.Ingredient,
 		Quantity:   ToNilString(item.Quantity),
 		Unit:       ToNilString(item.Unit),
+		Recipes:    make([]api.ShoppingListItemRecipe, len(item.Recipes)),
 		Done:       item.Done,
 		SortOrder:  item.SortOrder,
-	}, nil
+		Version:    item.Version,
+	}
+	if item.IngredientID != nil {
+		result.IngredientId = api.NewOptInt64(*item.IngredientID)
+	}
+	if item.UnitID != nil {
+		result.UnitId = api.NewOptInt64(*item.UnitID)
+	}
+	if item.Amount != nil {
+		result.Amount = api.NewOptFloat64(*item.Amount)
+	}
+	if item.SectionID != nil {
+		result.SectionId = api.NewOptInt64(*item.SectionID)
+	}
+	if item.ClientID != nil {
+		result.ClientId = api.NewOptString(*item.ClientID)
+	}
+	for i, recipe := range item.Recipes {
+		result.Recipes[i] = api.ShoppingListItemRecipe{
+			ID:   recipe.ID,
+			Name: recipe.Name,
+		}
+	}
+	return result, nil
 }
 
 func (m *APIMapper) ToShoppingListItems(items []domain.ShoppingListItem) ([]api.ReadShoppingListItem, error) {
@@ -207,11 +645,24 @@ func (m *APIMapper) ToShoppingList(list domain.ShoppingList) (*api.ReadShoppingL
 	if err != nil {
 		return nil, err
 	}
-	return &api.ReadShoppingList{
+	result := &api.ReadShoppingList{
 		ID:    list.ID,
 		Name:  list.Name,
 		Items: items,
-	}, nil
+	}
+	if list.StoreID != nil {
+		result.StoreId = api.NewOptInt64(*list.StoreID)
+		result.Sections = make([]api.ShoppingListSection, len(list.Sections))
+		for i, section := range list.Sections {
+			if result.Sections[i].Items, err = m.ToShoppingListItems(section.Items); err != nil {
+				return nil, err
+			}
+			if section.Section != nil {
+				result.Sections[i].Section = api.NewOptReadStoreSection(m.ToStoreSection(*section.Section))
+			}
+		}
+	}
+	return result, nil
 }
 
 func (m *APIMapper) ToShoppingLists(lists []domain.ShoppingList) ([]api.ReadShoppingList, error) {
@@ -225,3 +676,106 @@ func (m *APIMapper) ToShoppingLists(lists []domain.ShoppingList) ([]api.ReadShop
 	}
 	return result, nil
 }
+
+func (m *APIMapper) ToShoppingListSync(sync domain.ShoppingListSyncResult) (*api.ReadShoppingListSync, error) {
+	items, err := m.ToShoppingListItems(sync.List.Items)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.ReadShoppingListSync{
+		SyncToken:      sync.SyncToken,
+		Full:           sync.Full,
+		Items:          items,
+		DeletedItemIds: sync.DeletedItemIDs,
+		Results:        make([]api.ShoppingListOperationResult, len(sync.Results)),
+	}
+	for i, operation := range sync.Results {
+		result.Results[i] = api.ShoppingListOperationResult{
+			Status: api.ShoppingListOperationStatus(operation.Status),
+		}
+		if operation.ItemID != nil {
+			result.Results[i].ItemId = api.NewOptInt64(*operation.ItemID)
+		}
+	}
+	return result, nil
+}
+
+// ToHousehold maps the household as seen by the given member.
+func (m *APIMapper) ToHousehold(household domain.Household, userID int64) *api.ReadHousehold {
+	result := &api.ReadHousehold{
+		ID:      household.ID,
+		Name:    household.Name,
+		Members: make([]api.HouseholdMember, len(household.Members)),
+	}
+	for i, member := range household.Members {
+		result.Members[i] = api.HouseholdMember{
+			UserId: member.UserID,
+			Email:  member.Email,
+			Role:   api.HouseholdRole(member.Role),
+		}
+		if member.UserID == userID {
+			result.Role = api.HouseholdRole(member.Role)
+		}
+	}
+	return result
+}
+
+func (m *APIMapper) ToStore(store domain.Store) *api.ReadStore {
+	result := &api.ReadStore{
+		ID:       store.ID,
+		Name:     store.Name,
+		Sections: make([]api.ReadStoreSection, len(store.Sections)),
+	}
+	for i, section := range store.Sections {
+		result.Sections[i] = m.ToStoreSection(section)
+	}
+	return result
+}
+
+func (m *APIMapper) ToStoreSection(section domain.StoreSection) api.ReadStoreSection {
+	ingredientIDs := section.IngredientIDs
+	if ingredientIDs == nil {
+		ingredientIDs = []int64{}
+	}
+	return api.ReadStoreSection{
+		ID:            section.ID,
+		Name:          section.Name,
+		IngredientIds: ingredientIDs,
+	}
+}
+
+func (m *APIMapper) ToStores(stores []domain.Store) []api.ReadStore {
+	result := make([]api.ReadStore, len(stores))
+	for i, store := range stores {
+		result[i] = *m.ToStore(store)
+	}
+	return result
+}
+
+func toOptCursor(cursor string) api.OptString {
+	if cursor == "" {
+		return api.OptString{}
+	}
+	return api.NewOptString(cursor)
+}
+
+func (m *APIMapper) ToRecipeImport(result domain.RecipeImport) (*api.RecipeImport, error) {
+	recipe, err := m.ToWriteRecipe(result.Recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := make([]api.UnresolvedIngredient, len(result.Unresolved))
+	for i, ingredient := range result.Unresolved {
+		unresolved[i] = api.UnresolvedIngredient{
+			Line:       ingredient.Line,
+			Amount:     ingredient.Amount,
+			Unit:       ingredient.Unit,
+			Ingredient: ingredient.Ingredient,
+		}
+	}
+	return &api.RecipeImport{
+		Recipe:                *recipe,
+		UnresolvedIngredients: unresolved,
+	}, nil
+}
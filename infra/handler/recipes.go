@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/ogen-go/ogen/json"
@@ -40,12 +41,24 @@ func (h *RecipeHandler) AddRecipe(ctx context.Context, req *api.WriteRecipe) (*a
 	return h.mapper.ToReadRecipe(result)
 }
 
-func (h *RecipeHandler) BrowseRecipes(ctx context.Context) ([]api.ReadRecipe, error) {
-	recipes, err := h.Recipes.Browse(ctx)
+func (h *RecipeHandler) BrowseRecipes(ctx context.Context, params api.BrowseRecipesParams) (*api.RecipeListHeaders, error) {
+	page, err := h.Recipes.Browse(ctx, h.mapper.FromRecipeListParams(params))
 	if err != nil {
 		return nil, err
 	}
-	return h.mapper.ToRecipes(recipes)
+	return h.mapper.ToRecipePage(page)
+}
+
+func (h *RecipeHandler) SearchRecipes(ctx context.Context, params api.SearchRecipesParams) ([]api.RecipeSearchResult, error) {
+	results, err := h.Recipes.Search(ctx, domain.RecipeSearchQuery{
+		Text:   params.Q,
+		Limit:  params.Limit.Or(0),
+		Offset: params.Offset.Or(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToRecipeSearchResults(results)
 }
 
 func (h *RecipeHandler) DeleteRecipe(ctx context.Context, params api.DeleteRecipeParams) error {
@@ -74,41 +87,174 @@ func (h *RecipeHandler) GetMealPlan(ctx context.Context, params api.GetMealPlanP
 	return h.mapper.ToMealPlans(mealplan)
 }
 
+func (h *RecipeHandler) GetMealPlanNutrition(ctx context.Context, params api.GetMealPlanNutritionParams) (*api.MealPlanNutrition, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	from := params.From.Or(time.Now().UTC().Truncate(24 * time.Hour))
+	until := params.Until.Or(from.AddDate(0, 0, 6))
+	nutrition, err := h.Recipes.GetMealPlanNutrition(ctx, user, from, until)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToMealPlanNutrition(nutrition), nil
+}
+
 func (h *RecipeHandler) CreateMealPlan(ctx context.Context, req *api.WriteMealPlan) error {
 	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
 	if !ok || user == nil {
 		return domain.ErrAuthentication
 	}
-	return h.Recipes.CreateMealPlan(ctx, user, req.RecipeId, req.Date)
+	return h.Recipes.CreateMealPlan(ctx, user, h.mapper.FromWriteMealPlan(req))
 }
 
-func (h *RecipeHandler) DeleteMealPlan(ctx context.Context, params api.DeleteMealPlanParams) error {
+func (h *RecipeHandler) CreateMealPlanEntries(ctx context.Context, req *api.WriteMealPlanEntries) error {
 	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
 	if !ok || user == nil {
 		return domain.ErrAuthentication
 	}
-	return h.Recipes.DeleteMealPlan(ctx, user, params.RecipeId, params.Date)
+	return h.Recipes.CreateMealPlanEntries(ctx, user, h.mapper.FromWriteMealPlanEntries(req))
 }
 
-func (h *RecipeHandler) GetRecipes(ctx context.Context) ([]api.ReadRecipe, error) {
+func (h *RecipeHandler) GenerateMealPlan(ctx context.Context, req *api.WriteMealPlanGeneration) (*api.ReadMealPlanProposal, error) {
 	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
 	if !ok || user == nil {
 		return nil, domain.ErrAuthentication
 	}
-	recipes, err := h.Recipes.GetByUser(ctx, user)
+	proposal, err := h.Recipes.GenerateMealPlan(ctx, user, h.mapper.FromWriteMealPlanGeneration(req))
 	if err != nil {
 		return nil, err
 	}
-	return h.mapper.ToRecipes(recipes)
+	return h.mapper.ToMealPlanProposal(proposal)
 }
 
-func (h *RecipeHandler) GetRecipeById(ctx context.Context, params api.GetRecipeByIdParams) (*api.ReadRecipe, error) {
+func (h *RecipeHandler) UpdateMealPlanEntry(ctx context.Context, req *api.WriteMealPlanEntry, params api.UpdateMealPlanEntryParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Recipes.UpdateMealPlanEntry(ctx, user, params.EntryId, h.mapper.FromWriteMealPlanEntry(req))
+}
+
+func (h *RecipeHandler) DeleteMealPlanEntry(ctx context.Context, params api.DeleteMealPlanEntryParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Recipes.DeleteMealPlanEntry(ctx, user, params.EntryId)
+}
+
+func (h *RecipeHandler) GetMealPlanTemplates(ctx context.Context) ([]api.ReadMealPlanTemplate, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	templates, err := h.Recipes.GetMealPlanTemplates(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToMealPlanTemplates(templates), nil
+}
+
+func (h *RecipeHandler) CreateMealPlanTemplate(ctx context.Context, req *api.WriteMealPlanTemplate) (*api.ReadMealPlanTemplate, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	template, err := h.Recipes.CreateMealPlanTemplate(ctx, user, h.mapper.FromWriteMealPlanTemplate(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToMealPlanTemplate(template), nil
+}
+
+func (h *RecipeHandler) UpdateMealPlanTemplate(ctx context.Context, req *api.WriteMealPlanTemplate, params api.UpdateMealPlanTemplateParams) (*api.ReadMealPlanTemplate, error) {
 	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
 	if !ok || user == nil {
 		return nil, domain.ErrAuthentication
 	}
-	recipe, err := h.Recipes.GetById(ctx, user, params.RecipeId)
+	template, err := h.Recipes.UpdateMealPlanTemplate(ctx, user, params.TemplateId, h.mapper.FromWriteMealPlanTemplate(req))
 	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToMealPlanTemplate(template), nil
+}
+
+func (h *RecipeHandler) DeleteMealPlanTemplate(ctx context.Context, params api.DeleteMealPlanTemplateParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Recipes.DeleteMealPlanTemplate(ctx, user, params.TemplateId)
+}
+
+func (h *RecipeHandler) ApplyMealPlanTemplate(ctx context.Context, req *api.WriteMealPlanTemplateApplication, params api.ApplyMealPlanTemplateParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Recipes.ApplyMealPlanTemplate(ctx, user, params.TemplateId, req.From, req.Until)
+}
+
+func (h *RecipeHandler) GetMealSlots(ctx context.Context) ([]api.MealSlot, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	slots, err := h.Recipes.GetMealSlots(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToMealSlots(slots), nil
+}
+
+func (h *RecipeHandler) UpdateMealSlots(ctx context.Context, req *api.WriteMealSlots) ([]api.MealSlot, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	slots, err := h.Recipes.SaveMealSlots(ctx, user, h.mapper.FromWriteMealSlots(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToMealSlots(slots), nil
+}
+
+func (h *RecipeHandler) MoveMealPlanEntry(ctx context.Context, req *api.WriteMealPlanMove, params api.MoveMealPlanEntryParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	var slotID *int64
+	if v, ok := req.SlotId.Get(); ok {
+		slotID = &v
+	}
+	return h.Recipes.MoveMealPlanEntry(ctx, user, params.EntryId, req.Date, slotID, req.Position)
+}
+
+func (h *RecipeHandler) GetRecipes(ctx context.Context, params api.GetRecipesParams) (*api.RecipeListHeaders, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	query := h.mapper.FromRecipeListParams(api.BrowseRecipesParams(params))
+	page, err := h.Recipes.GetByUser(ctx, user, query)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToRecipePage(page)
+}
+
+func (h *RecipeHandler) GetRecipeById(ctx context.Context, params api.GetRecipeByIdParams) (*api.ReadRecipe, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	recipe, err := h.Recipes.GetScaledById(ctx, user, params.RecipeId, h.mapper.FromRecipeScaleParams(params))
+	if errors.Is(err, domain.ErrInvalidRecipeScale) {
+		return nil, err
+	} else if err != nil {
 		return nil, domain.ErrRecipeNotFound
 	}
 	return h.mapper.ToReadRecipe(recipe)
@@ -131,12 +277,15 @@ func (h *RecipeHandler) UpdateRecipe(ctx context.Context, req *api.WriteRecipe,
 	return h.mapper.ToReadRecipe(result)
 }
 
-func (h *RecipeHandler) GetIngredients(ctx context.Context) ([]api.Ingredient, error) {
-	ingredients, err := h.Recipes.GetIngredients(ctx)
+func (h *RecipeHandler) GetIngredients(ctx context.Context, params api.GetIngredientsParams) (*api.IngredientListHeaders, error) {
+	page, err := h.Recipes.GetIngredients(ctx, domain.IngredientListQuery{
+		Cursor: params.Cursor.Or(""),
+		Limit:  params.Limit.Or(0),
+	})
 	if err != nil {
 		return nil, err
 	}
-	return h.mapper.ToIngredients(ingredients)
+	return h.mapper.ToIngredientPage(page)
 }
 
 func (h *RecipeHandler) GetUnits(ctx context.Context) ([]api.ReadUnit, error) {
@@ -147,6 +296,14 @@ func (h *RecipeHandler) GetUnits(ctx context.Context) ([]api.ReadUnit, error) {
 	return h.mapper.ToUnits(units), nil
 }
 
+func (h *RecipeHandler) ConvertUnits(ctx context.Context, params api.ConvertUnitsParams) (*api.UnitConversion, error) {
+	amount, err := h.Recipes.ConvertAmount(ctx, h.mapper.FromConvertUnitsParams(params))
+	if err != nil {
+		return nil, err
+	}
+	return &api.UnitConversion{Amount: amount}, nil
+}
+
 func (h *RecipeHandler) GetTags(ctx context.Context) ([]api.ReadTag, error) {
 	tags, err := h.Recipes.GetTags(ctx)
 	if err != nil {
@@ -155,6 +312,14 @@ func (h *RecipeHandler) GetTags(ctx context.Context) ([]api.ReadTag, error) {
 	return h.mapper.ToTags(tags), nil
 }
 
+func (h *RecipeHandler) ParseIngredients(ctx context.Context, req *api.IngredientLines) ([]api.ParsedIngredient, error) {
+	parsed, err := h.Recipes.ParseIngredients(ctx, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToParsedIngredients(parsed), nil
+}
+
 func (h *RecipeHandler) AddIngredient(ctx context.Context, req *api.WriteIngredient) (*api.Ingredient, error) {
 	ingredient := h.mapper.FromWriteIngredient(req)
 
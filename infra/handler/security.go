@@ -19,13 +19,23 @@ func NewSecurityHandler(service *domain.UserService) *SecurityHandler {
 }
 
 func (h *SecurityHandler) HandleCookieAuth(ctx context.Context, _ string, t api.CookieAuth) (context.Context, error) {
-	userId, err := getUserFromSessionCookie(t.APIKey)
+	token, err := getSessionTokenFromCookie(t.APIKey)
 	if err != nil {
 		return nil, domain.WrapError(domain.ErrAuthentication, err)
 	}
-	user, err := h.Users.GetUserById(ctx, userId)
+	user, session, err := h.Users.GetUserBySession(ctx, token)
 	if err != nil {
 		return nil, domain.WrapError(domain.ErrAuthentication, err)
 	}
+	ctx = context.WithValue(ctx, config.CtxKeySession, &session)
+	return context.WithValue(ctx, config.CtxKeyUser, &user), nil
+}
+
+func (h *SecurityHandler) HandleBearerAuth(ctx context.Context, _ string, t api.BearerAuth) (context.Context, error) {
+	user, token, err := h.Users.GetUserByAPIToken(ctx, t.Token)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrAuthentication, err)
+	}
+	ctx = context.WithValue(ctx, config.CtxKeyAPIToken, &token)
 	return context.WithValue(ctx, config.CtxKeyUser, &user), nil
 }
@@ -8,12 +8,16 @@ type APIHandler struct {
 	*RecipeHandler
 	*UserHandler
 	*ShoppingHandler
+	*ImportHandler
+	*HouseholdHandler
 }
 
-func NewAPIHandler(recipes *domain.RecipeService, users *domain.UserService, shopping *domain.ShoppingService) *APIHandler {
+func NewAPIHandler(recipes *domain.RecipeService, users *domain.UserService, shopping *domain.ShoppingService, imports *domain.ImportService, households *domain.HouseholdService) *APIHandler {
 	return &APIHandler{
-		RecipeHandler:   NewRecipeHandler(recipes),
-		UserHandler:     NewUserHandler(users),
-		ShoppingHandler: NewShoppingHandler(shopping),
+		RecipeHandler:    NewRecipeHandler(recipes),
+		UserHandler:      NewUserHandler(users),
+		ShoppingHandler:  NewShoppingHandler(shopping),
+		ImportHandler:    NewImportHandler(imports),
+		HouseholdHandler: NewHouseholdHandler(households),
 	}
 }
@@ -46,12 +46,30 @@ func (h *ShoppingHandler) CreateShoppingList(ctx context.Context, req *api.Write
 	return h.mapper.ToShoppingList(list)
 }
 
+func (h *ShoppingHandler) GenerateShoppingList(ctx context.Context, req *api.WriteMealPlanShopping) (*api.ReadShoppingList, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	list, err := h.Shopping.AddFromMealPlan(ctx, user, h.mapper.FromWriteMealPlanShopping(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToShoppingList(list)
+}
+
 func (h *ShoppingHandler) GetShoppingListById(ctx context.Context, params api.GetShoppingListByIdParams) (*api.ReadShoppingList, error) {
 	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
 	if !ok || user == nil {
 		return nil, domain.ErrAuthentication
 	}
-	list, err := h.Shopping.GetByID(ctx, user, params.ShoppingListId)
+	var list domain.ShoppingList
+	var err error
+	if storeID, ok := params.StoreId.Get(); ok {
+		list, err = h.Shopping.GetByIDForStore(ctx, user, params.ShoppingListId, storeID)
+	} else {
+		list, err = h.Shopping.GetByID(ctx, user, params.ShoppingListId)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +109,42 @@ func (h *ShoppingHandler) AddShoppingListItem(ctx context.Context, req *api.Writ
 	return h.mapper.ToShoppingListItem(result)
 }
 
+func (h *ShoppingHandler) MoveShoppingListItem(ctx context.Context, req *api.WriteShoppingListItemMove, params api.MoveShoppingListItemParams) (*api.ReadShoppingList, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	list, err := h.Shopping.MoveItem(ctx, user, params.ShoppingListId, params.ItemId, req.Position)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToShoppingList(list)
+}
+
+func (h *ShoppingHandler) ReorderShoppingListItems(ctx context.Context, req *api.WriteShoppingListOrder, params api.ReorderShoppingListItemsParams) (*api.ReadShoppingList, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	list, err := h.Shopping.ReorderItems(ctx, user, params.ShoppingListId, req.ItemIds)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToShoppingList(list)
+}
+
+func (h *ShoppingHandler) SyncShoppingList(ctx context.Context, req *api.WriteShoppingListSync, params api.SyncShoppingListParams) (*api.ReadShoppingListSync, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	result, err := h.Shopping.Sync(ctx, user, params.ShoppingListId, h.mapper.FromWriteShoppingListSync(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToShoppingListSync(result)
+}
+
 func (h *ShoppingHandler) UpdateShoppingListItem(ctx context.Context, req *api.WriteShoppingListItem, params api.UpdateShoppingListItemParams) (*api.ReadShoppingListItem, error) {
 	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
 	if !ok || user == nil {
@@ -111,3 +165,59 @@ func (h *ShoppingHandler) DeleteShoppingListItem(ctx context.Context, params api
 	}
 	return h.Shopping.DeleteItem(ctx, user, params.ShoppingListId, params.ItemId)
 }
+
+func (h *ShoppingHandler) GetStores(ctx context.Context) ([]api.ReadStore, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	stores, err := h.Shopping.GetStores(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToStores(stores), nil
+}
+
+func (h *ShoppingHandler) AddStore(ctx context.Context, req *api.WriteStore) (*api.ReadStore, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	store, err := h.Shopping.CreateStore(ctx, user, h.mapper.FromWriteStore(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToStore(store), nil
+}
+
+func (h *ShoppingHandler) GetStoreById(ctx context.Context, params api.GetStoreByIdParams) (*api.ReadStore, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	store, err := h.Shopping.GetStore(ctx, user, params.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToStore(store), nil
+}
+
+func (h *ShoppingHandler) UpdateStore(ctx context.Context, req *api.WriteStore, params api.UpdateStoreParams) (*api.ReadStore, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	store, err := h.Shopping.UpdateStore(ctx, user, params.StoreId, h.mapper.FromWriteStore(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToStore(store), nil
+}
+
+func (h *ShoppingHandler) DeleteStore(ctx context.Context, params api.DeleteStoreParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Shopping.DeleteStore(ctx, user, params.StoreId)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/api/middleware"
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/config"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/oidc"
+)
+
+// SSOStateCookieName is the cookie that ties the login at the identity
+// provider to the browser that started it. Without it, someone could send
+// a victim to a callback with their own code and log them into the wrong
+// account.
+const SSOStateCookieName = "SSOSTATE"
+
+// SSOHandler logs users in at the OpenID Connect provider. Both steps are
+// browser redirects rather than API calls, so they are served next to the
+// API instead of by it.
+type SSOHandler struct {
+	baseURL      string
+	mux          *http.ServeMux
+	provider     domain.IdentityProvider
+	provisioning domain.SSOProvisioning
+	Users        *domain.UserService
+}
+
+func NewSSOHandler(service *domain.UserService) *SSOHandler {
+	h := &SSOHandler{
+		baseURL:      strings.TrimSuffix(env.MustGet("BASE_URL"), "/"),
+		mux:          http.NewServeMux(),
+		provider:     oidc.NewIdentityProvider(),
+		provisioning: oidc.NewProvisioning(),
+		Users:        service,
+	}
+	h.mux.HandleFunc("GET "+config.SSOPathPrefix+"/login", h.login)
+	h.mux.HandleFunc("GET "+config.SSOPathPrefix+"/callback", h.callback)
+	return h
+}
+
+func (h *SSOHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *SSOHandler) login(w http.ResponseWriter, r *http.Request) {
+	redirectURL, state, err := h.Users.BeginSSOLogin(r.Context(), h.provider)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		HttpOnly: true,
+		MaxAge:   int(domain.SSOLoginLifetime / time.Second),
+		Name:     SSOStateCookieName,
+		Path:     config.SSOPathPrefix,
+		// The provider sends the user back with a cross-site redirect, which
+		// strict cookies wouldn't survive.
+		SameSite: http.SameSiteLaxMode,
+		Secure:   true,
+		Value:    state,
+	})
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+func (h *SSOHandler) callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state := query.Get("state")
+	http.SetCookie(w, &http.Cookie{
+		HttpOnly: true,
+		MaxAge:   -1,
+		Name:     SSOStateCookieName,
+		Path:     config.SSOPathPrefix,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   true,
+	})
+
+	cookie, err := r.Cookie(SSOStateCookieName)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		h.fail(w, r, domain.ErrSSOLoginNotFound)
+		return
+	}
+	// The user canceled or the provider turned them down.
+	if query.Has("error") {
+		h.fail(w, r, domain.ErrSSOFailed)
+		return
+	}
+
+	user, err := h.Users.FinishSSOLogin(r.Context(), h.provider, h.provisioning, state, query.Get("code"))
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	session, err := h.Users.CreateSession(r.Context(), &user, middleware.SessionClient(r))
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	sessionCookie, err := createSessionCookie(session)
+	if err != nil {
+		h.fail(w, r, domain.WrapError(domain.ErrAuthentication, err))
+		return
+	}
+	w.Header().Add("Set-Cookie", sessionCookie)
+	http.Redirect(w, r, h.baseURL+"/", http.StatusFound)
+}
+
+// fail sends the user back to the login page, which shows the error.
+func (h *SSOHandler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr = domain.ErrUnhandled
+	if !errors.As(err, &domainErr) || domainErr.Inner != nil {
+		log.Println(fmt.Errorf("single sign-on failed: %w", err))
+	}
+	query := url.Values{"error": {domainErr.Message}}
+	http.Redirect(w, r, h.baseURL+"/auth/login?"+query.Encode(), http.StatusFound)
+}
@@ -54,7 +54,10 @@ func registerEventListeners(handler *tusd.Handler, users *domain.UserService) {
 }
 
 func onUploadCreated(users *domain.UserService, event tusd.HookEvent) {
-	_, err := getUserIdFromUpload(event.HTTPRequest)
+	token, err := getSessionTokenFromUpload(event.HTTPRequest)
+	if err == nil {
+		_, _, err = users.GetUserBySession(event.Context, token)
+	}
 	if err != nil {
 		event.Upload.StopUpload(newForbiddenResponse())
 	}
@@ -64,18 +67,18 @@ func onUploadCompleted(users *domain.UserService, event tusd.HookEvent) {
 	// Move file to correct location
 }
 
-func getUserIdFromUpload(req tusd.HTTPRequest) (int64, error) {
+func getSessionTokenFromUpload(req tusd.HTTPRequest) (string, error) {
 	for k, v := range req.Header {
 		if k == "Cookie" {
 			for _, cookie := range v {
 				if strings.HasPrefix(cookie, AuthCookieName) {
 					sessionCookie := strings.Split(cookie, "=")
-					return getUserFromSessionCookie(sessionCookie[1])
+					return getSessionTokenFromCookie(sessionCookie[1])
 				}
 			}
 		}
 	}
-	return 0, errors.New("upload requires authentication")
+	return "", errors.New("upload requires authentication")
 }
 
 func newForbiddenResponse() tusd.HTTPResponse {
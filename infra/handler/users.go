@@ -2,20 +2,35 @@ package handler
 
 import (
 	"context"
+	"log"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/wolfsblu/recipe-manager/api"
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/domain/security"
 	"github.com/wolfsblu/recipe-manager/infra/config"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/handler/mapper"
 )
 
 type UserHandler struct {
-	Users *domain.UserService
+	mapper *mapper.APIMapper
+	// webAuthn runs the passkey ceremonies for the address the app is
+	// served at.
+	webAuthn *webauthn.WebAuthn
+	Users    *domain.UserService
 }
 
 func NewUserHandler(service *domain.UserService) *UserHandler {
+	baseURL := env.MustGet("BASE_URL")
+	webAuthn, err := domain.NewWebAuthn(baseURL)
+	if err != nil {
+		log.Fatalf("env variable 'BASE_URL' is invalid: %v\n", err)
+	}
 	return &UserHandler{
-		Users: service,
+		mapper:   mapper.NewAPIMapper(baseURL),
+		webAuthn: webAuthn,
+		Users:    service,
 	}
 }
 
@@ -28,13 +43,217 @@ func (h *UserHandler) GetUserProfile(ctx context.Context) (*api.ReadUser, error)
 	if !ok || user == nil {
 		return nil, domain.ErrAuthentication
 	}
+	targets, err := h.Users.GetNutrientTargets(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := h.Users.IsTwoFactorEnabled(ctx, user)
+	if err != nil {
+		return nil, err
+	}
 	return &api.ReadUser{
-		ID:    user.ID,
-		Email: user.Email,
+		ID:               user.ID,
+		Email:            user.Email,
+		NutrientTargets:  h.mapper.ToNutrientTargets(targets),
+		TwoFactorEnabled: api.NewOptBool(twoFactor),
 	}, nil
 }
 
-func (h *UserHandler) Login(ctx context.Context, req *api.Credentials) (r *api.AuthenticatedUserHeaders, _ error) {
+func (h *UserHandler) UpdateNutrientTargets(ctx context.Context, req []api.WriteNutrientTarget) ([]api.NutrientTarget, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	targets, err := h.Users.UpdateNutrientTargets(ctx, user, h.mapper.FromWriteNutrientTargets(req))
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToNutrientTargets(targets), nil
+}
+
+func (h *UserHandler) GetSessions(ctx context.Context) ([]api.Session, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	sessions, err := h.Users.GetSessions(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	current, _ := ctx.Value(config.CtxKeySession).(*domain.Session)
+	return h.mapper.ToSessions(sessions, current), nil
+}
+
+func (h *UserHandler) DeleteSession(ctx context.Context, params api.DeleteSessionParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Users.DeleteSession(ctx, user, params.SessionId)
+}
+
+func (h *UserHandler) DeleteOtherSessions(ctx context.Context) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	session, ok := ctx.Value(config.CtxKeySession).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Users.DeleteOtherSessions(ctx, user, *session)
+}
+
+func (h *UserHandler) GetPasskeys(ctx context.Context) ([]api.Passkey, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	passkeys, err := h.Users.GetPasskeys(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToPasskeys(passkeys), nil
+}
+
+func (h *UserHandler) BeginPasskeyRegistration(ctx context.Context) (*api.PasskeyCreationOptions, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	options, err := h.Users.BeginPasskeyRegistration(ctx, h.webAuthn, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToPasskeyCreationOptions(options), nil
+}
+
+func (h *UserHandler) CreatePasskey(ctx context.Context, req *api.PasskeyRegistration) (*api.Passkey, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	response, err := h.mapper.FromPasskeyAttestation(req.Credential)
+	if err != nil {
+		return nil, err
+	}
+	passkey, err := h.Users.FinishPasskeyRegistration(ctx, h.webAuthn, user, req.Token, req.Name.Or(""), response)
+	if err != nil {
+		return nil, err
+	}
+	result := h.mapper.ToPasskey(passkey)
+	return &result, nil
+}
+
+func (h *UserHandler) DeletePasskey(ctx context.Context, params api.DeletePasskeyParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Users.DeletePasskey(ctx, user, params.PasskeyId)
+}
+
+func (h *UserHandler) GetAPITokens(ctx context.Context) ([]api.APIToken, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	tokens, err := h.Users.GetAPITokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToAPITokens(tokens), nil
+}
+
+func (h *UserHandler) CreateAPIToken(ctx context.Context, req *api.WriteAPIToken) (*api.NewAPIToken, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	name, scopes, expiresAt := h.mapper.FromWriteAPIToken(req)
+	token, secret, err := h.Users.CreateAPIToken(ctx, user, name, scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToNewAPIToken(token, secret), nil
+}
+
+func (h *UserHandler) DeleteAPIToken(ctx context.Context, params api.DeleteAPITokenParams) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Users.DeleteAPIToken(ctx, user, params.TokenId)
+}
+
+func (h *UserHandler) EnrollTOTP(ctx context.Context) (*api.TOTPEnrollment, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	enrollment, err := h.Users.EnrollTOTP(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TOTPEnrollment{
+		Secret:          enrollment.Secret,
+		ProvisioningUri: enrollment.ProvisioningURI,
+	}, nil
+}
+
+func (h *UserHandler) ConfirmTOTP(ctx context.Context, req *api.TwoFactorCode) (*api.RecoveryCodes, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	codes, err := h.Users.ConfirmTOTP(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func (h *UserHandler) DisableTOTP(ctx context.Context, req *api.TwoFactorCode) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Users.DisableTOTP(ctx, user, req.Code)
+}
+
+func (h *UserHandler) GetCalendarFeed(ctx context.Context) (*api.CalendarFeed, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	token, err := h.Users.GetCalendarToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToCalendarFeed(token)
+}
+
+func (h *UserHandler) CreateCalendarFeed(ctx context.Context) (*api.CalendarFeed, error) {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrAuthentication
+	}
+	token, err := h.Users.CreateCalendarToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToCalendarFeed(token)
+}
+
+func (h *UserHandler) DeleteCalendarFeed(ctx context.Context) error {
+	user, ok := ctx.Value(config.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return domain.ErrAuthentication
+	}
+	return h.Users.DeleteCalendarToken(ctx, user)
+}
+
+func (h *UserHandler) Login(ctx context.Context, req *api.Credentials) (api.LoginRes, error) {
 	user, err := h.Users.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, err
@@ -47,7 +266,57 @@ func (h *UserHandler) Login(ctx context.Context, req *api.Credentials) (r *api.A
 		return nil, domain.ErrUnconfirmedUser
 	}
 
-	cookie, err := createSessionCookie(user.ID)
+	twoFactor, err := h.Users.IsTwoFactorEnabled(ctx, &user)
+	if err != nil {
+		return nil, err
+	} else if twoFactor {
+		challenge, err := h.Users.CreateLoginChallenge(ctx, &user)
+		if err != nil {
+			return nil, err
+		}
+		return &api.LoginChallenge{
+			Token:     challenge.Token,
+			ExpiresAt: challenge.ExpiresAt(),
+		}, nil
+	}
+	return h.startSession(ctx, user)
+}
+
+func (h *UserHandler) LoginTOTP(ctx context.Context, req *api.TwoFactorLogin) (*api.AuthenticatedUserHeaders, error) {
+	user, err := h.Users.CompleteLoginChallenge(ctx, req.Token, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return h.startSession(ctx, user)
+}
+
+func (h *UserHandler) BeginPasskeyLogin(ctx context.Context) (*api.PasskeyRequestOptions, error) {
+	options, err := h.Users.BeginPasskeyLogin(ctx, h.webAuthn)
+	if err != nil {
+		return nil, err
+	}
+	return h.mapper.ToPasskeyRequestOptions(options), nil
+}
+
+func (h *UserHandler) LoginPasskey(ctx context.Context, req *api.PasskeyLogin) (*api.AuthenticatedUserHeaders, error) {
+	response, err := h.mapper.FromPasskeyAssertion(req.Credential)
+	if err != nil {
+		return nil, err
+	}
+	user, err := h.Users.FinishPasskeyLogin(ctx, h.webAuthn, req.Token, response)
+	if err != nil {
+		return nil, err
+	}
+	return h.startSession(ctx, user)
+}
+
+func (h *UserHandler) startSession(ctx context.Context, user domain.User) (*api.AuthenticatedUserHeaders, error) {
+	client, _ := ctx.Value(config.CtxKeyClient).(domain.SessionClient)
+	session, err := h.Users.CreateSession(ctx, &user, client)
+	if err != nil {
+		return nil, err
+	}
+	cookie, err := createSessionCookie(session)
 	if err != nil {
 		return nil, domain.WrapError(domain.ErrAuthentication, err)
 	}
@@ -63,7 +332,12 @@ func (h *UserHandler) Login(ctx context.Context, req *api.Credentials) (r *api.A
 	}, nil
 }
 
-func (h *UserHandler) Logout(_ context.Context) (*api.LogoutOK, error) {
+func (h *UserHandler) Logout(ctx context.Context) (*api.LogoutOK, error) {
+	if session, ok := ctx.Value(config.CtxKeySession).(*domain.Session); ok && session != nil {
+		if err := h.Users.DeleteSessionByToken(ctx, session.Token); err != nil {
+			return nil, err
+		}
+	}
 	cookie := expireSessionCookie()
 	return &api.LogoutOK{
 		SetCookie: api.OptString{
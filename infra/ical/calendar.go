@@ -0,0 +1,98 @@
+// Package ical writes iCalendar feeds (RFC 5545) that calendar apps can
+// subscribe to.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	productID      = "-//recipe-manager//Meal Plan//EN"
+	maxLineLength  = 75
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event takes up the whole day of Start when AllDay is set. Timed events are
+// written in floating time, so they happen at the same time of day wherever
+// the calendar is looked at.
+type Event struct {
+	UID         string
+	Start       time.Time
+	Duration    time.Duration
+	AllDay      bool
+	Summary     string
+	Description string
+	URL         string
+}
+
+// Write encodes the calendar, stamp is the time the feed was created at.
+func (c Calendar) Write(w io.Writer, stamp time.Time) error {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+productID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for _, event := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+event.UID)
+		writeLine(&b, "DTSTAMP:"+stamp.UTC().Format(dateTimeLayout)+"Z")
+		if event.AllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+event.Start.Format(dateLayout))
+			writeLine(&b, "DTEND;VALUE=DATE:"+event.Start.AddDate(0, 0, 1).Format(dateLayout))
+		} else {
+			writeLine(&b, "DTSTART:"+event.Start.Format(dateTimeLayout))
+			writeLine(&b, fmt.Sprintf("DURATION:PT%dM", int64(event.Duration.Minutes())))
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(&b, "URL:"+event.URL)
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// writeLine ends the line with CRLF and folds it into lines of at most 75
+// bytes, continuation lines start with a space. Characters are never split.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The space starting the continuation counts towards its length
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,99 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarWrite(t *testing.T) {
+	stamp := time.Date(2025, 10, 20, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	calendar := Calendar{
+		Name: "Meal Plan",
+		Events: []Event{
+			{
+				UID:     "1@example.com",
+				Start:   monday,
+				AllDay:  true,
+				Summary: "Eat out; Pizza, Pasta",
+			},
+			{
+				UID:         "2@example.com",
+				Start:       monday.Add(18*time.Hour + 30*time.Minute),
+				Duration:    45 * time.Minute,
+				Summary:     "Spätzle",
+				Description: "45 minutes\nhttps://example.com/recipes/2",
+				URL:         "https://example.com/recipes/2",
+			},
+		},
+	}
+
+	var b strings.Builder
+	if err := calendar.Write(&b, stamp); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//recipe-manager//Meal Plan//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Meal Plan",
+		"BEGIN:VEVENT",
+		"UID:1@example.com",
+		"DTSTAMP:20251020T073000Z",
+		"DTSTART;VALUE=DATE:20251020",
+		"DTEND;VALUE=DATE:20251021",
+		`SUMMARY:Eat out\; Pizza\, Pasta`,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@example.com",
+		"DTSTAMP:20251020T073000Z",
+		"DTSTART:20251020T183000",
+		"DURATION:PT45M",
+		"SUMMARY:Spätzle",
+		`DESCRIPTION:45 minutes\nhttps://example.com/recipes/2`,
+		"URL:https://example.com/recipes/2",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if b.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "Short",
+			line: "SUMMARY:Pizza",
+			want: "SUMMARY:Pizza\r\n",
+		},
+		{
+			name: "Folded",
+			line: "SUMMARY:" + strings.Repeat("a", 67+74+1),
+			want: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n " + strings.Repeat("a", 74) + "\r\n a\r\n",
+		},
+		{
+			name: "Keeps characters whole",
+			line: "SUMMARY:" + strings.Repeat("a", 66) + "äb",
+			want: "SUMMARY:" + strings.Repeat("a", 66) + "\r\n äb\r\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			writeLine(&b, tc.line)
+			if b.String() != tc.want {
+				t.Errorf("writeLine() = %q, want %q", b.String(), tc.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package job
 
 import "github.com/wolfsblu/recipe-manager/domain"
 
-func NewScheduler(service *domain.UserService) *Scheduler {
+func NewScheduler(service *domain.UserService, households *domain.HouseholdService, recipes *domain.RecipeService) *Scheduler {
 	s := &Scheduler{
-		service: service,
+		service:    service,
+		households: households,
+		recipes:    recipes,
 	}
 	s.Start()
 	return s
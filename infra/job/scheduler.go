@@ -7,8 +7,10 @@ import (
 )
 
 type Scheduler struct {
-	quit    chan struct{}
-	service *domain.UserService
+	quit       chan struct{}
+	service    *domain.UserService
+	households *domain.HouseholdService
+	recipes    *domain.RecipeService
 }
 
 func (s *Scheduler) Start() {
@@ -28,6 +30,30 @@ func (s *Scheduler) Start() {
 				go func() {
 					_ = s.service.DeleteRegistrationsOlderThan(ctx, oneWeek)
 				}()
+			case <-getC(cleanupHouseholdInvitations):
+				go func() {
+					_ = s.households.DeleteInvitationsOlderThan(ctx, oneWeek)
+				}()
+			case <-getC(applyMealPlanTemplates):
+				go func() {
+					_ = s.recipes.ApplyRecurringMealPlanTemplates(ctx)
+				}()
+			case <-getC(cleanupSessions):
+				go func() {
+					_ = s.service.DeleteExpiredSessions(ctx)
+				}()
+			case <-getC(cleanupLoginChallenges):
+				go func() {
+					_ = s.service.DeleteExpiredLoginChallenges(ctx)
+				}()
+			case <-getC(cleanupPasskeyChallenges):
+				go func() {
+					_ = s.service.DeleteExpiredPasskeyChallenges(ctx)
+				}()
+			case <-getC(cleanupSSOLogins):
+				go func() {
+					_ = s.service.DeleteExpiredSSOLogins(ctx)
+				}()
 			case <-s.quit:
 				cancel()
 				stopTickers()
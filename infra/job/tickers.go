@@ -7,14 +7,26 @@ type tickerType string
 var tickerMap map[tickerType]*time.Ticker
 
 var (
-	cleanupPasswordResets = tickerType("cleanupPasswordResets")
-	cleanupRegistrations  = tickerType("cleanupRegistrations")
+	cleanupPasswordResets       = tickerType("cleanupPasswordResets")
+	cleanupRegistrations        = tickerType("cleanupRegistrations")
+	cleanupHouseholdInvitations = tickerType("cleanupHouseholdInvitations")
+	applyMealPlanTemplates      = tickerType("applyMealPlanTemplates")
+	cleanupSessions             = tickerType("cleanupSessions")
+	cleanupLoginChallenges      = tickerType("cleanupLoginChallenges")
+	cleanupPasskeyChallenges    = tickerType("cleanupPasskeyChallenges")
+	cleanupSSOLogins            = tickerType("cleanupSSOLogins")
 )
 
 func initializeTickers() {
 	tickerMap = map[tickerType]*time.Ticker{
-		cleanupPasswordResets: time.NewTicker(24 * time.Hour),
-		cleanupRegistrations:  time.NewTicker(24 * time.Hour),
+		cleanupPasswordResets:       time.NewTicker(24 * time.Hour),
+		cleanupRegistrations:        time.NewTicker(24 * time.Hour),
+		cleanupHouseholdInvitations: time.NewTicker(24 * time.Hour),
+		applyMealPlanTemplates:      time.NewTicker(24 * time.Hour),
+		cleanupSessions:             time.NewTicker(24 * time.Hour),
+		cleanupLoginChallenges:      time.NewTicker(time.Hour),
+		cleanupPasskeyChallenges:    time.NewTicker(time.Hour),
+		cleanupSSOLogins:            time.NewTicker(time.Hour),
 	}
 }
 
@@ -0,0 +1,40 @@
+package oidc
+
+import (
+	"log"
+	"strings"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/domain/roles"
+	"github.com/wolfsblu/recipe-manager/infra/config"
+	"github.com/wolfsblu/recipe-manager/infra/env"
+)
+
+// NewIdentityProvider returns nil unless OIDC_ISSUER is set, which leaves
+// single sign-on turned off.
+func NewIdentityProvider() domain.IdentityProvider {
+	issuer := env.Get("OIDC_ISSUER", "")
+	if issuer == "" {
+		return nil
+	}
+	callbackURL := strings.TrimSuffix(env.MustGet("BASE_URL"), "/") + config.SSOPathPrefix + "/callback"
+	return NewProvider(Config{
+		Issuer:       issuer,
+		ClientID:     env.MustGet("OIDC_CLIENT_ID"),
+		ClientSecret: env.Get("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  env.Get("OIDC_REDIRECT_URL", callbackURL),
+		Scopes:       strings.Fields(env.Get("OIDC_SCOPES", "")),
+	}, nil)
+}
+
+func NewProvisioning() domain.SSOProvisioning {
+	name := env.Get("OIDC_DEFAULT_ROLE", "user")
+	role, ok := roles.Parse(name)
+	if !ok {
+		log.Fatalf("env variable 'OIDC_DEFAULT_ROLE' with value '%s' is not a role\n", name)
+	}
+	return domain.SSOProvisioning{
+		Enabled: env.GetBool("OIDC_AUTO_PROVISION", false),
+		Role:    role,
+	}
+}
@@ -0,0 +1,301 @@
+// Package oidctest runs an OpenID Connect provider for tests. It logs in
+// whoever the test says and signs ID tokens with an RSA key of its own.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	ClientID     = "recipe-manager"
+	ClientSecret = "client secret"
+	RedirectURL  = "https://recipes.example.com/api/sso/callback"
+)
+
+// User is who logs in at the issuer.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// Issuer is a provider serving discovery, its key set and the token and
+// userinfo endpoints. There is no login page, tests call Authorize instead.
+type Issuer struct {
+	// URL is the issuer identifier and where the provider is served.
+	URL string
+	// EmailInUserInfo leaves the email out of ID tokens like Authelia does,
+	// so it has to be asked for at the userinfo endpoint.
+	EmailInUserInfo bool
+
+	server *httptest.Server
+
+	mu           sync.Mutex
+	key          *rsa.PrivateKey
+	keyID        string
+	unpublished  bool
+	grants       map[string]grant
+	accessTokens map[string]User
+}
+
+type grant struct {
+	user          User
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+	claims        map[string]any
+}
+
+func NewIssuer(t testing.TB) *Issuer {
+	t.Helper()
+	issuer := &Issuer{
+		grants:       map[string]grant{},
+		accessTokens: map[string]User{},
+	}
+	issuer.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("GET /jwks", issuer.keySet)
+	mux.HandleFunc("POST /token", issuer.token)
+	mux.HandleFunc("GET /userinfo", issuer.userInfo)
+	issuer.server = httptest.NewServer(mux)
+	issuer.URL = issuer.server.URL
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// Authorize logs the user in like the provider's login page would and
+// returns where it sends the browser back to. The claims are put into the ID
+// token over the ones the issuer sets itself, nil values remove a claim.
+func (i *Issuer) Authorize(authorizationURL string, user User, claims map[string]any) (*url.URL, error) {
+	u, err := url.Parse(authorizationURL)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	switch {
+	case !strings.HasPrefix(authorizationURL, i.URL+"/authorize?"):
+		return nil, errors.New("not the authorization endpoint")
+	case query.Get("response_type") != "code":
+		return nil, errors.New("response type must be code")
+	case query.Get("client_id") != ClientID:
+		return nil, errors.New("unknown client")
+	case query.Get("redirect_uri") != RedirectURL:
+		return nil, errors.New("redirect uri is not registered")
+	case !strings.Contains(" "+query.Get("scope")+" ", " openid "):
+		return nil, errors.New("scope must contain openid")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		return nil, errors.New("PKCE with S256 is required")
+	}
+
+	code := randomToken()
+	i.mu.Lock()
+	i.grants[code] = grant{
+		user:          user,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		redirectURI:   query.Get("redirect_uri"),
+		claims:        claims,
+	}
+	i.mu.Unlock()
+
+	redirect, _ := url.Parse(RedirectURL)
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	return redirect, nil
+}
+
+// RotateKey signs ID tokens with a new key from now on.
+func (i *Issuer) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.key, i.keyID, i.unpublished = key, randomToken()[:8], false
+}
+
+// UseUnpublishedKey signs ID tokens with a key that isn't in the key set, but
+// claims to be the published one.
+func (i *Issuer) UseUnpublishedKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.key, i.unpublished = key, true
+}
+
+func (i *Issuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.URL,
+		"authorization_endpoint":                i.URL + "/authorize",
+		"token_endpoint":                        i.URL + "/token",
+		"userinfo_endpoint":                     i.URL + "/userinfo",
+		"jwks_uri":                              i.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+func (i *Issuer) keySet(w http.ResponseWriter, _ *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var keys []map[string]string
+	if !i.unpublished {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": i.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
+}
+
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	code := r.PostForm.Get("code")
+	g, ok := i.grants[code]
+	delete(i.grants, code)
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code":
+		tokenError(w, "unsupported_grant_type")
+		return
+	case !ok || r.PostForm.Get("redirect_uri") != g.redirectURI:
+		tokenError(w, "invalid_grant")
+		return
+	case challenge(r.PostForm.Get("code_verifier")) != g.codeChallenge:
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":   i.URL,
+		"sub":   g.user.Subject,
+		"aud":   ClientID,
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"iat":   now.Unix(),
+		"nonce": g.nonce,
+	}
+	if !i.EmailInUserInfo {
+		claims["email"] = g.user.Email
+		claims["email_verified"] = g.user.EmailVerified
+	}
+	for name, value := range g.claims {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	idToken, err := i.sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accessToken := randomToken()
+	i.accessTokens[accessToken] = g.user
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (i *Issuer) userInfo(w http.ResponseWriter, r *http.Request) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	i.mu.Lock()
+	user, ok := i.accessTokens[token]
+	i.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sub":            user.Subject,
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+	})
+}
+
+func (i *Issuer) sign(claims map[string]any) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": i.keyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
@@ -0,0 +1,218 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/wolfsblu/recipe-manager/domain"
+	"golang.org/x/oauth2"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	// clockSkew is how far the clock of the provider may be ahead of ours.
+	clockSkew = time.Minute
+)
+
+var (
+	ErrDiscovery     = errors.New("invalid provider configuration")
+	ErrTokenExchange = errors.New("failed to redeem authorization code")
+	ErrIDToken       = errors.New("invalid id token")
+	ErrUserInfo      = errors.New("invalid userinfo response")
+)
+
+var DefaultScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// Config is the client registered at the identity provider. The secret is
+// empty for public clients, PKCE protects the code either way.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Provider logs users in with the authorization code flow of an OpenID
+// Connect provider. Its endpoints and signing keys are discovered on first
+// use, so the app starts even while the provider is down.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+}
+
+// discovery is what the provider published about itself.
+type discovery struct {
+	provider *oidc.Provider
+	verifier *oidc.IDTokenVerifier
+	oauth2   oauth2.Config
+}
+
+// claims are those of the ID token that go-oidc leaves to the client.
+type claims struct {
+	AuthorizedParty string       `json:"azp"`
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+}
+
+// flexibleBool also takes "true" and "false", which some providers send for
+// email_verified.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = flexibleBool(v)
+	case string:
+		*b = v == "true"
+	default:
+		return fmt.Errorf("email_verified is neither a boolean nor a string")
+	}
+	return nil
+}
+
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	} else if !slices.Contains(config.Scopes, oidc.ScopeOpenID) {
+		config.Scopes = append([]string{oidc.ScopeOpenID}, config.Scopes...)
+	}
+	return &Provider{
+		config: config,
+		client: client,
+	}
+}
+
+// AuthorizationURL sends the user to the provider to log in. The code
+// challenge is derived from the verifier with S256.
+func (p *Provider) AuthorizationURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return d.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange redeems the authorization code and returns who logged in
+// according to the ID token. Providers that leave the email out of the ID
+// token are asked for it at their userinfo endpoint.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (domain.ExternalIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	ctx = oidc.ClientContext(ctx, p.client)
+	token, err := d.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: %w", ErrTokenExchange, err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: response has no id token", ErrTokenExchange)
+	}
+	idToken, c, err := p.verifyIDToken(ctx, d, rawIDToken, nonce)
+	if err != nil {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: %w", ErrIDToken, err)
+	}
+
+	identity := domain.ExternalIdentity{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         c.Email,
+		EmailVerified: bool(c.EmailVerified),
+	}
+	if identity.Email == "" && d.provider.UserInfoEndpoint() != "" && token.AccessToken != "" {
+		info, err := d.provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err != nil {
+			return domain.ExternalIdentity{}, fmt.Errorf("%w: %w", ErrUserInfo, err)
+		} else if info.Subject != idToken.Subject {
+			return domain.ExternalIdentity{}, fmt.Errorf("%w: subject does not match id token", ErrUserInfo)
+		}
+		identity.Email, identity.EmailVerified = info.Email, info.EmailVerified
+	}
+	return identity, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience and expiry with
+// go-oidc and the claims it doesn't check itself.
+func (p *Provider) verifyIDToken(ctx context.Context, d *discovery, rawIDToken, nonce string) (*oidc.IDToken, claims, error) {
+	idToken, err := d.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, claims{}, err
+	}
+	var c claims
+	if err = idToken.Claims(&c); err != nil {
+		return nil, claims{}, err
+	}
+	switch {
+	case idToken.Subject == "":
+		return nil, claims{}, errors.New("token has no subject")
+	case idToken.Nonce != nonce:
+		return nil, claims{}, errors.New("nonce does not match")
+	case c.AuthorizedParty != "" && c.AuthorizedParty != p.config.ClientID:
+		return nil, claims{}, fmt.Errorf("token was issued to %q", c.AuthorizedParty)
+	case idToken.IssuedAt.After(time.Now().Add(clockSkew)):
+		return nil, claims{}, errors.New("token was issued in the future")
+	}
+	return idToken, c, nil
+}
+
+// discover fetches the configuration of the provider once and keeps it. A
+// failed attempt is retried on the next login.
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	// go-oidc makes sure the provider calls itself by the configured issuer,
+	// otherwise ID tokens of another provider would be accepted.
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, p.client), p.config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+	var metadata struct {
+		CodeChallengeMethods []string `json:"code_challenge_methods_supported"`
+	}
+	if err = provider.Claims(&metadata); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+	endpoint := provider.Endpoint()
+	if endpoint.AuthURL == "" || endpoint.TokenURL == "" {
+		return nil, fmt.Errorf("%w: endpoints are missing", ErrDiscovery)
+	}
+	if len(metadata.CodeChallengeMethods) > 0 && !slices.Contains(metadata.CodeChallengeMethods, "S256") {
+		return nil, fmt.Errorf("%w: provider does not support PKCE with S256", ErrDiscovery)
+	}
+
+	p.discovery = &discovery{
+		provider: provider,
+		verifier: provider.Verifier(&oidc.Config{ClientID: p.config.ClientID}),
+		oauth2: oauth2.Config{
+			ClientID:     p.config.ClientID,
+			ClientSecret: p.config.ClientSecret,
+			Endpoint:     endpoint,
+			RedirectURL:  p.config.RedirectURL,
+			Scopes:       p.config.Scopes,
+		},
+	}
+	return p.discovery, nil
+}
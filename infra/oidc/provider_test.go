@@ -0,0 +1,149 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/oidc"
+	"github.com/wolfsblu/recipe-manager/infra/oidc/oidctest"
+)
+
+const (
+	nonce    = "nonce"
+	verifier = "0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+var alice = oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true}
+
+func newProvider(issuer *oidctest.Issuer) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		Issuer:       issuer.URL,
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  oidctest.RedirectURL,
+	}, nil)
+}
+
+// login goes through the authorization code flow and returns the code the
+// issuer sent back.
+func login(t *testing.T, issuer *oidctest.Issuer, provider *oidc.Provider, claims map[string]any) string {
+	t.Helper()
+	authorizationURL, err := provider.AuthorizationURL(context.Background(), "state", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+	redirect, err := issuer.Authorize(authorizationURL, alice, claims)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if state := redirect.Query().Get("state"); state != "state" {
+		t.Fatalf("Authorize() state = %q, want %q", state, "state")
+	}
+	return redirect.Query().Get("code")
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]any
+		nonce    string
+		verifier string
+		wantErr  error
+	}{
+		{name: "Valid"},
+		{name: "Other nonce", nonce: "other", wantErr: oidc.ErrIDToken},
+		{name: "Other verifier", verifier: verifier + "0", wantErr: oidc.ErrTokenExchange},
+		{name: "Other audience", claims: map[string]any{"aud": "other-client"}, wantErr: oidc.ErrIDToken},
+		{name: "Audiences", claims: map[string]any{"aud": []string{"other-client", oidctest.ClientID}}},
+		{name: "Other authorized party", claims: map[string]any{"azp": "other-client"}, wantErr: oidc.ErrIDToken},
+		{name: "Other issuer", claims: map[string]any{"iss": "https://evil.example.com"}, wantErr: oidc.ErrIDToken},
+		{name: "Expired", claims: map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}, wantErr: oidc.ErrIDToken},
+		{name: "Issued in the future", claims: map[string]any{"iat": time.Now().Add(time.Hour).Unix()}, wantErr: oidc.ErrIDToken},
+		{name: "Without subject", claims: map[string]any{"sub": nil}, wantErr: oidc.ErrIDToken},
+		{name: "Email verified as string", claims: map[string]any{"email_verified": "true"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := oidctest.NewIssuer(t)
+			provider := newProvider(issuer)
+			code := login(t, issuer, provider, tc.claims)
+			if tc.nonce == "" {
+				tc.nonce = nonce
+			}
+			if tc.verifier == "" {
+				tc.verifier = verifier
+			}
+
+			identity, err := provider.Exchange(context.Background(), code, tc.verifier, tc.nonce)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Exchange() error = %v, want %v", err, tc.wantErr)
+			}
+			want := domain.ExternalIdentity{Issuer: issuer.URL, Subject: "alice", Email: "alice@example.com", EmailVerified: true}
+			if err == nil && identity != want {
+				t.Errorf("Exchange() = %+v, want %+v", identity, want)
+			}
+		})
+	}
+}
+
+func TestExchangeCodeOnce(t *testing.T) {
+	issuer := oidctest.NewIssuer(t)
+	provider := newProvider(issuer)
+	code := login(t, issuer, provider, nil)
+	if _, err := provider.Exchange(context.Background(), code, verifier, nonce); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Exchange(context.Background(), code, verifier, nonce); !errors.Is(err, oidc.ErrTokenExchange) {
+		t.Errorf("Exchange() error = %v, want %v", err, oidc.ErrTokenExchange)
+	}
+}
+
+func TestExchangeUserInfo(t *testing.T) {
+	issuer := oidctest.NewIssuer(t)
+	issuer.EmailInUserInfo = true
+	provider := newProvider(issuer)
+	identity, err := provider.Exchange(context.Background(), login(t, issuer, provider, nil), verifier, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Email != alice.Email || !identity.EmailVerified {
+		t.Errorf("Exchange() = %+v, want the email from the userinfo endpoint", identity)
+	}
+}
+
+func TestExchangeSigningKeys(t *testing.T) {
+	issuer := oidctest.NewIssuer(t)
+	provider := newProvider(issuer)
+	if _, err := provider.Exchange(context.Background(), login(t, issuer, provider, nil), verifier, nonce); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new key isn't known yet, so the key set is fetched again
+	issuer.RotateKey()
+	if _, err := provider.Exchange(context.Background(), login(t, issuer, provider, nil), verifier, nonce); err != nil {
+		t.Errorf("Exchange() after key rotation error = %v", err)
+	}
+
+	issuer.UseUnpublishedKey()
+	if _, err := provider.Exchange(context.Background(), login(t, issuer, provider, nil), verifier, nonce); !errors.Is(err, oidc.ErrIDToken) {
+		t.Errorf("Exchange() with unpublished key error = %v, want %v", err, oidc.ErrIDToken)
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	issuer := oidctest.NewIssuer(t)
+	// The same provider under another name must not be trusted, its tokens
+	// name the real issuer.
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:   strings.Replace(issuer.URL, "127.0.0.1", "localhost", 1),
+		ClientID: oidctest.ClientID,
+	}, nil)
+	if _, err := provider.AuthorizationURL(context.Background(), "state", nonce, verifier); !errors.Is(err, oidc.ErrDiscovery) {
+		t.Errorf("AuthorizationURL() error = %v, want %v", err, oidc.ErrDiscovery)
+	}
+}
@@ -10,12 +10,15 @@ import (
 	"github.com/wolfsblu/recipe-manager/infra/env"
 )
 
-func NewServeMux(server *api.Server, uploadServer *tusd.Handler) *http.ServeMux {
+func NewServeMux(server *api.Server, uploadServer *tusd.Handler, eventServer http.Handler, calendarServer http.Handler, ssoServer http.Handler) *http.ServeMux {
 	mux := http.NewServeMux()
 	handleFrontend(mux)
 	handleImages(mux)
 	handleUploads(mux, uploadServer)
 	handleAPI(mux, server)
+	handleEvents(mux, eventServer)
+	handleCalendar(mux, calendarServer)
+	handleSingleSignOn(mux, ssoServer)
 	return mux
 }
 
@@ -36,6 +39,18 @@ func handleAPI(mux *http.ServeMux, apiServer http.Handler) {
 	mux.HandleFunc(config.APIPathPrefix+"/openapi.yml", apiDocs)
 }
 
+func handleEvents(mux *http.ServeMux, eventServer http.Handler) {
+	mux.Handle("GET "+config.APIPathPrefix+"/shopping-lists/{shoppingListId}/events", cors(eventServer))
+}
+
+func handleCalendar(mux *http.ServeMux, calendarServer http.Handler) {
+	mux.Handle("GET "+config.CalendarPathPrefix+"/{feed}", calendarServer)
+}
+
+func handleSingleSignOn(mux *http.ServeMux, ssoServer http.Handler) {
+	mux.Handle(config.SSOPathPrefix+"/", ssoServer)
+}
+
 func handleUploads(mux *http.ServeMux, uploadServer *tusd.Handler) {
 	mux.Handle(config.UploadPathPrefix+"/", cors(http.StripPrefix(config.UploadPathPrefix+"/", uploadServer)))
 	mux.Handle(config.UploadPathPrefix, cors(http.StripPrefix(config.UploadPathPrefix, uploadServer)))
@@ -0,0 +1,83 @@
+package schemaorg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const maxPageSize = 5 << 20
+
+var errForbiddenAddress = errors.New("refusing to connect to a non-public address")
+
+// Fetcher retrieves the HTML of a recipe page. It is an interface so that
+// tests can serve pages from a local server.
+type Fetcher interface {
+	Fetch(ctx context.Context, u *url.URL) ([]byte, error)
+}
+
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+// NewHTTPFetcher returns a fetcher that only connects to public addresses,
+// so users cannot make the server request pages from its own network.
+func NewHTTPFetcher() *HTTPFetcher {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: denyNonPublicAddresses,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &HTTPFetcher{
+		client: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "recipe-manager/1.0 (+recipe import)")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s fetching %s", res.Status, u)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxPageSize))
+}
+
+func denyNonPublicAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return errForbiddenAddress
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package schemaorg
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"golang.org/x/net/html"
+)
+
+type jsonObject = map[string]any
+
+func extractJSONLD(doc *html.Node) (domain.ScrapedRecipe, bool) {
+	for _, script := range findJSONLDScripts(doc) {
+		var data any
+		if err := json.Unmarshal([]byte(script), &data); err != nil {
+			continue
+		}
+		if recipe := findRecipeObject(data); recipe != nil {
+			return toScrapedRecipe(recipe), true
+		}
+	}
+	return domain.ScrapedRecipe{}, false
+}
+
+func findJSONLDScripts(n *html.Node) []string {
+	var scripts []string
+	if n.Type == html.ElementNode && n.Data == "script" {
+		if kind, _ := attr(n, "type"); strings.EqualFold(strings.TrimSpace(kind), "application/ld+json") && n.FirstChild != nil {
+			scripts = append(scripts, n.FirstChild.Data)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		scripts = append(scripts, findJSONLDScripts(c)...)
+	}
+	return scripts
+}
+
+// findRecipeObject searches arrays and @graph containers, which is how most
+// SEO plugins bundle the recipe with other page metadata.
+func findRecipeObject(data any) jsonObject {
+	switch v := data.(type) {
+	case []any:
+		for _, item := range v {
+			if recipe := findRecipeObject(item); recipe != nil {
+				return recipe
+			}
+		}
+	case jsonObject:
+		for _, t := range toStrings(v["@type"]) {
+			if isRecipeType(t) {
+				return v
+			}
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findRecipeObject(graph)
+		}
+	}
+	return nil
+}
+
+func toScrapedRecipe(obj jsonObject) domain.ScrapedRecipe {
+	recipe := domain.ScrapedRecipe{
+		Name:        cleanText(firstString(obj["name"])),
+		Description: cleanText(firstString(obj["description"])),
+		Minutes:     parseDuration(firstString(obj["totalTime"])),
+	}
+	if recipe.Minutes == 0 {
+		recipe.Minutes = parseDuration(firstString(obj["prepTime"])) + parseDuration(firstString(obj["cookTime"]))
+	}
+	for _, yield := range toStrings(obj["recipeYield"]) {
+		if recipe.Servings = parseYield(yield); recipe.Servings > 0 {
+			break
+		}
+	}
+
+	ingredients := obj["recipeIngredient"]
+	if ingredients == nil {
+		ingredients = obj["ingredients"]
+	}
+	for _, line := range toStrings(ingredients) {
+		if line = cleanText(line); line != "" {
+			recipe.Ingredients = append(recipe.Ingredients, line)
+		}
+	}
+
+	recipe.Instructions = toInstructions(obj["recipeInstructions"])
+	for _, image := range toImageURLs(obj["image"]) {
+		if u := parseImageURL(image); u != nil {
+			recipe.Images = append(recipe.Images, u)
+		}
+	}
+	return recipe
+}
+
+// toInstructions flattens plain text, HowToStep and HowToSection values.
+func toInstructions(data any) []string {
+	switch v := data.(type) {
+	case string:
+		return splitLines(v)
+	case []any:
+		var steps []string
+		for _, item := range v {
+			steps = append(steps, toInstructions(item)...)
+		}
+		return steps
+	case jsonObject:
+		if items, ok := v["itemListElement"]; ok {
+			return toInstructions(items)
+		}
+		text := firstString(v["text"])
+		if text == "" {
+			text = firstString(v["name"])
+		}
+		if text = cleanText(text); text != "" {
+			return []string{text}
+		}
+	}
+	return nil
+}
+
+func toImageURLs(data any) []string {
+	switch v := data.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var urls []string
+		for _, item := range v {
+			urls = append(urls, toImageURLs(item)...)
+		}
+		return urls
+	case jsonObject:
+		if u := firstString(v["url"]); u != "" {
+			return []string{u}
+		}
+		return toImageURLs(v["contentUrl"])
+	}
+	return nil
+}
+
+func toStrings(data any) []string {
+	switch v := data.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []any:
+		var values []string
+		for _, item := range v {
+			values = append(values, toStrings(item)...)
+		}
+		return values
+	}
+	return nil
+}
+
+func firstString(data any) string {
+	if values := toStrings(data); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
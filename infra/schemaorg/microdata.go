@@ -0,0 +1,144 @@
+package schemaorg
+
+import (
+	"strings"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"golang.org/x/net/html"
+)
+
+type microdataItem map[string][]microdataValue
+
+type microdataValue struct {
+	text string
+	item microdataItem
+}
+
+func extractMicrodata(doc *html.Node) (domain.ScrapedRecipe, bool) {
+	node := findMicrodataRecipe(doc)
+	if node == nil {
+		return domain.ScrapedRecipe{}, false
+	}
+	item := readMicrodataItem(node)
+
+	recipe := domain.ScrapedRecipe{
+		Name:        item.first("name"),
+		Description: item.first("description"),
+		Minutes:     parseDuration(item.first("totalTime")),
+	}
+	if recipe.Minutes == 0 {
+		recipe.Minutes = parseDuration(item.first("prepTime")) + parseDuration(item.first("cookTime"))
+	}
+	for _, yield := range item["recipeYield"] {
+		if recipe.Servings = parseYield(yield.text); recipe.Servings > 0 {
+			break
+		}
+	}
+
+	ingredients := item["recipeIngredient"]
+	if len(ingredients) == 0 {
+		ingredients = item["ingredients"]
+	}
+	for _, line := range ingredients {
+		if line.text != "" {
+			recipe.Ingredients = append(recipe.Ingredients, line.text)
+		}
+	}
+
+	for _, step := range item["recipeInstructions"] {
+		if step.item != nil && step.item.first("text") != "" {
+			recipe.Instructions = append(recipe.Instructions, step.item.first("text"))
+		} else if step.text != "" {
+			recipe.Instructions = append(recipe.Instructions, step.text)
+		}
+	}
+
+	for _, image := range item["image"] {
+		value := image.text
+		if image.item != nil {
+			value = image.item.first("url")
+			if value == "" {
+				value = image.item.first("contentUrl")
+			}
+		}
+		if u := parseImageURL(value); u != nil {
+			recipe.Images = append(recipe.Images, u)
+		}
+	}
+	return recipe, true
+}
+
+func findMicrodataRecipe(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode {
+		_, scoped := attr(n, "itemscope")
+		itemType, _ := attr(n, "itemtype")
+		if scoped && isRecipeType(itemType) {
+			return n
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findMicrodataRecipe(c); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// readMicrodataItem collects the properties of an itemscope element. Nested
+// items are read separately and not searched for properties of the parent.
+func readMicrodataItem(scope *html.Node) microdataItem {
+	item := microdataItem{}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			props, _ := attr(c, "itemprop")
+			if _, scoped := attr(c, "itemscope"); scoped {
+				value := microdataValue{text: textContent(c), item: readMicrodataItem(c)}
+				for _, prop := range strings.Fields(props) {
+					item[prop] = append(item[prop], value)
+				}
+				continue
+			}
+			if props != "" {
+				value := microdataValue{text: propertyValue(c)}
+				for _, prop := range strings.Fields(props) {
+					item[prop] = append(item[prop], value)
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(scope)
+	return item
+}
+
+func propertyValue(n *html.Node) string {
+	if content, ok := attr(n, "content"); ok {
+		return cleanText(content)
+	}
+	var key string
+	switch n.Data {
+	case "img", "audio", "video", "source", "iframe", "embed", "track":
+		key = "src"
+	case "a", "area", "link":
+		key = "href"
+	case "time":
+		key = "datetime"
+	case "data", "meter":
+		key = "value"
+	}
+	if value, ok := attr(n, key); key != "" && ok {
+		return strings.TrimSpace(value)
+	}
+	return textContent(n)
+}
+
+func (i microdataItem) first(prop string) string {
+	if values := i[prop]; len(values) > 0 {
+		return values[0].text
+	}
+	return ""
+}
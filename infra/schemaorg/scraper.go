@@ -0,0 +1,153 @@
+package schemaorg
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"golang.org/x/net/html"
+)
+
+var (
+	durationPattern = regexp.MustCompile(`(?i)^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	numberPattern   = regexp.MustCompile(`\d+`)
+	tagPattern      = regexp.MustCompile(`<[^>]*>`)
+)
+
+type Scraper struct {
+	fetcher Fetcher
+}
+
+func NewScraper(fetcher Fetcher) *Scraper {
+	return &Scraper{
+		fetcher: fetcher,
+	}
+}
+
+// Scrape extracts the first schema.org/Recipe from the page, preferring
+// JSON-LD over microdata since it is usually the more complete of the two.
+func (s *Scraper) Scrape(ctx context.Context, source domain.RecipeImportSource) (domain.ScrapedRecipe, error) {
+	page := source.HTML
+	base := source.BaseURL
+	if source.URL != nil {
+		var err error
+		if page, err = s.fetcher.Fetch(ctx, source.URL); err != nil {
+			log.Printf("failed to fetch %s: %v", source.URL, err)
+			return domain.ScrapedRecipe{}, domain.ErrFetchingRecipePage
+		}
+		base = source.URL
+	}
+
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return domain.ScrapedRecipe{}, domain.ErrRecipeNotFoundInPage
+	}
+
+	recipe, ok := extractJSONLD(doc)
+	if !ok {
+		recipe, ok = extractMicrodata(doc)
+	}
+	if !ok {
+		return domain.ScrapedRecipe{}, domain.ErrRecipeNotFoundInPage
+	}
+	recipe.Images = resolveImages(base, recipe.Images)
+	return recipe, nil
+}
+
+func resolveImages(base *url.URL, images []*url.URL) []*url.URL {
+	resolved := make([]*url.URL, 0, len(images))
+	for _, image := range images {
+		if base != nil {
+			image = base.ResolveReference(image)
+		}
+		if image.Scheme == "http" || image.Scheme == "https" {
+			resolved = append(resolved, image)
+		}
+	}
+	return resolved
+}
+
+func parseImageURL(value string) *url.URL {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// parseDuration converts ISO 8601 durations like PT1H30M to minutes.
+func parseDuration(value string) int64 {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0
+	}
+	days, _ := strconv.ParseInt(match[1], 10, 64)
+	hours, _ := strconv.ParseInt(match[2], 10, 64)
+	minutes, _ := strconv.ParseInt(match[3], 10, 64)
+	seconds, _ := strconv.ParseFloat(match[4], 64)
+	return days*24*60 + hours*60 + minutes + int64(math.Round(seconds/60))
+}
+
+// parseYield reads servings from values like "4", "4 servings" or "Serves 4-6".
+func parseYield(value string) int64 {
+	servings, _ := strconv.ParseInt(numberPattern.FindString(value), 10, 64)
+	return servings
+}
+
+// cleanText strips markup and collapses whitespace, since many sites embed
+// HTML and entities in their structured data.
+func cleanText(value string) string {
+	value = tagPattern.ReplaceAllString(value, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(value)), " ")
+}
+
+func splitLines(value string) []string {
+	value = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n").Replace(value)
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = cleanText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return cleanText(sb.String())
+}
+
+func isRecipeType(value string) bool {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "/")
+	return value == "Recipe" || strings.HasSuffix(value, "schema.org/Recipe") || value == "schema:Recipe"
+}
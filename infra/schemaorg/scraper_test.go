@@ -0,0 +1,214 @@
+package schemaorg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+)
+
+const jsonLDPage = `<!doctype html>
+<html>
+<head>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "WebSite", "name": "Cooking Blog"},
+    {
+      "@type": ["Recipe", "NewsArticle"],
+      "name": "Tomato Soup &amp; Basil",
+      "description": "<p>A quick weeknight soup.</p>",
+      "recipeYield": ["4", "4 servings"],
+      "prepTime": "PT10M",
+      "cookTime": "PT1H5M",
+      "image": [{"@type": "ImageObject", "url": "/images/soup.jpg"}, "https://cdn.example.com/soup-wide.jpg"],
+      "recipeIngredient": ["800 g tomatoes", "1 onion, diced", "2 tbsp olive oil"],
+      "recipeInstructions": [
+        {
+          "@type": "HowToSection",
+          "name": "Soup",
+          "itemListElement": [
+            {"@type": "HowToStep", "text": "Sweat the onion in the oil."},
+            {"@type": "HowToStep", "text": "Add tomatoes and simmer."}
+          ]
+        },
+        "Blend until smooth."
+      ]
+    }
+  ]
+}
+</script>
+</head>
+<body><h1>Tomato Soup</h1></body>
+</html>`
+
+const microdataPage = `<!doctype html>
+<html>
+<body>
+<article itemscope itemtype="http://schema.org/Recipe">
+  <h1 itemprop="name">Pancakes</h1>
+  <img itemprop="image" src="pancakes.jpg" alt="">
+  <p itemprop="description">Fluffy   breakfast pancakes.</p>
+  <meta itemprop="totalTime" content="PT25M">
+  <span itemprop="recipeYield">Serves 6</span>
+  <ul>
+    <li itemprop="recipeIngredient">250 g flour</li>
+    <li itemprop="recipeIngredient">2 eggs</li>
+  </ul>
+  <ol>
+    <li itemprop="recipeInstructions" itemscope itemtype="http://schema.org/HowToStep">
+      <span itemprop="text">Whisk everything together.</span>
+    </li>
+    <li itemprop="recipeInstructions">Fry in a hot pan.</li>
+  </ol>
+  <div itemprop="author" itemscope itemtype="http://schema.org/Person">
+    <span itemprop="name">Jane</span>
+  </div>
+</article>
+</body>
+</html>`
+
+func TestScraperScrapeURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json-ld", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(jsonLDPage))
+	})
+	mux.HandleFunc("/microdata/pancakes", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(microdataPage))
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body>No recipe here</body></html>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := NewScraper(&HTTPFetcher{client: server.Client()})
+
+	tests := []struct {
+		name    string
+		path    string
+		want    domain.ScrapedRecipe
+		wantErr error
+	}{
+		{
+			name: "JSON-LD recipe inside a graph",
+			path: "/json-ld",
+			want: domain.ScrapedRecipe{
+				Name:         "Tomato Soup & Basil",
+				Description:  "A quick weeknight soup.",
+				Servings:     4,
+				Minutes:      75,
+				Instructions: []string{"Sweat the onion in the oil.", "Add tomatoes and simmer.", "Blend until smooth."},
+				Ingredients:  []string{"800 g tomatoes", "1 onion, diced", "2 tbsp olive oil"},
+				Images:       []*url.URL{mustParseURL(t, server.URL+"/images/soup.jpg"), mustParseURL(t, "https://cdn.example.com/soup-wide.jpg")},
+			},
+		},
+		{
+			name: "Microdata recipe",
+			path: "/microdata/pancakes",
+			want: domain.ScrapedRecipe{
+				Name:         "Pancakes",
+				Description:  "Fluffy breakfast pancakes.",
+				Servings:     6,
+				Minutes:      25,
+				Instructions: []string{"Whisk everything together.", "Fry in a hot pan."},
+				Ingredients:  []string{"250 g flour", "2 eggs"},
+				Images:       []*url.URL{mustParseURL(t, server.URL+"/microdata/pancakes.jpg")},
+			},
+		},
+		{
+			name:    "Page without a recipe",
+			path:    "/plain",
+			wantErr: domain.ErrRecipeNotFoundInPage,
+		},
+		{
+			name:    "Missing page",
+			path:    "/missing",
+			wantErr: domain.ErrFetchingRecipePage,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := scraper.Scrape(context.Background(), domain.RecipeImportSource{
+				URL: mustParseURL(t, server.URL+tc.path),
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Scrape() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Scrape() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScraperScrapeHTML(t *testing.T) {
+	scraper := NewScraper(nil)
+	got, err := scraper.Scrape(context.Background(), domain.RecipeImportSource{
+		HTML:    []byte(microdataPage),
+		BaseURL: mustParseURL(t, "https://blog.example.com/recipes/"),
+	})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if got.Name != "Pancakes" {
+		t.Errorf("Scrape() name = %q, want %q", got.Name, "Pancakes")
+	}
+	if len(got.Images) != 1 || got.Images[0].String() != "https://blog.example.com/recipes/pancakes.jpg" {
+		t.Errorf("Scrape() images = %v, want resolved against base url", got.Images)
+	}
+}
+
+func TestHTTPFetcherRejectsLocalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(jsonLDPage))
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPFetcher().Fetch(context.Background(), mustParseURL(t, server.URL))
+	if !errors.Is(err, errForbiddenAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, errForbiddenAddress)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{value: "PT45M", want: 45},
+		{value: "PT1H30M", want: 90},
+		{value: "P0DT2H", want: 120},
+		{value: "P1D", want: 1440},
+		{value: "PT90S", want: 2},
+		{value: "45 minutes", want: 0},
+		{value: "", want: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := parseDuration(tc.value); got != tc.want {
+				t.Errorf("parseDuration(%q) = %d, want %d", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("failed to parse url %q: %v", raw, err)
+	}
+	return u
+}
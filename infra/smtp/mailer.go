@@ -41,6 +41,21 @@ func (s *Mailer) SendUserRegistration(registration domain.UserRegistration) erro
 	return nil
 }
 
+func (s *Mailer) SendHouseholdInvitation(invitation domain.HouseholdInvitation) error {
+	tpl, err := buildTemplate("household-invitation.html", HouseholdInvitationTemplate{
+		HouseholdName: invitation.Household.Name,
+		InvitedBy:     invitation.InvitedBy.Email,
+		JoinLink:      buildUrlWithQuery("households/join", map[string]string{"token": invitation.Token}),
+	})
+	if err != nil {
+		return err
+	}
+	if err = s.sendMessage(s.config.User, invitation.Email, "Household Invitation", tpl); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *Mailer) sendMessage(sender, recipient, subject, body string) error {
 	msg := gomail.NewMessage()
 	msg.SetHeader("From", sender)
@@ -17,6 +17,12 @@ type UserRegistrationTemplate struct {
 	ConfirmLink string
 }
 
+type HouseholdInvitationTemplate struct {
+	HouseholdName string
+	InvitedBy     string
+	JoinLink      string
+}
+
 func buildTemplate(path string, data any) (string, error) {
 	t := template.New(path)
 	t, err := t.ParseFS(templateFS, fmt.Sprintf("templates/%s", path))
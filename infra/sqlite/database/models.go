@@ -8,9 +8,55 @@ import (
 	"time"
 )
 
+type ApiToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	TokenHash  string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+type ApiTokenPermission struct {
+	ApiTokenID   int64
+	PermissionID int64
+}
+
+type CalendarToken struct {
+	UserID    int64
+	TokenHash string
+	CreatedAt time.Time
+}
+
+type Household struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type HouseholdInvitation struct {
+	ID          int64
+	HouseholdID int64
+	Email       string
+	Role        string
+	Token       string
+	InvitedBy   int64
+	CreatedAt   time.Time
+}
+
+type HouseholdMember struct {
+	HouseholdID int64
+	UserID      int64
+	Role        string
+}
+
 type Ingredient struct {
-	ID   int64
-	Name string
+	ID              int64
+	Name            string
+	Density         *float64
+	ReferenceAmount float64
+	ReferenceUnitID *int64
 }
 
 type IngredientNutrient struct {
@@ -19,12 +65,62 @@ type IngredientNutrient struct {
 	Amount       float64
 }
 
-type MealPlan struct {
+type LoginChallenge struct {
 	ID        int64
-	Date      string
 	UserID    int64
-	RecipeID  int64
-	SortOrder int64
+	Token     string
+	Attempts  int64
+	CreatedAt time.Time
+}
+
+type MealPlan struct {
+	ID              int64
+	Date            string
+	UserID          int64
+	RecipeID        *int64
+	SortOrder       int64
+	Servings        *int64
+	HouseholdID     *int64
+	SlotID          *int64
+	Note            *string
+	TemplateEntryID *int64
+}
+
+type MealPlanTemplate struct {
+	ID          int64
+	HouseholdID int64
+	UserID      int64
+	Name        string
+	Recurring   bool
+}
+
+type MealPlanTemplateEntry struct {
+	ID         int64
+	TemplateID int64
+	Weekday    int64
+	SlotID     *int64
+	RecipeID   *int64
+	Note       *string
+	Servings   *int64
+	SortOrder  int64
+}
+
+type MealPlanTemplateException struct {
+	TemplateEntryID int64
+	Date            string
+}
+
+type MealPlanTemplateWeek struct {
+	TemplateID int64
+	Week       string
+}
+
+type MealSlot struct {
+	ID          int64
+	HouseholdID int64
+	Name        string
+	SortOrder   int64
+	Time        *string
 }
 
 type Nutrient struct {
@@ -33,6 +129,27 @@ type Nutrient struct {
 	Unit string
 }
 
+type Passkey struct {
+	ID           int64
+	UserID       int64
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    int64
+	Flags        *int64
+	Name         string
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+type PasskeyChallenge struct {
+	ID        int64
+	Token     string
+	Ceremony  string
+	UserID    *int64
+	Session   string
+	CreatedAt time.Time
+}
+
 type PasswordReset struct {
 	UserID    int64
 	Token     string
@@ -53,6 +170,7 @@ type Recipe struct {
 	Description string
 	CreatedBy   int64
 	CreatedAt   time.Time
+	HouseholdID *int64
 }
 
 type RecipeImage struct {
@@ -84,6 +202,14 @@ type RecipeTag struct {
 	TagID    int64
 }
 
+type RecipesFt struct {
+	Name         string
+	Description  string
+	Instructions string
+	Ingredients  string
+	Tags         string
+}
+
 type Role struct {
 	ID   int64
 	Name string
@@ -94,10 +220,22 @@ type RolePermission struct {
 	PermissionID int64
 }
 
+type Session struct {
+	ID         int64
+	UserID     int64
+	TokenHash  string
+	UserAgent  string
+	IpAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
 type ShoppingList struct {
-	ID     int64
-	UserID int64
-	Name   string
+	ID          int64
+	UserID      int64
+	Name        string
+	HouseholdID *int64
+	Revision    int64
 }
 
 type ShoppingListItem struct {
@@ -108,6 +246,53 @@ type ShoppingListItem struct {
 	Unit           *string
 	Done           bool
 	SortOrder      int64
+	IngredientID   *int64
+	UnitID         *int64
+	Amount         *float64
+	Version        int64
+	Revision       int64
+	UpdatedAt      *time.Time
+	ClientID       *string
+}
+
+type ShoppingListItemDeletion struct {
+	ShoppingListID int64
+	ItemID         int64
+	ClientID       *string
+	Revision       int64
+}
+
+type ShoppingListItemRecipe struct {
+	ShoppingListItemID int64
+	RecipeID           int64
+}
+
+type SsoLogin struct {
+	ID           int64
+	State        string
+	Nonce        string
+	CodeVerifier string
+	CreatedAt    time.Time
+}
+
+type Store struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	HouseholdID *int64
+}
+
+type StoreSection struct {
+	ID        int64
+	StoreID   int64
+	Name      string
+	SortOrder int64
+}
+
+type StoreSectionIngredient struct {
+	StoreID      int64
+	IngredientID int64
+	SectionID    int64
 }
 
 type Tag struct {
@@ -116,9 +301,12 @@ type Tag struct {
 }
 
 type Unit struct {
-	ID     int64
-	Symbol *string
-	Name   string
+	ID        int64
+	Symbol    *string
+	Name      string
+	Dimension *string
+	System    *string
+	Factor    *float64
 }
 
 type User struct {
@@ -131,8 +319,36 @@ type User struct {
 	CreatedAt    time.Time
 }
 
+type UserIdentity struct {
+	ID        int64
+	UserID    int64
+	Issuer    string
+	Subject   string
+	CreatedAt time.Time
+}
+
+type UserNutrientTarget struct {
+	UserID     int64
+	NutrientID int64
+	Amount     float64
+}
+
+type UserRecoveryCode struct {
+	ID       int64
+	UserID   int64
+	CodeHash string
+}
+
 type UserRegistration struct {
 	UserID    int64
 	Token     string
 	CreatedAt time.Time
 }
+
+type UserTotp struct {
+	UserID       int64
+	Secret       string
+	Confirmed    bool
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
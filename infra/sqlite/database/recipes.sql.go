@@ -27,77 +27,278 @@ func (q *Queries) AddIngredientNutrient(ctx context.Context, arg AddIngredientNu
 	return err
 }
 
-const browseRecipes = `-- name: BrowseRecipes :many
-SELECT id, name, servings, minutes, description, created_by, created_at
-FROM recipes
+const appendMealPlanEntry = `-- name: AppendMealPlanEntry :exec
+UPDATE meal_plan
+SET household_id = ?1,
+    slot_id      = ?2,
+    sort_order   = (SELECT COALESCE(MAX(other.sort_order) + 1, 0)
+                    FROM meal_plan AS other
+                    WHERE other.household_id = ?1
+                      AND other.date = meal_plan.date
+                      AND other.slot_id IS ?2)
+WHERE meal_plan.id = ?3
 `
 
-func (q *Queries) BrowseRecipes(ctx context.Context) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, browseRecipes)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Recipe
-	for rows.Next() {
-		var i Recipe
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Servings,
-			&i.Minutes,
-			&i.Description,
-			&i.CreatedBy,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type AppendMealPlanEntryParams struct {
+	HouseholdID *int64
+	SlotID      *int64
+	ID          int64
+}
+
+func (q *Queries) AppendMealPlanEntry(ctx context.Context, arg AppendMealPlanEntryParams) error {
+	_, err := q.db.ExecContext(ctx, appendMealPlanEntry, arg.HouseholdID, arg.SlotID, arg.ID)
+	return err
 }
 
 const createIngredient = `-- name: CreateIngredient :one
-INSERT INTO ingredients (name)
-VALUES (?)
+INSERT INTO ingredients (name, density, reference_amount, reference_unit_id)
+VALUES (?, ?, ?, ?)
 RETURNING id
 `
 
-func (q *Queries) CreateIngredient(ctx context.Context, name string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, createIngredient, name)
+type CreateIngredientParams struct {
+	Name            string
+	Density         *float64
+	ReferenceAmount float64
+	ReferenceUnitID *int64
+}
+
+func (q *Queries) CreateIngredient(ctx context.Context, arg CreateIngredientParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createIngredient,
+		arg.Name,
+		arg.Density,
+		arg.ReferenceAmount,
+		arg.ReferenceUnitID,
+	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
 const createMealPlan = `-- name: CreateMealPlan :exec
-INSERT INTO meal_plan (date, user_id, recipe_id, sort_order)
-VALUES (?, ?, ?, ?)
+INSERT INTO meal_plan (date, household_id, user_id, recipe_id, servings, slot_id, note, sort_order)
+SELECT ?1,
+       ?2,
+       ?3,
+       ?4,
+       ?5,
+       ?6,
+       ?7,
+       COALESCE(MAX(sort_order) + 1, 0)
+FROM meal_plan
+WHERE household_id = ?2
+  AND date = ?1
+  AND slot_id IS ?6
 `
 
 type CreateMealPlanParams struct {
-	Date      string
-	UserID    int64
-	RecipeID  int64
-	SortOrder int64
+	Date        string
+	HouseholdID *int64
+	UserID      int64
+	RecipeID    *int64
+	Servings    *int64
+	SlotID      *int64
+	Note        *string
 }
 
 func (q *Queries) CreateMealPlan(ctx context.Context, arg CreateMealPlanParams) error {
 	_, err := q.db.ExecContext(ctx, createMealPlan,
 		arg.Date,
+		arg.HouseholdID,
+		arg.UserID,
+		arg.RecipeID,
+		arg.Servings,
+		arg.SlotID,
+		arg.Note,
+	)
+	return err
+}
+
+const createMealPlanOccurrence = `-- name: CreateMealPlanOccurrence :exec
+INSERT INTO meal_plan (date, household_id, user_id, recipe_id, servings, slot_id, note, template_entry_id, sort_order)
+SELECT ?1,
+       ?2,
+       ?3,
+       ?4,
+       ?5,
+       ?6,
+       ?7,
+       ?8,
+       next.sort_order
+FROM (SELECT COALESCE(MAX(sort_order) + 1, 0) AS sort_order
+      FROM meal_plan
+      WHERE household_id = ?2
+        AND date = ?1
+        AND slot_id IS ?6) AS next
+WHERE EXISTS (SELECT 1
+              FROM meal_plan
+              WHERE template_entry_id = ?8
+                AND date = ?1) = FALSE
+  AND EXISTS (SELECT 1
+              FROM meal_plan_template_exceptions
+              WHERE template_entry_id = ?8
+                AND date = ?1) = FALSE
+`
+
+type CreateMealPlanOccurrenceParams struct {
+	Date            string
+	HouseholdID     *int64
+	UserID          int64
+	RecipeID        *int64
+	Servings        *int64
+	SlotID          *int64
+	Note            *string
+	TemplateEntryID *int64
+}
+
+func (q *Queries) CreateMealPlanOccurrence(ctx context.Context, arg CreateMealPlanOccurrenceParams) error {
+	_, err := q.db.ExecContext(ctx, createMealPlanOccurrence,
+		arg.Date,
+		arg.HouseholdID,
+		arg.UserID,
+		arg.RecipeID,
+		arg.Servings,
+		arg.SlotID,
+		arg.Note,
+		arg.TemplateEntryID,
+	)
+	return err
+}
+
+const createMealPlanTemplate = `-- name: CreateMealPlanTemplate :one
+INSERT INTO meal_plan_templates (household_id, user_id, name, recurring)
+VALUES (?, ?, ?, ?)
+RETURNING id, household_id, user_id, name, recurring
+`
+
+type CreateMealPlanTemplateParams struct {
+	HouseholdID int64
+	UserID      int64
+	Name        string
+	Recurring   bool
+}
+
+func (q *Queries) CreateMealPlanTemplate(ctx context.Context, arg CreateMealPlanTemplateParams) (MealPlanTemplate, error) {
+	row := q.db.QueryRowContext(ctx, createMealPlanTemplate,
+		arg.HouseholdID,
 		arg.UserID,
+		arg.Name,
+		arg.Recurring,
+	)
+	var i MealPlanTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.HouseholdID,
+		&i.UserID,
+		&i.Name,
+		&i.Recurring,
+	)
+	return i, err
+}
+
+const createMealPlanTemplateEntry = `-- name: CreateMealPlanTemplateEntry :one
+INSERT INTO meal_plan_template_entries (template_id, weekday, slot_id, recipe_id, note, servings, sort_order)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, template_id, weekday, slot_id, recipe_id, note, servings, sort_order
+`
+
+type CreateMealPlanTemplateEntryParams struct {
+	TemplateID int64
+	Weekday    int64
+	SlotID     *int64
+	RecipeID   *int64
+	Note       *string
+	Servings   *int64
+	SortOrder  int64
+}
+
+func (q *Queries) CreateMealPlanTemplateEntry(ctx context.Context, arg CreateMealPlanTemplateEntryParams) (MealPlanTemplateEntry, error) {
+	row := q.db.QueryRowContext(ctx, createMealPlanTemplateEntry,
+		arg.TemplateID,
+		arg.Weekday,
+		arg.SlotID,
 		arg.RecipeID,
+		arg.Note,
+		arg.Servings,
 		arg.SortOrder,
 	)
+	var i MealPlanTemplateEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Weekday,
+		&i.SlotID,
+		&i.RecipeID,
+		&i.Note,
+		&i.Servings,
+		&i.SortOrder,
+	)
+	return i, err
+}
+
+const createMealPlanTemplateException = `-- name: CreateMealPlanTemplateException :exec
+INSERT OR IGNORE INTO meal_plan_template_exceptions (template_entry_id, date)
+SELECT template_entry_id, date
+FROM meal_plan
+WHERE id = ? AND household_id = ? AND template_entry_id IS NOT NULL
+`
+
+type CreateMealPlanTemplateExceptionParams struct {
+	ID          int64
+	HouseholdID *int64
+}
+
+func (q *Queries) CreateMealPlanTemplateException(ctx context.Context, arg CreateMealPlanTemplateExceptionParams) error {
+	_, err := q.db.ExecContext(ctx, createMealPlanTemplateException, arg.ID, arg.HouseholdID)
+	return err
+}
+
+const createMealPlanTemplateWeek = `-- name: CreateMealPlanTemplateWeek :exec
+INSERT OR IGNORE INTO meal_plan_template_weeks (template_id, week)
+VALUES (?, ?)
+`
+
+type CreateMealPlanTemplateWeekParams struct {
+	TemplateID int64
+	Week       string
+}
+
+func (q *Queries) CreateMealPlanTemplateWeek(ctx context.Context, arg CreateMealPlanTemplateWeekParams) error {
+	_, err := q.db.ExecContext(ctx, createMealPlanTemplateWeek, arg.TemplateID, arg.Week)
 	return err
 }
 
+const createMealSlot = `-- name: CreateMealSlot :one
+INSERT INTO meal_slots (household_id, name, sort_order, time)
+VALUES (?, ?, ?, ?)
+RETURNING id, household_id, name, sort_order, time
+`
+
+type CreateMealSlotParams struct {
+	HouseholdID int64
+	Name        string
+	SortOrder   int64
+	Time        *string
+}
+
+func (q *Queries) CreateMealSlot(ctx context.Context, arg CreateMealSlotParams) (MealSlot, error) {
+	row := q.db.QueryRowContext(ctx, createMealSlot,
+		arg.HouseholdID,
+		arg.Name,
+		arg.SortOrder,
+		arg.Time,
+	)
+	var i MealSlot
+	err := row.Scan(
+		&i.ID,
+		&i.HouseholdID,
+		&i.Name,
+		&i.SortOrder,
+		&i.Time,
+	)
+	return i, err
+}
+
 const createNutrient = `-- name: CreateNutrient :one
 INSERT INTO nutrients (name, unit)
 VALUES (?, ?)
@@ -117,8 +318,8 @@ func (q *Queries) CreateNutrient(ctx context.Context, arg CreateNutrientParams)
 }
 
 const createRecipe = `-- name: CreateRecipe :one
-INSERT INTO recipes (name, servings, minutes, description, created_by)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO recipes (name, servings, minutes, description, created_by, household_id)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING id
 `
 
@@ -128,6 +329,7 @@ type CreateRecipeParams struct {
 	Minutes     int64
 	Description string
 	CreatedBy   int64
+	HouseholdID *int64
 }
 
 func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (int64, error) {
@@ -137,6 +339,7 @@ func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (int
 		arg.Minutes,
 		arg.Description,
 		arg.CreatedBy,
+		arg.HouseholdID,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -224,18 +427,27 @@ func (q *Queries) CreateStepIngredient(ctx context.Context, arg CreateStepIngred
 }
 
 const createUnit = `-- name: CreateUnit :one
-INSERT INTO units (name, symbol)
-VALUES (?, ?)
+INSERT INTO units (name, symbol, dimension, system, factor)
+VALUES (?, ?, ?, ?, ?)
 RETURNING id
 `
 
 type CreateUnitParams struct {
-	Name   string
-	Symbol *string
+	Name      string
+	Symbol    *string
+	Dimension *string
+	System    *string
+	Factor    *float64
 }
 
 func (q *Queries) CreateUnit(ctx context.Context, arg CreateUnitParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, createUnit, arg.Name, arg.Symbol)
+	row := q.db.QueryRowContext(ctx, createUnit,
+		arg.Name,
+		arg.Symbol,
+		arg.Dimension,
+		arg.System,
+		arg.Factor,
+	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
@@ -261,19 +473,67 @@ func (q *Queries) DeleteIngredientNutrients(ctx context.Context, ingredientID in
 	return err
 }
 
-const deleteMealPlan = `-- name: DeleteMealPlan :exec
+const deleteMealPlanEntry = `-- name: DeleteMealPlanEntry :execrows
 DELETE FROM meal_plan
-WHERE user_id = ? AND recipe_id = ? AND date = ?
+WHERE id = ? AND household_id = ?
 `
 
-type DeleteMealPlanParams struct {
-	UserID   int64
-	RecipeID int64
-	Date     string
+type DeleteMealPlanEntryParams struct {
+	ID          int64
+	HouseholdID *int64
+}
+
+func (q *Queries) DeleteMealPlanEntry(ctx context.Context, arg DeleteMealPlanEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMealPlanEntry, arg.ID, arg.HouseholdID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMealPlanTemplate = `-- name: DeleteMealPlanTemplate :exec
+DELETE FROM meal_plan_templates
+WHERE id = ?
+`
+
+func (q *Queries) DeleteMealPlanTemplate(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMealPlanTemplate, id)
+	return err
+}
+
+const deleteMealPlanTemplateEntry = `-- name: DeleteMealPlanTemplateEntry :exec
+DELETE FROM meal_plan_template_entries
+WHERE id = ?
+`
+
+func (q *Queries) DeleteMealPlanTemplateEntry(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMealPlanTemplateEntry, id)
+	return err
+}
+
+const deleteMealPlanTemplateOccurrences = `-- name: DeleteMealPlanTemplateOccurrences :exec
+DELETE FROM meal_plan
+WHERE template_entry_id IN (SELECT id FROM meal_plan_template_entries WHERE template_id = ?)
+  AND date >= ?
+`
+
+type DeleteMealPlanTemplateOccurrencesParams struct {
+	TemplateID int64
+	Date       string
+}
+
+func (q *Queries) DeleteMealPlanTemplateOccurrences(ctx context.Context, arg DeleteMealPlanTemplateOccurrencesParams) error {
+	_, err := q.db.ExecContext(ctx, deleteMealPlanTemplateOccurrences, arg.TemplateID, arg.Date)
+	return err
 }
 
-func (q *Queries) DeleteMealPlan(ctx context.Context, arg DeleteMealPlanParams) error {
-	_, err := q.db.ExecContext(ctx, deleteMealPlan, arg.UserID, arg.RecipeID, arg.Date)
+const deleteMealSlot = `-- name: DeleteMealSlot :exec
+DELETE FROM meal_slots
+WHERE id = ?
+`
+
+func (q *Queries) DeleteMealSlot(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMealSlot, id)
 	return err
 }
 
@@ -350,6 +610,22 @@ func (q *Queries) DeleteUnit(ctx context.Context, id int64) error {
 	return err
 }
 
+const detachMealPlanEntry = `-- name: DetachMealPlanEntry :exec
+UPDATE meal_plan
+SET template_entry_id = NULL
+WHERE id = ? AND household_id = ?
+`
+
+type DetachMealPlanEntryParams struct {
+	ID          int64
+	HouseholdID *int64
+}
+
+func (q *Queries) DetachMealPlanEntry(ctx context.Context, arg DetachMealPlanEntryParams) error {
+	_, err := q.db.ExecContext(ctx, detachMealPlanEntry, arg.ID, arg.HouseholdID)
+	return err
+}
+
 const getImagesForRecipes = `-- name: GetImagesForRecipes :many
 SELECT id, path, sort_order, recipe_id
 FROM recipe_images
@@ -405,7 +681,7 @@ func (q *Queries) GetImagesForRecipes(ctx context.Context, recipeIds []int64) ([
 }
 
 const getIngredients = `-- name: GetIngredients :many
-SELECT id, name
+SELECT id, name, density, reference_amount, reference_unit_id
 FROM ingredients
 ORDER BY name
 `
@@ -419,7 +695,13 @@ func (q *Queries) GetIngredients(ctx context.Context) ([]Ingredient, error) {
 	var items []Ingredient
 	for rows.Next() {
 		var i Ingredient
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Density,
+			&i.ReferenceAmount,
+			&i.ReferenceUnitID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -437,16 +719,28 @@ const getIngredientsForRecipes = `-- name: GetIngredientsForRecipes :many
 SELECT recipe_ingredients.id as recipe_ingredient_id, 
        ingredients.id as ingredient_id, 
        ingredients.name as ingredient_name, 
-       units.id as unit_id, 
-       units.name as unit_name, 
-       units.symbol as unit_symbol, 
+       recipe_units.id as unit_id, 
+       recipe_units.name as unit_name, 
+       recipe_units.symbol as unit_symbol, 
+       recipe_units.dimension as unit_dimension, 
+       recipe_units.system as unit_system, 
+       recipe_units.factor as unit_factor, 
+       ingredients.density as ingredient_density, 
+       ingredients.reference_amount as ingredient_reference_amount, 
+       reference_units.id as reference_unit_id, 
+       reference_units.name as reference_unit_name, 
+       reference_units.symbol as reference_unit_symbol, 
+       reference_units.dimension as reference_unit_dimension, 
+       reference_units.system as reference_unit_system, 
+       reference_units.factor as reference_unit_factor, 
        recipe_steps.id as step_id, 
        recipe_ingredients.amount, 
        recipe_ingredients.sort_order
 FROM recipe_ingredients
          INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
          INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
-         INNER JOIN units ON recipe_ingredients.unit_id = units.id
+         INNER JOIN units AS recipe_units ON recipe_ingredients.unit_id = recipe_units.id
+         LEFT JOIN units AS reference_units ON ingredients.reference_unit_id = reference_units.id
 WHERE recipe_steps.recipe_id IN (
     /*SLICE:recipe_ids*/?
     )
@@ -454,15 +748,26 @@ ORDER BY recipe_ingredients.sort_order
 `
 
 type GetIngredientsForRecipesRow struct {
-	RecipeIngredientID int64
-	IngredientID       int64
-	IngredientName     string
-	UnitID             int64
-	UnitName           string
-	UnitSymbol         *string
-	StepID             int64
-	Amount             float64
-	SortOrder          int64
+	RecipeIngredientID        int64
+	IngredientID              int64
+	IngredientName            string
+	UnitID                    int64
+	UnitName                  string
+	UnitSymbol                *string
+	UnitDimension             *string
+	UnitSystem                *string
+	UnitFactor                *float64
+	IngredientDensity         *float64
+	IngredientReferenceAmount float64
+	ReferenceUnitID           *int64
+	ReferenceUnitName         *string
+	ReferenceUnitSymbol       *string
+	ReferenceUnitDimension    *string
+	ReferenceUnitSystem       *string
+	ReferenceUnitFactor       *float64
+	StepID                    int64
+	Amount                    float64
+	SortOrder                 int64
 }
 
 func (q *Queries) GetIngredientsForRecipes(ctx context.Context, recipeIds []int64) ([]GetIngredientsForRecipesRow, error) {
@@ -491,6 +796,17 @@ func (q *Queries) GetIngredientsForRecipes(ctx context.Context, recipeIds []int6
 			&i.UnitID,
 			&i.UnitName,
 			&i.UnitSymbol,
+			&i.UnitDimension,
+			&i.UnitSystem,
+			&i.UnitFactor,
+			&i.IngredientDensity,
+			&i.IngredientReferenceAmount,
+			&i.ReferenceUnitID,
+			&i.ReferenceUnitName,
+			&i.ReferenceUnitSymbol,
+			&i.ReferenceUnitDimension,
+			&i.ReferenceUnitSystem,
+			&i.ReferenceUnitFactor,
 			&i.StepID,
 			&i.Amount,
 			&i.SortOrder,
@@ -509,49 +825,41 @@ func (q *Queries) GetIngredientsForRecipes(ctx context.Context, recipeIds []int6
 }
 
 const getMealPlan = `-- name: GetMealPlan :many
-SELECT meal_plan.id, meal_plan.date, meal_plan.user_id, meal_plan.recipe_id, meal_plan.sort_order,
-       recipes.id, recipes.name, recipes.servings, recipes.minutes, recipes.description, recipes.created_by, recipes.created_at
+SELECT meal_plan.id, meal_plan.date, meal_plan.user_id, meal_plan.recipe_id, meal_plan.sort_order, meal_plan.servings, meal_plan.household_id, meal_plan.slot_id, meal_plan.note, meal_plan.template_entry_id
 FROM meal_plan
-         INNER JOIN recipes ON meal_plan.recipe_id = recipes.id
-WHERE user_id = ?
+         LEFT JOIN meal_slots ON meal_slots.id = meal_plan.slot_id
+WHERE meal_plan.household_id = ?
   AND meal_plan.date >= ?2
   AND meal_plan.date <= ?3
-ORDER BY meal_plan.date, meal_plan.sort_order
+ORDER BY meal_plan.date, meal_slots.sort_order IS NULL, meal_slots.sort_order, meal_plan.sort_order
 `
 
 type GetMealPlanParams struct {
-	UserID    int64
-	FromDate  string
-	UntilDate string
-}
-
-type GetMealPlanRow struct {
-	MealPlan MealPlan
-	Recipe   Recipe
+	HouseholdID *int64
+	FromDate    string
+	UntilDate   string
 }
 
-func (q *Queries) GetMealPlan(ctx context.Context, arg GetMealPlanParams) ([]GetMealPlanRow, error) {
-	rows, err := q.db.QueryContext(ctx, getMealPlan, arg.UserID, arg.FromDate, arg.UntilDate)
+func (q *Queries) GetMealPlan(ctx context.Context, arg GetMealPlanParams) ([]MealPlan, error) {
+	rows, err := q.db.QueryContext(ctx, getMealPlan, arg.HouseholdID, arg.FromDate, arg.UntilDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetMealPlanRow
+	var items []MealPlan
 	for rows.Next() {
-		var i GetMealPlanRow
+		var i MealPlan
 		if err := rows.Scan(
-			&i.MealPlan.ID,
-			&i.MealPlan.Date,
-			&i.MealPlan.UserID,
-			&i.MealPlan.RecipeID,
-			&i.MealPlan.SortOrder,
-			&i.Recipe.ID,
-			&i.Recipe.Name,
-			&i.Recipe.Servings,
-			&i.Recipe.Minutes,
-			&i.Recipe.Description,
-			&i.Recipe.CreatedBy,
-			&i.Recipe.CreatedAt,
+			&i.ID,
+			&i.Date,
+			&i.UserID,
+			&i.RecipeID,
+			&i.SortOrder,
+			&i.Servings,
+			&i.HouseholdID,
+			&i.SlotID,
+			&i.Note,
+			&i.TemplateEntryID,
 		); err != nil {
 			return nil, err
 		}
@@ -566,22 +874,41 @@ func (q *Queries) GetMealPlan(ctx context.Context, arg GetMealPlanParams) ([]Get
 	return items, nil
 }
 
-const getNutrients = `-- name: GetNutrients :many
-SELECT id, name, unit
-FROM nutrients
-ORDER BY name
+const getMealPlanEntriesByDates = `-- name: GetMealPlanEntriesByDates :many
+SELECT id, date, user_id, recipe_id, sort_order, servings, household_id, slot_id, note, template_entry_id
+FROM meal_plan
+WHERE household_id = ?
+  AND date IN (?2, ?3)
+ORDER BY date, slot_id, sort_order
 `
 
-func (q *Queries) GetNutrients(ctx context.Context) ([]Nutrient, error) {
-	rows, err := q.db.QueryContext(ctx, getNutrients)
+type GetMealPlanEntriesByDatesParams struct {
+	HouseholdID *int64
+	SourceDate  string
+	TargetDate  string
+}
+
+func (q *Queries) GetMealPlanEntriesByDates(ctx context.Context, arg GetMealPlanEntriesByDatesParams) ([]MealPlan, error) {
+	rows, err := q.db.QueryContext(ctx, getMealPlanEntriesByDates, arg.HouseholdID, arg.SourceDate, arg.TargetDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Nutrient
+	var items []MealPlan
 	for rows.Next() {
-		var i Nutrient
-		if err := rows.Scan(&i.ID, &i.Name, &i.Unit); err != nil {
+		var i MealPlan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.UserID,
+			&i.RecipeID,
+			&i.SortOrder,
+			&i.Servings,
+			&i.HouseholdID,
+			&i.SlotID,
+			&i.Note,
+			&i.TemplateEntryID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -595,33 +922,33 @@ func (q *Queries) GetNutrients(ctx context.Context) ([]Nutrient, error) {
 	return items, nil
 }
 
-const getNutrientsForIngredient = `-- name: GetNutrientsForIngredient :many
-SELECT nutrients.id, nutrients.name, nutrients.unit, ingredient_nutrients.amount
-FROM ingredient_nutrients
-INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
-WHERE ingredient_nutrients.ingredient_id = ?
-ORDER BY nutrients.name
+const getMealPlanEntriesByHousehold = `-- name: GetMealPlanEntriesByHousehold :many
+SELECT id, date, user_id, recipe_id, sort_order, servings, household_id, slot_id, note, template_entry_id
+FROM meal_plan
+WHERE household_id = ?
+ORDER BY date, slot_id, sort_order
 `
 
-type GetNutrientsForIngredientRow struct {
-	Nutrient Nutrient
-	Amount   float64
-}
-
-func (q *Queries) GetNutrientsForIngredient(ctx context.Context, ingredientID int64) ([]GetNutrientsForIngredientRow, error) {
-	rows, err := q.db.QueryContext(ctx, getNutrientsForIngredient, ingredientID)
+func (q *Queries) GetMealPlanEntriesByHousehold(ctx context.Context, householdID *int64) ([]MealPlan, error) {
+	rows, err := q.db.QueryContext(ctx, getMealPlanEntriesByHousehold, householdID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetNutrientsForIngredientRow
+	var items []MealPlan
 	for rows.Next() {
-		var i GetNutrientsForIngredientRow
+		var i MealPlan
 		if err := rows.Scan(
-			&i.Nutrient.ID,
-			&i.Nutrient.Name,
-			&i.Nutrient.Unit,
-			&i.Amount,
+			&i.ID,
+			&i.Date,
+			&i.UserID,
+			&i.RecipeID,
+			&i.SortOrder,
+			&i.Servings,
+			&i.HouseholdID,
+			&i.SlotID,
+			&i.Note,
+			&i.TemplateEntryID,
 		); err != nil {
 			return nil, err
 		}
@@ -636,43 +963,314 @@ func (q *Queries) GetNutrientsForIngredient(ctx context.Context, ingredientID in
 	return items, nil
 }
 
-const getNutrientsForIngredients = `-- name: GetNutrientsForIngredients :many
-SELECT ingredient_nutrients.ingredient_id, nutrients.id, nutrients.name, nutrients.unit, ingredient_nutrients.amount
-FROM ingredient_nutrients
-INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
-WHERE ingredient_nutrients.ingredient_id IN (/*SLICE:ingredient_ids*/?)
-ORDER BY ingredient_nutrients.ingredient_id, nutrients.name
+const getMealPlanEntry = `-- name: GetMealPlanEntry :one
+SELECT id, date, user_id, recipe_id, sort_order, servings, household_id, slot_id, note, template_entry_id
+FROM meal_plan
+WHERE id = ? AND household_id = ?
+LIMIT 1
 `
 
-type GetNutrientsForIngredientsRow struct {
-	IngredientID int64
-	Nutrient     Nutrient
-	Amount       float64
+type GetMealPlanEntryParams struct {
+	ID          int64
+	HouseholdID *int64
 }
 
-func (q *Queries) GetNutrientsForIngredients(ctx context.Context, ingredientIds []int64) ([]GetNutrientsForIngredientsRow, error) {
-	query := getNutrientsForIngredients
-	var queryParams []interface{}
-	if len(ingredientIds) > 0 {
-		for _, v := range ingredientIds {
-			queryParams = append(queryParams, v)
-		}
-		query = strings.Replace(query, "/*SLICE:ingredient_ids*/?", strings.Repeat(",?", len(ingredientIds))[1:], 1)
-	} else {
-		query = strings.Replace(query, "/*SLICE:ingredient_ids*/?", "NULL", 1)
-	}
-	rows, err := q.db.QueryContext(ctx, query, queryParams...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetNutrientsForIngredientsRow
-	for rows.Next() {
-		var i GetNutrientsForIngredientsRow
-		if err := rows.Scan(
-			&i.IngredientID,
-			&i.Nutrient.ID,
-			&i.Nutrient.Name,
+func (q *Queries) GetMealPlanEntry(ctx context.Context, arg GetMealPlanEntryParams) (MealPlan, error) {
+	row := q.db.QueryRowContext(ctx, getMealPlanEntry, arg.ID, arg.HouseholdID)
+	var i MealPlan
+	err := row.Scan(
+		&i.ID,
+		&i.Date,
+		&i.UserID,
+		&i.RecipeID,
+		&i.SortOrder,
+		&i.Servings,
+		&i.HouseholdID,
+		&i.SlotID,
+		&i.Note,
+		&i.TemplateEntryID,
+	)
+	return i, err
+}
+
+const getMealPlanTemplate = `-- name: GetMealPlanTemplate :one
+SELECT id, household_id, user_id, name, recurring
+FROM meal_plan_templates
+WHERE id = ? AND household_id = ?
+LIMIT 1
+`
+
+type GetMealPlanTemplateParams struct {
+	ID          int64
+	HouseholdID int64
+}
+
+func (q *Queries) GetMealPlanTemplate(ctx context.Context, arg GetMealPlanTemplateParams) (MealPlanTemplate, error) {
+	row := q.db.QueryRowContext(ctx, getMealPlanTemplate, arg.ID, arg.HouseholdID)
+	var i MealPlanTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.HouseholdID,
+		&i.UserID,
+		&i.Name,
+		&i.Recurring,
+	)
+	return i, err
+}
+
+const getMealPlanTemplateEntries = `-- name: GetMealPlanTemplateEntries :many
+SELECT id, template_id, weekday, slot_id, recipe_id, note, servings, sort_order
+FROM meal_plan_template_entries
+WHERE template_id = ?
+ORDER BY sort_order
+`
+
+func (q *Queries) GetMealPlanTemplateEntries(ctx context.Context, templateID int64) ([]MealPlanTemplateEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getMealPlanTemplateEntries, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MealPlanTemplateEntry
+	for rows.Next() {
+		var i MealPlanTemplateEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Weekday,
+			&i.SlotID,
+			&i.RecipeID,
+			&i.Note,
+			&i.Servings,
+			&i.SortOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMealPlanTemplateWeeks = `-- name: GetMealPlanTemplateWeeks :many
+SELECT week
+FROM meal_plan_template_weeks
+WHERE template_id = ? AND week >= ?
+ORDER BY week
+`
+
+type GetMealPlanTemplateWeeksParams struct {
+	TemplateID int64
+	Week       string
+}
+
+func (q *Queries) GetMealPlanTemplateWeeks(ctx context.Context, arg GetMealPlanTemplateWeeksParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getMealPlanTemplateWeeks, arg.TemplateID, arg.Week)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var week string
+		if err := rows.Scan(&week); err != nil {
+			return nil, err
+		}
+		items = append(items, week)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMealPlanTemplatesByHousehold = `-- name: GetMealPlanTemplatesByHousehold :many
+SELECT id, household_id, user_id, name, recurring
+FROM meal_plan_templates
+WHERE household_id = ?
+ORDER BY name, id
+`
+
+func (q *Queries) GetMealPlanTemplatesByHousehold(ctx context.Context, householdID int64) ([]MealPlanTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, getMealPlanTemplatesByHousehold, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MealPlanTemplate
+	for rows.Next() {
+		var i MealPlanTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.HouseholdID,
+			&i.UserID,
+			&i.Name,
+			&i.Recurring,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMealSlotsByHousehold = `-- name: GetMealSlotsByHousehold :many
+SELECT id, household_id, name, sort_order, time
+FROM meal_slots
+WHERE household_id = ?
+ORDER BY sort_order
+`
+
+func (q *Queries) GetMealSlotsByHousehold(ctx context.Context, householdID int64) ([]MealSlot, error) {
+	rows, err := q.db.QueryContext(ctx, getMealSlotsByHousehold, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MealSlot
+	for rows.Next() {
+		var i MealSlot
+		if err := rows.Scan(
+			&i.ID,
+			&i.HouseholdID,
+			&i.Name,
+			&i.SortOrder,
+			&i.Time,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNutrients = `-- name: GetNutrients :many
+SELECT id, name, unit
+FROM nutrients
+ORDER BY name
+`
+
+func (q *Queries) GetNutrients(ctx context.Context) ([]Nutrient, error) {
+	rows, err := q.db.QueryContext(ctx, getNutrients)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Nutrient
+	for rows.Next() {
+		var i Nutrient
+		if err := rows.Scan(&i.ID, &i.Name, &i.Unit); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNutrientsForIngredient = `-- name: GetNutrientsForIngredient :many
+SELECT nutrients.id, nutrients.name, nutrients.unit, ingredient_nutrients.amount
+FROM ingredient_nutrients
+INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
+WHERE ingredient_nutrients.ingredient_id = ?
+ORDER BY nutrients.name
+`
+
+type GetNutrientsForIngredientRow struct {
+	Nutrient Nutrient
+	Amount   float64
+}
+
+func (q *Queries) GetNutrientsForIngredient(ctx context.Context, ingredientID int64) ([]GetNutrientsForIngredientRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNutrientsForIngredient, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNutrientsForIngredientRow
+	for rows.Next() {
+		var i GetNutrientsForIngredientRow
+		if err := rows.Scan(
+			&i.Nutrient.ID,
+			&i.Nutrient.Name,
+			&i.Nutrient.Unit,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNutrientsForIngredients = `-- name: GetNutrientsForIngredients :many
+SELECT DISTINCT ingredient_nutrients.ingredient_id, nutrients.id, nutrients.name, nutrients.unit, ingredient_nutrients.amount
+FROM ingredient_nutrients
+INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
+WHERE ingredient_nutrients.ingredient_id IN (/*SLICE:ingredient_ids*/?)
+ORDER BY ingredient_nutrients.ingredient_id, nutrients.name
+`
+
+type GetNutrientsForIngredientsRow struct {
+	IngredientID int64
+	Nutrient     Nutrient
+	Amount       float64
+}
+
+func (q *Queries) GetNutrientsForIngredients(ctx context.Context, ingredientIds []int64) ([]GetNutrientsForIngredientsRow, error) {
+	query := getNutrientsForIngredients
+	var queryParams []interface{}
+	if len(ingredientIds) > 0 {
+		for _, v := range ingredientIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ingredient_ids*/?", strings.Repeat(",?", len(ingredientIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ingredient_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNutrientsForIngredientsRow
+	for rows.Next() {
+		var i GetNutrientsForIngredientsRow
+		if err := rows.Scan(
+			&i.IngredientID,
+			&i.Nutrient.ID,
+			&i.Nutrient.Name,
 			&i.Nutrient.Unit,
 			&i.Amount,
 		); err != nil {
@@ -690,7 +1288,7 @@ func (q *Queries) GetNutrientsForIngredients(ctx context.Context, ingredientIds
 }
 
 const getNutrientsForRecipes = `-- name: GetNutrientsForRecipes :many
-SELECT ingredient_nutrients.ingredient_id, nutrients.id, nutrients.name, nutrients.unit, ingredient_nutrients.amount
+SELECT DISTINCT ingredient_nutrients.ingredient_id, nutrients.id, nutrients.name, nutrients.unit, ingredient_nutrients.amount
 FROM ingredient_nutrients
 INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
 INNER JOIN recipe_ingredients ON ingredient_nutrients.ingredient_id = recipe_ingredients.ingredient_id
@@ -745,7 +1343,7 @@ func (q *Queries) GetNutrientsForRecipes(ctx context.Context, recipeIds []int64)
 }
 
 const getRecipe = `-- name: GetRecipe :one
-SELECT id, name, servings, minutes, description, created_by, created_at
+SELECT id, name, servings, minutes, description, created_by, created_at, household_id
 FROM recipes
 WHERE id = ?
 LIMIT 1
@@ -762,10 +1360,136 @@ func (q *Queries) GetRecipe(ctx context.Context, id int64) (Recipe, error) {
 		&i.Description,
 		&i.CreatedBy,
 		&i.CreatedAt,
+		&i.HouseholdID,
 	)
 	return i, err
 }
 
+const getRecipesByHousehold = `-- name: GetRecipesByHousehold :many
+SELECT id, name, servings, minutes, description, created_by, created_at, household_id
+FROM recipes
+WHERE household_id = ?
+ORDER BY id
+`
+
+func (q *Queries) GetRecipesByHousehold(ctx context.Context, householdID *int64) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, getRecipesByHousehold, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Recipe
+	for rows.Next() {
+		var i Recipe
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Servings,
+			&i.Minutes,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.HouseholdID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipesByIDs = `-- name: GetRecipesByIDs :many
+SELECT id, name, servings, minutes, description, created_by, created_at, household_id
+FROM recipes
+WHERE id IN (
+    /*SLICE:ids*/?
+    )
+`
+
+func (q *Queries) GetRecipesByIDs(ctx context.Context, ids []int64) ([]Recipe, error) {
+	query := getRecipesByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Recipe
+	for rows.Next() {
+		var i Recipe
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Servings,
+			&i.Minutes,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.HouseholdID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecurringMealPlanTemplates = `-- name: GetRecurringMealPlanTemplates :many
+SELECT id, household_id, user_id, name, recurring
+FROM meal_plan_templates
+WHERE recurring = 1
+ORDER BY id
+`
+
+func (q *Queries) GetRecurringMealPlanTemplates(ctx context.Context) ([]MealPlanTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, getRecurringMealPlanTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MealPlanTemplate
+	for rows.Next() {
+		var i MealPlanTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.HouseholdID,
+			&i.UserID,
+			&i.Name,
+			&i.Recurring,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStepsForRecipes = `-- name: GetStepsForRecipes :many
 SELECT id, instructions, sort_order, recipe_id
 FROM recipe_steps
@@ -898,7 +1622,7 @@ func (q *Queries) GetTagsForRecipes(ctx context.Context, recipeIds []int64) ([]G
 }
 
 const getUnits = `-- name: GetUnits :many
-SELECT id, symbol, name
+SELECT id, symbol, name, dimension, system, factor
 FROM units
 ORDER BY name
 `
@@ -912,7 +1636,14 @@ func (q *Queries) GetUnits(ctx context.Context) ([]Unit, error) {
 	var items []Unit
 	for rows.Next() {
 		var i Unit
-		if err := rows.Scan(&i.ID, &i.Symbol, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Symbol,
+			&i.Name,
+			&i.Dimension,
+			&i.System,
+			&i.Factor,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -926,30 +1657,210 @@ func (q *Queries) GetUnits(ctx context.Context) ([]Unit, error) {
 	return items, nil
 }
 
-const listRecipes = `-- name: ListRecipes :many
-SELECT id, name, servings, minutes, description, created_by, created_at
-FROM recipes
-WHERE created_by = ?
-ORDER BY name
+const listIngredients = `-- name: ListIngredients :many
+SELECT id, name, density, reference_amount, reference_unit_id
+FROM ingredients
+WHERE CAST(?1 AS TEXT) IS NULL
+   OR name > ?1
+   OR (name = ?1 AND id > ?2)
+ORDER BY name, id
+LIMIT ?3
 `
 
-func (q *Queries) ListRecipes(ctx context.Context, createdBy int64) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipes, createdBy)
+type ListIngredientsParams struct {
+	CursorName *string
+	CursorID   *int64
+	PageSize   int64
+}
+
+func (q *Queries) ListIngredients(ctx context.Context, arg ListIngredientsParams) ([]Ingredient, error) {
+	rows, err := q.db.QueryContext(ctx, listIngredients, arg.CursorName, arg.CursorID, arg.PageSize)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Recipe
+	var items []Ingredient
 	for rows.Next() {
-		var i Recipe
+		var i Ingredient
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
-			&i.Servings,
-			&i.Minutes,
-			&i.Description,
-			&i.CreatedBy,
-			&i.CreatedAt,
+			&i.Density,
+			&i.ReferenceAmount,
+			&i.ReferenceUnitID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipes = `-- name: ListRecipes :many
+SELECT recipes.id, recipes.name, recipes.servings, recipes.minutes, recipes.description, recipes.created_by, recipes.created_at, recipes.household_id,
+       CASE WHEN CAST(?1 AS BOOLEAN) THEN NULL ELSE CASE CAST(?2 AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END END AS ascending_value,
+       CASE WHEN CAST(?1 AS BOOLEAN) THEN CASE CAST(?2 AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END END AS descending_value,
+       CASE WHEN CAST(?1 AS BOOLEAN) THEN -recipes.id ELSE recipes.id END AS id_order
+FROM recipes
+WHERE (CAST(?3 AS INTEGER) IS NULL OR recipes.created_by = ?3)
+  AND (CAST(?4 AS INTEGER) IS NULL OR recipes.household_id = ?4)
+  AND (CAST(?5 AS INTEGER) IS NULL OR recipes.minutes <= ?5)
+  AND (CAST(?6 AS INTEGER) IS NULL OR recipes.servings >= ?6)
+  AND (CAST(?7 AS INTEGER) IS NULL OR recipes.servings <= ?7)
+  AND (?8 IS NULL OR recipes.id IN (
+    SELECT recipe_tags.recipe_id
+    FROM recipe_tags
+    WHERE recipe_tags.tag_id IN (SELECT value FROM (SELECT ?8 AS ids) AS filter, json_each(filter.ids))
+    GROUP BY recipe_tags.recipe_id
+    HAVING COUNT(DISTINCT recipe_tags.tag_id) = json_array_length(?8)
+    ))
+  AND (?9 IS NULL OR recipes.id IN (
+    SELECT recipe_steps.recipe_id
+    FROM recipe_ingredients
+             INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+    WHERE recipe_ingredients.ingredient_id IN (SELECT value FROM (SELECT ?9 AS ids) AS filter, json_each(filter.ids))
+    GROUP BY recipe_steps.recipe_id
+    HAVING COUNT(DISTINCT recipe_ingredients.ingredient_id) = json_array_length(?9)
+    ))
+  AND (?10 IS NULL OR recipes.id NOT IN (
+    SELECT recipe_steps.recipe_id
+    FROM recipe_ingredients
+             INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+    WHERE recipe_ingredients.ingredient_id IN (SELECT value FROM (SELECT ?10 AS ids) AS filter, json_each(filter.ids))
+    ))
+  AND (?11 IS NULL
+    OR (CAST(?1 AS BOOLEAN) = FALSE AND (CASE CAST(?2 AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END > ?11
+        OR (CASE CAST(?2 AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END = ?11 AND recipes.id > ?12)))
+    OR (CAST(?1 AS BOOLEAN) = TRUE AND (CASE CAST(?2 AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END < ?11
+        OR (CASE CAST(?2 AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END = ?11 AND recipes.id < ?12))))
+ORDER BY ascending_value, descending_value DESC, id_order
+LIMIT ?13
+`
+
+type ListRecipesParams struct {
+	Descending           bool
+	SortKey              string
+	CreatedBy            *int64
+	HouseholdID          *int64
+	MaxMinutes           *int64
+	MinServings          *int64
+	MaxServings          *int64
+	TagIds               interface{}
+	IncludeIngredientIds interface{}
+	ExcludeIngredientIds interface{}
+	CursorValue          interface{}
+	CursorID             *int64
+	PageSize             int64
+}
+
+type ListRecipesRow struct {
+	Recipe          Recipe
+	AscendingValue  interface{}
+	DescendingValue interface{}
+	IDOrder         interface{}
+}
+
+func (q *Queries) ListRecipes(ctx context.Context, arg ListRecipesParams) ([]ListRecipesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipes,
+		arg.Descending,
+		arg.SortKey,
+		arg.CreatedBy,
+		arg.HouseholdID,
+		arg.MaxMinutes,
+		arg.MinServings,
+		arg.MaxServings,
+		arg.TagIds,
+		arg.IncludeIngredientIds,
+		arg.ExcludeIngredientIds,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecipesRow
+	for rows.Next() {
+		var i ListRecipesRow
+		if err := rows.Scan(
+			&i.Recipe.ID,
+			&i.Recipe.Name,
+			&i.Recipe.Servings,
+			&i.Recipe.Minutes,
+			&i.Recipe.Description,
+			&i.Recipe.CreatedBy,
+			&i.Recipe.CreatedAt,
+			&i.Recipe.HouseholdID,
+			&i.AscendingValue,
+			&i.DescendingValue,
+			&i.IDOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchRecipes = `-- name: SearchRecipes :many
+SELECT recipes.id, recipes.name, recipes.servings, recipes.minutes, recipes.description, recipes.created_by, recipes.created_at, recipes.household_id,
+       CAST(highlight(recipes_fts, 0, '<mark>', '</mark>') AS TEXT)           AS highlighted_name,
+       CAST(snippet(recipes_fts, -1, '<mark>', '</mark>', char(8230), 16) AS TEXT) AS snippet,
+       CAST(bm25(recipes_fts, 10.0, 4.0, 1.0, 6.0, 6.0) AS REAL)           AS "rank"
+FROM recipes_fts
+         INNER JOIN recipes ON recipes_fts.rowid = recipes.id
+WHERE match(CAST(?1 AS TEXT), recipes_fts)
+ORDER BY "rank"
+LIMIT ?3 OFFSET ?2
+`
+
+type SearchRecipesParams struct {
+	Query        string
+	ResultOffset int64
+	ResultLimit  int64
+}
+
+type SearchRecipesRow struct {
+	Recipe          Recipe
+	HighlightedName string
+	Snippet         string
+	Rank            float64
+}
+
+func (q *Queries) SearchRecipes(ctx context.Context, arg SearchRecipesParams) ([]SearchRecipesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchRecipes, arg.Query, arg.ResultOffset, arg.ResultLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchRecipesRow
+	for rows.Next() {
+		var i SearchRecipesRow
+		if err := rows.Scan(
+			&i.Recipe.ID,
+			&i.Recipe.Name,
+			&i.Recipe.Servings,
+			&i.Recipe.Minutes,
+			&i.Recipe.Description,
+			&i.Recipe.CreatedBy,
+			&i.Recipe.CreatedAt,
+			&i.Recipe.HouseholdID,
+			&i.HighlightedName,
+			&i.Snippet,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -966,20 +1877,203 @@ func (q *Queries) ListRecipes(ctx context.Context, createdBy int64) ([]Recipe, e
 
 const updateIngredient = `-- name: UpdateIngredient :exec
 UPDATE ingredients
-SET name = ?
+SET name = ?, density = ?, reference_amount = ?, reference_unit_id = ?
 WHERE id = ?
 `
 
 type UpdateIngredientParams struct {
-	Name string
-	ID   int64
+	Name            string
+	Density         *float64
+	ReferenceAmount float64
+	ReferenceUnitID *int64
+	ID              int64
 }
 
 func (q *Queries) UpdateIngredient(ctx context.Context, arg UpdateIngredientParams) error {
-	_, err := q.db.ExecContext(ctx, updateIngredient, arg.Name, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateIngredient,
+		arg.Name,
+		arg.Density,
+		arg.ReferenceAmount,
+		arg.ReferenceUnitID,
+		arg.ID,
+	)
 	return err
 }
 
+const updateMealPlanEntry = `-- name: UpdateMealPlanEntry :execrows
+UPDATE meal_plan
+SET recipe_id  = ?1,
+    note       = ?2,
+    servings   = ?3,
+    sort_order = CASE
+                     WHEN slot_id IS ?4 THEN sort_order
+                     ELSE (SELECT COALESCE(MAX(other.sort_order) + 1, 0)
+                           FROM meal_plan AS other
+                           WHERE other.household_id = meal_plan.household_id
+                             AND other.date = meal_plan.date
+                             AND other.slot_id IS ?4)
+        END,
+    slot_id    = ?4
+WHERE meal_plan.id = ?5
+  AND meal_plan.household_id = ?6
+`
+
+type UpdateMealPlanEntryParams struct {
+	RecipeID    *int64
+	Note        *string
+	Servings    *int64
+	SlotID      *int64
+	ID          int64
+	HouseholdID *int64
+}
+
+func (q *Queries) UpdateMealPlanEntry(ctx context.Context, arg UpdateMealPlanEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateMealPlanEntry,
+		arg.RecipeID,
+		arg.Note,
+		arg.Servings,
+		arg.SlotID,
+		arg.ID,
+		arg.HouseholdID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateMealPlanEntryPosition = `-- name: UpdateMealPlanEntryPosition :exec
+UPDATE meal_plan
+SET date = ?, slot_id = ?, sort_order = ?
+WHERE id = ?
+`
+
+type UpdateMealPlanEntryPositionParams struct {
+	Date      string
+	SlotID    *int64
+	SortOrder int64
+	ID        int64
+}
+
+func (q *Queries) UpdateMealPlanEntryPosition(ctx context.Context, arg UpdateMealPlanEntryPositionParams) error {
+	_, err := q.db.ExecContext(ctx, updateMealPlanEntryPosition,
+		arg.Date,
+		arg.SlotID,
+		arg.SortOrder,
+		arg.ID,
+	)
+	return err
+}
+
+const updateMealPlanTemplate = `-- name: UpdateMealPlanTemplate :one
+UPDATE meal_plan_templates
+SET name = ?, recurring = ?
+WHERE id = ? AND household_id = ?
+RETURNING id, household_id, user_id, name, recurring
+`
+
+type UpdateMealPlanTemplateParams struct {
+	Name        string
+	Recurring   bool
+	ID          int64
+	HouseholdID int64
+}
+
+func (q *Queries) UpdateMealPlanTemplate(ctx context.Context, arg UpdateMealPlanTemplateParams) (MealPlanTemplate, error) {
+	row := q.db.QueryRowContext(ctx, updateMealPlanTemplate,
+		arg.Name,
+		arg.Recurring,
+		arg.ID,
+		arg.HouseholdID,
+	)
+	var i MealPlanTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.HouseholdID,
+		&i.UserID,
+		&i.Name,
+		&i.Recurring,
+	)
+	return i, err
+}
+
+const updateMealPlanTemplateEntry = `-- name: UpdateMealPlanTemplateEntry :one
+UPDATE meal_plan_template_entries
+SET weekday = ?, slot_id = ?, recipe_id = ?, note = ?, servings = ?, sort_order = ?
+WHERE id = ? AND template_id = ?
+RETURNING id, template_id, weekday, slot_id, recipe_id, note, servings, sort_order
+`
+
+type UpdateMealPlanTemplateEntryParams struct {
+	Weekday    int64
+	SlotID     *int64
+	RecipeID   *int64
+	Note       *string
+	Servings   *int64
+	SortOrder  int64
+	ID         int64
+	TemplateID int64
+}
+
+func (q *Queries) UpdateMealPlanTemplateEntry(ctx context.Context, arg UpdateMealPlanTemplateEntryParams) (MealPlanTemplateEntry, error) {
+	row := q.db.QueryRowContext(ctx, updateMealPlanTemplateEntry,
+		arg.Weekday,
+		arg.SlotID,
+		arg.RecipeID,
+		arg.Note,
+		arg.Servings,
+		arg.SortOrder,
+		arg.ID,
+		arg.TemplateID,
+	)
+	var i MealPlanTemplateEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Weekday,
+		&i.SlotID,
+		&i.RecipeID,
+		&i.Note,
+		&i.Servings,
+		&i.SortOrder,
+	)
+	return i, err
+}
+
+const updateMealSlot = `-- name: UpdateMealSlot :one
+UPDATE meal_slots
+SET name = ?, sort_order = ?, time = ?
+WHERE id = ? AND household_id = ?
+RETURNING id, household_id, name, sort_order, time
+`
+
+type UpdateMealSlotParams struct {
+	Name        string
+	SortOrder   int64
+	Time        *string
+	ID          int64
+	HouseholdID int64
+}
+
+func (q *Queries) UpdateMealSlot(ctx context.Context, arg UpdateMealSlotParams) (MealSlot, error) {
+	row := q.db.QueryRowContext(ctx, updateMealSlot,
+		arg.Name,
+		arg.SortOrder,
+		arg.Time,
+		arg.ID,
+		arg.HouseholdID,
+	)
+	var i MealSlot
+	err := row.Scan(
+		&i.ID,
+		&i.HouseholdID,
+		&i.Name,
+		&i.SortOrder,
+		&i.Time,
+	)
+	return i, err
+}
+
 const updateNutrient = `-- name: UpdateNutrient :exec
 UPDATE nutrients
 SET name = ?, unit = ?
@@ -1024,17 +2118,27 @@ func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) erro
 
 const updateUnit = `-- name: UpdateUnit :exec
 UPDATE units
-SET name = ?, symbol = ?
+SET name = ?, symbol = ?, dimension = ?, system = ?, factor = ?
 WHERE id = ?
 `
 
 type UpdateUnitParams struct {
-	Name   string
-	Symbol *string
-	ID     int64
+	Name      string
+	Symbol    *string
+	Dimension *string
+	System    *string
+	Factor    *float64
+	ID        int64
 }
 
 func (q *Queries) UpdateUnit(ctx context.Context, arg UpdateUnitParams) error {
-	_, err := q.db.ExecContext(ctx, updateUnit, arg.Name, arg.Symbol, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateUnit,
+		arg.Name,
+		arg.Symbol,
+		arg.Dimension,
+		arg.System,
+		arg.Factor,
+		arg.ID,
+	)
 	return err
 }
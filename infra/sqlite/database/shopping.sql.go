@@ -7,30 +7,100 @@ package database
 
 import (
 	"context"
+	"strings"
+	"time"
 )
 
-const createShoppingList = `-- name: CreateShoppingList :one
-INSERT INTO shopping_lists (user_id, name)
+const addShoppingListItemRecipe = `-- name: AddShoppingListItemRecipe :exec
+INSERT INTO shopping_list_item_recipes (shopping_list_item_id, recipe_id)
 VALUES (?, ?)
-RETURNING id, user_id, name
+ON CONFLICT DO NOTHING
+`
+
+type AddShoppingListItemRecipeParams struct {
+	ShoppingListItemID int64
+	RecipeID           int64
+}
+
+func (q *Queries) AddShoppingListItemRecipe(ctx context.Context, arg AddShoppingListItemRecipeParams) error {
+	_, err := q.db.ExecContext(ctx, addShoppingListItemRecipe, arg.ShoppingListItemID, arg.RecipeID)
+	return err
+}
+
+const bumpShoppingListRevision = `-- name: BumpShoppingListRevision :one
+UPDATE shopping_lists
+SET revision = revision + 1
+WHERE id = ?
+RETURNING revision
+`
+
+func (q *Queries) BumpShoppingListRevision(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, bumpShoppingListRevision, id)
+	var revision int64
+	err := row.Scan(&revision)
+	return revision, err
+}
+
+const bumpShoppingListRevisionByItemID = `-- name: BumpShoppingListRevisionByItemID :one
+UPDATE shopping_lists
+SET revision = revision + 1
+WHERE id = (SELECT shopping_list_id FROM shopping_list_items WHERE shopping_list_items.id = ?)
+RETURNING revision
+`
+
+func (q *Queries) BumpShoppingListRevisionByItemID(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, bumpShoppingListRevisionByItemID, id)
+	var revision int64
+	err := row.Scan(&revision)
+	return revision, err
+}
+
+const countShoppingListItemDeletionsByClientID = `-- name: CountShoppingListItemDeletionsByClientID :one
+SELECT COUNT(*) FROM shopping_list_item_deletions
+WHERE shopping_list_id = ? AND client_id = ?
+`
+
+type CountShoppingListItemDeletionsByClientIDParams struct {
+	ShoppingListID int64
+	ClientID       *string
+}
+
+func (q *Queries) CountShoppingListItemDeletionsByClientID(ctx context.Context, arg CountShoppingListItemDeletionsByClientIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countShoppingListItemDeletionsByClientID, arg.ShoppingListID, arg.ClientID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createShoppingList = `-- name: CreateShoppingList :one
+INSERT INTO shopping_lists (household_id, user_id, name)
+VALUES (?, ?, ?)
+RETURNING id, user_id, name, household_id, revision
 `
 
 type CreateShoppingListParams struct {
-	UserID int64
-	Name   string
+	HouseholdID *int64
+	UserID      int64
+	Name        string
 }
 
 func (q *Queries) CreateShoppingList(ctx context.Context, arg CreateShoppingListParams) (ShoppingList, error) {
-	row := q.db.QueryRowContext(ctx, createShoppingList, arg.UserID, arg.Name)
+	row := q.db.QueryRowContext(ctx, createShoppingList, arg.HouseholdID, arg.UserID, arg.Name)
 	var i ShoppingList
-	err := row.Scan(&i.ID, &i.UserID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.HouseholdID,
+		&i.Revision,
+	)
 	return i, err
 }
 
 const createShoppingListItem = `-- name: CreateShoppingListItem :one
-INSERT INTO shopping_list_items (shopping_list_id, ingredient, quantity, unit, done, sort_order)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order
+INSERT INTO shopping_list_items (shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, revision, updated_at, client_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
 `
 
 type CreateShoppingListItemParams struct {
@@ -40,6 +110,12 @@ type CreateShoppingListItemParams struct {
 	Unit           *string
 	Done           bool
 	SortOrder      int64
+	IngredientID   *int64
+	UnitID         *int64
+	Amount         *float64
+	Revision       int64
+	UpdatedAt      *time.Time
+	ClientID       *string
 }
 
 func (q *Queries) CreateShoppingListItem(ctx context.Context, arg CreateShoppingListItemParams) (ShoppingListItem, error) {
@@ -50,6 +126,12 @@ func (q *Queries) CreateShoppingListItem(ctx context.Context, arg CreateShopping
 		arg.Unit,
 		arg.Done,
 		arg.SortOrder,
+		arg.IngredientID,
+		arg.UnitID,
+		arg.Amount,
+		arg.Revision,
+		arg.UpdatedAt,
+		arg.ClientID,
 	)
 	var i ShoppingListItem
 	err := row.Scan(
@@ -60,10 +142,98 @@ func (q *Queries) CreateShoppingListItem(ctx context.Context, arg CreateShopping
 		&i.Unit,
 		&i.Done,
 		&i.SortOrder,
+		&i.IngredientID,
+		&i.UnitID,
+		&i.Amount,
+		&i.Version,
+		&i.Revision,
+		&i.UpdatedAt,
+		&i.ClientID,
+	)
+	return i, err
+}
+
+const createShoppingListItemDeletion = `-- name: CreateShoppingListItemDeletion :exec
+INSERT INTO shopping_list_item_deletions (shopping_list_id, item_id, client_id, revision)
+SELECT shopping_list_id, id, client_id, ? FROM shopping_list_items
+WHERE id = ?
+ON CONFLICT (shopping_list_id, item_id) DO UPDATE SET client_id = excluded.client_id, revision = excluded.revision
+`
+
+type CreateShoppingListItemDeletionParams struct {
+	Revision int64
+	ID       int64
+}
+
+func (q *Queries) CreateShoppingListItemDeletion(ctx context.Context, arg CreateShoppingListItemDeletionParams) error {
+	_, err := q.db.ExecContext(ctx, createShoppingListItemDeletion, arg.Revision, arg.ID)
+	return err
+}
+
+const createStore = `-- name: CreateStore :one
+INSERT INTO stores (household_id, user_id, name)
+VALUES (?, ?, ?)
+RETURNING id, user_id, name, household_id
+`
+
+type CreateStoreParams struct {
+	HouseholdID *int64
+	UserID      int64
+	Name        string
+}
+
+func (q *Queries) CreateStore(ctx context.Context, arg CreateStoreParams) (Store, error) {
+	row := q.db.QueryRowContext(ctx, createStore, arg.HouseholdID, arg.UserID, arg.Name)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.HouseholdID,
+	)
+	return i, err
+}
+
+const createStoreSection = `-- name: CreateStoreSection :one
+INSERT INTO store_sections (store_id, name, sort_order)
+VALUES (?, ?, ?)
+RETURNING id, store_id, name, sort_order
+`
+
+type CreateStoreSectionParams struct {
+	StoreID   int64
+	Name      string
+	SortOrder int64
+}
+
+func (q *Queries) CreateStoreSection(ctx context.Context, arg CreateStoreSectionParams) (StoreSection, error) {
+	row := q.db.QueryRowContext(ctx, createStoreSection, arg.StoreID, arg.Name, arg.SortOrder)
+	var i StoreSection
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.SortOrder,
 	)
 	return i, err
 }
 
+const createStoreSectionIngredient = `-- name: CreateStoreSectionIngredient :exec
+INSERT INTO store_section_ingredients (store_id, ingredient_id, section_id)
+VALUES (?, ?, ?)
+`
+
+type CreateStoreSectionIngredientParams struct {
+	StoreID      int64
+	IngredientID int64
+	SectionID    int64
+}
+
+func (q *Queries) CreateStoreSectionIngredient(ctx context.Context, arg CreateStoreSectionIngredientParams) error {
+	_, err := q.db.ExecContext(ctx, createStoreSectionIngredient, arg.StoreID, arg.IngredientID, arg.SectionID)
+	return err
+}
+
 const deleteShoppingList = `-- name: DeleteShoppingList :exec
 DELETE FROM shopping_lists
 WHERE id = ?
@@ -84,20 +254,141 @@ func (q *Queries) DeleteShoppingListItem(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteStore = `-- name: DeleteStore :exec
+DELETE FROM stores
+WHERE id = ?
+`
+
+func (q *Queries) DeleteStore(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStore, id)
+	return err
+}
+
+const deleteStoreSection = `-- name: DeleteStoreSection :exec
+DELETE FROM store_sections
+WHERE id = ?
+`
+
+func (q *Queries) DeleteStoreSection(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStoreSection, id)
+	return err
+}
+
+const deleteStoreSectionIngredientsByStoreID = `-- name: DeleteStoreSectionIngredientsByStoreID :exec
+DELETE FROM store_section_ingredients
+WHERE store_id = ?
+`
+
+func (q *Queries) DeleteStoreSectionIngredientsByStoreID(ctx context.Context, storeID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStoreSectionIngredientsByStoreID, storeID)
+	return err
+}
+
+const getExistingIngredientIDs = `-- name: GetExistingIngredientIDs :many
+SELECT id FROM ingredients
+WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) GetExistingIngredientIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	query := getExistingIngredientIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipesForShoppingListItems = `-- name: GetRecipesForShoppingListItems :many
+SELECT shopping_list_item_recipes.shopping_list_item_id, recipes.id, recipes.name
+FROM shopping_list_item_recipes
+INNER JOIN recipes ON shopping_list_item_recipes.recipe_id = recipes.id
+WHERE shopping_list_item_recipes.shopping_list_item_id IN (/*SLICE:item_ids*/?)
+ORDER BY recipes.name
+`
+
+type GetRecipesForShoppingListItemsRow struct {
+	ShoppingListItemID int64
+	ID                 int64
+	Name               string
+}
+
+func (q *Queries) GetRecipesForShoppingListItems(ctx context.Context, itemIds []int64) ([]GetRecipesForShoppingListItemsRow, error) {
+	query := getRecipesForShoppingListItems
+	var queryParams []interface{}
+	if len(itemIds) > 0 {
+		for _, v := range itemIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:item_ids*/?", strings.Repeat(",?", len(itemIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:item_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecipesForShoppingListItemsRow
+	for rows.Next() {
+		var i GetRecipesForShoppingListItemsRow
+		if err := rows.Scan(&i.ShoppingListItemID, &i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getShoppingListByID = `-- name: GetShoppingListByID :one
-SELECT id, user_id, name FROM shopping_lists
+SELECT id, user_id, name, household_id, revision FROM shopping_lists
 WHERE id = ?
 `
 
 func (q *Queries) GetShoppingListByID(ctx context.Context, id int64) (ShoppingList, error) {
 	row := q.db.QueryRowContext(ctx, getShoppingListByID, id)
 	var i ShoppingList
-	err := row.Scan(&i.ID, &i.UserID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.HouseholdID,
+		&i.Revision,
+	)
 	return i, err
 }
 
 const getShoppingListItemByID = `-- name: GetShoppingListItemByID :one
-SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order
+SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
 FROM shopping_list_items
 WHERE id = ? AND shopping_list_id = ?
 `
@@ -118,12 +409,53 @@ func (q *Queries) GetShoppingListItemByID(ctx context.Context, arg GetShoppingLi
 		&i.Unit,
 		&i.Done,
 		&i.SortOrder,
+		&i.IngredientID,
+		&i.UnitID,
+		&i.Amount,
+		&i.Version,
+		&i.Revision,
+		&i.UpdatedAt,
+		&i.ClientID,
 	)
 	return i, err
 }
 
+const getShoppingListItemDeletionsSince = `-- name: GetShoppingListItemDeletionsSince :many
+SELECT item_id FROM shopping_list_item_deletions
+WHERE shopping_list_id = ? AND revision > ?
+ORDER BY item_id
+`
+
+type GetShoppingListItemDeletionsSinceParams struct {
+	ShoppingListID int64
+	Revision       int64
+}
+
+func (q *Queries) GetShoppingListItemDeletionsSince(ctx context.Context, arg GetShoppingListItemDeletionsSinceParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getShoppingListItemDeletionsSince, arg.ShoppingListID, arg.Revision)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var item_id int64
+		if err := rows.Scan(&item_id); err != nil {
+			return nil, err
+		}
+		items = append(items, item_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getShoppingListItemsByListID = `-- name: GetShoppingListItemsByListID :many
-SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order
+SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
 FROM shopping_list_items
 WHERE shopping_list_id = ?
 ORDER BY sort_order ASC
@@ -146,6 +478,63 @@ func (q *Queries) GetShoppingListItemsByListID(ctx context.Context, shoppingList
 			&i.Unit,
 			&i.Done,
 			&i.SortOrder,
+			&i.IngredientID,
+			&i.UnitID,
+			&i.Amount,
+			&i.Version,
+			&i.Revision,
+			&i.UpdatedAt,
+			&i.ClientID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getShoppingListItemsChangedSince = `-- name: GetShoppingListItemsChangedSince :many
+SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
+FROM shopping_list_items
+WHERE shopping_list_id = ? AND revision > ?
+ORDER BY sort_order ASC
+`
+
+type GetShoppingListItemsChangedSinceParams struct {
+	ShoppingListID int64
+	Revision       int64
+}
+
+func (q *Queries) GetShoppingListItemsChangedSince(ctx context.Context, arg GetShoppingListItemsChangedSinceParams) ([]ShoppingListItem, error) {
+	rows, err := q.db.QueryContext(ctx, getShoppingListItemsChangedSince, arg.ShoppingListID, arg.Revision)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShoppingListItem
+	for rows.Next() {
+		var i ShoppingListItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShoppingListID,
+			&i.Ingredient,
+			&i.Quantity,
+			&i.Unit,
+			&i.Done,
+			&i.SortOrder,
+			&i.IngredientID,
+			&i.UnitID,
+			&i.Amount,
+			&i.Version,
+			&i.Revision,
+			&i.UpdatedAt,
+			&i.ClientID,
 		); err != nil {
 			return nil, err
 		}
@@ -160,14 +549,14 @@ func (q *Queries) GetShoppingListItemsByListID(ctx context.Context, shoppingList
 	return items, nil
 }
 
-const getShoppingListsByUserID = `-- name: GetShoppingListsByUserID :many
-SELECT id, user_id, name FROM shopping_lists
-WHERE user_id = ?
+const getShoppingListsByHouseholdID = `-- name: GetShoppingListsByHouseholdID :many
+SELECT id, user_id, name, household_id, revision FROM shopping_lists
+WHERE household_id = ?
 ORDER BY id DESC
 `
 
-func (q *Queries) GetShoppingListsByUserID(ctx context.Context, userID int64) ([]ShoppingList, error) {
-	rows, err := q.db.QueryContext(ctx, getShoppingListsByUserID, userID)
+func (q *Queries) GetShoppingListsByHouseholdID(ctx context.Context, householdID *int64) ([]ShoppingList, error) {
+	rows, err := q.db.QueryContext(ctx, getShoppingListsByHouseholdID, householdID)
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +564,127 @@ func (q *Queries) GetShoppingListsByUserID(ctx context.Context, userID int64) ([
 	var items []ShoppingList
 	for rows.Next() {
 		var i ShoppingList
-		if err := rows.Scan(&i.ID, &i.UserID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.HouseholdID,
+			&i.Revision,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStoreByID = `-- name: GetStoreByID :one
+SELECT id, user_id, name, household_id FROM stores
+WHERE id = ?
+`
+
+func (q *Queries) GetStoreByID(ctx context.Context, id int64) (Store, error) {
+	row := q.db.QueryRowContext(ctx, getStoreByID, id)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.HouseholdID,
+	)
+	return i, err
+}
+
+const getStoreSectionIngredientsByStoreID = `-- name: GetStoreSectionIngredientsByStoreID :many
+SELECT store_id, ingredient_id, section_id FROM store_section_ingredients
+WHERE store_id = ?
+ORDER BY ingredient_id
+`
+
+func (q *Queries) GetStoreSectionIngredientsByStoreID(ctx context.Context, storeID int64) ([]StoreSectionIngredient, error) {
+	rows, err := q.db.QueryContext(ctx, getStoreSectionIngredientsByStoreID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StoreSectionIngredient
+	for rows.Next() {
+		var i StoreSectionIngredient
+		if err := rows.Scan(&i.StoreID, &i.IngredientID, &i.SectionID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStoreSectionsByStoreID = `-- name: GetStoreSectionsByStoreID :many
+SELECT id, store_id, name, sort_order FROM store_sections
+WHERE store_id = ?
+ORDER BY sort_order
+`
+
+func (q *Queries) GetStoreSectionsByStoreID(ctx context.Context, storeID int64) ([]StoreSection, error) {
+	rows, err := q.db.QueryContext(ctx, getStoreSectionsByStoreID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StoreSection
+	for rows.Next() {
+		var i StoreSection
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Name,
+			&i.SortOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStoresByHouseholdID = `-- name: GetStoresByHouseholdID :many
+SELECT id, user_id, name, household_id FROM stores
+WHERE household_id = ?
+ORDER BY name
+`
+
+func (q *Queries) GetStoresByHouseholdID(ctx context.Context, householdID *int64) ([]Store, error) {
+	rows, err := q.db.QueryContext(ctx, getStoresByHouseholdID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Store
+	for rows.Next() {
+		var i Store
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.HouseholdID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -193,7 +702,7 @@ const updateShoppingList = `-- name: UpdateShoppingList :one
 UPDATE shopping_lists
 SET name = ?
 WHERE id = ?
-RETURNING id, user_id, name
+RETURNING id, user_id, name, household_id, revision
 `
 
 type UpdateShoppingListParams struct {
@@ -204,23 +713,35 @@ type UpdateShoppingListParams struct {
 func (q *Queries) UpdateShoppingList(ctx context.Context, arg UpdateShoppingListParams) (ShoppingList, error) {
 	row := q.db.QueryRowContext(ctx, updateShoppingList, arg.Name, arg.ID)
 	var i ShoppingList
-	err := row.Scan(&i.ID, &i.UserID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.HouseholdID,
+		&i.Revision,
+	)
 	return i, err
 }
 
 const updateShoppingListItem = `-- name: UpdateShoppingListItem :one
 UPDATE shopping_list_items
-SET ingredient = ?, quantity = ?, unit = ?, done = ?
+SET ingredient = ?, quantity = ?, unit = ?, done = ?, ingredient_id = ?, unit_id = ?, amount = ?,
+    version = version + 1, revision = ?, updated_at = ?
 WHERE id = ?
-RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order
+RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
 `
 
 type UpdateShoppingListItemParams struct {
-	Ingredient string
-	Quantity   *string
-	Unit       *string
-	Done       bool
-	ID         int64
+	Ingredient   string
+	Quantity     *string
+	Unit         *string
+	Done         bool
+	IngredientID *int64
+	UnitID       *int64
+	Amount       *float64
+	Revision     int64
+	UpdatedAt    *time.Time
+	ID           int64
 }
 
 func (q *Queries) UpdateShoppingListItem(ctx context.Context, arg UpdateShoppingListItemParams) (ShoppingListItem, error) {
@@ -229,6 +750,11 @@ func (q *Queries) UpdateShoppingListItem(ctx context.Context, arg UpdateShopping
 		arg.Quantity,
 		arg.Unit,
 		arg.Done,
+		arg.IngredientID,
+		arg.UnitID,
+		arg.Amount,
+		arg.Revision,
+		arg.UpdatedAt,
 		arg.ID,
 	)
 	var i ShoppingListItem
@@ -240,22 +766,85 @@ func (q *Queries) UpdateShoppingListItem(ctx context.Context, arg UpdateShopping
 		&i.Unit,
 		&i.Done,
 		&i.SortOrder,
+		&i.IngredientID,
+		&i.UnitID,
+		&i.Amount,
+		&i.Version,
+		&i.Revision,
+		&i.UpdatedAt,
+		&i.ClientID,
 	)
 	return i, err
 }
 
 const updateShoppingListItemSortOrder = `-- name: UpdateShoppingListItemSortOrder :exec
 UPDATE shopping_list_items
-SET sort_order = ?
+SET sort_order = ?, revision = ?
 WHERE id = ?
 `
 
 type UpdateShoppingListItemSortOrderParams struct {
 	SortOrder int64
+	Revision  int64
 	ID        int64
 }
 
 func (q *Queries) UpdateShoppingListItemSortOrder(ctx context.Context, arg UpdateShoppingListItemSortOrderParams) error {
-	_, err := q.db.ExecContext(ctx, updateShoppingListItemSortOrder, arg.SortOrder, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateShoppingListItemSortOrder, arg.SortOrder, arg.Revision, arg.ID)
 	return err
 }
+
+const updateStore = `-- name: UpdateStore :one
+UPDATE stores
+SET name = ?
+WHERE id = ?
+RETURNING id, user_id, name, household_id
+`
+
+type UpdateStoreParams struct {
+	Name string
+	ID   int64
+}
+
+func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error) {
+	row := q.db.QueryRowContext(ctx, updateStore, arg.Name, arg.ID)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.HouseholdID,
+	)
+	return i, err
+}
+
+const updateStoreSection = `-- name: UpdateStoreSection :one
+UPDATE store_sections
+SET name = ?, sort_order = ?
+WHERE id = ? AND store_id = ?
+RETURNING id, store_id, name, sort_order
+`
+
+type UpdateStoreSectionParams struct {
+	Name      string
+	SortOrder int64
+	ID        int64
+	StoreID   int64
+}
+
+func (q *Queries) UpdateStoreSection(ctx context.Context, arg UpdateStoreSectionParams) (StoreSection, error) {
+	row := q.db.QueryRowContext(ctx, updateStoreSection,
+		arg.Name,
+		arg.SortOrder,
+		arg.ID,
+		arg.StoreID,
+	)
+	var i StoreSection
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.SortOrder,
+	)
+	return i, err
+}
@@ -10,6 +10,202 @@ import (
 	"time"
 )
 
+const confirmUserTOTP = `-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed = 1, last_used_step = ?
+WHERE user_id = ?
+`
+
+type ConfirmUserTOTPParams struct {
+	LastUsedStep int64
+	UserID       int64
+}
+
+func (q *Queries) ConfirmUserTOTP(ctx context.Context, arg ConfirmUserTOTPParams) error {
+	_, err := q.db.ExecContext(ctx, confirmUserTOTP, arg.LastUsedStep, arg.UserID)
+	return err
+}
+
+const createAPIToken = `-- name: CreateAPIToken :one
+INSERT INTO api_tokens (user_id, name, token_hash, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING id, user_id, name, token_hash, created_at, expires_at, last_used_at
+`
+
+type CreateAPITokenParams struct {
+	UserID    int64
+	Name      string
+	TokenHash string
+	ExpiresAt *time.Time
+}
+
+func (q *Queries) CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error) {
+	row := q.db.QueryRowContext(ctx, createAPIToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i ApiToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const createAPITokenPermission = `-- name: CreateAPITokenPermission :exec
+INSERT INTO api_token_permissions (api_token_id, permission_id)
+SELECT ?1, id
+FROM permissions
+WHERE slug = ?2
+`
+
+type CreateAPITokenPermissionParams struct {
+	ApiTokenID int64
+	Slug       string
+}
+
+func (q *Queries) CreateAPITokenPermission(ctx context.Context, arg CreateAPITokenPermissionParams) error {
+	_, err := q.db.ExecContext(ctx, createAPITokenPermission, arg.ApiTokenID, arg.Slug)
+	return err
+}
+
+const createCalendarToken = `-- name: CreateCalendarToken :one
+INSERT INTO calendar_tokens (user_id, token_hash)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = CURRENT_TIMESTAMP
+RETURNING user_id, token_hash, created_at
+`
+
+type CreateCalendarTokenParams struct {
+	UserID    int64
+	TokenHash string
+}
+
+func (q *Queries) CreateCalendarToken(ctx context.Context, arg CreateCalendarTokenParams) (CalendarToken, error) {
+	row := q.db.QueryRowContext(ctx, createCalendarToken, arg.UserID, arg.TokenHash)
+	var i CalendarToken
+	err := row.Scan(&i.UserID, &i.TokenHash, &i.CreatedAt)
+	return i, err
+}
+
+const createLoginChallenge = `-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (user_id, token)
+VALUES (?, ?)
+RETURNING id, user_id, token, attempts, created_at
+`
+
+type CreateLoginChallengeParams struct {
+	UserID int64
+	Token  string
+}
+
+func (q *Queries) CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error) {
+	row := q.db.QueryRowContext(ctx, createLoginChallenge, arg.UserID, arg.Token)
+	var i LoginChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.Attempts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createNutrientTarget = `-- name: CreateNutrientTarget :exec
+INSERT INTO user_nutrient_targets (user_id, nutrient_id, amount)
+VALUES (?, ?, ?)
+`
+
+type CreateNutrientTargetParams struct {
+	UserID     int64
+	NutrientID int64
+	Amount     float64
+}
+
+func (q *Queries) CreateNutrientTarget(ctx context.Context, arg CreateNutrientTargetParams) error {
+	_, err := q.db.ExecContext(ctx, createNutrientTarget, arg.UserID, arg.NutrientID, arg.Amount)
+	return err
+}
+
+const createPasskey = `-- name: CreatePasskey :one
+INSERT INTO passkeys (user_id, credential_id, public_key, sign_count, flags, name)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, credential_id, public_key, sign_count, flags, name, created_at, last_used_at
+`
+
+type CreatePasskeyParams struct {
+	UserID       int64
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    int64
+	Flags        *int64
+	Name         string
+}
+
+func (q *Queries) CreatePasskey(ctx context.Context, arg CreatePasskeyParams) (Passkey, error) {
+	row := q.db.QueryRowContext(ctx, createPasskey,
+		arg.UserID,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.Flags,
+		arg.Name,
+	)
+	var i Passkey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.Flags,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const createPasskeyChallenge = `-- name: CreatePasskeyChallenge :one
+INSERT INTO passkey_challenges (token, ceremony, user_id, session)
+VALUES (?, ?, ?, ?)
+RETURNING id, token, ceremony, user_id, session, created_at
+`
+
+type CreatePasskeyChallengeParams struct {
+	Token    string
+	Ceremony string
+	UserID   *int64
+	Session  string
+}
+
+func (q *Queries) CreatePasskeyChallenge(ctx context.Context, arg CreatePasskeyChallengeParams) (PasskeyChallenge, error) {
+	row := q.db.QueryRowContext(ctx, createPasskeyChallenge,
+		arg.Token,
+		arg.Ceremony,
+		arg.UserID,
+		arg.Session,
+	)
+	var i PasskeyChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.Ceremony,
+		&i.UserID,
+		&i.Session,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createPasswordResetToken = `-- name: CreatePasswordResetToken :one
 INSERT INTO password_resets (user_id, token)
 VALUES (?, ?)
@@ -28,6 +224,79 @@ func (q *Queries) CreatePasswordResetToken(ctx context.Context, arg CreatePasswo
 	return i, err
 }
 
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash)
+VALUES (?, ?)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   int64
+	CodeHash string
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const createSSOLogin = `-- name: CreateSSOLogin :one
+INSERT INTO sso_logins (state, nonce, code_verifier)
+VALUES (?, ?, ?)
+RETURNING id, state, nonce, code_verifier, created_at
+`
+
+type CreateSSOLoginParams struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+func (q *Queries) CreateSSOLogin(ctx context.Context, arg CreateSSOLoginParams) (SsoLogin, error) {
+	row := q.db.QueryRowContext(ctx, createSSOLogin, arg.State, arg.Nonce, arg.CodeVerifier)
+	var i SsoLogin
+	err := row.Scan(
+		&i.ID,
+		&i.State,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions (user_id, token_hash, user_agent, ip_address)
+VALUES (?, ?, ?, ?)
+RETURNING id, user_id, token_hash, user_agent, ip_address, created_at, last_seen_at
+`
+
+type CreateSessionParams struct {
+	UserID    int64
+	TokenHash string
+	UserAgent string
+	IpAddress string
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, createSession,
+		arg.UserID,
+		arg.TokenHash,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.CreatedAt,
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, password_hash, role_id, locale)
 VALUES (?, ?, ?, ?)
@@ -61,66 +330,509 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 	return i, err
 }
 
-const createUserRegistration = `-- name: CreateUserRegistration :one
-INSERT INTO user_registrations (user_id, token)
-VALUES (?, ?)
-RETURNING user_id, token, created_at
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (user_id, issuer, subject)
+VALUES (?, ?, ?)
+`
+
+type CreateUserIdentityParams struct {
+	UserID  int64
+	Issuer  string
+	Subject string
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.ExecContext(ctx, createUserIdentity, arg.UserID, arg.Issuer, arg.Subject)
+	return err
+}
+
+const createUserRegistration = `-- name: CreateUserRegistration :one
+INSERT INTO user_registrations (user_id, token)
+VALUES (?, ?)
+RETURNING user_id, token, created_at
+`
+
+type CreateUserRegistrationParams struct {
+	UserID int64
+	Token  string
+}
+
+func (q *Queries) CreateUserRegistration(ctx context.Context, arg CreateUserRegistrationParams) (UserRegistration, error) {
+	row := q.db.QueryRowContext(ctx, createUserRegistration, arg.UserID, arg.Token)
+	var i UserRegistration
+	err := row.Scan(&i.UserID, &i.Token, &i.CreatedAt)
+	return i, err
+}
+
+const deleteAPIToken = `-- name: DeleteAPIToken :execrows
+DELETE
+FROM api_tokens
+WHERE id = ? AND user_id = ?
+`
+
+type DeleteAPITokenParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeleteAPIToken(ctx context.Context, arg DeleteAPITokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAPIToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCalendarTokenByUserId = `-- name: DeleteCalendarTokenByUserId :exec
+DELETE
+FROM calendar_tokens
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteCalendarTokenByUserId(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCalendarTokenByUserId, userID)
+	return err
+}
+
+const deleteLoginChallenge = `-- name: DeleteLoginChallenge :exec
+DELETE
+FROM login_challenges
+WHERE id = ?
+`
+
+func (q *Queries) DeleteLoginChallenge(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteLoginChallenge, id)
+	return err
+}
+
+const deleteLoginChallengesBefore = `-- name: DeleteLoginChallengesBefore :exec
+DELETE
+FROM login_challenges
+WHERE created_at < ?
+`
+
+func (q *Queries) DeleteLoginChallengesBefore(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteLoginChallengesBefore, createdAt)
+	return err
+}
+
+const deleteNutrientTargetsByUserId = `-- name: DeleteNutrientTargetsByUserId :exec
+DELETE
+FROM user_nutrient_targets
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteNutrientTargetsByUserId(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteNutrientTargetsByUserId, userID)
+	return err
+}
+
+const deletePasskey = `-- name: DeletePasskey :execrows
+DELETE
+FROM passkeys
+WHERE id = ? AND user_id = ?
+`
+
+type DeletePasskeyParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeletePasskey(ctx context.Context, arg DeletePasskeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePasskey, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePasskeyChallengesBefore = `-- name: DeletePasskeyChallengesBefore :exec
+DELETE
+FROM passkey_challenges
+WHERE created_at < ?
+`
+
+func (q *Queries) DeletePasskeyChallengesBefore(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deletePasskeyChallengesBefore, createdAt)
+	return err
+}
+
+const deletePasswordResetTokenByUserId = `-- name: DeletePasswordResetTokenByUserId :exec
+DELETE
+FROM password_resets
+WHERE user_id = ?
+`
+
+func (q *Queries) DeletePasswordResetTokenByUserId(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePasswordResetTokenByUserId, userID)
+	return err
+}
+
+const deletePasswordResetsBefore = `-- name: DeletePasswordResetsBefore :exec
+DELETE
+FROM password_resets
+WHERE created_at < ?
+`
+
+func (q *Queries) DeletePasswordResetsBefore(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deletePasswordResetsBefore, createdAt)
+	return err
+}
+
+const deleteRecoveryCode = `-- name: DeleteRecoveryCode :execrows
+DELETE
+FROM user_recovery_codes
+WHERE id = ?
+`
+
+func (q *Queries) DeleteRecoveryCode(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRecoveryCode, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRecoveryCodesByUserId = `-- name: DeleteRecoveryCodesByUserId :exec
+DELETE
+FROM user_recovery_codes
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRecoveryCodesByUserId(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRecoveryCodesByUserId, userID)
+	return err
+}
+
+const deleteRegistrationByUserId = `-- name: DeleteRegistrationByUserId :exec
+DELETE
+FROM user_registrations
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRegistrationByUserId(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRegistrationByUserId, userID)
+	return err
+}
+
+const deleteRegistrationsBefore = `-- name: DeleteRegistrationsBefore :exec
+DELETE
+FROM user_registrations
+WHERE created_at < ?
+`
+
+func (q *Queries) DeleteRegistrationsBefore(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteRegistrationsBefore, createdAt)
+	return err
+}
+
+const deleteSSOLoginsBefore = `-- name: DeleteSSOLoginsBefore :exec
+DELETE
+FROM sso_logins
+WHERE created_at < ?
+`
+
+func (q *Queries) DeleteSSOLoginsBefore(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteSSOLoginsBefore, createdAt)
+	return err
+}
+
+const deleteSession = `-- name: DeleteSession :execrows
+DELETE
+FROM sessions
+WHERE id = ? AND user_id = ?
+`
+
+type DeleteSessionParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeleteSession(ctx context.Context, arg DeleteSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSessionByTokenHash = `-- name: DeleteSessionByTokenHash :exec
+DELETE
+FROM sessions
+WHERE token_hash = ?
+`
+
+func (q *Queries) DeleteSessionByTokenHash(ctx context.Context, tokenHash string) error {
+	_, err := q.db.ExecContext(ctx, deleteSessionByTokenHash, tokenHash)
+	return err
+}
+
+const deleteSessionsBefore = `-- name: DeleteSessionsBefore :exec
+DELETE
+FROM sessions
+WHERE created_at < ?
+`
+
+func (q *Queries) DeleteSessionsBefore(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteSessionsBefore, createdAt)
+	return err
+}
+
+const deleteSessionsByUserId = `-- name: DeleteSessionsByUserId :exec
+DELETE
+FROM sessions
+WHERE user_id = ? AND id != ?2
+`
+
+type DeleteSessionsByUserIdParams struct {
+	UserID int64
+	KeepID int64
+}
+
+func (q *Queries) DeleteSessionsByUserId(ctx context.Context, arg DeleteSessionsByUserIdParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSessionsByUserId, arg.UserID, arg.KeepID)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE
+FROM user_totp
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteUserTOTP(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTP, userID)
+	return err
+}
+
+const getAPITokenByHash = `-- name: GetAPITokenByHash :one
+SELECT id, user_id, name, token_hash, created_at, expires_at, last_used_at
+FROM api_tokens
+WHERE token_hash = ?
+LIMIT 1
+`
+
+func (q *Queries) GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error) {
+	row := q.db.QueryRowContext(ctx, getAPITokenByHash, tokenHash)
+	var i ApiToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getAPITokenPermissions = `-- name: GetAPITokenPermissions :many
+SELECT permissions.slug
+FROM permissions
+INNER JOIN api_token_permissions ON permissions.id = api_token_permissions.permission_id
+WHERE api_token_permissions.api_token_id = ?
+ORDER BY permissions.id
+`
+
+func (q *Queries) GetAPITokenPermissions(ctx context.Context, apiTokenID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getAPITokenPermissions, apiTokenID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAPITokensByUserId = `-- name: GetAPITokensByUserId :many
+SELECT id, user_id, name, token_hash, created_at, expires_at, last_used_at
+FROM api_tokens
+WHERE user_id = ?
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetAPITokensByUserId(ctx context.Context, userID int64) ([]ApiToken, error) {
+	rows, err := q.db.QueryContext(ctx, getAPITokensByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiToken
+	for rows.Next() {
+		var i ApiToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCalendarTokenByUser = `-- name: GetCalendarTokenByUser :one
+SELECT user_id, token_hash, created_at
+FROM calendar_tokens
+WHERE user_id = ?
+LIMIT 1
 `
 
-type CreateUserRegistrationParams struct {
-	UserID int64
-	Token  string
-}
-
-func (q *Queries) CreateUserRegistration(ctx context.Context, arg CreateUserRegistrationParams) (UserRegistration, error) {
-	row := q.db.QueryRowContext(ctx, createUserRegistration, arg.UserID, arg.Token)
-	var i UserRegistration
-	err := row.Scan(&i.UserID, &i.Token, &i.CreatedAt)
+func (q *Queries) GetCalendarTokenByUser(ctx context.Context, userID int64) (CalendarToken, error) {
+	row := q.db.QueryRowContext(ctx, getCalendarTokenByUser, userID)
+	var i CalendarToken
+	err := row.Scan(&i.UserID, &i.TokenHash, &i.CreatedAt)
 	return i, err
 }
 
-const deletePasswordResetTokenByUserId = `-- name: DeletePasswordResetTokenByUserId :exec
-DELETE
-FROM password_resets
-WHERE user_id = ?
+const getLoginChallenge = `-- name: GetLoginChallenge :one
+SELECT id, user_id, token, attempts, created_at
+FROM login_challenges
+WHERE token = ?
+LIMIT 1
 `
 
-func (q *Queries) DeletePasswordResetTokenByUserId(ctx context.Context, userID int64) error {
-	_, err := q.db.ExecContext(ctx, deletePasswordResetTokenByUserId, userID)
-	return err
+func (q *Queries) GetLoginChallenge(ctx context.Context, token string) (LoginChallenge, error) {
+	row := q.db.QueryRowContext(ctx, getLoginChallenge, token)
+	var i LoginChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.Attempts,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const deletePasswordResetsBefore = `-- name: DeletePasswordResetsBefore :exec
-DELETE
-FROM password_resets
-WHERE created_at < ?
+const getNutrientTargetsByUserId = `-- name: GetNutrientTargetsByUserId :many
+SELECT nutrients.id, nutrients.name, nutrients.unit, user_nutrient_targets.amount
+FROM user_nutrient_targets
+         INNER JOIN nutrients ON user_nutrient_targets.nutrient_id = nutrients.id
+WHERE user_nutrient_targets.user_id = ?
+ORDER BY nutrients.name
 `
 
-func (q *Queries) DeletePasswordResetsBefore(ctx context.Context, createdAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, deletePasswordResetsBefore, createdAt)
-	return err
+type GetNutrientTargetsByUserIdRow struct {
+	Nutrient Nutrient
+	Amount   float64
 }
 
-const deleteRegistrationByUserId = `-- name: DeleteRegistrationByUserId :exec
-DELETE
-FROM user_registrations
-WHERE user_id = ?
+func (q *Queries) GetNutrientTargetsByUserId(ctx context.Context, userID int64) ([]GetNutrientTargetsByUserIdRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNutrientTargetsByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNutrientTargetsByUserIdRow
+	for rows.Next() {
+		var i GetNutrientTargetsByUserIdRow
+		if err := rows.Scan(
+			&i.Nutrient.ID,
+			&i.Nutrient.Name,
+			&i.Nutrient.Unit,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPasskeyByCredentialId = `-- name: GetPasskeyByCredentialId :one
+SELECT id, user_id, credential_id, public_key, sign_count, flags, name, created_at, last_used_at
+FROM passkeys
+WHERE credential_id = ?
+LIMIT 1
 `
 
-func (q *Queries) DeleteRegistrationByUserId(ctx context.Context, userID int64) error {
-	_, err := q.db.ExecContext(ctx, deleteRegistrationByUserId, userID)
-	return err
+func (q *Queries) GetPasskeyByCredentialId(ctx context.Context, credentialID []byte) (Passkey, error) {
+	row := q.db.QueryRowContext(ctx, getPasskeyByCredentialId, credentialID)
+	var i Passkey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.Flags,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
 }
 
-const deleteRegistrationsBefore = `-- name: DeleteRegistrationsBefore :exec
-DELETE
-FROM user_registrations
-WHERE created_at < ?
+const getPasskeysByUserId = `-- name: GetPasskeysByUserId :many
+SELECT id, user_id, credential_id, public_key, sign_count, flags, name, created_at, last_used_at
+FROM passkeys
+WHERE user_id = ?
+ORDER BY created_at, id
 `
 
-func (q *Queries) DeleteRegistrationsBefore(ctx context.Context, createdAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, deleteRegistrationsBefore, createdAt)
-	return err
+func (q *Queries) GetPasskeysByUserId(ctx context.Context, userID int64) ([]Passkey, error) {
+	rows, err := q.db.QueryContext(ctx, getPasskeysByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Passkey
+	for rows.Next() {
+		var i Passkey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.SignCount,
+			&i.Flags,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getPasswordResetToken = `-- name: GetPasswordResetToken :one
@@ -204,6 +916,95 @@ func (q *Queries) GetPermissionsByRole(ctx context.Context, roleID int64) ([]Get
 	return items, nil
 }
 
+const getRecoveryCodesByUserId = `-- name: GetRecoveryCodesByUserId :many
+SELECT id, user_id, code_hash
+FROM user_recovery_codes
+WHERE user_id = ?
+`
+
+func (q *Queries) GetRecoveryCodesByUserId(ctx context.Context, userID int64) ([]UserRecoveryCode, error) {
+	rows, err := q.db.QueryContext(ctx, getRecoveryCodesByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRecoveryCode
+	for rows.Next() {
+		var i UserRecoveryCode
+		if err := rows.Scan(&i.ID, &i.UserID, &i.CodeHash); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionByTokenHash = `-- name: GetSessionByTokenHash :one
+SELECT id, user_id, token_hash, user_agent, ip_address, created_at, last_seen_at
+FROM sessions
+WHERE token_hash = ?
+LIMIT 1
+`
+
+func (q *Queries) GetSessionByTokenHash(ctx context.Context, tokenHash string) (Session, error) {
+	row := q.db.QueryRowContext(ctx, getSessionByTokenHash, tokenHash)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.CreatedAt,
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const getSessionsByUserId = `-- name: GetSessionsByUserId :many
+SELECT id, user_id, token_hash, user_agent, ip_address, created_at, last_seen_at
+FROM sessions
+WHERE user_id = ?
+ORDER BY last_seen_at DESC, id DESC
+`
+
+func (q *Queries) GetSessionsByUserId(ctx context.Context, userID int64) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TokenHash,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUser = `-- name: GetUser :one
 SELECT users.id, users.email, users.password_hash, users.is_confirmed, users.role_id, users.locale, users.created_at,
        roles.name as role_name
@@ -262,6 +1063,48 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 	return i, err
 }
 
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+SELECT users.id, users.email, users.password_hash, users.is_confirmed, users.role_id, users.locale, users.created_at
+FROM users
+INNER JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = ? AND user_identities.subject = ?
+LIMIT 1
+`
+
+type GetUserByIdentityParams struct {
+	Issuer  string
+	Subject string
+}
+
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIdentity, arg.Issuer, arg.Subject)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.IsConfirmed,
+		&i.RoleID,
+		&i.Locale,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserIdByCalendarTokenHash = `-- name: GetUserIdByCalendarTokenHash :one
+SELECT user_id
+FROM calendar_tokens
+WHERE token_hash = ?
+LIMIT 1
+`
+
+func (q *Queries) GetUserIdByCalendarTokenHash(ctx context.Context, tokenHash string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserIdByCalendarTokenHash, tokenHash)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
 const getUserRegistration = `-- name: GetUserRegistration :one
 SELECT user_registrations.user_id, user_registrations.token, user_registrations.created_at, users.id, users.email, users.password_hash, users.is_confirmed, users.role_id, users.locale, users.created_at
 FROM user_registrations
@@ -293,6 +1136,153 @@ func (q *Queries) GetUserRegistration(ctx context.Context, token string) (GetUse
 	return i, err
 }
 
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT user_id, secret, confirmed, last_used_step, created_at
+FROM user_totp
+WHERE user_id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetUserTOTP(ctx context.Context, userID int64) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTP, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Confirmed,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const saveUserTOTP = `-- name: SaveUserTOTP :one
+INSERT INTO user_totp (user_id, secret)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, confirmed = 0, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+RETURNING user_id, secret, confirmed, last_used_step, created_at
+`
+
+type SaveUserTOTPParams struct {
+	UserID int64
+	Secret string
+}
+
+func (q *Queries) SaveUserTOTP(ctx context.Context, arg SaveUserTOTPParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, saveUserTOTP, arg.UserID, arg.Secret)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Confirmed,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const takePasskeyChallenge = `-- name: TakePasskeyChallenge :one
+DELETE
+FROM passkey_challenges
+WHERE token = ? AND ceremony = ?
+RETURNING id, token, ceremony, user_id, session, created_at
+`
+
+type TakePasskeyChallengeParams struct {
+	Token    string
+	Ceremony string
+}
+
+func (q *Queries) TakePasskeyChallenge(ctx context.Context, arg TakePasskeyChallengeParams) (PasskeyChallenge, error) {
+	row := q.db.QueryRowContext(ctx, takePasskeyChallenge, arg.Token, arg.Ceremony)
+	var i PasskeyChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.Ceremony,
+		&i.UserID,
+		&i.Session,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const takeSSOLogin = `-- name: TakeSSOLogin :one
+DELETE
+FROM sso_logins
+WHERE state = ?
+RETURNING id, state, nonce, code_verifier, created_at
+`
+
+func (q *Queries) TakeSSOLogin(ctx context.Context, state string) (SsoLogin, error) {
+	row := q.db.QueryRowContext(ctx, takeSSOLogin, state)
+	var i SsoLogin
+	err := row.Scan(
+		&i.ID,
+		&i.State,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateAPITokenLastUsed = `-- name: UpdateAPITokenLastUsed :exec
+UPDATE api_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) UpdateAPITokenLastUsed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, updateAPITokenLastUsed, id)
+	return err
+}
+
+const updateLoginChallengeAttempts = `-- name: UpdateLoginChallengeAttempts :execrows
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE login_challenges.id = ?1
+  AND (SELECT SUM(user_challenges.attempts)
+       FROM login_challenges AS user_challenges
+       WHERE user_challenges.user_id = login_challenges.user_id
+         AND user_challenges.created_at >= ?2) < ?3
+`
+
+type UpdateLoginChallengeAttemptsParams struct {
+	ID          int64
+	Since       time.Time
+	MaxAttempts int64
+}
+
+func (q *Queries) UpdateLoginChallengeAttempts(ctx context.Context, arg UpdateLoginChallengeAttemptsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateLoginChallengeAttempts, arg.ID, arg.Since, arg.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updatePasskeyUsage = `-- name: UpdatePasskeyUsage :execrows
+UPDATE passkeys
+SET sign_count = ?1, flags = ?2, last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?3
+  AND (sign_count < ?1 OR (sign_count = 0 AND ?1 = 0))
+`
+
+type UpdatePasskeyUsageParams struct {
+	SignCount int64
+	Flags     *int64
+	ID        int64
+}
+
+func (q *Queries) UpdatePasskeyUsage(ctx context.Context, arg UpdatePasskeyUsageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updatePasskeyUsage, arg.SignCount, arg.Flags, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updatePasswordByUserId = `-- name: UpdatePasswordByUserId :exec
 UPDATE users
 SET password_hash = ?
@@ -309,6 +1299,17 @@ func (q *Queries) UpdatePasswordByUserId(ctx context.Context, arg UpdatePassword
 	return err
 }
 
+const updateSessionLastSeen = `-- name: UpdateSessionLastSeen :exec
+UPDATE sessions
+SET last_seen_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) UpdateSessionLastSeen(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, updateSessionLastSeen, id)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :exec
 UPDATE users
 SET email        = ?,
@@ -326,3 +1327,22 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
 	_, err := q.db.ExecContext(ctx, updateUser, arg.Email, arg.IsConfirmed, arg.ID)
 	return err
 }
+
+const updateUserTOTPLastUsedStep = `-- name: UpdateUserTOTPLastUsedStep :execrows
+UPDATE user_totp
+SET last_used_step = ?1
+WHERE user_id = ?2 AND last_used_step < ?1
+`
+
+type UpdateUserTOTPLastUsedStepParams struct {
+	Step   int64
+	UserID int64
+}
+
+func (q *Queries) UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserTOTPLastUsedStep, arg.Step, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
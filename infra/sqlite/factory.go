@@ -24,7 +24,6 @@ func NewSqliteStore() (*Store, error) {
 	if err != nil {
 		return nil, err
 	}
-	con.SetMaxOpenConns(1)
 
 	store := &Store{
 		db:     con,
@@ -50,8 +49,13 @@ func (s *Store) Close() error {
 	return nil
 }
 
+// connect opens the database with the settings every connection of the pool
+// needs. modernc.org/sqlite only applies pragmas passed as _pragma. Writers
+// wait for each other instead of failing with SQLITE_BUSY, and transactions
+// take the write lock right away, so two of them can't both read and then
+// fail to upgrade their lock.
 func connect(path string) (*sql.DB, error) {
-	constr := fmt.Sprintf("%s?_fk=1&_journal=WAL&_sync=NORMAL", path)
+	constr := fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)&_txlock=immediate", path)
 	con, err := sql.Open("sqlite", constr)
 	if err != nil {
 		return nil, err
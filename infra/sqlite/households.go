@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/domain/security"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (s *Store) GetHouseholdByID(ctx context.Context, id int64) (domain.Household, error) {
+	row, err := s.query().GetHousehold(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Household{}, domain.ErrHouseholdNotFound
+	} else if err != nil {
+		return domain.Household{}, err
+	}
+
+	household := s.mapper.ToHousehold(row)
+	members, err := s.query().GetHouseholdMembers(ctx, id)
+	if err != nil {
+		return domain.Household{}, err
+	}
+	for _, member := range members {
+		household.Members = append(household.Members, s.mapper.ToHouseholdMember(member))
+	}
+	return household, nil
+}
+
+func (s *Store) UpdateHousehold(ctx context.Context, id int64, name string) (domain.Household, error) {
+	_, err := s.query().UpdateHousehold(ctx, database.UpdateHouseholdParams{
+		Name: name,
+		ID:   id,
+	})
+	if err != nil {
+		return domain.Household{}, err
+	}
+	return s.GetHouseholdByID(ctx, id)
+}
+
+func (s *Store) UpdateHouseholdMember(ctx context.Context, householdID int64, member domain.HouseholdMember) error {
+	return s.query().UpdateHouseholdMember(ctx, database.UpdateHouseholdMemberParams{
+		Role:        string(member.Role),
+		HouseholdID: householdID,
+		UserID:      member.UserID,
+	})
+}
+
+// RemoveHouseholdMember moves the user into a new household of their own.
+func (s *Store) RemoveHouseholdMember(ctx context.Context, householdID int64, userID int64) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		err := tx.query().DeleteHouseholdMember(ctx, database.DeleteHouseholdMemberParams{
+			HouseholdID: householdID,
+			UserID:      userID,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.createHousehold(ctx, userID)
+	})
+}
+
+// moveMealPlanToHousehold appends the planned entries to the slot of the same
+// name in the next household, or to the entries without a slot if it has none.
+func (s *Store) moveMealPlanToHousehold(ctx context.Context, householdID int64, nextHouseholdID int64) error {
+	entries, err := s.query().GetMealPlanEntriesByHousehold(ctx, &householdID)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	slots, err := s.query().GetMealSlotsByHousehold(ctx, householdID)
+	if err != nil {
+		return err
+	}
+	nextSlots, err := s.query().GetMealSlotsByHousehold(ctx, nextHouseholdID)
+	if err != nil {
+		return err
+	}
+
+	slotNames := make(map[int64]string, len(slots))
+	for _, slot := range slots {
+		slotNames[slot.ID] = slot.Name
+	}
+	slotIDs := make(map[string]int64, len(nextSlots))
+	for _, slot := range nextSlots {
+		if _, ok := slotIDs[slot.Name]; !ok {
+			slotIDs[slot.Name] = slot.ID
+		}
+	}
+	return s.appendMealPlanEntries(ctx, nextHouseholdID, entries, func(entry database.MealPlan) *int64 {
+		if entry.SlotID == nil {
+			return nil
+		}
+		if id, ok := slotIDs[slotNames[*entry.SlotID]]; ok {
+			return &id
+		}
+		return nil
+	})
+}
+
+// createHousehold creates a new household with the user as its owner.
+func (s *Store) createHousehold(ctx context.Context, userID int64) error {
+	household, err := s.query().CreateHousehold(ctx, domain.DefaultHouseholdName)
+	if err != nil {
+		return err
+	}
+	err = s.query().CreateHouseholdMember(ctx, s.mapper.FromHouseholdMember(household.ID, domain.HouseholdMember{
+		UserID: userID,
+		Role:   domain.HouseholdRoleOwner,
+	}))
+	if err != nil {
+		return err
+	}
+
+	slots := make([]domain.MealSlot, len(domain.DefaultMealSlots))
+	for i, slot := range domain.DefaultMealSlots {
+		slot.SortOrder = int64(i)
+		slots[i] = slot
+	}
+	return s.saveMealSlots(ctx, household.ID, slots)
+}
+
+func (s *Store) CreateHouseholdInvitation(ctx context.Context, invitation domain.HouseholdInvitation) (domain.HouseholdInvitation, error) {
+	generatedToken := security.GenerateToken(security.DefaultTokenLength)
+	row, err := s.query().CreateHouseholdInvitation(ctx, s.mapper.FromHouseholdInvitation(invitation, generatedToken))
+	if err != nil {
+		return domain.HouseholdInvitation{}, err
+	}
+
+	invitation.ID = row.ID
+	invitation.Token = row.Token
+	invitation.CreatedAt = row.CreatedAt
+	return invitation, nil
+}
+
+func (s *Store) GetHouseholdInvitationByToken(ctx context.Context, token string) (domain.HouseholdInvitation, error) {
+	row, err := s.query().GetHouseholdInvitationByToken(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.HouseholdInvitation{}, domain.ErrInvitationNotFound
+	} else if err != nil {
+		return domain.HouseholdInvitation{}, err
+	}
+	return s.mapper.ToHouseholdInvitation(row), nil
+}
+
+func (s *Store) DeleteHouseholdInvitationsBefore(ctx context.Context, before time.Time) error {
+	return s.query().DeleteHouseholdInvitationsBefore(ctx, before)
+}
+
+// AcceptHouseholdInvitation moves the user into the invited household. A
+// household the user leaves empty is removed, its recipes, meal plan,
+// shopping lists and stores move along with the user.
+func (s *Store) AcceptHouseholdInvitation(ctx context.Context, invitation domain.HouseholdInvitation, user *domain.User) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		previous := user.Membership.HouseholdID
+		if previous != invitation.Household.ID {
+			if err := tx.leaveHousehold(ctx, previous, invitation.Household.ID, user.ID); err != nil {
+				return err
+			}
+			err := tx.query().CreateHouseholdMember(ctx, s.mapper.FromHouseholdMember(invitation.Household.ID, domain.HouseholdMember{
+				UserID: user.ID,
+				Role:   invitation.Role,
+			}))
+			if err != nil {
+				return err
+			}
+		}
+		return tx.query().DeleteHouseholdInvitation(ctx, invitation.ID)
+	})
+}
+
+func (s *Store) leaveHousehold(ctx context.Context, householdID int64, nextHouseholdID int64, userID int64) error {
+	count, err := s.query().CountHouseholdMembers(ctx, householdID)
+	if err != nil {
+		return err
+	}
+	if err = s.query().DeleteHouseholdMember(ctx, database.DeleteHouseholdMemberParams{
+		HouseholdID: householdID,
+		UserID:      userID,
+	}); err != nil {
+		return err
+	}
+	if count > 1 {
+		return nil
+	}
+
+	if err = s.query().MoveRecipesToHousehold(ctx, database.MoveRecipesToHouseholdParams{
+		NewHouseholdID: &nextHouseholdID,
+		OldHouseholdID: &householdID,
+	}); err != nil {
+		return err
+	}
+	if err = s.moveMealPlanToHousehold(ctx, householdID, nextHouseholdID); err != nil {
+		return err
+	}
+	if err = s.query().MoveShoppingListsToHousehold(ctx, database.MoveShoppingListsToHouseholdParams{
+		NewHouseholdID: &nextHouseholdID,
+		OldHouseholdID: &householdID,
+	}); err != nil {
+		return err
+	}
+	if err = s.query().MoveStoresToHousehold(ctx, database.MoveStoresToHouseholdParams{
+		NewHouseholdID: &nextHouseholdID,
+		OldHouseholdID: &householdID,
+	}); err != nil {
+		return err
+	}
+	return s.query().DeleteHousehold(ctx, householdID)
+}
@@ -13,6 +13,25 @@ func (s *Store) GetIngredients(ctx context.Context) ([]domain.Ingredient, error)
 		return nil, err
 	}
 
+	ingredients := make([]domain.Ingredient, len(result))
+	for i, ingredient := range result {
+		ingredients[i] = s.mapper.ToIngredient(ingredient)
+	}
+	return ingredients, nil
+}
+
+func (s *Store) ListIngredients(ctx context.Context, after *domain.Cursor, limit int64) ([]domain.Ingredient, error) {
+	params := database.ListIngredientsParams{PageSize: limit}
+	if after != nil {
+		params.CursorID = &after.ID
+		params.CursorName = &after.Value
+	}
+
+	result, err := s.query().ListIngredients(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
 	ingredients := make([]domain.Ingredient, len(result))
 	for i, ingredient := range result {
 		ingredients[i] = s.mapper.ToIngredient(ingredient)
@@ -65,7 +84,7 @@ func (s *Store) CreateIngredient(ctx context.Context, ingredient domain.Ingredie
 	var id int64
 	err := s.WithTransaction(ctx, func(tx *TxStore) error {
 		var err error
-		id, err = tx.query().CreateIngredient(ctx, ingredient.Name)
+		id, err = tx.query().CreateIngredient(ctx, tx.mapper.FromIngredient(ingredient))
 		if err != nil {
 			return err
 		}
@@ -91,9 +110,13 @@ func (s *Store) CreateIngredient(ctx context.Context, ingredient domain.Ingredie
 
 func (s *Store) UpdateIngredient(ctx context.Context, ingredient domain.Ingredient) (domain.Ingredient, error) {
 	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		params := tx.mapper.FromIngredient(ingredient)
 		err := tx.query().UpdateIngredient(ctx, database.UpdateIngredientParams{
-			Name: ingredient.Name,
-			ID:   ingredient.ID,
+			Name:            params.Name,
+			Density:         params.Density,
+			ReferenceAmount: params.ReferenceAmount,
+			ReferenceUnitID: params.ReferenceUnitID,
+			ID:              ingredient.ID,
 		})
 		if err != nil {
 			return err
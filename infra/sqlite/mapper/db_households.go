@@ -0,0 +1,44 @@
+package mapper
+
+import (
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (m *DBMapper) ToHousehold(r database.Household) domain.Household {
+	return domain.Household{
+		ID:        r.ID,
+		Name:      r.Name,
+		Members:   []domain.HouseholdMember{},
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (m *DBMapper) ToHouseholdMember(r database.GetHouseholdMembersRow) domain.HouseholdMember {
+	return domain.HouseholdMember{
+		UserID: r.UserID,
+		Email:  r.Email,
+		Role:   domain.HouseholdRole(r.Role),
+	}
+}
+
+func (m *DBMapper) ToHouseholdInvitation(r database.GetHouseholdInvitationByTokenRow) domain.HouseholdInvitation {
+	return domain.HouseholdInvitation{
+		ID:        r.HouseholdInvitation.ID,
+		Household: m.ToHousehold(r.Household),
+		Email:     r.HouseholdInvitation.Email,
+		Role:      domain.HouseholdRole(r.HouseholdInvitation.Role),
+		Token:     r.HouseholdInvitation.Token,
+		InvitedBy: &domain.User{
+			ID: r.HouseholdInvitation.InvitedBy,
+		},
+		CreatedAt: r.HouseholdInvitation.CreatedAt,
+	}
+}
+
+func (m *DBMapper) ToMembership(r database.GetHouseholdMembershipRow) domain.Membership {
+	return domain.Membership{
+		HouseholdID: r.HouseholdID,
+		Role:        domain.HouseholdRole(r.Role),
+	}
+}
@@ -1,24 +1,45 @@
 package mapper
 
 import (
+	"time"
+
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
 )
 
 func (m *DBMapper) ToIngredient(r database.Ingredient) domain.Ingredient {
-	return domain.Ingredient{
-		ID:        r.ID,
-		Name:      r.Name,
-		Nutrients: []domain.IngredientNutrient{},
+	ingredient := domain.Ingredient{
+		ID:              r.ID,
+		Name:            r.Name,
+		Density:         r.Density,
+		ReferenceAmount: r.ReferenceAmount,
+		Nutrients:       []domain.IngredientNutrient{},
 	}
+	if r.ReferenceUnitID != nil {
+		ingredient.ReferenceUnit = &domain.Unit{ID: *r.ReferenceUnitID}
+	}
+	return ingredient
 }
 
 func (m *DBMapper) ToIngredientFromRecipeRow(r database.GetIngredientsForRecipesRow) domain.Ingredient {
-	return domain.Ingredient{
-		ID:        r.IngredientID,
-		Name:      r.IngredientName,
-		Nutrients: []domain.IngredientNutrient{},
+	ingredient := domain.Ingredient{
+		ID:              r.IngredientID,
+		Name:            r.IngredientName,
+		Density:         r.IngredientDensity,
+		ReferenceAmount: r.IngredientReferenceAmount,
+		Nutrients:       []domain.IngredientNutrient{},
+	}
+	if r.ReferenceUnitID != nil {
+		ingredient.ReferenceUnit = &domain.Unit{
+			ID:        *r.ReferenceUnitID,
+			Name:      fromNullable(r.ReferenceUnitName),
+			Symbol:    r.ReferenceUnitSymbol,
+			Dimension: domain.UnitDimension(fromNullable(r.ReferenceUnitDimension)),
+			System:    domain.UnitSystem(fromNullable(r.ReferenceUnitSystem)),
+			Factor:    fromNullable(r.ReferenceUnitFactor),
+		}
 	}
+	return ingredient
 }
 
 func (m *DBMapper) ToNutrient(r database.Nutrient) domain.Nutrient {
@@ -38,7 +59,9 @@ func (m *DBMapper) ToIngredientNutrient(r database.GetNutrientsForIngredientRow)
 
 func (m *DBMapper) ToRecipe(r database.Recipe) domain.Recipe {
 	return domain.Recipe{
-		ID: r.ID,
+		ID:          r.ID,
+		HouseholdID: fromNullable(r.HouseholdID),
+		CreatedAt:   r.CreatedAt,
 		RecipeDetails: domain.RecipeDetails{
 			Name:        r.Name,
 			Description: r.Description,
@@ -60,9 +83,12 @@ func (m *DBMapper) ToTag(t database.Tag) domain.Tag {
 
 func (m *DBMapper) ToUnit(u database.Unit) domain.Unit {
 	return domain.Unit{
-		ID:     u.ID,
-		Name:   u.Name,
-		Symbol: u.Symbol,
+		ID:        u.ID,
+		Name:      u.Name,
+		Symbol:    u.Symbol,
+		Dimension: domain.UnitDimension(fromNullable(u.Dimension)),
+		System:    domain.UnitSystem(fromNullable(u.System)),
+		Factor:    fromNullable(u.Factor),
 	}
 }
 
@@ -77,13 +103,17 @@ func (m *DBMapper) ToRecipeStep(r database.GetStepsForRecipesRow) domain.RecipeS
 func (m *DBMapper) ToStepIngredient(r database.GetIngredientsForRecipesRow) domain.StepIngredient {
 	return domain.StepIngredient{
 		Unit: domain.Unit{
-			ID:     r.UnitID,
-			Name:   r.UnitName,
-			Symbol: r.UnitSymbol,
+			ID:        r.UnitID,
+			Name:      r.UnitName,
+			Symbol:    r.UnitSymbol,
+			Dimension: domain.UnitDimension(fromNullable(r.UnitDimension)),
+			System:    domain.UnitSystem(fromNullable(r.UnitSystem)),
+			Factor:    fromNullable(r.UnitFactor),
 		},
 		Ingredient: domain.Ingredient{
-			ID:   r.IngredientID,
-			Name: r.IngredientName,
+			ID:      r.IngredientID,
+			Name:    r.IngredientName,
+			Density: r.IngredientDensity,
 		},
 		Amount: r.Amount,
 	}
@@ -94,3 +124,61 @@ func (m *DBMapper) ToRecipeImage(r database.GetImagesForRecipesRow) domain.Recip
 		ID: r.ID,
 	}
 }
+
+func (m *DBMapper) ToMealPlanEntry(r database.MealPlan) (domain.MealPlanEntry, error) {
+	date, err := time.Parse(time.DateOnly, r.Date)
+	if err != nil {
+		return domain.MealPlanEntry{}, err
+	}
+	return domain.MealPlanEntry{
+		ID:              r.ID,
+		HouseholdID:     fromNullable(r.HouseholdID),
+		UserID:          r.UserID,
+		RecipeID:        r.RecipeID,
+		SlotID:          r.SlotID,
+		Note:            r.Note,
+		Date:            date,
+		SortOrder:       r.SortOrder,
+		Servings:        r.Servings,
+		TemplateEntryID: r.TemplateEntryID,
+	}, nil
+}
+
+func (m *DBMapper) ToMealPlanTemplate(r database.MealPlanTemplate, entries []database.MealPlanTemplateEntry) domain.MealPlanTemplate {
+	template := domain.MealPlanTemplate{
+		ID:          r.ID,
+		HouseholdID: r.HouseholdID,
+		UserID:      r.UserID,
+		Name:        r.Name,
+		Recurring:   r.Recurring,
+		Entries:     make([]domain.MealPlanTemplateEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		template.Entries[i] = domain.MealPlanTemplateEntry{
+			ID:       entry.ID,
+			Weekday:  time.Weekday(entry.Weekday),
+			SlotID:   entry.SlotID,
+			RecipeID: entry.RecipeID,
+			Note:     entry.Note,
+			Servings: entry.Servings,
+		}
+	}
+	return template
+}
+
+func (m *DBMapper) ToMealSlot(r database.MealSlot) domain.MealSlot {
+	return domain.MealSlot{
+		ID:        r.ID,
+		Name:      r.Name,
+		SortOrder: r.SortOrder,
+		Time:      fromNullable(r.Time),
+	}
+}
+
+func fromNullable[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
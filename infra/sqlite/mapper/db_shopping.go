@@ -0,0 +1,63 @@
+package mapper
+
+import (
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (m *DBMapper) ToShoppingList(r database.ShoppingList) domain.ShoppingList {
+	return domain.ShoppingList{
+		ID:          r.ID,
+		HouseholdID: fromNullable(r.HouseholdID),
+		UserID:      r.UserID,
+		Name:        r.Name,
+		Items:       []domain.ShoppingListItem{},
+		Revision:    r.Revision,
+	}
+}
+
+func (m *DBMapper) ToShoppingListItem(r database.ShoppingListItem) domain.ShoppingListItem {
+	return domain.ShoppingListItem{
+		ID:           r.ID,
+		Ingredient:   r.Ingredient,
+		Quantity:     r.Quantity,
+		Unit:         r.Unit,
+		IngredientID: r.IngredientID,
+		UnitID:       r.UnitID,
+		Amount:       r.Amount,
+		Recipes:      []domain.Recipe{},
+		Done:         r.Done,
+		SortOrder:    r.SortOrder,
+		Version:      r.Version,
+		UpdatedAt:    fromNullable(r.UpdatedAt),
+		ClientID:     r.ClientID,
+	}
+}
+
+func (m *DBMapper) ToShoppingListItemRecipe(r database.GetRecipesForShoppingListItemsRow) domain.Recipe {
+	return domain.Recipe{
+		ID: r.ID,
+		RecipeDetails: domain.RecipeDetails{
+			Name: r.Name,
+		},
+	}
+}
+
+func (m *DBMapper) ToStore(r database.Store) domain.Store {
+	return domain.Store{
+		ID:          r.ID,
+		HouseholdID: fromNullable(r.HouseholdID),
+		UserID:      r.UserID,
+		Name:        r.Name,
+		Sections:    []domain.StoreSection{},
+	}
+}
+
+func (m *DBMapper) ToStoreSection(r database.StoreSection) domain.StoreSection {
+	return domain.StoreSection{
+		ID:            r.ID,
+		Name:          r.Name,
+		SortOrder:     r.SortOrder,
+		IngredientIDs: []int64{},
+	}
+}
@@ -1,6 +1,10 @@
 package mapper
 
 import (
+	"encoding/json"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/domain/permissions"
 	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
@@ -37,6 +41,114 @@ func (m *DBMapper) ToPasswordResetToken(t database.PasswordReset) domain.Passwor
 	}
 }
 
+func (m *DBMapper) ToCalendarToken(t database.CalendarToken) domain.CalendarToken {
+	return domain.CalendarToken{
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func (m *DBMapper) ToSession(s database.Session) domain.Session {
+	return domain.Session{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		SessionClient: domain.SessionClient{
+			UserAgent: s.UserAgent,
+			IPAddress: s.IpAddress,
+		},
+	}
+}
+
+func (m *DBMapper) ToTOTP(t database.UserTotp) domain.TOTP {
+	return domain.TOTP{
+		Secret:       t.Secret,
+		Confirmed:    t.Confirmed,
+		LastUsedStep: t.LastUsedStep,
+	}
+}
+
+func (m *DBMapper) ToRecoveryCode(c database.UserRecoveryCode) domain.RecoveryCode {
+	return domain.RecoveryCode{
+		ID:   c.ID,
+		Hash: c.CodeHash,
+	}
+}
+
+func (m *DBMapper) ToLoginChallenge(c database.LoginChallenge) domain.LoginChallenge {
+	return domain.LoginChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		Token:     c.Token,
+		Attempts:  c.Attempts,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func (m *DBMapper) ToPasskey(p database.Passkey) domain.Passkey {
+	passkey := domain.Passkey{
+		ID:     p.ID,
+		UserID: p.UserID,
+		Name:   p.Name,
+		Credential: webauthn.Credential{
+			ID:        p.CredentialID,
+			PublicKey: p.PublicKey,
+			Authenticator: webauthn.Authenticator{
+				SignCount: uint32(p.SignCount),
+			},
+		},
+		UnknownFlags: p.Flags == nil,
+		CreatedAt:    p.CreatedAt,
+		LastUsedAt:   p.LastUsedAt,
+	}
+	if p.Flags != nil {
+		passkey.Credential.Flags = webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(*p.Flags))
+	}
+	return passkey
+}
+
+func (m *DBMapper) ToPasskeyChallenge(c database.PasskeyChallenge) (domain.PasskeyChallenge, error) {
+	challenge := domain.PasskeyChallenge{
+		ID:        c.ID,
+		Token:     c.Token,
+		Ceremony:  c.Ceremony,
+		CreatedAt: c.CreatedAt,
+	}
+	if c.UserID != nil {
+		challenge.UserID = *c.UserID
+	}
+	if err := json.Unmarshal([]byte(c.Session), &challenge.Session); err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+	return challenge, nil
+}
+
+func (m *DBMapper) ToSSOLogin(l database.SsoLogin) domain.SSOLogin {
+	return domain.SSOLogin{
+		ID:           l.ID,
+		State:        l.State,
+		Nonce:        l.Nonce,
+		CodeVerifier: l.CodeVerifier,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+func (m *DBMapper) ToAPIToken(t database.ApiToken, scopes []string) domain.APIToken {
+	token := domain.APIToken{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Scopes:     make([]permissions.Slug, len(scopes)),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+	for i, scope := range scopes {
+		token.Scopes[i] = permissions.Slug(scope)
+	}
+	return token
+}
+
 func (m *DBMapper) ToUser(r database.User) domain.User {
 	return domain.User{
 		ID:        r.ID,
@@ -55,3 +167,10 @@ func (m *DBMapper) ToUserRegistration(r database.UserRegistration) domain.UserRe
 		CreatedAt: r.CreatedAt,
 	}
 }
+
+func (m *DBMapper) ToNutrientTarget(r database.GetNutrientTargetsByUserIdRow) domain.NutrientTarget {
+	return domain.NutrientTarget{
+		Nutrient: m.ToNutrient(r.Nutrient),
+		Amount:   r.Amount,
+	}
+}
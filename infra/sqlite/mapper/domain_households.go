@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (m *DBMapper) FromHouseholdInvitation(invitation domain.HouseholdInvitation, token string) database.CreateHouseholdInvitationParams {
+	return database.CreateHouseholdInvitationParams{
+		HouseholdID: invitation.Household.ID,
+		Email:       invitation.Email,
+		Role:        string(invitation.Role),
+		Token:       token,
+		InvitedBy:   invitation.InvitedBy.ID,
+	}
+}
+
+func (m *DBMapper) FromHouseholdMember(householdID int64, member domain.HouseholdMember) database.CreateHouseholdMemberParams {
+	return database.CreateHouseholdMemberParams{
+		HouseholdID: householdID,
+		UserID:      member.UserID,
+		Role:        string(member.Role),
+	}
+}
@@ -1,6 +1,8 @@
 package mapper
 
 import (
+	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/wolfsblu/recipe-manager/domain"
@@ -14,6 +16,7 @@ func (m *DBMapper) FromRecipe(recipe domain.Recipe) database.CreateRecipeParams
 		Minutes:     recipe.Minutes,
 		Description: recipe.Description,
 		CreatedBy:   recipe.CreatedBy.ID,
+		HouseholdID: toNullable(recipe.HouseholdID),
 	}
 }
 
@@ -61,10 +64,99 @@ func (m *DBMapper) FromRecipeTag(recipeID int64, tag domain.Tag) database.Create
 
 func (m *DBMapper) FromMealPlanEntry(entry domain.MealPlanEntry) database.CreateMealPlanParams {
 	return database.CreateMealPlanParams{
-		Date:      entry.Date.Format(time.DateOnly),
-		UserID:    entry.UserID,
-		RecipeID:  entry.RecipeID,
-		SortOrder: entry.SortOrder,
+		Date:        entry.Date.Format(time.DateOnly),
+		HouseholdID: toNullable(entry.HouseholdID),
+		UserID:      entry.UserID,
+		RecipeID:    entry.RecipeID,
+		Servings:    entry.Servings,
+		SlotID:      entry.SlotID,
+		Note:        entry.Note,
+	}
+}
+
+func (m *DBMapper) FromMealPlanEntryForUpdate(entry domain.MealPlanEntry) database.UpdateMealPlanEntryParams {
+	return database.UpdateMealPlanEntryParams{
+		RecipeID:    entry.RecipeID,
+		SlotID:      entry.SlotID,
+		Note:        entry.Note,
+		Servings:    entry.Servings,
+		ID:          entry.ID,
+		HouseholdID: toNullable(entry.HouseholdID),
+	}
+}
+
+func (m *DBMapper) FromMealSlot(householdID int64, slot domain.MealSlot) database.CreateMealSlotParams {
+	return database.CreateMealSlotParams{
+		HouseholdID: householdID,
+		Name:        slot.Name,
+		SortOrder:   slot.SortOrder,
+		Time:        toNullable(slot.Time),
+	}
+}
+
+func (m *DBMapper) FromMealSlotForUpdate(householdID int64, slot domain.MealSlot) database.UpdateMealSlotParams {
+	return database.UpdateMealSlotParams{
+		Name:        slot.Name,
+		SortOrder:   slot.SortOrder,
+		Time:        toNullable(slot.Time),
+		ID:          slot.ID,
+		HouseholdID: householdID,
+	}
+}
+
+func (m *DBMapper) FromMealPlanOccurrence(entry domain.MealPlanEntry) database.CreateMealPlanOccurrenceParams {
+	return database.CreateMealPlanOccurrenceParams{
+		Date:            entry.Date.Format(time.DateOnly),
+		HouseholdID:     toNullable(entry.HouseholdID),
+		UserID:          entry.UserID,
+		RecipeID:        entry.RecipeID,
+		Servings:        entry.Servings,
+		SlotID:          entry.SlotID,
+		Note:            entry.Note,
+		TemplateEntryID: entry.TemplateEntryID,
+	}
+}
+
+func (m *DBMapper) FromMealPlanTemplate(template domain.MealPlanTemplate) database.CreateMealPlanTemplateParams {
+	return database.CreateMealPlanTemplateParams{
+		HouseholdID: template.HouseholdID,
+		UserID:      template.UserID,
+		Name:        template.Name,
+		Recurring:   template.Recurring,
+	}
+}
+
+func (m *DBMapper) FromMealPlanTemplateForUpdate(template domain.MealPlanTemplate) database.UpdateMealPlanTemplateParams {
+	return database.UpdateMealPlanTemplateParams{
+		Name:        template.Name,
+		Recurring:   template.Recurring,
+		ID:          template.ID,
+		HouseholdID: template.HouseholdID,
+	}
+}
+
+func (m *DBMapper) FromMealPlanTemplateEntry(templateID int64, sortOrder int, entry domain.MealPlanTemplateEntry) database.CreateMealPlanTemplateEntryParams {
+	return database.CreateMealPlanTemplateEntryParams{
+		TemplateID: templateID,
+		Weekday:    int64(entry.Weekday),
+		SlotID:     entry.SlotID,
+		RecipeID:   entry.RecipeID,
+		Note:       entry.Note,
+		Servings:   entry.Servings,
+		SortOrder:  int64(sortOrder),
+	}
+}
+
+func (m *DBMapper) FromMealPlanTemplateEntryForUpdate(templateID int64, sortOrder int, entry domain.MealPlanTemplateEntry) database.UpdateMealPlanTemplateEntryParams {
+	return database.UpdateMealPlanTemplateEntryParams{
+		Weekday:    int64(entry.Weekday),
+		SlotID:     entry.SlotID,
+		RecipeID:   entry.RecipeID,
+		Note:       entry.Note,
+		Servings:   entry.Servings,
+		SortOrder:  int64(sortOrder),
+		ID:         entry.ID,
+		TemplateID: templateID,
 	}
 }
 
@@ -75,3 +167,74 @@ func (m *DBMapper) FromIngredientNutrient(ingredientID int64, nutrient domain.In
 		Amount:       nutrient.Amount,
 	}
 }
+
+func (m *DBMapper) FromRecipeListQuery(query domain.RecipeListQuery, after *domain.Cursor) (database.ListRecipesParams, error) {
+	params := database.ListRecipesParams{
+		SortKey:     string(query.SortBy),
+		CreatedBy:   query.Filter.CreatedBy,
+		HouseholdID: query.Filter.HouseholdID,
+		MaxMinutes:  query.Filter.MaxMinutes,
+		MinServings: query.Filter.MinServings,
+		MaxServings: query.Filter.MaxServings,
+		Descending:  query.Order == domain.SortDescending,
+		PageSize:    query.Limit,
+	}
+
+	params.TagIds = toJSONArray(query.Filter.TagIDs)
+	params.IncludeIngredientIds = toJSONArray(query.Filter.IncludeIngredientIDs)
+	params.ExcludeIngredientIds = toJSONArray(query.Filter.ExcludeIngredientIDs)
+
+	if after != nil {
+		params.CursorID = &after.ID
+		params.CursorValue = after.Value
+		if query.SortBy == domain.RecipeSortByMinutes {
+			minutes, err := strconv.ParseInt(after.Value, 10, 64)
+			if err != nil {
+				return params, domain.ErrInvalidCursor
+			}
+			params.CursorValue = minutes
+		}
+	}
+	return params, nil
+}
+
+func (m *DBMapper) FromIngredient(ingredient domain.Ingredient) database.CreateIngredientParams {
+	params := database.CreateIngredientParams{
+		Name:            ingredient.Name,
+		Density:         ingredient.Density,
+		ReferenceAmount: ingredient.ReferenceAmount,
+	}
+	if ingredient.ReferenceUnit != nil {
+		params.ReferenceUnitID = &ingredient.ReferenceUnit.ID
+	}
+	return params
+}
+
+func (m *DBMapper) FromUnit(unit domain.Unit) database.CreateUnitParams {
+	return database.CreateUnitParams{
+		Name:      unit.Name,
+		Symbol:    unit.Symbol,
+		Dimension: toNullable(string(unit.Dimension)),
+		System:    toNullable(string(unit.System)),
+		Factor:    toNullable(unit.Factor),
+	}
+}
+
+// toNullable stores zero values as NULL.
+func toNullable[T comparable](value T) *T {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return &value
+}
+
+// toJSONArray encodes ids for use with json_each, returning nil for an
+// empty filter so the query skips it entirely.
+func toJSONArray(ids []int64) any {
+	if len(ids) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(ids)
+	return string(data)
+}
@@ -0,0 +1,55 @@
+package mapper
+
+import (
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (m *DBMapper) FromShoppingListItem(listID int64, revision int64, item domain.ShoppingListItem) database.CreateShoppingListItemParams {
+	return database.CreateShoppingListItemParams{
+		ShoppingListID: listID,
+		Ingredient:     item.Ingredient,
+		Quantity:       item.Quantity,
+		Unit:           item.Unit,
+		Done:           item.Done,
+		SortOrder:      item.SortOrder,
+		IngredientID:   item.IngredientID,
+		UnitID:         item.UnitID,
+		Amount:         item.Amount,
+		Revision:       revision,
+		UpdatedAt:      toNullable(item.UpdatedAt),
+		ClientID:       item.ClientID,
+	}
+}
+
+func (m *DBMapper) FromShoppingListItemForUpdate(itemID int64, revision int64, item domain.ShoppingListItem) database.UpdateShoppingListItemParams {
+	return database.UpdateShoppingListItemParams{
+		Ingredient:   item.Ingredient,
+		Quantity:     item.Quantity,
+		Unit:         item.Unit,
+		Done:         item.Done,
+		IngredientID: item.IngredientID,
+		UnitID:       item.UnitID,
+		Amount:       item.Amount,
+		Revision:     revision,
+		UpdatedAt:    toNullable(item.UpdatedAt),
+		ID:           itemID,
+	}
+}
+
+func (m *DBMapper) FromStoreSection(storeID int64, section domain.StoreSection) database.CreateStoreSectionParams {
+	return database.CreateStoreSectionParams{
+		StoreID:   storeID,
+		Name:      section.Name,
+		SortOrder: section.SortOrder,
+	}
+}
+
+func (m *DBMapper) FromStoreSectionForUpdate(storeID int64, section domain.StoreSection) database.UpdateStoreSectionParams {
+	return database.UpdateStoreSectionParams{
+		Name:      section.Name,
+		SortOrder: section.SortOrder,
+		ID:        section.ID,
+		StoreID:   storeID,
+	}
+}
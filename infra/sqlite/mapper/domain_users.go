@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
 )
@@ -12,6 +13,34 @@ func (m *DBMapper) FromUserForPasswordResetToken(user *domain.User, token string
 	}
 }
 
+func (m *DBMapper) FromUserForCalendarToken(userID int64, hash string) database.CreateCalendarTokenParams {
+	return database.CreateCalendarTokenParams{
+		UserID:    userID,
+		TokenHash: hash,
+	}
+}
+
+func (m *DBMapper) FromSessionClient(userID int64, hash string, client domain.SessionClient) database.CreateSessionParams {
+	return database.CreateSessionParams{
+		UserID:    userID,
+		TokenHash: hash,
+		UserAgent: client.UserAgent,
+		IpAddress: client.IPAddress,
+	}
+}
+
+func (m *DBMapper) FromPasskeyCredential(userID int64, name string, credential webauthn.Credential) database.CreatePasskeyParams {
+	flags := int64(credential.Flags.ProtocolValue())
+	return database.CreatePasskeyParams{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.Authenticator.SignCount),
+		Flags:        &flags,
+		Name:         name,
+	}
+}
+
 func (m *DBMapper) FromUserDetails(userDetails domain.UserDetails, roleID int64) database.CreateUserParams {
 	return database.CreateUserParams{
 		Email:        userDetails.Email,
@@ -21,6 +50,14 @@ func (m *DBMapper) FromUserDetails(userDetails domain.UserDetails, roleID int64)
 	}
 }
 
+func (m *DBMapper) FromUserIdentity(userID int64, identity domain.ExternalIdentity) database.CreateUserIdentityParams {
+	return database.CreateUserIdentityParams{
+		UserID:  userID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	}
+}
+
 func (m *DBMapper) FromUserForRegistration(user *domain.User, token string) database.CreateUserRegistrationParams {
 	return database.CreateUserRegistrationParams{
 		UserID: user.ID,
@@ -42,3 +79,11 @@ func (m *DBMapper) FromUserForUpdate(user *domain.User) database.UpdateUserParam
 		ID:          user.ID,
 	}
 }
+
+func (m *DBMapper) FromNutrientTarget(userID int64, target domain.NutrientTarget) database.CreateNutrientTargetParams {
+	return database.CreateNutrientTargetParams{
+		UserID:     userID,
+		NutrientID: target.Nutrient.ID,
+		Amount:     target.Amount,
+	}
+}
@@ -0,0 +1,333 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/mapper"
+)
+
+func TestCreateMealPlanConcurrently(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	recipes := domain.NewRecipeService(nil, store)
+	date := time.Date(2025, 10, 23, 0, 0, 0, 0, time.UTC)
+
+	users := make([]*domain.User, 3)
+	for i := range users {
+		users[i] = registerTestUser(t, store, fmt.Sprintf("user%d@example.com", i))
+	}
+	// A second member plans on the same household as the first user
+	member := registerTestUser(t, store, "member@example.com")
+	execTestSQL(t, store, `DELETE FROM household_members WHERE user_id = ?`, member.ID)
+	execTestSQL(t, store, `INSERT INTO household_members (household_id, user_id, role) VALUES (?, ?, 'editor')`, users[0].Membership.HouseholdID, member.ID)
+	member.Membership = domain.Membership{HouseholdID: users[0].Membership.HouseholdID, Role: domain.HouseholdRoleEditor}
+	users = append(users, member)
+
+	const perUser = 10
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, len(users)*perUser)
+	for _, user := range users {
+		slots, err := recipes.GetMealSlots(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range perUser {
+			entry := domain.MealPlanEntry{Date: date, Note: ptr(fmt.Sprintf("Meal %d", i))}
+			if i%2 == 0 {
+				entry.SlotID = &slots[0].ID
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				errs <- recipes.CreateMealPlan(ctx, user, entry)
+			}()
+		}
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateMealPlan() error = %v", err)
+		}
+	}
+
+	for _, user := range users[:3] {
+		plan, err := recipes.GetMealPlan(ctx, user, date, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := perUser
+		if user == users[0] {
+			want = 2 * perUser
+		}
+		if len(plan) != 1 || len(plan[0].Meals) != want {
+			t.Fatalf("GetMealPlan() = %v, want %d meals", plan, want)
+		}
+
+		entries, err := store.query().GetMealPlanEntriesByHousehold(ctx, &user.Membership.HouseholdID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSortOrders(t, entries)
+	}
+}
+
+func TestSaveMealSlotsKeepsEntries(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	recipes := domain.NewRecipeService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+	date := time.Date(2025, 10, 23, 0, 0, 0, 0, time.UTC)
+
+	slots, err := recipes.GetMealSlots(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slotID := range []*int64{nil, &slots[0].ID, &slots[0].ID} {
+		if err = recipes.CreateMealPlan(ctx, user, domain.MealPlanEntry{Date: date, SlotID: slotID, Note: ptr("Leftovers")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = recipes.SaveMealSlots(ctx, user, slots[1:]); err != nil {
+		t.Fatalf("SaveMealSlots() error = %v", err)
+	}
+	entries, err := store.query().GetMealPlanEntriesByHousehold(ctx, &user.Membership.HouseholdID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("SaveMealSlots() left %d entries, want 3", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.SlotID != nil {
+			t.Errorf("SaveMealSlots() left entry %d in slot %d", entry.ID, *entry.SlotID)
+		}
+	}
+	assertSortOrders(t, entries)
+}
+
+func TestMealPlanTemplatePropagation(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	recipes := domain.NewRecipeService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	nextMonday, friday, nextFriday := monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 4), monday.AddDate(0, 0, 11)
+
+	slots, err := recipes.GetMealSlots(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := store.CreateMealPlanTemplate(ctx, domain.MealPlanTemplate{
+		HouseholdID: user.Membership.HouseholdID,
+		UserID:      user.ID,
+		Name:        "Week",
+		Entries: []domain.MealPlanTemplateEntry{
+			{Weekday: time.Monday, SlotID: &slots[0].ID, Note: ptr("Porridge")},
+			{Weekday: time.Friday, SlotID: &slots[2].ID, Note: ptr("Pizza")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Applying it twice adds every entry once
+	for range 2 {
+		if err = store.ApplyMealPlanTemplate(ctx, template, []time.Time{monday, nextMonday}, monday); err != nil {
+			t.Fatalf("ApplyMealPlanTemplate() error = %v", err)
+		}
+	}
+
+	plan, err := recipes.GetMealPlan(ctx, user, monday, nextFriday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entryIDs := make(map[string]int64)
+	for _, day := range plan {
+		if len(day.Meals) != 1 || day.Meals[0].TemplateEntryID == nil {
+			t.Fatalf("ApplyMealPlanTemplate() planned %+v on %s, want one entry of the template", day.Meals, day.Date.Format(time.DateOnly))
+		}
+		entryIDs[day.Date.Format(time.DateOnly)] = day.Meals[0].EntryID
+	}
+	if len(entryIDs) != 4 {
+		t.Fatalf("ApplyMealPlanTemplate() planned %d days, want 4", len(entryIDs))
+	}
+
+	// Changed and removed entries are left alone by the template from now on
+	err = recipes.UpdateMealPlanEntry(ctx, user, entryIDs[nextFriday.Format(time.DateOnly)], domain.MealPlanEntry{SlotID: &slots[2].ID, Note: ptr("Sushi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = recipes.DeleteMealPlanEntry(ctx, user, entryIDs[nextMonday.Format(time.DateOnly)]); err != nil {
+		t.Fatal(err)
+	}
+
+	template.Entries[1].Note = ptr("Calzone")
+	if _, err = store.UpdateMealPlanTemplate(ctx, template, monday.AddDate(0, 0, 3)); err != nil {
+		t.Fatalf("UpdateMealPlanTemplate() error = %v", err)
+	}
+	want := map[string]struct {
+		note   string
+		linked bool
+	}{
+		monday.Format(time.DateOnly):     {"Porridge", true},
+		friday.Format(time.DateOnly):     {"Calzone", true},
+		nextFriday.Format(time.DateOnly): {"Sushi", false},
+	}
+	plan, err = recipes.GetMealPlan(ctx, user, monday, nextFriday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != len(want) {
+		t.Fatalf("UpdateMealPlanTemplate() left %d days planned, want %d", len(plan), len(want))
+	}
+	for _, day := range plan {
+		w := want[day.Date.Format(time.DateOnly)]
+		meal := day.Meals[0]
+		if len(day.Meals) != 1 || *meal.Note != w.note || (meal.TemplateEntryID != nil) != w.linked {
+			t.Errorf("UpdateMealPlanTemplate() planned %+v on %s, want %q", day.Meals, day.Date.Format(time.DateOnly), w.note)
+		}
+	}
+
+	if err = store.DeleteMealPlanTemplate(ctx, user.Membership.HouseholdID, template.ID, monday.AddDate(0, 0, 3)); err != nil {
+		t.Fatalf("DeleteMealPlanTemplate() error = %v", err)
+	}
+	entries, err := store.query().GetMealPlanEntriesByHousehold(ctx, &user.Membership.HouseholdID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].TemplateEntryID != nil {
+		t.Errorf("DeleteMealPlanTemplate() left %+v, want Porridge and Sushi without a template", entries)
+	}
+	assertSortOrders(t, entries)
+}
+
+func TestMealPlanSortOrderMigration(t *testing.T) {
+	const migration = "20251023081907.sql"
+	store := newTestStore(t, migration)
+	execTestSQL(t, store, `INSERT INTO users (id, email, password_hash, role_id) VALUES (1, 'a@example.com', '', (SELECT id FROM roles LIMIT 1))`)
+	execTestSQL(t, store, `INSERT INTO households (id, name) VALUES (1, 'A'), (2, 'B')`)
+	execTestSQL(t, store, `INSERT INTO meal_plan (id, date, user_id, household_id, note, sort_order) VALUES
+		(1, '2025-10-23', 1, 1, 'a', 7),
+		(2, '2025-10-23', 1, 1, 'b', 3),
+		(3, '2025-10-23', 1, 2, 'c', 5),
+		(4, '2025-10-24', 1, 1, 'd', 4)`)
+	applyTestMigrations(t, store, func(name string) bool { return name >= migration })
+
+	rows, err := store.db.Query(`SELECT id, sort_order FROM meal_plan ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	want := map[int64]int64{1: 1, 2: 0, 3: 0, 4: 0}
+	for rows.Next() {
+		var id, sortOrder int64
+		if err = rows.Scan(&id, &sortOrder); err != nil {
+			t.Fatal(err)
+		}
+		if sortOrder != want[id] {
+			t.Errorf("entry %d at %d, want %d", id, sortOrder, want[id])
+		}
+	}
+
+	// Both households can now plan the first meal of a day
+	execTestSQL(t, store, `INSERT INTO meal_plan (date, user_id, household_id, note, sort_order) VALUES ('2025-10-25', 1, 1, 'e', 0), ('2025-10-25', 1, 2, 'f', 0)`)
+}
+
+// newTestStore creates a store on a new database with the migrations before
+// the given one applied, or all of them if it is empty.
+func newTestStore(t *testing.T, before string) *Store {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	con, err := connect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = con.Close() })
+
+	store := &Store{db: con, path: path, q: database.New(con), mapper: mapper.New()}
+	applyTestMigrations(t, store, func(name string) bool { return before == "" || name < before })
+	return store
+}
+
+// applyTestMigrations runs the migration files directly, tests can't rely on
+// the atlas binary being installed.
+func applyTestMigrations(t *testing.T, store *Store, include func(name string) bool) {
+	t.Helper()
+	files, err := fs.Glob(migrationFS, "migrations/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		if !include(name) {
+			continue
+		}
+		migration, err := migrationFS.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = store.db.Exec(string(migration)); err != nil {
+			t.Fatalf("migration %s: %v", name, err)
+		}
+	}
+}
+
+func registerTestUser(t *testing.T, store *Store, email string) *domain.User {
+	t.Helper()
+	ctx := context.Background()
+	registered, _, err := store.RegisterUser(ctx, domain.UserDetails{Email: email, Locale: "en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := store.GetUserById(ctx, registered.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+func execTestSQL(t *testing.T, store *Store, query string, args ...any) {
+	t.Helper()
+	if _, err := store.db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertSortOrders checks that the entries of every day and slot are
+// numbered from 0 without gaps or duplicates.
+func assertSortOrders(t *testing.T, entries []database.MealPlan) {
+	t.Helper()
+	positions := make(map[string][]int64)
+	for _, entry := range entries {
+		key := entry.Date
+		if entry.SlotID != nil {
+			key += fmt.Sprintf("/%d", *entry.SlotID)
+		}
+		positions[key] = append(positions[key], entry.SortOrder)
+	}
+	for key, sortOrders := range positions {
+		for i, sortOrder := range sortOrders {
+			if sortOrder != int64(i) {
+				t.Errorf("sort orders of %s = %v, want 0 to %d", key, sortOrders, len(sortOrders)-1)
+				break
+			}
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
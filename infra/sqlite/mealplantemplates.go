@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (s *Store) GetMealPlanTemplates(ctx context.Context, householdID int64) ([]domain.MealPlanTemplate, error) {
+	rows, err := s.query().GetMealPlanTemplatesByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	return s.populateMealPlanTemplates(ctx, rows)
+}
+
+func (s *Store) GetRecurringMealPlanTemplates(ctx context.Context) ([]domain.MealPlanTemplate, error) {
+	rows, err := s.query().GetRecurringMealPlanTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.populateMealPlanTemplates(ctx, rows)
+}
+
+func (s *Store) GetMealPlanTemplate(ctx context.Context, householdID int64, templateID int64) (domain.MealPlanTemplate, error) {
+	row, err := s.query().GetMealPlanTemplate(ctx, database.GetMealPlanTemplateParams{
+		ID:          templateID,
+		HouseholdID: householdID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.MealPlanTemplate{}, domain.ErrMealPlanTemplateNotFound
+	} else if err != nil {
+		return domain.MealPlanTemplate{}, err
+	}
+	entries, err := s.query().GetMealPlanTemplateEntries(ctx, row.ID)
+	if err != nil {
+		return domain.MealPlanTemplate{}, err
+	}
+	return s.mapper.ToMealPlanTemplate(row, entries), nil
+}
+
+func (s *Store) CreateMealPlanTemplate(ctx context.Context, template domain.MealPlanTemplate) (result domain.MealPlanTemplate, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		row, err := tx.query().CreateMealPlanTemplate(ctx, tx.mapper.FromMealPlanTemplate(template))
+		if err != nil {
+			return err
+		}
+		template.ID = row.ID
+		if err = tx.saveMealPlanTemplateEntries(ctx, template); err != nil {
+			return err
+		}
+		result, err = tx.GetMealPlanTemplate(ctx, template.HouseholdID, template.ID)
+		return err
+	})
+	return result, err
+}
+
+func (s *Store) UpdateMealPlanTemplate(ctx context.Context, template domain.MealPlanTemplate, from time.Time) (result domain.MealPlanTemplate, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		_, err := tx.query().UpdateMealPlanTemplate(ctx, tx.mapper.FromMealPlanTemplateForUpdate(template))
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrMealPlanTemplateNotFound
+		} else if err != nil {
+			return err
+		}
+		if err = tx.deleteMealPlanTemplateOccurrences(ctx, template.ID, from); err != nil {
+			return err
+		}
+		if err = tx.saveMealPlanTemplateEntries(ctx, template); err != nil {
+			return err
+		}
+		if result, err = tx.GetMealPlanTemplate(ctx, template.HouseholdID, template.ID); err != nil {
+			return err
+		}
+
+		// Every week that ends on or after from has days left to fill again
+		rows, err := tx.query().GetMealPlanTemplateWeeks(ctx, database.GetMealPlanTemplateWeeksParams{
+			TemplateID: template.ID,
+			Week:       from.AddDate(0, 0, -6).Format(time.DateOnly),
+		})
+		if err != nil {
+			return err
+		}
+		weeks := make([]time.Time, len(rows))
+		for i, row := range rows {
+			if weeks[i], err = time.Parse(time.DateOnly, row); err != nil {
+				return err
+			}
+		}
+		return tx.createMealPlanOccurrences(ctx, result.Occurrences(weeks, from))
+	})
+	return result, err
+}
+
+func (s *Store) DeleteMealPlanTemplate(ctx context.Context, householdID int64, templateID int64, from time.Time) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		if _, err := tx.GetMealPlanTemplate(ctx, householdID, templateID); err != nil {
+			return err
+		}
+		if err := tx.deleteMealPlanTemplateOccurrences(ctx, templateID, from); err != nil {
+			return err
+		}
+		return tx.query().DeleteMealPlanTemplate(ctx, templateID)
+	})
+}
+
+func (s *Store) ApplyMealPlanTemplate(ctx context.Context, template domain.MealPlanTemplate, weeks []time.Time, from time.Time) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		for _, week := range weeks {
+			err := tx.query().CreateMealPlanTemplateWeek(ctx, database.CreateMealPlanTemplateWeekParams{
+				TemplateID: template.ID,
+				Week:       week.Format(time.DateOnly),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return tx.createMealPlanOccurrences(ctx, template.Occurrences(weeks, from))
+	})
+}
+
+func (s *Store) populateMealPlanTemplates(ctx context.Context, rows []database.MealPlanTemplate) ([]domain.MealPlanTemplate, error) {
+	templates := make([]domain.MealPlanTemplate, len(rows))
+	for i, row := range rows {
+		entries, err := s.query().GetMealPlanTemplateEntries(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		templates[i] = s.mapper.ToMealPlanTemplate(row, entries)
+	}
+	return templates, nil
+}
+
+// saveMealPlanTemplateEntries makes the entries of the template match the
+// given ones. Entries with an ID are updated, new ones created and all others
+// removed.
+func (s *Store) saveMealPlanTemplateEntries(ctx context.Context, template domain.MealPlanTemplate) error {
+	existing, err := s.query().GetMealPlanTemplateEntries(ctx, template.ID)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[int64]bool, len(template.Entries))
+	for i, entry := range template.Entries {
+		var row database.MealPlanTemplateEntry
+		if entry.ID == 0 {
+			row, err = s.query().CreateMealPlanTemplateEntry(ctx, s.mapper.FromMealPlanTemplateEntry(template.ID, i, entry))
+		} else {
+			row, err = s.query().UpdateMealPlanTemplateEntry(ctx, s.mapper.FromMealPlanTemplateEntryForUpdate(template.ID, i, entry))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			// The entry belongs to another template
+			return domain.ErrInvalidMealPlanTemplate
+		} else if err != nil {
+			return err
+		}
+		kept[row.ID] = true
+	}
+
+	for _, entry := range existing {
+		if kept[entry.ID] {
+			continue
+		}
+		if err = s.query().DeleteMealPlanTemplateEntry(ctx, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteMealPlanTemplateOccurrences removes the entries the template added
+// from the given day on, except for the ones somebody changed since.
+func (s *Store) deleteMealPlanTemplateOccurrences(ctx context.Context, templateID int64, from time.Time) error {
+	return s.query().DeleteMealPlanTemplateOccurrences(ctx, database.DeleteMealPlanTemplateOccurrencesParams{
+		TemplateID: templateID,
+		Date:       from.Format(time.DateOnly),
+	})
+}
+
+// createMealPlanOccurrences adds the entries to the bottom of their slots,
+// skipping the ones the template already added on their day and the ones
+// that were changed or removed there.
+func (s *Store) createMealPlanOccurrences(ctx context.Context, entries []domain.MealPlanEntry) error {
+	for _, entry := range entries {
+		if err := s.query().CreateMealPlanOccurrence(ctx, s.mapper.FromMealPlanOccurrence(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachMealPlanEntry marks an entry a template added as changed, so the
+// template leaves its day alone from now on.
+func (s *Store) detachMealPlanEntry(ctx context.Context, householdID int64, entryID int64) error {
+	err := s.query().CreateMealPlanTemplateException(ctx, database.CreateMealPlanTemplateExceptionParams{
+		ID:          entryID,
+		HouseholdID: &householdID,
+	})
+	if err != nil {
+		return err
+	}
+	return s.query().DetachMealPlanEntry(ctx, database.DetachMealPlanEntryParams{
+		ID:          entryID,
+		HouseholdID: &householdID,
+	})
+}
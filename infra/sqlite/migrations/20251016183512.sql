@@ -0,0 +1,121 @@
+-- Create "recipes_fts" full-text index covering recipe names, descriptions, steps, ingredients and tags
+CREATE VIRTUAL TABLE `recipes_fts` USING fts5(`name`, `description`, `instructions`, `ingredients`, `tags`, tokenize = 'unicode61 remove_diacritics 2');
+-- Create trigger "recipes_fts_recipes_insert" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipes_insert` AFTER INSERT ON `recipes` BEGIN
+    INSERT INTO recipes_fts (rowid, name, description, instructions, ingredients, tags)
+    VALUES (new.id, new.name, new.description, '', '', '');
+END;
+-- Create trigger "recipes_fts_recipes_update" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipes_update` AFTER UPDATE OF `name`, `description` ON `recipes` BEGIN
+    UPDATE recipes_fts SET name = new.name, description = new.description WHERE rowid = new.id;
+END;
+-- Create trigger "recipes_fts_recipes_delete" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipes_delete` AFTER DELETE ON `recipes` BEGIN
+    DELETE FROM recipes_fts WHERE rowid = old.id;
+END;
+-- Create trigger "recipes_fts_recipe_steps_insert" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_steps_insert` AFTER INSERT ON `recipe_steps` BEGIN
+    UPDATE recipes_fts
+    SET instructions = (SELECT coalesce(group_concat(instructions, ' '), '') FROM recipe_steps WHERE recipe_id = new.recipe_id)
+    WHERE rowid = new.recipe_id;
+END;
+-- Create trigger "recipes_fts_recipe_steps_update" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_steps_update` AFTER UPDATE OF `instructions` ON `recipe_steps` BEGIN
+    UPDATE recipes_fts
+    SET instructions = (SELECT coalesce(group_concat(instructions, ' '), '') FROM recipe_steps WHERE recipe_id = new.recipe_id)
+    WHERE rowid = new.recipe_id;
+END;
+-- Create trigger "recipes_fts_recipe_steps_delete" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_steps_delete` AFTER DELETE ON `recipe_steps` BEGIN
+    UPDATE recipes_fts
+    SET instructions = (SELECT coalesce(group_concat(instructions, ' '), '') FROM recipe_steps WHERE recipe_id = old.recipe_id)
+    WHERE rowid = old.recipe_id;
+END;
+-- Create trigger "recipes_fts_recipe_ingredients_insert" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_ingredients_insert` AFTER INSERT ON `recipe_ingredients` BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid = (SELECT recipe_id FROM recipe_steps WHERE id = new.step_id);
+END;
+-- Create trigger "recipes_fts_recipe_ingredients_update" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_ingredients_update` AFTER UPDATE OF `ingredient_id` ON `recipe_ingredients` BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid = (SELECT recipe_id FROM recipe_steps WHERE id = new.step_id);
+END;
+-- Create trigger "recipes_fts_recipe_ingredients_delete" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_ingredients_delete` AFTER DELETE ON `recipe_ingredients` BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid = (SELECT recipe_id FROM recipe_steps WHERE id = old.step_id);
+END;
+-- Create trigger "recipes_fts_ingredients_update" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_ingredients_update` AFTER UPDATE OF `name` ON `ingredients` BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid IN (SELECT recipe_steps.recipe_id
+                    FROM recipe_ingredients
+                             INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                    WHERE recipe_ingredients.ingredient_id = new.id);
+END;
+-- Create trigger "recipes_fts_recipe_tags_insert" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_tags_insert` AFTER INSERT ON `recipe_tags` BEGIN
+    UPDATE recipes_fts
+    SET tags = (SELECT coalesce(group_concat(tags.name, ' '), '')
+                FROM recipe_tags
+                         INNER JOIN tags ON recipe_tags.tag_id = tags.id
+                WHERE recipe_tags.recipe_id = new.recipe_id)
+    WHERE rowid = new.recipe_id;
+END;
+-- Create trigger "recipes_fts_recipe_tags_delete" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_recipe_tags_delete` AFTER DELETE ON `recipe_tags` BEGIN
+    UPDATE recipes_fts
+    SET tags = (SELECT coalesce(group_concat(tags.name, ' '), '')
+                FROM recipe_tags
+                         INNER JOIN tags ON recipe_tags.tag_id = tags.id
+                WHERE recipe_tags.recipe_id = old.recipe_id)
+    WHERE rowid = old.recipe_id;
+END;
+-- Create trigger "recipes_fts_tags_update" to keep "recipes_fts" up to date
+CREATE TRIGGER `recipes_fts_tags_update` AFTER UPDATE OF `name` ON `tags` BEGIN
+    UPDATE recipes_fts
+    SET tags = (SELECT coalesce(group_concat(tags.name, ' '), '')
+                FROM recipe_tags
+                         INNER JOIN tags ON recipe_tags.tag_id = tags.id
+                WHERE recipe_tags.recipe_id = recipes_fts.rowid)
+    WHERE rowid IN (SELECT recipe_id FROM recipe_tags WHERE tag_id = new.id);
+END;
+-- Populate "recipes_fts" from existing recipes
+INSERT INTO recipes_fts (rowid, name, description, instructions, ingredients, tags)
+SELECT recipes.id,
+       recipes.name,
+       recipes.description,
+       (SELECT coalesce(group_concat(recipe_steps.instructions, ' '), '')
+        FROM recipe_steps
+        WHERE recipe_steps.recipe_id = recipes.id),
+       (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+        FROM recipe_ingredients
+                 INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                 INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+        WHERE recipe_steps.recipe_id = recipes.id),
+       (SELECT coalesce(group_concat(tags.name, ' '), '')
+        FROM recipe_tags
+                 INNER JOIN tags ON recipe_tags.tag_id = tags.id
+        WHERE recipe_tags.recipe_id = recipes.id)
+FROM recipes;
@@ -0,0 +1,2 @@
+-- Add column "servings" to table: "meal_plan"
+ALTER TABLE `meal_plan` ADD COLUMN `servings` integer NULL;
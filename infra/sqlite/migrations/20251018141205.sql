@@ -0,0 +1,26 @@
+-- Add column "dimension" to table: "units"
+ALTER TABLE `units` ADD COLUMN `dimension` text NULL;
+-- Add column "system" to table: "units"
+ALTER TABLE `units` ADD COLUMN `system` text NULL;
+-- Add column "factor" to table: "units"
+ALTER TABLE `units` ADD COLUMN `factor` real NULL;
+-- Add column "density" to table: "ingredients"
+ALTER TABLE `ingredients` ADD COLUMN `density` real NULL;
+-- Backfill conversion factors of the seeded units, base units are g, ml, piece and cm
+UPDATE `units` SET `dimension` = 'mass', `system` = 'metric', `factor` = 1 WHERE `name` = 'Gram';
+UPDATE `units` SET `dimension` = 'mass', `system` = 'metric', `factor` = 1000 WHERE `name` = 'Kilogram';
+UPDATE `units` SET `dimension` = 'mass', `system` = 'imperial', `factor` = 28.349523125 WHERE `name` = 'Ounce';
+UPDATE `units` SET `dimension` = 'mass', `system` = 'imperial', `factor` = 453.59237 WHERE `name` = 'Pound';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'metric', `factor` = 1 WHERE `name` = 'Milliliter';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'metric', `factor` = 1000 WHERE `name` = 'Liter';
+UPDATE `units` SET `dimension` = 'volume', `factor` = 4.92892159375 WHERE `name` = 'Teaspoon';
+UPDATE `units` SET `dimension` = 'volume', `factor` = 14.78676478125 WHERE `name` = 'Tablespoon';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'imperial', `factor` = 29.5735295625 WHERE `name` = 'Fluid Ounce';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'imperial', `factor` = 236.5882365 WHERE `name` = 'Cup';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'imperial', `factor` = 473.176473 WHERE `name` = 'Pint';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'imperial', `factor` = 946.352946 WHERE `name` = 'Quart';
+UPDATE `units` SET `dimension` = 'volume', `system` = 'imperial', `factor` = 3785.411784 WHERE `name` = 'Gallon';
+UPDATE `units` SET `dimension` = 'count', `factor` = 1 WHERE `name` IN ('Each', 'Piece');
+UPDATE `units` SET `dimension` = 'length', `system` = 'metric', `factor` = 0.1 WHERE `name` = 'Millimeter';
+UPDATE `units` SET `dimension` = 'length', `system` = 'metric', `factor` = 1 WHERE `name` = 'Centimeter';
+UPDATE `units` SET `dimension` = 'length', `system` = 'imperial', `factor` = 2.54 WHERE `name` = 'Inch';
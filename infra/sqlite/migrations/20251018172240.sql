@@ -0,0 +1,4 @@
+-- Add column "reference_amount" to table: "ingredients"
+ALTER TABLE `ingredients` ADD COLUMN `reference_amount` real NOT NULL DEFAULT 100;
+-- Add column "reference_unit_id" to table: "ingredients"
+ALTER TABLE `ingredients` ADD COLUMN `reference_unit_id` integer NULL REFERENCES `units` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+-- Create "user_nutrient_targets" table
+CREATE TABLE `user_nutrient_targets` (`user_id` integer NOT NULL, `nutrient_id` integer NOT NULL, `amount` real NOT NULL, PRIMARY KEY (`user_id`, `nutrient_id`), CONSTRAINT `0` FOREIGN KEY (`nutrient_id`) REFERENCES `nutrients` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
@@ -0,0 +1,17 @@
+-- Add column "ingredient_id" to table: "shopping_list_items"
+ALTER TABLE `shopping_list_items` ADD COLUMN `ingredient_id` integer NULL REFERENCES `ingredients` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL;
+-- Add column "unit_id" to table: "shopping_list_items"
+ALTER TABLE `shopping_list_items` ADD COLUMN `unit_id` integer NULL REFERENCES `units` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL;
+-- Add column "amount" to table: "shopping_list_items"
+ALTER TABLE `shopping_list_items` ADD COLUMN `amount` real NULL;
+-- Create "shopping_list_item_recipes" table
+CREATE TABLE `shopping_list_item_recipes` (`shopping_list_item_id` integer NOT NULL, `recipe_id` integer NOT NULL, PRIMARY KEY (`shopping_list_item_id`, `recipe_id`), CONSTRAINT `0` FOREIGN KEY (`recipe_id`) REFERENCES `recipes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`shopping_list_item_id`) REFERENCES `shopping_list_items` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Link existing items to the ingredients and units they name
+UPDATE `shopping_list_items`
+SET `ingredient_id` = (SELECT `id` FROM `ingredients` WHERE lower(`ingredients`.`name`) = lower(trim(`shopping_list_items`.`ingredient`)) LIMIT 1);
+UPDATE `shopping_list_items`
+SET `unit_id` = (SELECT `id` FROM `units` WHERE lower(`units`.`name`) = lower(trim(`shopping_list_items`.`unit`)) OR `units`.`symbol` = trim(`shopping_list_items`.`unit`) LIMIT 1)
+WHERE `unit` IS NOT NULL;
+UPDATE `shopping_list_items`
+SET `amount` = CAST(replace(trim(`quantity`), ',', '.') AS REAL)
+WHERE trim(`quantity`) GLOB '[0-9]*' AND NOT replace(trim(`quantity`), ',', '.') GLOB '*[^0-9.]*';
@@ -0,0 +1,10 @@
+-- Create "stores" table
+CREATE TABLE `stores` (`id` integer NULL, `user_id` integer NOT NULL, `name` text NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_stores_user_id" to table: "stores"
+CREATE INDEX `idx_stores_user_id` ON `stores` (`user_id`);
+-- Create "store_sections" table
+CREATE TABLE `store_sections` (`id` integer NULL, `store_id` integer NOT NULL, `name` text NOT NULL, `sort_order` integer NOT NULL DEFAULT 0, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_store_sections_store_id" to table: "store_sections"
+CREATE INDEX `idx_store_sections_store_id` ON `store_sections` (`store_id`);
+-- Create "store_section_ingredients" table
+CREATE TABLE `store_section_ingredients` (`store_id` integer NOT NULL, `ingredient_id` integer NOT NULL, `section_id` integer NOT NULL, PRIMARY KEY (`store_id`, `ingredient_id`), CONSTRAINT `0` FOREIGN KEY (`section_id`) REFERENCES `store_sections` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredients` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `2` FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
@@ -0,0 +1,30 @@
+-- Create "households" table
+CREATE TABLE `households` (`id` integer NULL, `name` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`));
+-- Create "household_members" table
+CREATE TABLE `household_members` (`household_id` integer NOT NULL, `user_id` integer NOT NULL, `role` text NOT NULL, PRIMARY KEY (`household_id`, `user_id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`household_id`) REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CHECK (role IN ('owner', 'editor', 'viewer')));
+-- Create index "household_members_user_id" to table: "household_members"
+CREATE UNIQUE INDEX `household_members_user_id` ON `household_members` (`user_id`);
+-- Create "household_invitations" table
+CREATE TABLE `household_invitations` (`id` integer NULL, `household_id` integer NOT NULL, `email` text NOT NULL, `role` text NOT NULL, `token` text NOT NULL, `invited_by` integer NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`invited_by`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`household_id`) REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CHECK (role IN ('owner', 'editor', 'viewer')));
+-- Create index "household_invitations_token" to table: "household_invitations"
+CREATE UNIQUE INDEX `household_invitations_token` ON `household_invitations` (`token`);
+-- Add column "household_id" to table: "recipes"
+ALTER TABLE `recipes` ADD COLUMN `household_id` integer NULL REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Add column "household_id" to table: "meal_plan"
+ALTER TABLE `meal_plan` ADD COLUMN `household_id` integer NULL REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Add column "household_id" to table: "shopping_lists"
+ALTER TABLE `shopping_lists` ADD COLUMN `household_id` integer NULL REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Create index "idx_recipes_household_id" to table: "recipes"
+CREATE INDEX `idx_recipes_household_id` ON `recipes` (`household_id`);
+-- Create index "idx_meal_plan_household_id" to table: "meal_plan"
+CREATE INDEX `idx_meal_plan_household_id` ON `meal_plan` (`household_id`);
+-- Create index "idx_shopping_lists_household_id" to table: "shopping_lists"
+CREATE INDEX `idx_shopping_lists_household_id` ON `shopping_lists` (`household_id`);
+-- Give every existing user a household of their own, sharing the user's id
+INSERT INTO `households` (`id`, `name`)
+SELECT `id`, 'Household' FROM `users`;
+INSERT INTO `household_members` (`household_id`, `user_id`, `role`)
+SELECT `id`, `id`, 'owner' FROM `users`;
+UPDATE `recipes` SET `household_id` = `created_by`;
+UPDATE `meal_plan` SET `household_id` = `user_id`;
+UPDATE `shopping_lists` SET `household_id` = `user_id`;
@@ -0,0 +1,28 @@
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Add column "revision" to table: "shopping_lists"
+ALTER TABLE `shopping_lists` ADD COLUMN `revision` integer NOT NULL DEFAULT 0;
+-- Create "new_shopping_list_items" table
+CREATE TABLE `new_shopping_list_items` (`id` integer NULL, `shopping_list_id` integer NOT NULL, `ingredient` text NOT NULL, `quantity` text NULL, `unit` text NULL, `done` boolean NOT NULL DEFAULT 0, `sort_order` integer NOT NULL DEFAULT 0, `ingredient_id` integer NULL, `unit_id` integer NULL, `amount` real NULL, `version` integer NOT NULL DEFAULT 1, `revision` integer NOT NULL DEFAULT 0, `updated_at` timestamp NULL, `client_id` text NULL, PRIMARY KEY (`id` AUTOINCREMENT), CONSTRAINT `0` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT `1` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredients` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT `2` FOREIGN KEY (`shopping_list_id`) REFERENCES `shopping_lists` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Copy rows from old table "shopping_list_items" to new temporary table "new_shopping_list_items"
+INSERT INTO `new_shopping_list_items` (`id`, `shopping_list_id`, `ingredient`, `quantity`, `unit`, `done`, `sort_order`, `ingredient_id`, `unit_id`, `amount`) SELECT `id`, `shopping_list_id`, `ingredient`, `quantity`, `unit`, `done`, `sort_order`, `ingredient_id`, `unit_id`, `amount` FROM `shopping_list_items`;
+-- Drop "shopping_list_items" table after copying rows
+DROP TABLE `shopping_list_items`;
+-- Rename temporary table "new_shopping_list_items" to "shopping_list_items"
+ALTER TABLE `new_shopping_list_items` RENAME TO `shopping_list_items`;
+-- Create index "shopping_list_items_shopping_list_id_sort_order" to table: "shopping_list_items"
+CREATE UNIQUE INDEX `shopping_list_items_shopping_list_id_sort_order` ON `shopping_list_items` (`shopping_list_id`, `sort_order`);
+-- Create index "shopping_list_items_shopping_list_id_client_id" to table: "shopping_list_items"
+CREATE UNIQUE INDEX `shopping_list_items_shopping_list_id_client_id` ON `shopping_list_items` (`shopping_list_id`, `client_id`);
+-- Create index "idx_shopping_list_items_shopping_list_id" to table: "shopping_list_items"
+CREATE INDEX `idx_shopping_list_items_shopping_list_id` ON `shopping_list_items` (`shopping_list_id`);
+-- Create index "idx_shopping_list_items_sort_order" to table: "shopping_list_items"
+CREATE INDEX `idx_shopping_list_items_sort_order` ON `shopping_list_items` (`sort_order`);
+-- Create index "idx_shopping_list_items_revision" to table: "shopping_list_items"
+CREATE INDEX `idx_shopping_list_items_revision` ON `shopping_list_items` (`shopping_list_id`, `revision`);
+-- Create "shopping_list_item_deletions" table
+CREATE TABLE `shopping_list_item_deletions` (`shopping_list_id` integer NOT NULL, `item_id` integer NOT NULL, `client_id` text NULL, `revision` integer NOT NULL, PRIMARY KEY (`shopping_list_id`, `item_id`), CONSTRAINT `0` FOREIGN KEY (`shopping_list_id`) REFERENCES `shopping_lists` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_shopping_list_item_deletions_revision" to table: "shopping_list_item_deletions"
+CREATE INDEX `idx_shopping_list_item_deletions_revision` ON `shopping_list_item_deletions` (`shopping_list_id`, `revision`);
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
@@ -0,0 +1,31 @@
+-- Create "meal_slots" table
+CREATE TABLE `meal_slots` (`id` integer NULL, `household_id` integer NOT NULL, `name` text NOT NULL, `sort_order` integer NOT NULL DEFAULT 0, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`household_id`) REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_meal_slots_household_id" to table: "meal_slots"
+CREATE INDEX `idx_meal_slots_household_id` ON `meal_slots` (`household_id`);
+-- Add the default slots to every household
+INSERT INTO `meal_slots` (`household_id`, `name`, `sort_order`)
+SELECT `id`, 'Breakfast', 0 FROM `households`
+UNION ALL
+SELECT `id`, 'Lunch', 1 FROM `households`
+UNION ALL
+SELECT `id`, 'Dinner', 2 FROM `households`
+UNION ALL
+SELECT `id`, 'Snack', 3 FROM `households`;
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Create "new_meal_plan" table
+CREATE TABLE `new_meal_plan` (`id` integer NULL, `date` text NOT NULL DEFAULT (CURRENT_DATE), `user_id` integer NOT NULL, `recipe_id` integer NULL, `sort_order` integer NOT NULL DEFAULT 0, `servings` integer NULL, `household_id` integer NULL, `slot_id` integer NULL, `note` text NULL, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`slot_id`) REFERENCES `meal_slots` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT `1` FOREIGN KEY (`household_id`) REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `2` FOREIGN KEY (`recipe_id`) REFERENCES `recipes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `3` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CHECK (`recipe_id` IS NOT NULL OR `note` IS NOT NULL));
+-- Copy rows from old table "meal_plan" to new temporary table "new_meal_plan"
+INSERT INTO `new_meal_plan` (`id`, `date`, `user_id`, `recipe_id`, `sort_order`, `servings`, `household_id`) SELECT `id`, `date`, `user_id`, `recipe_id`, `sort_order`, `servings`, `household_id` FROM `meal_plan`;
+-- Drop "meal_plan" table after copying rows
+DROP TABLE `meal_plan`;
+-- Rename temporary table "new_meal_plan" to "meal_plan"
+ALTER TABLE `new_meal_plan` RENAME TO `meal_plan`;
+-- Create index "meal_plan_date_sort_order" to table: "meal_plan"
+CREATE UNIQUE INDEX `meal_plan_date_sort_order` ON `meal_plan` (`date`, `sort_order`);
+-- Create index "idx_meal_plan_sort_order" to table: "meal_plan"
+CREATE INDEX `idx_meal_plan_sort_order` ON `meal_plan` (`sort_order`);
+-- Create index "idx_meal_plan_household_id" to table: "meal_plan"
+CREATE INDEX `idx_meal_plan_household_id` ON `meal_plan` (`household_id`);
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
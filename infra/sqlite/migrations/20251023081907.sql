@@ -0,0 +1,9 @@
+-- Drop index "meal_plan_date_sort_order" from table: "meal_plan"
+DROP INDEX `meal_plan_date_sort_order`;
+-- Number the entries of every household, day and slot from 0
+UPDATE `meal_plan`
+SET `sort_order` = `ranked`.`position`
+FROM (SELECT `id`, ROW_NUMBER() OVER (PARTITION BY `household_id`, `date`, `slot_id` ORDER BY `sort_order`, `id`) - 1 AS `position` FROM `meal_plan`) AS `ranked`
+WHERE `meal_plan`.`id` = `ranked`.`id`;
+-- Create index "meal_plan_household_date_slot_sort_order" to table: "meal_plan"
+CREATE UNIQUE INDEX `meal_plan_household_date_slot_sort_order` ON `meal_plan` (`household_id`, `date`, (IFNULL(`slot_id`, 0)), `sort_order`);
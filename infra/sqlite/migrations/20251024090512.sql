@@ -0,0 +1,32 @@
+-- Create "meal_plan_templates" table
+CREATE TABLE `meal_plan_templates` (`id` integer NULL, `household_id` integer NOT NULL, `user_id` integer NOT NULL, `name` text NOT NULL, `recurring` boolean NOT NULL DEFAULT 0, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`household_id`) REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_meal_plan_templates_household_id" to table: "meal_plan_templates"
+CREATE INDEX `idx_meal_plan_templates_household_id` ON `meal_plan_templates` (`household_id`);
+-- Create "meal_plan_template_entries" table
+CREATE TABLE `meal_plan_template_entries` (`id` integer NULL, `template_id` integer NOT NULL, `weekday` integer NOT NULL, `slot_id` integer NULL, `recipe_id` integer NULL, `note` text NULL, `servings` integer NULL, `sort_order` integer NOT NULL DEFAULT 0, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`recipe_id`) REFERENCES `recipes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`slot_id`) REFERENCES `meal_slots` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT `2` FOREIGN KEY (`template_id`) REFERENCES `meal_plan_templates` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CHECK (`recipe_id` IS NOT NULL OR `note` IS NOT NULL));
+-- Create index "idx_meal_plan_template_entries_template_id" to table: "meal_plan_template_entries"
+CREATE INDEX `idx_meal_plan_template_entries_template_id` ON `meal_plan_template_entries` (`template_id`);
+-- Create "meal_plan_template_weeks" table
+CREATE TABLE `meal_plan_template_weeks` (`template_id` integer NOT NULL, `week` text NOT NULL, PRIMARY KEY (`template_id`, `week`), CONSTRAINT `0` FOREIGN KEY (`template_id`) REFERENCES `meal_plan_templates` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "meal_plan_template_exceptions" table
+CREATE TABLE `meal_plan_template_exceptions` (`template_entry_id` integer NOT NULL, `date` text NOT NULL, PRIMARY KEY (`template_entry_id`, `date`), CONSTRAINT `0` FOREIGN KEY (`template_entry_id`) REFERENCES `meal_plan_template_entries` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Create "new_meal_plan" table
+CREATE TABLE `new_meal_plan` (`id` integer NULL, `date` text NOT NULL DEFAULT (CURRENT_DATE), `user_id` integer NOT NULL, `recipe_id` integer NULL, `sort_order` integer NOT NULL DEFAULT 0, `servings` integer NULL, `household_id` integer NULL, `slot_id` integer NULL, `note` text NULL, `template_entry_id` integer NULL, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`template_entry_id`) REFERENCES `meal_plan_template_entries` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT `1` FOREIGN KEY (`slot_id`) REFERENCES `meal_slots` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT `2` FOREIGN KEY (`household_id`) REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `3` FOREIGN KEY (`recipe_id`) REFERENCES `recipes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `4` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CHECK (`recipe_id` IS NOT NULL OR `note` IS NOT NULL));
+-- Copy rows from old table "meal_plan" to new temporary table "new_meal_plan"
+INSERT INTO `new_meal_plan` (`id`, `date`, `user_id`, `recipe_id`, `sort_order`, `servings`, `household_id`, `slot_id`, `note`) SELECT `id`, `date`, `user_id`, `recipe_id`, `sort_order`, `servings`, `household_id`, `slot_id`, `note` FROM `meal_plan`;
+-- Drop "meal_plan" table after copying rows
+DROP TABLE `meal_plan`;
+-- Rename temporary table "new_meal_plan" to "meal_plan"
+ALTER TABLE `new_meal_plan` RENAME TO `meal_plan`;
+-- Create index "idx_meal_plan_sort_order" to table: "meal_plan"
+CREATE INDEX `idx_meal_plan_sort_order` ON `meal_plan` (`sort_order`);
+-- Create index "meal_plan_household_date_slot_sort_order" to table: "meal_plan"
+CREATE UNIQUE INDEX `meal_plan_household_date_slot_sort_order` ON `meal_plan` (`household_id`, `date`, (IFNULL(`slot_id`, 0)), `sort_order`);
+-- Create index "idx_meal_plan_household_id" to table: "meal_plan"
+CREATE INDEX `idx_meal_plan_household_id` ON `meal_plan` (`household_id`);
+-- Create index "idx_meal_plan_template_entry_id" to table: "meal_plan"
+CREATE INDEX `idx_meal_plan_template_entry_id` ON `meal_plan` (`template_entry_id`);
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
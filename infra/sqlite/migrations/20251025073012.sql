@@ -0,0 +1,8 @@
+-- Add column "time" to table: "meal_slots"
+ALTER TABLE `meal_slots` ADD COLUMN `time` text NULL;
+-- Give the default slots their usual time of day
+UPDATE `meal_slots` SET `time` = CASE `name` WHEN 'Breakfast' THEN '08:00' WHEN 'Lunch' THEN '12:30' WHEN 'Dinner' THEN '18:30' END WHERE `name` IN ('Breakfast', 'Lunch', 'Dinner');
+-- Create "calendar_tokens" table
+CREATE TABLE `calendar_tokens` (`user_id` integer NULL, `token` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`user_id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "calendar_tokens_token" to table: "calendar_tokens"
+CREATE UNIQUE INDEX `calendar_tokens_token` ON `calendar_tokens` (`token`);
@@ -0,0 +1,10 @@
+-- Create "user_totp" table
+CREATE TABLE `user_totp` (`user_id` integer NULL, `secret` text NOT NULL, `confirmed` boolean NOT NULL DEFAULT 0, `last_used_step` integer NOT NULL DEFAULT 0, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`user_id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "user_recovery_codes" table
+CREATE TABLE `user_recovery_codes` (`id` integer NULL, `user_id` integer NOT NULL, `code_hash` text NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_user_recovery_codes_user_id" to table: "user_recovery_codes"
+CREATE INDEX `idx_user_recovery_codes_user_id` ON `user_recovery_codes` (`user_id`);
+-- Create "login_challenges" table
+CREATE TABLE `login_challenges` (`id` integer NULL, `user_id` integer NOT NULL, `token` text NOT NULL, `attempts` integer NOT NULL DEFAULT 0, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "login_challenges_token" to table: "login_challenges"
+CREATE UNIQUE INDEX `login_challenges_token` ON `login_challenges` (`token`);
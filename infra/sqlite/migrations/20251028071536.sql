@@ -0,0 +1,10 @@
+-- Create "passkeys" table
+CREATE TABLE `passkeys` (`id` integer NULL, `user_id` integer NOT NULL, `credential_id` blob NOT NULL, `public_key` blob NOT NULL, `sign_count` integer NOT NULL DEFAULT 0, `name` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), `last_used_at` timestamp NULL, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "passkeys_credential_id" to table: "passkeys"
+CREATE UNIQUE INDEX `passkeys_credential_id` ON `passkeys` (`credential_id`);
+-- Create index "idx_passkeys_user_id" to table: "passkeys"
+CREATE INDEX `idx_passkeys_user_id` ON `passkeys` (`user_id`);
+-- Create "passkey_challenges" table
+CREATE TABLE `passkey_challenges` (`id` integer NULL, `token` text NOT NULL, `ceremony` text NOT NULL, `user_id` integer NULL, `challenge` blob NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "passkey_challenges_token" to table: "passkey_challenges"
+CREATE UNIQUE INDEX `passkey_challenges_token` ON `passkey_challenges` (`token`);
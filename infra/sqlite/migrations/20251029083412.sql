@@ -0,0 +1,10 @@
+-- Create "user_identities" table
+CREATE TABLE `user_identities` (`id` integer NULL, `user_id` integer NOT NULL, `issuer` text NOT NULL, `subject` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "user_identities_issuer_subject" to table: "user_identities"
+CREATE UNIQUE INDEX `user_identities_issuer_subject` ON `user_identities` (`issuer`, `subject`);
+-- Create index "idx_user_identities_user_id" to table: "user_identities"
+CREATE INDEX `idx_user_identities_user_id` ON `user_identities` (`user_id`);
+-- Create "sso_logins" table
+CREATE TABLE `sso_logins` (`id` integer NULL, `state` text NOT NULL, `nonce` text NOT NULL, `code_verifier` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`));
+-- Create index "sso_logins_state" to table: "sso_logins"
+CREATE UNIQUE INDEX `sso_logins_state` ON `sso_logins` (`state`);
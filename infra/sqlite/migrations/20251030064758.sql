@@ -0,0 +1,8 @@
+-- Create "api_tokens" table
+CREATE TABLE `api_tokens` (`id` integer NULL, `user_id` integer NOT NULL, `name` text NOT NULL, `token_hash` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), `expires_at` timestamp NULL, `last_used_at` timestamp NULL, PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "api_tokens_token_hash" to table: "api_tokens"
+CREATE UNIQUE INDEX `api_tokens_token_hash` ON `api_tokens` (`token_hash`);
+-- Create index "idx_api_tokens_user_id" to table: "api_tokens"
+CREATE INDEX `idx_api_tokens_user_id` ON `api_tokens` (`user_id`);
+-- Create "api_token_permissions" table
+CREATE TABLE `api_token_permissions` (`api_token_id` integer NOT NULL, `permission_id` integer NOT NULL, PRIMARY KEY (`api_token_id`, `permission_id`), CONSTRAINT `0` FOREIGN KEY (`permission_id`) REFERENCES `permissions` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT `1` FOREIGN KEY (`api_token_id`) REFERENCES `api_tokens` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
@@ -0,0 +1,6 @@
+-- Drop "calendar_tokens" table, the tokens it holds can't be hashed in place
+DROP TABLE `calendar_tokens`;
+-- Create "calendar_tokens" table
+CREATE TABLE `calendar_tokens` (`user_id` integer NULL, `token_hash` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`user_id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "calendar_tokens_token_hash" to table: "calendar_tokens"
+CREATE UNIQUE INDEX `calendar_tokens_token_hash` ON `calendar_tokens` (`token_hash`);
@@ -0,0 +1,8 @@
+-- Add column "flags" to table: "passkeys"
+ALTER TABLE `passkeys` ADD COLUMN `flags` integer NULL;
+-- Drop "passkey_challenges" table, pending ceremonies have to be started again
+DROP TABLE `passkey_challenges`;
+-- Create "passkey_challenges" table
+CREATE TABLE `passkey_challenges` (`id` integer NULL, `token` text NOT NULL, `ceremony` text NOT NULL, `user_id` integer NULL, `session` text NOT NULL, `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "passkey_challenges_token" to table: "passkey_challenges"
+CREATE UNIQUE INDEX `passkey_challenges_token` ON `passkey_challenges` (`token`);
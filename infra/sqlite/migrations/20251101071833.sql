@@ -0,0 +1,8 @@
+-- Drop "sessions" table, the tokens it holds can't be hashed in place and everyone has to log in again
+DROP TABLE `sessions`;
+-- Create "sessions" table
+CREATE TABLE `sessions` (`id` integer NULL, `user_id` integer NOT NULL, `token_hash` text NOT NULL, `user_agent` text NOT NULL DEFAULT '', `ip_address` text NOT NULL DEFAULT '', `created_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), `last_seen_at` timestamp NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`id`), CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "sessions_token_hash" to table: "sessions"
+CREATE UNIQUE INDEX `sessions_token_hash` ON `sessions` (`token_hash`);
+-- Create index "idx_sessions_user_id" to table: "sessions"
+CREATE INDEX `idx_sessions_user_id` ON `sessions` (`user_id`);
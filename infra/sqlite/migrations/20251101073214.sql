@@ -0,0 +1,19 @@
+INSERT INTO permissions (id, slug, name)
+VALUES
+    -- Stores
+    (29, 'can_create_store', 'Create Store'),
+    (30, 'can_delete_store', 'Delete Store'),
+    (31, 'can_list_stores', 'List Stores'),
+    (32, 'can_update_store', 'Update Store'),
+    (33, 'can_view_store', 'View Store'),
+
+    -- Households
+    (34, 'can_update_household', 'Update Household'),
+    (35, 'can_view_household', 'View Household');
+
+-- Give the new permissions to all roles
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE p.id BETWEEN 29 AND 35;
@@ -0,0 +1,6 @@
+-- Add column "household_id" to table: "stores"
+ALTER TABLE `stores` ADD COLUMN `household_id` integer NULL REFERENCES `households` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Create index "idx_stores_household_id" to table: "stores"
+CREATE INDEX `idx_stores_household_id` ON `stores` (`household_id`);
+-- Share every store with the household of the user who created it
+UPDATE `stores` SET `household_id` = (SELECT `household_id` FROM `household_members` WHERE `household_members`.`user_id` = `stores`.`user_id`);
@@ -0,0 +1,216 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+)
+
+func TestListRecipesPagination(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	recipes := domain.NewRecipeService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+	other := registerTestUser(t, store, "other@example.com")
+
+	// Two recipes share a name and minutes, so pages have to break ties by id
+	var ids []int64
+	for _, details := range []domain.RecipeDetails{
+		{Name: "Curry", Minutes: 40},
+		{Name: "Apple Pie", Minutes: 90},
+		{Name: "Bread", Minutes: 40},
+		{Name: "Bread", Minutes: 40},
+		{Name: "Dumplings", Minutes: 20},
+	} {
+		details.CreatedBy, details.Servings = user, 2
+		recipe, err := store.CreateRecipe(ctx, domain.Recipe{HouseholdID: user.Membership.HouseholdID, RecipeDetails: details})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, recipe.ID)
+	}
+	_, err := store.CreateRecipe(ctx, domain.Recipe{
+		HouseholdID:   other.Membership.HouseholdID,
+		RecipeDetails: domain.RecipeDetails{Name: "Another household's", CreatedBy: other, Servings: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listAll := func(query domain.RecipeListQuery) (result []int64) {
+		t.Helper()
+		for page := 0; ; page++ {
+			if page > len(ids) {
+				t.Fatalf("GetByUser(%+v) returned more pages than recipes", query)
+			}
+			got, err := recipes.GetByUser(ctx, user, query)
+			if err != nil {
+				t.Fatalf("GetByUser(%+v) error = %v", query, err)
+			}
+			if int64(len(got.Items)) > query.Limit || (got.NextCursor != "" && int64(len(got.Items)) != query.Limit) {
+				t.Fatalf("GetByUser(%+v) returned %d recipes with cursor %q", query, len(got.Items), got.NextCursor)
+			}
+			for _, recipe := range got.Items {
+				result = append(result, recipe.ID)
+			}
+			if got.NextCursor == "" {
+				return result
+			}
+			query.Cursor = got.NextCursor
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query domain.RecipeListQuery
+		want  []int64
+	}{
+		{"By name", domain.RecipeListQuery{Limit: 2}, []int64{ids[1], ids[2], ids[3], ids[0], ids[4]}},
+		{"By name descending", domain.RecipeListQuery{Order: domain.SortDescending, Limit: 2}, []int64{ids[4], ids[0], ids[3], ids[2], ids[1]}},
+		{"By minutes", domain.RecipeListQuery{SortBy: domain.RecipeSortByMinutes, Limit: 2}, []int64{ids[4], ids[0], ids[2], ids[3], ids[1]}},
+		{"Page size of all recipes", domain.RecipeListQuery{Limit: 5}, []int64{ids[1], ids[2], ids[3], ids[0], ids[4]}},
+		{"Created at descending", domain.RecipeListQuery{SortBy: domain.RecipeSortByCreatedAt, Order: domain.SortDescending, Limit: 3}, []int64{ids[4], ids[3], ids[2], ids[1], ids[0]}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := listAll(tc.query); !slices.Equal(got, tc.want) {
+				t.Errorf("GetByUser() listed %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	byName, err := recipes.GetByUser(ctx, user, domain.RecipeListQuery{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, query := range map[string]domain.RecipeListQuery{
+		"Malformed cursor":           {Cursor: "not a cursor!"},
+		"Cursor of another sort key": {SortBy: domain.RecipeSortByMinutes, Cursor: byName.NextCursor},
+		"Cursor with invalid value":  {SortBy: domain.RecipeSortByMinutes, Cursor: domain.Cursor{Key: "minutes", Value: "soon", ID: ids[0]}.Encode()},
+		"Cursor with invalid date":   {SortBy: domain.RecipeSortByCreatedAt, Cursor: domain.Cursor{Key: "createdAt", Value: "yesterday", ID: ids[0]}.Encode()},
+	} {
+		if _, err = recipes.GetByUser(ctx, user, query); err != domain.ErrInvalidCursor {
+			t.Errorf("GetByUser() with %s error = %v, want %v", name, err, domain.ErrInvalidCursor)
+		}
+	}
+}
+
+func TestListIngredientsPagination(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	recipes := domain.NewRecipeService(nil, store)
+	for range 2 {
+		if _, err := store.CreateIngredient(ctx, domain.Ingredient{Name: "Salt", ReferenceAmount: 100}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := store.GetIngredients(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listed []domain.Ingredient
+	query := domain.IngredientListQuery{Limit: 3}
+	for {
+		page, err := recipes.GetIngredients(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed = append(listed, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if len(listed) != len(all) {
+		t.Fatalf("GetIngredients() listed %d ingredients over all pages, want %d", len(listed), len(all))
+	}
+	for i := 1; i < len(listed); i++ {
+		previous, current := listed[i-1], listed[i]
+		if previous.Name > current.Name || (previous.Name == current.Name && previous.ID >= current.ID) {
+			t.Errorf("GetIngredients() listed %q (%d) after %q (%d)", current.Name, current.ID, previous.Name, previous.ID)
+		}
+	}
+
+	recipeCursor := domain.Cursor{Key: string(domain.RecipeSortByMinutes), Value: "10", ID: 1}.Encode()
+	if _, err = recipes.GetIngredients(ctx, domain.IngredientListQuery{Cursor: recipeCursor}); err != domain.ErrInvalidCursor {
+		t.Errorf("GetIngredients() with a recipe cursor error = %v, want %v", err, domain.ErrInvalidCursor)
+	}
+}
+
+func TestListRecipesFilters(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	user := registerTestUser(t, store, "user@example.com")
+	units, err := store.GetUnits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := store.GetTags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quick, vegan := tags[0], tags[1]
+	var ingredients []domain.Ingredient
+	for _, name := range []string{"Rice", "Tofu"} {
+		ingredient, err := store.CreateIngredient(ctx, domain.Ingredient{Name: name, ReferenceAmount: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	rice, tofu := ingredients[0], ingredients[1]
+
+	var ids []int64
+	for _, recipe := range []domain.Recipe{
+		{RecipeDetails: domain.RecipeDetails{Name: "Fried Rice", Minutes: 15, Servings: 2}, Tags: []domain.Tag{quick, vegan}, Steps: withIngredients(units[0], rice)},
+		{RecipeDetails: domain.RecipeDetails{Name: "Mapo Tofu", Minutes: 30, Servings: 4}, Tags: []domain.Tag{quick}, Steps: withIngredients(units[0], rice, tofu)},
+		{RecipeDetails: domain.RecipeDetails{Name: "Stew", Minutes: 120, Servings: 6}},
+	} {
+		recipe.HouseholdID, recipe.CreatedBy = user.Membership.HouseholdID, user
+		created, err := store.CreateRecipe(ctx, recipe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.RecipeFilter
+		want   []int64
+	}{
+		{"No filter", domain.RecipeFilter{}, ids},
+		{"All tags", domain.RecipeFilter{TagIDs: []int64{quick.ID, vegan.ID}}, ids[:1]},
+		{"Max minutes", domain.RecipeFilter{MaxMinutes: ptr(int64(30))}, ids[:2]},
+		{"Servings", domain.RecipeFilter{MinServings: ptr(int64(3)), MaxServings: ptr(int64(4))}, ids[1:2]},
+		{"Included ingredients", domain.RecipeFilter{IncludeIngredientIDs: []int64{rice.ID, tofu.ID}}, ids[1:2]},
+		{"Excluded ingredients", domain.RecipeFilter{ExcludeIngredientIDs: []int64{tofu.ID}}, []int64{ids[0], ids[2]}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.filter.HouseholdID = &user.Membership.HouseholdID
+			recipes, err := store.ListRecipes(ctx, domain.RecipeListQuery{Filter: tc.filter, Limit: 10}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, recipe := range recipes {
+				got = append(got, recipe.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("ListRecipes() listed %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func withIngredients(unit domain.Unit, ingredients ...domain.Ingredient) []domain.RecipeStep {
+	step := domain.RecipeStep{Instructions: "Cook"}
+	for _, ingredient := range ingredients {
+		step.Ingredients = append(step.Ingredients, domain.StepIngredient{Unit: unit, Amount: 100, Ingredient: ingredient})
+	}
+	return []domain.RecipeStep{step}
+}
@@ -0,0 +1,92 @@
+-- name: CountHouseholdMembers :one
+SELECT COUNT(*)
+FROM household_members
+WHERE household_id = ?;
+
+-- name: CreateHousehold :one
+INSERT INTO households (name)
+VALUES (?)
+RETURNING *;
+
+-- name: CreateHouseholdInvitation :one
+INSERT INTO household_invitations (household_id, email, role, token, invited_by)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: CreateHouseholdMember :exec
+INSERT INTO household_members (household_id, user_id, role)
+VALUES (?, ?, ?);
+
+-- name: DeleteHousehold :exec
+DELETE
+FROM households
+WHERE id = ?;
+
+-- name: DeleteHouseholdInvitation :exec
+DELETE
+FROM household_invitations
+WHERE id = ?;
+
+-- name: DeleteHouseholdInvitationsBefore :exec
+DELETE
+FROM household_invitations
+WHERE created_at < ?;
+
+-- name: DeleteHouseholdMember :exec
+DELETE
+FROM household_members
+WHERE household_id = ?
+  AND user_id = ?;
+
+-- name: GetHousehold :one
+SELECT *
+FROM households
+WHERE id = ?
+LIMIT 1;
+
+-- name: GetHouseholdInvitationByToken :one
+SELECT sqlc.embed(household_invitations), sqlc.embed(households)
+FROM household_invitations
+         INNER JOIN households ON households.id = household_invitations.household_id
+WHERE token = ?
+LIMIT 1;
+
+-- name: GetHouseholdMembers :many
+SELECT household_members.user_id, users.email, household_members.role
+FROM household_members
+         INNER JOIN users ON users.id = household_members.user_id
+WHERE household_members.household_id = ?
+ORDER BY users.email;
+
+-- name: GetHouseholdMembership :one
+SELECT household_id, role
+FROM household_members
+WHERE user_id = ?
+LIMIT 1;
+
+-- name: MoveRecipesToHousehold :exec
+UPDATE recipes
+SET household_id = sqlc.arg(new_household_id)
+WHERE household_id = sqlc.arg(old_household_id);
+
+-- name: MoveShoppingListsToHousehold :exec
+UPDATE shopping_lists
+SET household_id = sqlc.arg(new_household_id)
+WHERE household_id = sqlc.arg(old_household_id);
+
+-- name: MoveStoresToHousehold :exec
+UPDATE stores
+SET household_id = sqlc.arg(new_household_id)
+WHERE household_id = sqlc.arg(old_household_id);
+
+-- name: UpdateHousehold :one
+UPDATE households
+SET name = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: UpdateHouseholdMember :exec
+UPDATE household_members
+SET role = ?
+WHERE household_id = ?
+  AND user_id = ?;
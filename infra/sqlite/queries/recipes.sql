@@ -1,10 +1,6 @@
--- name: BrowseRecipes :many
-SELECT *
-FROM recipes;
-
 -- name: CreateRecipe :one
-INSERT INTO recipes (name, servings, minutes, description, created_by)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO recipes (name, servings, minutes, description, created_by, household_id)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING id;
 
 -- name: CreateRecipeImages :one
@@ -37,15 +33,27 @@ FROM recipes
 WHERE id = ?
 LIMIT 1;
 
+-- name: GetRecipesByIDs :many
+SELECT *
+FROM recipes
+WHERE id IN (
+    sqlc.slice(ids)
+    );
+
+-- name: GetRecipesByHousehold :many
+SELECT *
+FROM recipes
+WHERE household_id = ?
+ORDER BY id;
+
 -- name: GetMealPlan :many
-SELECT sqlc.embed(meal_plan),
-       sqlc.embed(recipes)
+SELECT meal_plan.*
 FROM meal_plan
-         INNER JOIN recipes ON meal_plan.recipe_id = recipes.id
-WHERE user_id = ?
+         LEFT JOIN meal_slots ON meal_slots.id = meal_plan.slot_id
+WHERE meal_plan.household_id = ?
   AND meal_plan.date >= sqlc.arg(from_date)
   AND meal_plan.date <= sqlc.arg(until_date)
-ORDER BY meal_plan.date, meal_plan.sort_order;
+ORDER BY meal_plan.date, meal_slots.sort_order IS NULL, meal_slots.sort_order, meal_plan.sort_order;
 
 -- name: GetImagesForRecipes :many
 SELECT id, path, sort_order, recipe_id
@@ -59,16 +67,28 @@ ORDER BY sort_order;
 SELECT recipe_ingredients.id as recipe_ingredient_id, 
        ingredients.id as ingredient_id, 
        ingredients.name as ingredient_name, 
-       units.id as unit_id, 
-       units.name as unit_name, 
-       units.symbol as unit_symbol, 
+       recipe_units.id as unit_id, 
+       recipe_units.name as unit_name, 
+       recipe_units.symbol as unit_symbol, 
+       recipe_units.dimension as unit_dimension, 
+       recipe_units.system as unit_system, 
+       recipe_units.factor as unit_factor, 
+       ingredients.density as ingredient_density, 
+       ingredients.reference_amount as ingredient_reference_amount, 
+       reference_units.id as reference_unit_id, 
+       reference_units.name as reference_unit_name, 
+       reference_units.symbol as reference_unit_symbol, 
+       reference_units.dimension as reference_unit_dimension, 
+       reference_units.system as reference_unit_system, 
+       reference_units.factor as reference_unit_factor, 
        recipe_steps.id as step_id, 
        recipe_ingredients.amount, 
        recipe_ingredients.sort_order
 FROM recipe_ingredients
          INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
          INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
-         INNER JOIN units ON recipe_ingredients.unit_id = units.id
+         INNER JOIN units AS recipe_units ON recipe_ingredients.unit_id = recipe_units.id
+         LEFT JOIN units AS reference_units ON ingredients.reference_unit_id = reference_units.id
 WHERE recipe_steps.recipe_id IN (
     sqlc.slice(recipe_ids)
     )
@@ -79,6 +99,15 @@ SELECT *
 FROM ingredients
 ORDER BY name;
 
+-- name: ListIngredients :many
+SELECT *
+FROM ingredients
+WHERE CAST(sqlc.narg(cursor_name) AS TEXT) IS NULL
+   OR name > sqlc.narg(cursor_name)
+   OR (name = sqlc.narg(cursor_name) AND id > sqlc.narg(cursor_id))
+ORDER BY name, id
+LIMIT sqlc.arg(page_size);
+
 -- name: GetUnits :many
 SELECT *
 FROM units
@@ -107,10 +136,44 @@ WHERE recipe_tags.recipe_id IN (
 ORDER BY tags.name;
 
 -- name: ListRecipes :many
-SELECT *
+SELECT sqlc.embed(recipes),
+       CASE WHEN CAST(sqlc.arg(descending) AS BOOLEAN) THEN NULL ELSE CASE CAST(sqlc.arg(sort_key) AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END END AS ascending_value,
+       CASE WHEN CAST(sqlc.arg(descending) AS BOOLEAN) THEN CASE CAST(sqlc.arg(sort_key) AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END END AS descending_value,
+       CASE WHEN CAST(sqlc.arg(descending) AS BOOLEAN) THEN -recipes.id ELSE recipes.id END AS id_order
 FROM recipes
-WHERE created_by = ?
-ORDER BY name;
+WHERE (CAST(sqlc.narg(created_by) AS INTEGER) IS NULL OR recipes.created_by = sqlc.narg(created_by))
+  AND (CAST(sqlc.narg(household_id) AS INTEGER) IS NULL OR recipes.household_id = sqlc.narg(household_id))
+  AND (CAST(sqlc.narg(max_minutes) AS INTEGER) IS NULL OR recipes.minutes <= sqlc.narg(max_minutes))
+  AND (CAST(sqlc.narg(min_servings) AS INTEGER) IS NULL OR recipes.servings >= sqlc.narg(min_servings))
+  AND (CAST(sqlc.narg(max_servings) AS INTEGER) IS NULL OR recipes.servings <= sqlc.narg(max_servings))
+  AND (sqlc.narg(tag_ids) IS NULL OR recipes.id IN (
+    SELECT recipe_tags.recipe_id
+    FROM recipe_tags
+    WHERE recipe_tags.tag_id IN (SELECT value FROM (SELECT sqlc.narg(tag_ids) AS ids) AS filter, json_each(filter.ids))
+    GROUP BY recipe_tags.recipe_id
+    HAVING COUNT(DISTINCT recipe_tags.tag_id) = json_array_length(sqlc.narg(tag_ids))
+    ))
+  AND (sqlc.narg(include_ingredient_ids) IS NULL OR recipes.id IN (
+    SELECT recipe_steps.recipe_id
+    FROM recipe_ingredients
+             INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+    WHERE recipe_ingredients.ingredient_id IN (SELECT value FROM (SELECT sqlc.narg(include_ingredient_ids) AS ids) AS filter, json_each(filter.ids))
+    GROUP BY recipe_steps.recipe_id
+    HAVING COUNT(DISTINCT recipe_ingredients.ingredient_id) = json_array_length(sqlc.narg(include_ingredient_ids))
+    ))
+  AND (sqlc.narg(exclude_ingredient_ids) IS NULL OR recipes.id NOT IN (
+    SELECT recipe_steps.recipe_id
+    FROM recipe_ingredients
+             INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+    WHERE recipe_ingredients.ingredient_id IN (SELECT value FROM (SELECT sqlc.narg(exclude_ingredient_ids) AS ids) AS filter, json_each(filter.ids))
+    ))
+  AND (sqlc.narg(cursor_value) IS NULL
+    OR (CAST(sqlc.arg(descending) AS BOOLEAN) = FALSE AND (CASE CAST(sqlc.arg(sort_key) AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END > sqlc.narg(cursor_value)
+        OR (CASE CAST(sqlc.arg(sort_key) AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END = sqlc.narg(cursor_value) AND recipes.id > sqlc.narg(cursor_id))))
+    OR (CAST(sqlc.arg(descending) AS BOOLEAN) = TRUE AND (CASE CAST(sqlc.arg(sort_key) AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END < sqlc.narg(cursor_value)
+        OR (CASE CAST(sqlc.arg(sort_key) AS TEXT) WHEN 'minutes' THEN recipes.minutes WHEN 'createdAt' THEN recipes.created_at ELSE recipes.name END = sqlc.narg(cursor_value) AND recipes.id < sqlc.narg(cursor_id)))))
+ORDER BY ascending_value, descending_value DESC, id_order
+LIMIT sqlc.arg(page_size);
 
 -- name: UpdateRecipe :exec
 UPDATE recipes
@@ -136,13 +199,13 @@ DELETE FROM recipe_tags
 WHERE recipe_id = ?;
 
 -- name: CreateIngredient :one
-INSERT INTO ingredients (name)
-VALUES (?)
+INSERT INTO ingredients (name, density, reference_amount, reference_unit_id)
+VALUES (?, ?, ?, ?)
 RETURNING id;
 
 -- name: UpdateIngredient :exec
 UPDATE ingredients
-SET name = ?
+SET name = ?, density = ?, reference_amount = ?, reference_unit_id = ?
 WHERE id = ?;
 
 -- name: DeleteIngredient :exec
@@ -150,13 +213,13 @@ DELETE FROM ingredients
 WHERE id = ?;
 
 -- name: CreateUnit :one
-INSERT INTO units (name, symbol)
-VALUES (?, ?)
+INSERT INTO units (name, symbol, dimension, system, factor)
+VALUES (?, ?, ?, ?, ?)
 RETURNING id;
 
 -- name: UpdateUnit :exec
 UPDATE units
-SET name = ?, symbol = ?
+SET name = ?, symbol = ?, dimension = ?, system = ?, factor = ?
 WHERE id = ?;
 
 -- name: DeleteUnit :exec
@@ -164,12 +227,201 @@ DELETE FROM units
 WHERE id = ?;
 
 -- name: CreateMealPlan :exec
-INSERT INTO meal_plan (date, user_id, recipe_id, sort_order)
-VALUES (?, ?, ?, ?);
+INSERT INTO meal_plan (date, household_id, user_id, recipe_id, servings, slot_id, note, sort_order)
+SELECT sqlc.arg(date),
+       sqlc.arg(household_id),
+       sqlc.arg(user_id),
+       sqlc.narg(recipe_id),
+       sqlc.narg(servings),
+       sqlc.narg(slot_id),
+       sqlc.narg(note),
+       COALESCE(MAX(sort_order) + 1, 0)
+FROM meal_plan
+WHERE household_id = sqlc.arg(household_id)
+  AND date = sqlc.arg(date)
+  AND slot_id IS sqlc.narg(slot_id);
+
+-- name: UpdateMealPlanEntry :execrows
+UPDATE meal_plan
+SET recipe_id  = sqlc.narg(recipe_id),
+    note       = sqlc.narg(note),
+    servings   = sqlc.narg(servings),
+    sort_order = CASE
+                     WHEN slot_id IS sqlc.narg(slot_id) THEN sort_order
+                     ELSE (SELECT COALESCE(MAX(other.sort_order) + 1, 0)
+                           FROM meal_plan AS other
+                           WHERE other.household_id = meal_plan.household_id
+                             AND other.date = meal_plan.date
+                             AND other.slot_id IS sqlc.narg(slot_id))
+        END,
+    slot_id    = sqlc.narg(slot_id)
+WHERE meal_plan.id = sqlc.arg(id)
+  AND meal_plan.household_id = sqlc.arg(household_id);
+
+-- name: AppendMealPlanEntry :exec
+UPDATE meal_plan
+SET household_id = sqlc.arg(household_id),
+    slot_id      = sqlc.narg(slot_id),
+    sort_order   = (SELECT COALESCE(MAX(other.sort_order) + 1, 0)
+                    FROM meal_plan AS other
+                    WHERE other.household_id = sqlc.arg(household_id)
+                      AND other.date = meal_plan.date
+                      AND other.slot_id IS sqlc.narg(slot_id))
+WHERE meal_plan.id = sqlc.arg(id);
+
+-- name: DeleteMealPlanEntry :execrows
+DELETE FROM meal_plan
+WHERE id = ? AND household_id = ?;
+
+-- name: GetMealSlotsByHousehold :many
+SELECT *
+FROM meal_slots
+WHERE household_id = ?
+ORDER BY sort_order;
+
+-- name: CreateMealSlot :one
+INSERT INTO meal_slots (household_id, name, sort_order, time)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateMealSlot :one
+UPDATE meal_slots
+SET name = ?, sort_order = ?, time = ?
+WHERE id = ? AND household_id = ?
+RETURNING *;
+
+-- name: DeleteMealSlot :exec
+DELETE FROM meal_slots
+WHERE id = ?;
+
+-- name: GetMealPlanEntry :one
+SELECT *
+FROM meal_plan
+WHERE id = ? AND household_id = ?
+LIMIT 1;
+
+-- name: GetMealPlanEntriesByDates :many
+SELECT *
+FROM meal_plan
+WHERE household_id = ?
+  AND date IN (sqlc.arg(source_date), sqlc.arg(target_date))
+ORDER BY date, slot_id, sort_order;
+
+-- name: GetMealPlanEntriesByHousehold :many
+SELECT *
+FROM meal_plan
+WHERE household_id = ?
+ORDER BY date, slot_id, sort_order;
+
+-- name: UpdateMealPlanEntryPosition :exec
+UPDATE meal_plan
+SET date = ?, slot_id = ?, sort_order = ?
+WHERE id = ?;
+
+-- name: DetachMealPlanEntry :exec
+UPDATE meal_plan
+SET template_entry_id = NULL
+WHERE id = ? AND household_id = ?;
+
+-- name: CreateMealPlanTemplateException :exec
+INSERT OR IGNORE INTO meal_plan_template_exceptions (template_entry_id, date)
+SELECT template_entry_id, date
+FROM meal_plan
+WHERE id = ? AND household_id = ? AND template_entry_id IS NOT NULL;
+
+-- name: GetMealPlanTemplatesByHousehold :many
+SELECT *
+FROM meal_plan_templates
+WHERE household_id = ?
+ORDER BY name, id;
+
+-- name: GetRecurringMealPlanTemplates :many
+SELECT *
+FROM meal_plan_templates
+WHERE recurring = 1
+ORDER BY id;
+
+-- name: GetMealPlanTemplate :one
+SELECT *
+FROM meal_plan_templates
+WHERE id = ? AND household_id = ?
+LIMIT 1;
+
+-- name: CreateMealPlanTemplate :one
+INSERT INTO meal_plan_templates (household_id, user_id, name, recurring)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateMealPlanTemplate :one
+UPDATE meal_plan_templates
+SET name = ?, recurring = ?
+WHERE id = ? AND household_id = ?
+RETURNING *;
+
+-- name: DeleteMealPlanTemplate :exec
+DELETE FROM meal_plan_templates
+WHERE id = ?;
+
+-- name: GetMealPlanTemplateEntries :many
+SELECT *
+FROM meal_plan_template_entries
+WHERE template_id = ?
+ORDER BY sort_order;
+
+-- name: CreateMealPlanTemplateEntry :one
+INSERT INTO meal_plan_template_entries (template_id, weekday, slot_id, recipe_id, note, servings, sort_order)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateMealPlanTemplateEntry :one
+UPDATE meal_plan_template_entries
+SET weekday = ?, slot_id = ?, recipe_id = ?, note = ?, servings = ?, sort_order = ?
+WHERE id = ? AND template_id = ?
+RETURNING *;
+
+-- name: DeleteMealPlanTemplateEntry :exec
+DELETE FROM meal_plan_template_entries
+WHERE id = ?;
+
+-- name: GetMealPlanTemplateWeeks :many
+SELECT week
+FROM meal_plan_template_weeks
+WHERE template_id = ? AND week >= ?
+ORDER BY week;
+
+-- name: CreateMealPlanTemplateWeek :exec
+INSERT OR IGNORE INTO meal_plan_template_weeks (template_id, week)
+VALUES (?, ?);
 
--- name: DeleteMealPlan :exec
+-- name: CreateMealPlanOccurrence :exec
+INSERT INTO meal_plan (date, household_id, user_id, recipe_id, servings, slot_id, note, template_entry_id, sort_order)
+SELECT sqlc.arg(date),
+       sqlc.arg(household_id),
+       sqlc.arg(user_id),
+       sqlc.narg(recipe_id),
+       sqlc.narg(servings),
+       sqlc.narg(slot_id),
+       sqlc.narg(note),
+       sqlc.arg(template_entry_id),
+       next.sort_order
+FROM (SELECT COALESCE(MAX(sort_order) + 1, 0) AS sort_order
+      FROM meal_plan
+      WHERE household_id = sqlc.arg(household_id)
+        AND date = sqlc.arg(date)
+        AND slot_id IS sqlc.narg(slot_id)) AS next
+WHERE EXISTS (SELECT 1
+              FROM meal_plan
+              WHERE template_entry_id = sqlc.arg(template_entry_id)
+                AND date = sqlc.arg(date)) = FALSE
+  AND EXISTS (SELECT 1
+              FROM meal_plan_template_exceptions
+              WHERE template_entry_id = sqlc.arg(template_entry_id)
+                AND date = sqlc.arg(date)) = FALSE;
+
+-- name: DeleteMealPlanTemplateOccurrences :exec
 DELETE FROM meal_plan
-WHERE user_id = ? AND recipe_id = ? AND date = ?;
+WHERE template_entry_id IN (SELECT id FROM meal_plan_template_entries WHERE template_id = ?)
+  AND date >= ?;
 
 -- name: GetNutrients :many
 SELECT *
@@ -198,14 +450,14 @@ WHERE ingredient_nutrients.ingredient_id = ?
 ORDER BY nutrients.name;
 
 -- name: GetNutrientsForIngredients :many
-SELECT ingredient_nutrients.ingredient_id, sqlc.embed(nutrients), ingredient_nutrients.amount
+SELECT DISTINCT ingredient_nutrients.ingredient_id, sqlc.embed(nutrients), ingredient_nutrients.amount
 FROM ingredient_nutrients
 INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
 WHERE ingredient_nutrients.ingredient_id IN (sqlc.slice(ingredient_ids))
 ORDER BY ingredient_nutrients.ingredient_id, nutrients.name;
 
 -- name: GetNutrientsForRecipes :many
-SELECT ingredient_nutrients.ingredient_id, sqlc.embed(nutrients), ingredient_nutrients.amount
+SELECT DISTINCT ingredient_nutrients.ingredient_id, sqlc.embed(nutrients), ingredient_nutrients.amount
 FROM ingredient_nutrients
 INNER JOIN nutrients ON ingredient_nutrients.nutrient_id = nutrients.id
 INNER JOIN recipe_ingredients ON ingredient_nutrients.ingredient_id = recipe_ingredients.ingredient_id
@@ -220,4 +472,15 @@ ON CONFLICT (ingredient_id, nutrient_id) DO UPDATE SET amount = excluded.amount;
 
 -- name: DeleteIngredientNutrients :exec
 DELETE FROM ingredient_nutrients
-WHERE ingredient_id = ?;
\ No newline at end of file
+WHERE ingredient_id = ?;
+
+-- name: SearchRecipes :many
+SELECT sqlc.embed(recipes),
+       CAST(highlight(recipes_fts, 0, '<mark>', '</mark>') AS TEXT)           AS highlighted_name,
+       CAST(snippet(recipes_fts, -1, '<mark>', '</mark>', char(8230), 16) AS TEXT) AS snippet,
+       CAST(bm25(recipes_fts, 10.0, 4.0, 1.0, 6.0, 6.0) AS REAL)           AS "rank"
+FROM recipes_fts
+         INNER JOIN recipes ON recipes_fts.rowid = recipes.id
+WHERE match(CAST(sqlc.arg(query) AS TEXT), recipes_fts)
+ORDER BY "rank"
+LIMIT sqlc.arg(result_limit) OFFSET sqlc.arg(result_offset);
@@ -1,54 +1,161 @@
--- name: GetShoppingListsByUserID :many
-SELECT id, user_id, name FROM shopping_lists
-WHERE user_id = ?
+-- name: GetShoppingListsByHouseholdID :many
+SELECT id, user_id, name, household_id, revision FROM shopping_lists
+WHERE household_id = ?
 ORDER BY id DESC;
 
 -- name: GetShoppingListByID :one
-SELECT id, user_id, name FROM shopping_lists
+SELECT id, user_id, name, household_id, revision FROM shopping_lists
 WHERE id = ?;
 
 -- name: CreateShoppingList :one
-INSERT INTO shopping_lists (user_id, name)
-VALUES (?, ?)
-RETURNING id, user_id, name;
+INSERT INTO shopping_lists (household_id, user_id, name)
+VALUES (?, ?, ?)
+RETURNING id, user_id, name, household_id, revision;
 
 -- name: UpdateShoppingList :one
 UPDATE shopping_lists
 SET name = ?
 WHERE id = ?
-RETURNING id, user_id, name;
+RETURNING id, user_id, name, household_id, revision;
+
+-- name: BumpShoppingListRevision :one
+UPDATE shopping_lists
+SET revision = revision + 1
+WHERE id = ?
+RETURNING revision;
+
+-- name: BumpShoppingListRevisionByItemID :one
+UPDATE shopping_lists
+SET revision = revision + 1
+WHERE id = (SELECT shopping_list_id FROM shopping_list_items WHERE shopping_list_items.id = ?)
+RETURNING revision;
 
 -- name: DeleteShoppingList :exec
 DELETE FROM shopping_lists
 WHERE id = ?;
 
 -- name: GetShoppingListItemsByListID :many
-SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order
+SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
 FROM shopping_list_items
 WHERE shopping_list_id = ?
 ORDER BY sort_order ASC;
 
+-- name: GetShoppingListItemsChangedSince :many
+SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
+FROM shopping_list_items
+WHERE shopping_list_id = ? AND revision > ?
+ORDER BY sort_order ASC;
+
 -- name: GetShoppingListItemByID :one
-SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order
+SELECT id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id
 FROM shopping_list_items
 WHERE id = ? AND shopping_list_id = ?;
 
 -- name: CreateShoppingListItem :one
-INSERT INTO shopping_list_items (shopping_list_id, ingredient, quantity, unit, done, sort_order)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order;
+INSERT INTO shopping_list_items (shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, revision, updated_at, client_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id;
 
 -- name: UpdateShoppingListItem :one
 UPDATE shopping_list_items
-SET ingredient = ?, quantity = ?, unit = ?, done = ?
+SET ingredient = ?, quantity = ?, unit = ?, done = ?, ingredient_id = ?, unit_id = ?, amount = ?,
+    version = version + 1, revision = ?, updated_at = ?
 WHERE id = ?
-RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order;
+RETURNING id, shopping_list_id, ingredient, quantity, unit, done, sort_order, ingredient_id, unit_id, amount, version, revision, updated_at, client_id;
 
 -- name: DeleteShoppingListItem :exec
 DELETE FROM shopping_list_items
 WHERE id = ?;
 
+-- name: CreateShoppingListItemDeletion :exec
+INSERT INTO shopping_list_item_deletions (shopping_list_id, item_id, client_id, revision)
+SELECT shopping_list_id, id, client_id, ? FROM shopping_list_items
+WHERE id = ?
+ON CONFLICT (shopping_list_id, item_id) DO UPDATE SET client_id = excluded.client_id, revision = excluded.revision;
+
+-- name: GetShoppingListItemDeletionsSince :many
+SELECT item_id FROM shopping_list_item_deletions
+WHERE shopping_list_id = ? AND revision > ?
+ORDER BY item_id;
+
+-- name: CountShoppingListItemDeletionsByClientID :one
+SELECT COUNT(*) FROM shopping_list_item_deletions
+WHERE shopping_list_id = ? AND client_id = ?;
+
 -- name: UpdateShoppingListItemSortOrder :exec
 UPDATE shopping_list_items
-SET sort_order = ?
-WHERE id = ?;
\ No newline at end of file
+SET sort_order = ?, revision = ?
+WHERE id = ?;
+
+-- name: AddShoppingListItemRecipe :exec
+INSERT INTO shopping_list_item_recipes (shopping_list_item_id, recipe_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: GetRecipesForShoppingListItems :many
+SELECT shopping_list_item_recipes.shopping_list_item_id, recipes.id, recipes.name
+FROM shopping_list_item_recipes
+INNER JOIN recipes ON shopping_list_item_recipes.recipe_id = recipes.id
+WHERE shopping_list_item_recipes.shopping_list_item_id IN (sqlc.slice(item_ids))
+ORDER BY recipes.name;
+
+-- name: GetStoresByHouseholdID :many
+SELECT id, user_id, name, household_id FROM stores
+WHERE household_id = ?
+ORDER BY name;
+
+-- name: GetStoreByID :one
+SELECT id, user_id, name, household_id FROM stores
+WHERE id = ?;
+
+-- name: CreateStore :one
+INSERT INTO stores (household_id, user_id, name)
+VALUES (?, ?, ?)
+RETURNING id, user_id, name, household_id;
+
+-- name: UpdateStore :one
+UPDATE stores
+SET name = ?
+WHERE id = ?
+RETURNING id, user_id, name, household_id;
+
+-- name: DeleteStore :exec
+DELETE FROM stores
+WHERE id = ?;
+
+-- name: GetStoreSectionsByStoreID :many
+SELECT id, store_id, name, sort_order FROM store_sections
+WHERE store_id = ?
+ORDER BY sort_order;
+
+-- name: CreateStoreSection :one
+INSERT INTO store_sections (store_id, name, sort_order)
+VALUES (?, ?, ?)
+RETURNING id, store_id, name, sort_order;
+
+-- name: UpdateStoreSection :one
+UPDATE store_sections
+SET name = ?, sort_order = ?
+WHERE id = ? AND store_id = ?
+RETURNING id, store_id, name, sort_order;
+
+-- name: DeleteStoreSection :exec
+DELETE FROM store_sections
+WHERE id = ?;
+
+-- name: GetStoreSectionIngredientsByStoreID :many
+SELECT store_id, ingredient_id, section_id FROM store_section_ingredients
+WHERE store_id = ?
+ORDER BY ingredient_id;
+
+-- name: GetExistingIngredientIDs :many
+SELECT id FROM ingredients
+WHERE id IN (sqlc.slice(ids));
+
+-- name: CreateStoreSectionIngredient :exec
+INSERT INTO store_section_ingredients (store_id, ingredient_id, section_id)
+VALUES (?, ?, ?);
+
+-- name: DeleteStoreSectionIngredientsByStoreID :exec
+DELETE FROM store_section_ingredients
+WHERE store_id = ?;
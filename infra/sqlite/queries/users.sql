@@ -1,18 +1,112 @@
+-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed = 1, last_used_step = ?
+WHERE user_id = ?;
+
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (user_id, name, token_hash, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: CreateAPITokenPermission :exec
+INSERT INTO api_token_permissions (api_token_id, permission_id)
+SELECT sqlc.arg(api_token_id), id
+FROM permissions
+WHERE slug = sqlc.arg(slug);
+
+-- name: CreateCalendarToken :one
+INSERT INTO calendar_tokens (user_id, token_hash)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (user_id, token)
+VALUES (?, ?)
+RETURNING *;
+
+-- name: CreateNutrientTarget :exec
+INSERT INTO user_nutrient_targets (user_id, nutrient_id, amount)
+VALUES (?, ?, ?);
+
+-- name: CreatePasskey :one
+INSERT INTO passkeys (user_id, credential_id, public_key, sign_count, flags, name)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: CreatePasskeyChallenge :one
+INSERT INTO passkey_challenges (token, ceremony, user_id, session)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
 -- name: CreatePasswordResetToken :one
 INSERT INTO password_resets (user_id, token)
 VALUES (?, ?)
 RETURNING *;
 
+-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash)
+VALUES (?, ?);
+
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, token_hash, user_agent, ip_address)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: CreateSSOLogin :one
+INSERT INTO sso_logins (state, nonce, code_verifier)
+VALUES (?, ?, ?)
+RETURNING *;
+
 -- name: CreateUser :one
 INSERT INTO users (email, password_hash, role_id, locale)
 VALUES (?, ?, ?, ?)
 RETURNING *;
 
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (user_id, issuer, subject)
+VALUES (?, ?, ?);
+
 -- name: CreateUserRegistration :one
 INSERT INTO user_registrations (user_id, token)
 VALUES (?, ?)
 RETURNING *;
 
+-- name: DeleteAPIToken :execrows
+DELETE
+FROM api_tokens
+WHERE id = ? AND user_id = ?;
+
+-- name: DeleteCalendarTokenByUserId :exec
+DELETE
+FROM calendar_tokens
+WHERE user_id = ?;
+
+-- name: DeleteLoginChallenge :exec
+DELETE
+FROM login_challenges
+WHERE id = ?;
+
+-- name: DeleteLoginChallengesBefore :exec
+DELETE
+FROM login_challenges
+WHERE created_at < ?;
+
+-- name: DeleteNutrientTargetsByUserId :exec
+DELETE
+FROM user_nutrient_targets
+WHERE user_id = ?;
+
+-- name: DeletePasskey :execrows
+DELETE
+FROM passkeys
+WHERE id = ? AND user_id = ?;
+
+-- name: DeletePasskeyChallengesBefore :exec
+DELETE
+FROM passkey_challenges
+WHERE created_at < ?;
+
 -- name: DeletePasswordResetsBefore :exec
 DELETE
 FROM password_resets
@@ -23,6 +117,36 @@ DELETE
 FROM password_resets
 WHERE user_id = ?;
 
+-- name: DeleteRecoveryCode :execrows
+DELETE
+FROM user_recovery_codes
+WHERE id = ?;
+
+-- name: DeleteRecoveryCodesByUserId :exec
+DELETE
+FROM user_recovery_codes
+WHERE user_id = ?;
+
+-- name: DeleteSession :execrows
+DELETE
+FROM sessions
+WHERE id = ? AND user_id = ?;
+
+-- name: DeleteSessionByTokenHash :exec
+DELETE
+FROM sessions
+WHERE token_hash = ?;
+
+-- name: DeleteSessionsBefore :exec
+DELETE
+FROM sessions
+WHERE created_at < ?;
+
+-- name: DeleteSessionsByUserId :exec
+DELETE
+FROM sessions
+WHERE user_id = ? AND id != sqlc.arg(keep_id);
+
 -- name: DeleteRegistrationByUserId :exec
 DELETE
 FROM user_registrations
@@ -33,6 +157,72 @@ DELETE
 FROM user_registrations
 WHERE created_at < ?;
 
+-- name: DeleteSSOLoginsBefore :exec
+DELETE
+FROM sso_logins
+WHERE created_at < ?;
+
+-- name: DeleteUserTOTP :exec
+DELETE
+FROM user_totp
+WHERE user_id = ?;
+
+-- name: GetAPITokenByHash :one
+SELECT *
+FROM api_tokens
+WHERE token_hash = ?
+LIMIT 1;
+
+-- name: GetAPITokenPermissions :many
+SELECT permissions.slug
+FROM permissions
+INNER JOIN api_token_permissions ON permissions.id = api_token_permissions.permission_id
+WHERE api_token_permissions.api_token_id = ?
+ORDER BY permissions.id;
+
+-- name: GetAPITokensByUserId :many
+SELECT *
+FROM api_tokens
+WHERE user_id = ?
+ORDER BY created_at, id;
+
+-- name: GetCalendarTokenByUser :one
+SELECT *
+FROM calendar_tokens
+WHERE user_id = ?
+LIMIT 1;
+
+-- name: GetUserIdByCalendarTokenHash :one
+SELECT user_id
+FROM calendar_tokens
+WHERE token_hash = ?
+LIMIT 1;
+
+-- name: GetLoginChallenge :one
+SELECT *
+FROM login_challenges
+WHERE token = ?
+LIMIT 1;
+
+-- name: GetNutrientTargetsByUserId :many
+SELECT sqlc.embed(nutrients), user_nutrient_targets.amount
+FROM user_nutrient_targets
+         INNER JOIN nutrients ON user_nutrient_targets.nutrient_id = nutrients.id
+WHERE user_nutrient_targets.user_id = ?
+ORDER BY nutrients.name;
+
+-- name: GetPasskeyByCredentialId :one
+SELECT *
+FROM passkeys
+WHERE credential_id = ?
+LIMIT 1;
+
+-- name: GetPasskeysByUserId :many
+SELECT *
+FROM passkeys
+WHERE user_id = ?
+ORDER BY created_at, id;
+
 -- name: GetPasswordResetToken :one
 SELECT sqlc.embed(password_resets), sqlc.embed(users)
 FROM password_resets
@@ -52,6 +242,23 @@ FROM permissions
 INNER JOIN role_permissions ON permissions.id = role_permissions.permission_id
 WHERE role_permissions.role_id = ?;
 
+-- name: GetRecoveryCodesByUserId :many
+SELECT *
+FROM user_recovery_codes
+WHERE user_id = ?;
+
+-- name: GetSessionByTokenHash :one
+SELECT *
+FROM sessions
+WHERE token_hash = ?
+LIMIT 1;
+
+-- name: GetSessionsByUserId :many
+SELECT *
+FROM sessions
+WHERE user_id = ?
+ORDER BY last_seen_at DESC, id DESC;
+
 -- name: GetUser :one
 SELECT users.*,
        roles.name as role_name
@@ -66,6 +273,13 @@ FROM users
 WHERE email = ?
 LIMIT 1;
 
+-- name: GetUserByIdentity :one
+SELECT users.*
+FROM users
+INNER JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = ? AND user_identities.subject = ?
+LIMIT 1;
+
 -- name: GetUserRegistration :one
 SELECT sqlc.embed(user_registrations), sqlc.embed(users)
 FROM user_registrations
@@ -73,13 +287,67 @@ FROM user_registrations
 WHERE token = ?
 LIMIT 1;
 
+-- name: GetUserTOTP :one
+SELECT *
+FROM user_totp
+WHERE user_id = ?
+LIMIT 1;
+
+-- name: SaveUserTOTP :one
+INSERT INTO user_totp (user_id, secret)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, confirmed = 0, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: TakePasskeyChallenge :one
+DELETE
+FROM passkey_challenges
+WHERE token = ? AND ceremony = ?
+RETURNING *;
+
+-- name: TakeSSOLogin :one
+DELETE
+FROM sso_logins
+WHERE state = ?
+RETURNING *;
+
+-- name: UpdateAPITokenLastUsed :exec
+UPDATE api_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: UpdateLoginChallengeAttempts :execrows
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE login_challenges.id = sqlc.arg(id)
+  AND (SELECT SUM(user_challenges.attempts)
+       FROM login_challenges AS user_challenges
+       WHERE user_challenges.user_id = login_challenges.user_id
+         AND user_challenges.created_at >= sqlc.arg(since)) < sqlc.arg(max_attempts);
+
+-- name: UpdatePasskeyUsage :execrows
+UPDATE passkeys
+SET sign_count = sqlc.arg(sign_count), flags = sqlc.arg(flags), last_used_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+  AND (sign_count < sqlc.arg(sign_count) OR (sign_count = 0 AND sqlc.arg(sign_count) = 0));
+
 -- name: UpdatePasswordByUserId :exec
 UPDATE users
 SET password_hash = ?
 WHERE id = ?;
 
+-- name: UpdateSessionLastSeen :exec
+UPDATE sessions
+SET last_seen_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
 -- name: UpdateUser :exec
 UPDATE users
 SET email        = ?,
     is_confirmed = ?
-WHERE id = ?;
\ No newline at end of file
+WHERE id = ?;
+
+-- name: UpdateUserTOTPLastUsedStep :execrows
+UPDATE user_totp
+SET last_used_step = sqlc.arg(step)
+WHERE user_id = sqlc.arg(user_id) AND last_used_step < sqlc.arg(step);
\ No newline at end of file
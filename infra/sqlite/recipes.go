@@ -2,7 +2,10 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"net/url"
+	"slices"
 	"sort"
 	"time"
 
@@ -19,17 +22,6 @@ type recipeRelations struct {
 	nutrients   []database.GetNutrientsForRecipesRow
 }
 
-func (s *Store) BrowseRecipes(ctx context.Context) (recipes []domain.Recipe, err error) {
-	result, err := s.query().BrowseRecipes(ctx)
-	if err != nil {
-		return nil, err
-	}
-	for _, recipe := range result {
-		recipes = append(recipes, s.mapper.ToRecipe(recipe))
-	}
-	return recipes, err
-}
-
 func (s *Store) CreateRecipe(ctx context.Context, recipe domain.Recipe) (domain.Recipe, error) {
 	var recipeId int64
 	err := s.WithTransaction(ctx, func(tx *TxStore) error {
@@ -107,36 +99,231 @@ func (s *Store) CreateMealPlan(ctx context.Context, entry domain.MealPlanEntry)
 	return s.query().CreateMealPlan(ctx, s.mapper.FromMealPlanEntry(entry))
 }
 
-func (s *Store) DeleteMealPlan(ctx context.Context, userID int64, recipeID int64, date time.Time) error {
-	return s.query().DeleteMealPlan(ctx, database.DeleteMealPlanParams{
-		UserID:   userID,
-		RecipeID: recipeID,
-		Date:     date.Format(time.DateOnly),
+func (s *Store) CreateMealPlanEntries(ctx context.Context, entries []domain.MealPlanEntry) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		for _, entry := range entries {
+			if err := tx.CreateMealPlan(ctx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) UpdateMealPlanEntry(ctx context.Context, entry domain.MealPlanEntry) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		if err := tx.detachMealPlanEntry(ctx, entry.HouseholdID, entry.ID); err != nil {
+			return err
+		}
+		updated, err := tx.query().UpdateMealPlanEntry(ctx, tx.mapper.FromMealPlanEntryForUpdate(entry))
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return domain.ErrMealPlanEntryNotFound
+		}
+		return nil
+	})
+}
+
+func (s *Store) DeleteMealPlanEntry(ctx context.Context, householdID int64, entryID int64) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		if err := tx.detachMealPlanEntry(ctx, householdID, entryID); err != nil {
+			return err
+		}
+		deleted, err := tx.query().DeleteMealPlanEntry(ctx, database.DeleteMealPlanEntryParams{
+			ID:          entryID,
+			HouseholdID: &householdID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return domain.ErrMealPlanEntryNotFound
+		}
+		return nil
+	})
+}
+
+func (s *Store) GetMealSlots(ctx context.Context, householdID int64) ([]domain.MealSlot, error) {
+	result, err := s.query().GetMealSlotsByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]domain.MealSlot, len(result))
+	for i, row := range result {
+		slots[i] = s.mapper.ToMealSlot(row)
+	}
+	return slots, nil
+}
+
+func (s *Store) SaveMealSlots(ctx context.Context, householdID int64, slots []domain.MealSlot) (result []domain.MealSlot, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		if err := tx.saveMealSlots(ctx, householdID, slots); err != nil {
+			return err
+		}
+		var err error
+		result, err = tx.GetMealSlots(ctx, householdID)
+		return err
+	})
+	return result, err
+}
+
+// saveMealSlots makes the slots of the household match the given ones.
+// Slots with an ID are updated, new ones created and all others removed.
+func (s *Store) saveMealSlots(ctx context.Context, householdID int64, slots []domain.MealSlot) error {
+	existing, err := s.query().GetMealSlotsByHousehold(ctx, householdID)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[int64]bool, len(slots))
+	for _, slot := range slots {
+		var row database.MealSlot
+		if slot.ID == 0 {
+			row, err = s.query().CreateMealSlot(ctx, s.mapper.FromMealSlot(householdID, slot))
+		} else {
+			row, err = s.query().UpdateMealSlot(ctx, s.mapper.FromMealSlotForUpdate(householdID, slot))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			// The slot belongs to another household
+			return domain.ErrInvalidMealSlot
+		} else if err != nil {
+			return err
+		}
+		kept[row.ID] = true
+	}
+
+	var entries []database.MealPlan
+	for _, slot := range existing {
+		if kept[slot.ID] {
+			continue
+		}
+		if entries == nil {
+			if entries, err = s.query().GetMealPlanEntriesByHousehold(ctx, &householdID); err != nil {
+				return err
+			}
+		}
+		// Entries of the slot join the ones without a slot before it is
+		// deleted, which would put them on the same positions otherwise
+		inSlot := slices.DeleteFunc(slices.Clone(entries), func(entry database.MealPlan) bool {
+			return entry.SlotID == nil || *entry.SlotID != slot.ID
+		})
+		if err = s.appendMealPlanEntries(ctx, householdID, inSlot, func(database.MealPlan) *int64 { return nil }); err != nil {
+			return err
+		}
+		if err = s.query().DeleteMealSlot(ctx, slot.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) MoveMealPlanEntry(ctx context.Context, householdID int64, entryID int64, date time.Time, move func([]domain.MealPlanEntry) ([]domain.MealPlanEntry, error)) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		entry, err := tx.query().GetMealPlanEntry(ctx, database.GetMealPlanEntryParams{
+			ID:          entryID,
+			HouseholdID: &householdID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrMealPlanEntryNotFound
+		} else if err != nil {
+			return err
+		}
+
+		result, err := tx.query().GetMealPlanEntriesByDates(ctx, database.GetMealPlanEntriesByDatesParams{
+			HouseholdID: &householdID,
+			SourceDate:  entry.Date,
+			TargetDate:  date.Format(time.DateOnly),
+		})
+		if err != nil {
+			return err
+		}
+		entries := make([]domain.MealPlanEntry, len(result))
+		for i, row := range result {
+			if entries[i], err = tx.mapper.ToMealPlanEntry(row); err != nil {
+				return err
+			}
+		}
+
+		moved, err := move(entries)
+		if err != nil {
+			return err
+		}
+		// Only the entry itself counts as changed, not the ones shifted by it
+		if slices.ContainsFunc(moved, func(entry domain.MealPlanEntry) bool { return entry.ID == entryID }) {
+			if err = tx.detachMealPlanEntry(ctx, householdID, entryID); err != nil {
+				return err
+			}
+		}
+		// Entries are parked on a position of their own first, so they never
+		// take the place of an entry that has not moved yet
+		for _, entry := range moved {
+			if err = tx.updateMealPlanEntryPosition(ctx, entry, -entry.ID); err != nil {
+				return err
+			}
+		}
+		for _, entry := range moved {
+			if err = tx.updateMealPlanEntryPosition(ctx, entry, entry.SortOrder); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
-func (s *Store) GetMealPlan(ctx context.Context, user *domain.User, from time.Time, until time.Time) ([]domain.MealPlan, error) {
+func (s *Store) updateMealPlanEntryPosition(ctx context.Context, entry domain.MealPlanEntry, sortOrder int64) error {
+	return s.query().UpdateMealPlanEntryPosition(ctx, database.UpdateMealPlanEntryPositionParams{
+		Date:      entry.Date.Format(time.DateOnly),
+		SlotID:    entry.SlotID,
+		SortOrder: sortOrder,
+		ID:        entry.ID,
+	})
+}
+
+// appendMealPlanEntries moves the entries to the bottom of the given slot of
+// the household on their day, keeping their order. slotID returns the slot
+// of each entry, nil for none.
+func (s *Store) appendMealPlanEntries(ctx context.Context, householdID int64, entries []database.MealPlan, slotID func(database.MealPlan) *int64) error {
+	for _, entry := range entries {
+		err := s.query().AppendMealPlanEntry(ctx, database.AppendMealPlanEntryParams{
+			HouseholdID: &householdID,
+			SlotID:      slotID(entry),
+			ID:          entry.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetMealPlan(ctx context.Context, householdID int64, from time.Time, until time.Time) ([]domain.MealPlan, error) {
 	result, err := s.query().GetMealPlan(ctx, database.GetMealPlanParams{
-		UserID:    user.ID,
-		FromDate:  from.Format(time.DateOnly),
-		UntilDate: until.Format(time.DateOnly),
+		HouseholdID: &householdID,
+		FromDate:    from.Format(time.DateOnly),
+		UntilDate:   until.Format(time.DateOnly),
 	})
 	if err != nil {
 		return []domain.MealPlan{}, err
 	}
 
-	recipeMap := make(map[int64]domain.Recipe)
-	for _, item := range result {
-		recipe := s.mapper.ToRecipe(item.Recipe)
-		recipeMap[recipe.ID] = recipe
+	var recipeIDs []int64
+	for _, entry := range result {
+		if entry.RecipeID != nil && !slices.Contains(recipeIDs, *entry.RecipeID) {
+			recipeIDs = append(recipeIDs, *entry.RecipeID)
+		}
 	}
-
-	recipes := make([]domain.Recipe, 0, len(recipeMap))
-	for _, recipe := range recipeMap {
-		recipes = append(recipes, recipe)
+	rows, err := s.query().GetRecipesByIDs(ctx, recipeIDs)
+	if err != nil {
+		return []domain.MealPlan{}, err
+	}
+	recipes := make([]domain.Recipe, len(rows))
+	for i, row := range rows {
+		recipes[i] = s.mapper.ToRecipe(row)
 	}
 
-	populatedRecipes, err := s.populateRecipeRelations(ctx, user, recipes)
+	populatedRecipes, err := s.populateRecipeRelations(ctx, nil, recipes)
 	if err != nil {
 		return []domain.MealPlan{}, err
 	}
@@ -146,22 +333,32 @@ func (s *Store) GetMealPlan(ctx context.Context, user *domain.User, from time.Ti
 		populatedRecipeMap[recipe.ID] = recipe
 	}
 
-	grouped := make(map[string][]domain.Recipe)
-	for _, item := range result {
-		recipe := populatedRecipeMap[item.Recipe.ID]
-		grouped[item.MealPlan.Date] = append(grouped[item.MealPlan.Date], recipe)
+	grouped := make(map[string][]domain.PlannedMeal)
+	for _, entry := range result {
+		meal := domain.PlannedMeal{
+			EntryID:         entry.ID,
+			SlotID:          entry.SlotID,
+			Note:            entry.Note,
+			Servings:        entry.Servings,
+			TemplateEntryID: entry.TemplateEntryID,
+		}
+		if entry.RecipeID != nil {
+			recipe := populatedRecipeMap[*entry.RecipeID]
+			meal.Recipe = &recipe
+		}
+		grouped[entry.Date] = append(grouped[entry.Date], meal)
 	}
 
 	i := 0
 	mealPlan := make([]domain.MealPlan, len(grouped))
-	for key, recipes := range grouped {
+	for key, meals := range grouped {
 		date, err := time.Parse(time.DateOnly, key)
 		if err != nil {
 			return []domain.MealPlan{}, err
 		}
 		mealPlan[i] = domain.MealPlan{
-			Date:    date,
-			Recipes: recipes,
+			Date:  date,
+			Meals: meals,
 		}
 		i++
 	}
@@ -201,18 +398,37 @@ func (s *Store) GetRecipeById(ctx context.Context, user *domain.User, id int64)
 	return populatedRecipes[0], nil
 }
 
-func (s *Store) GetRecipesByUser(ctx context.Context, user *domain.User) (recipes []domain.Recipe, _ error) {
-	result, err := s.query().ListRecipes(ctx, user.ID)
+func (s *Store) GetRecipesByHousehold(ctx context.Context, householdID int64) ([]domain.Recipe, error) {
+	result, err := s.query().GetRecipesByHousehold(ctx, &householdID)
 	if err != nil {
 		return nil, err
 	}
 
-	recipes = make([]domain.Recipe, len(result))
+	recipes := make([]domain.Recipe, len(result))
 	for i, recipe := range result {
 		recipes[i] = s.mapper.ToRecipe(recipe)
 	}
 
-	return s.populateRecipeRelations(ctx, user, recipes)
+	return s.populateRecipeRelations(ctx, nil, recipes)
+}
+
+func (s *Store) ListRecipes(ctx context.Context, query domain.RecipeListQuery, after *domain.Cursor) ([]domain.Recipe, error) {
+	params, err := s.mapper.FromRecipeListQuery(query, after)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.query().ListRecipes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]domain.Recipe, len(result))
+	for i, recipe := range result {
+		recipes[i] = s.mapper.ToRecipe(recipe.Recipe)
+	}
+
+	return s.populateRecipeRelations(ctx, nil, recipes)
 }
 
 func (s *Store) populateRecipeRelations(ctx context.Context, user *domain.User, recipes []domain.Recipe) ([]domain.Recipe, error) {
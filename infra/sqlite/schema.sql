@@ -1,7 +1,10 @@
 CREATE TABLE ingredients
 (
-    id   INTEGER PRIMARY KEY,
-    name TEXT NOT NULL
+    id                INTEGER PRIMARY KEY,
+    name              TEXT    NOT NULL,
+    density           REAL,
+    reference_amount  REAL    NOT NULL DEFAULT 100,
+    reference_unit_id INTEGER REFERENCES units (id) ON DELETE SET NULL
 );
 
 CREATE TABLE nutrients
@@ -28,13 +31,14 @@ CREATE TABLE permissions
 
 CREATE TABLE recipes
 (
-    id          INTEGER PRIMARY KEY,
-    name        TEXT      NOT NULL,
-    servings    INTEGER   NOT NULL,
-    minutes     INTEGER   NOT NULL,
-    description TEXT      NOT NULL,
-    created_by  INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
-    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    id           INTEGER PRIMARY KEY,
+    name         TEXT      NOT NULL,
+    servings     INTEGER   NOT NULL,
+    minutes      INTEGER   NOT NULL,
+    description  TEXT      NOT NULL,
+    created_by   INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    household_id INTEGER REFERENCES households (id) ON DELETE CASCADE
 );
 
 CREATE TABLE roles
@@ -57,9 +61,12 @@ CREATE TABLE tags
 
 CREATE TABLE units
 (
-    id     INTEGER PRIMARY KEY,
-    symbol TEXT,
-    name   TEXT NOT NULL
+    id        INTEGER PRIMARY KEY,
+    symbol    TEXT,
+    name      TEXT NOT NULL,
+    dimension TEXT,
+    system    TEXT,
+    factor    REAL
 );
 
 CREATE TABLE users
@@ -73,6 +80,32 @@ CREATE TABLE users
     created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE households
+(
+    id         INTEGER PRIMARY KEY,
+    name       TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE household_members
+(
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    user_id      INTEGER NOT NULL UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    role         TEXT    NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    PRIMARY KEY (household_id, user_id)
+);
+
+CREATE TABLE household_invitations
+(
+    id           INTEGER PRIMARY KEY,
+    household_id INTEGER   NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    email        TEXT      NOT NULL,
+    role         TEXT      NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    token        TEXT      NOT NULL UNIQUE,
+    invited_by   INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE user_registrations
 (
     user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
@@ -80,6 +113,14 @@ CREATE TABLE user_registrations
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE user_nutrient_targets
+(
+    user_id     INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    nutrient_id INTEGER NOT NULL REFERENCES nutrients (id) ON DELETE CASCADE,
+    amount      REAL    NOT NULL,
+    PRIMARY KEY (user_id, nutrient_id)
+);
+
 CREATE TABLE password_resets
 (
     user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
@@ -87,6 +128,109 @@ CREATE TABLE password_resets
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE sessions
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash   TEXT      NOT NULL UNIQUE,
+    user_agent   TEXT      NOT NULL DEFAULT '',
+    ip_address   TEXT      NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_totp
+(
+    user_id        INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         TEXT      NOT NULL,
+    confirmed      BOOLEAN   NOT NULL DEFAULT 0,
+    last_used_step INTEGER   NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_recovery_codes
+(
+    id        INTEGER PRIMARY KEY,
+    user_id   INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash TEXT    NOT NULL
+);
+
+CREATE TABLE login_challenges
+(
+    id         INTEGER PRIMARY KEY,
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token      TEXT      NOT NULL UNIQUE,
+    attempts   INTEGER   NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE passkeys
+(
+    id            INTEGER PRIMARY KEY,
+    user_id       INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    credential_id BLOB      NOT NULL UNIQUE,
+    public_key    BLOB      NOT NULL,
+    sign_count    INTEGER   NOT NULL DEFAULT 0,
+    flags         INTEGER,
+    name          TEXT      NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at  TIMESTAMP
+);
+
+CREATE TABLE passkey_challenges
+(
+    id         INTEGER PRIMARY KEY,
+    token      TEXT      NOT NULL UNIQUE,
+    ceremony   TEXT      NOT NULL,
+    user_id    INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    session    TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_identities
+(
+    id         INTEGER PRIMARY KEY,
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    issuer     TEXT      NOT NULL,
+    subject    TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issuer, subject)
+);
+
+CREATE TABLE sso_logins
+(
+    id            INTEGER PRIMARY KEY,
+    state         TEXT      NOT NULL UNIQUE,
+    nonce         TEXT      NOT NULL,
+    code_verifier TEXT      NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE api_tokens
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT      NOT NULL,
+    token_hash   TEXT      NOT NULL UNIQUE,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP
+);
+
+CREATE TABLE api_token_permissions
+(
+    api_token_id  INTEGER NOT NULL REFERENCES api_tokens (id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (api_token_id, permission_id)
+);
+
+CREATE TABLE calendar_tokens
+(
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT      NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE recipe_images
 (
     id         INTEGER PRIMARY KEY,
@@ -124,38 +268,267 @@ CREATE TABLE recipe_tags
     PRIMARY KEY (recipe_id, tag_id)
 );
 
+CREATE TABLE meal_slots
+(
+    id           INTEGER PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name         TEXT    NOT NULL,
+    sort_order   INTEGER NOT NULL DEFAULT 0,
+    time         TEXT
+);
+
 CREATE TABLE meal_plan
 (
-    id         INTEGER PRIMARY KEY,
-    date       TEXT    NOT NULL DEFAULT CURRENT_DATE,
-    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
-    recipe_id  INTEGER NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
-    sort_order INTEGER NOT NULL DEFAULT 0,
-    UNIQUE (date, sort_order)
+    id                INTEGER PRIMARY KEY,
+    date              TEXT    NOT NULL DEFAULT CURRENT_DATE,
+    user_id           INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipe_id         INTEGER REFERENCES recipes (id) ON DELETE CASCADE,
+    sort_order        INTEGER NOT NULL DEFAULT 0,
+    servings          INTEGER,
+    household_id      INTEGER REFERENCES households (id) ON DELETE CASCADE,
+    slot_id           INTEGER REFERENCES meal_slots (id) ON DELETE SET NULL,
+    note              TEXT,
+    template_entry_id INTEGER REFERENCES meal_plan_template_entries (id) ON DELETE SET NULL,
+    CHECK (recipe_id IS NOT NULL OR note IS NOT NULL)
+);
+
+CREATE TABLE meal_plan_templates
+(
+    id           INTEGER PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT    NOT NULL,
+    recurring    BOOLEAN NOT NULL DEFAULT 0
+);
+
+CREATE TABLE meal_plan_template_entries
+(
+    id          INTEGER PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES meal_plan_templates (id) ON DELETE CASCADE,
+    weekday     INTEGER NOT NULL,
+    slot_id     INTEGER REFERENCES meal_slots (id) ON DELETE SET NULL,
+    recipe_id   INTEGER REFERENCES recipes (id) ON DELETE CASCADE,
+    note        TEXT,
+    servings    INTEGER,
+    sort_order  INTEGER NOT NULL DEFAULT 0,
+    CHECK (recipe_id IS NOT NULL OR note IS NOT NULL)
+);
+
+CREATE TABLE meal_plan_template_weeks
+(
+    template_id INTEGER NOT NULL REFERENCES meal_plan_templates (id) ON DELETE CASCADE,
+    week        TEXT    NOT NULL,
+    PRIMARY KEY (template_id, week)
+);
+
+CREATE TABLE meal_plan_template_exceptions
+(
+    template_entry_id INTEGER NOT NULL REFERENCES meal_plan_template_entries (id) ON DELETE CASCADE,
+    date              TEXT    NOT NULL,
+    PRIMARY KEY (template_entry_id, date)
 );
 
 CREATE TABLE shopping_lists
 (
-    id      INTEGER PRIMARY KEY,
-    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
-    name    TEXT    NOT NULL DEFAULT 'Shopping List'
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT    NOT NULL DEFAULT 'Shopping List',
+    household_id INTEGER REFERENCES households (id) ON DELETE CASCADE,
+    revision     INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE TABLE shopping_list_items
 (
-    id               INTEGER PRIMARY KEY,
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
     shopping_list_id INTEGER NOT NULL REFERENCES shopping_lists (id) ON DELETE CASCADE,
     ingredient       TEXT    NOT NULL,
     quantity         TEXT,
     unit             TEXT,
     done             BOOLEAN NOT NULL DEFAULT 0,
     sort_order       INTEGER NOT NULL DEFAULT 0,
-    UNIQUE (shopping_list_id, sort_order)
+    ingredient_id    INTEGER REFERENCES ingredients (id) ON DELETE SET NULL,
+    unit_id          INTEGER REFERENCES units (id) ON DELETE SET NULL,
+    amount           REAL,
+    version          INTEGER NOT NULL DEFAULT 1,
+    revision         INTEGER NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMP,
+    client_id        TEXT,
+    UNIQUE (shopping_list_id, sort_order),
+    UNIQUE (shopping_list_id, client_id)
+);
+
+CREATE TABLE shopping_list_item_deletions
+(
+    shopping_list_id INTEGER NOT NULL REFERENCES shopping_lists (id) ON DELETE CASCADE,
+    item_id          INTEGER NOT NULL,
+    client_id        TEXT,
+    revision         INTEGER NOT NULL,
+    PRIMARY KEY (shopping_list_id, item_id)
+);
+
+CREATE TABLE shopping_list_item_recipes
+(
+    shopping_list_item_id INTEGER NOT NULL REFERENCES shopping_list_items (id) ON DELETE CASCADE,
+    recipe_id             INTEGER NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    PRIMARY KEY (shopping_list_item_id, recipe_id)
+);
+
+CREATE TABLE stores
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT    NOT NULL,
+    household_id INTEGER REFERENCES households (id) ON DELETE CASCADE
+);
+
+CREATE TABLE store_sections
+(
+    id         INTEGER PRIMARY KEY,
+    store_id   INTEGER NOT NULL REFERENCES stores (id) ON DELETE CASCADE,
+    name       TEXT    NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE store_section_ingredients
+(
+    store_id      INTEGER NOT NULL REFERENCES stores (id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    section_id    INTEGER NOT NULL REFERENCES store_sections (id) ON DELETE CASCADE,
+    PRIMARY KEY (store_id, ingredient_id)
 );
 
 CREATE INDEX idx_meal_plan_sort_order ON meal_plan (sort_order);
+CREATE UNIQUE INDEX meal_plan_household_date_slot_sort_order ON meal_plan (household_id, date, IFNULL(slot_id, 0), sort_order);
 CREATE INDEX idx_recipe_ingredients_sort_order ON recipe_ingredients (sort_order);
 CREATE INDEX idx_recipe_steps_sort_order ON recipe_steps (sort_order);
 CREATE INDEX idx_shopping_lists_user_id ON shopping_lists (user_id);
 CREATE INDEX idx_shopping_list_items_shopping_list_id ON shopping_list_items (shopping_list_id);
 CREATE INDEX idx_shopping_list_items_sort_order ON shopping_list_items (sort_order);
+CREATE INDEX idx_shopping_list_items_revision ON shopping_list_items (shopping_list_id, revision);
+CREATE INDEX idx_shopping_list_item_deletions_revision ON shopping_list_item_deletions (shopping_list_id, revision);
+CREATE INDEX idx_stores_user_id ON stores (user_id);
+CREATE INDEX idx_recipes_household_id ON recipes (household_id);
+CREATE INDEX idx_meal_plan_household_id ON meal_plan (household_id);
+CREATE INDEX idx_meal_slots_household_id ON meal_slots (household_id);
+CREATE INDEX idx_meal_plan_template_entry_id ON meal_plan (template_entry_id);
+CREATE INDEX idx_meal_plan_templates_household_id ON meal_plan_templates (household_id);
+CREATE INDEX idx_meal_plan_template_entries_template_id ON meal_plan_template_entries (template_id);
+CREATE INDEX idx_shopping_lists_household_id ON shopping_lists (household_id);
+CREATE INDEX idx_stores_household_id ON stores (household_id);
+CREATE INDEX idx_store_sections_store_id ON store_sections (store_id);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes (user_id);
+CREATE INDEX idx_passkeys_user_id ON passkeys (user_id);
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+CREATE INDEX idx_api_tokens_user_id ON api_tokens (user_id);
+
+CREATE VIRTUAL TABLE recipes_fts USING fts5
+(
+    name,
+    description,
+    instructions,
+    ingredients,
+    tags,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER recipes_fts_recipes_insert AFTER INSERT ON recipes BEGIN
+    INSERT INTO recipes_fts (rowid, name, description, instructions, ingredients, tags)
+    VALUES (new.id, new.name, new.description, '', '', '');
+END;
+
+CREATE TRIGGER recipes_fts_recipes_update AFTER UPDATE OF name, description ON recipes BEGIN
+    UPDATE recipes_fts SET name = new.name, description = new.description WHERE rowid = new.id;
+END;
+
+CREATE TRIGGER recipes_fts_recipes_delete AFTER DELETE ON recipes BEGIN
+    DELETE FROM recipes_fts WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER recipes_fts_recipe_steps_insert AFTER INSERT ON recipe_steps BEGIN
+    UPDATE recipes_fts
+    SET instructions = (SELECT coalesce(group_concat(instructions, ' '), '') FROM recipe_steps WHERE recipe_id = new.recipe_id)
+    WHERE rowid = new.recipe_id;
+END;
+
+CREATE TRIGGER recipes_fts_recipe_steps_update AFTER UPDATE OF instructions ON recipe_steps BEGIN
+    UPDATE recipes_fts
+    SET instructions = (SELECT coalesce(group_concat(instructions, ' '), '') FROM recipe_steps WHERE recipe_id = new.recipe_id)
+    WHERE rowid = new.recipe_id;
+END;
+
+CREATE TRIGGER recipes_fts_recipe_steps_delete AFTER DELETE ON recipe_steps BEGIN
+    UPDATE recipes_fts
+    SET instructions = (SELECT coalesce(group_concat(instructions, ' '), '') FROM recipe_steps WHERE recipe_id = old.recipe_id)
+    WHERE rowid = old.recipe_id;
+END;
+
+CREATE TRIGGER recipes_fts_recipe_ingredients_insert AFTER INSERT ON recipe_ingredients BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid = (SELECT recipe_id FROM recipe_steps WHERE id = new.step_id);
+END;
+
+CREATE TRIGGER recipes_fts_recipe_ingredients_update AFTER UPDATE OF ingredient_id ON recipe_ingredients BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid = (SELECT recipe_id FROM recipe_steps WHERE id = new.step_id);
+END;
+
+CREATE TRIGGER recipes_fts_recipe_ingredients_delete AFTER DELETE ON recipe_ingredients BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid = (SELECT recipe_id FROM recipe_steps WHERE id = old.step_id);
+END;
+
+CREATE TRIGGER recipes_fts_ingredients_update AFTER UPDATE OF name ON ingredients BEGIN
+    UPDATE recipes_fts
+    SET ingredients = (SELECT coalesce(group_concat(ingredients.name, ' '), '')
+                       FROM recipe_ingredients
+                                INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                                INNER JOIN ingredients ON recipe_ingredients.ingredient_id = ingredients.id
+                       WHERE recipe_steps.recipe_id = recipes_fts.rowid)
+    WHERE rowid IN (SELECT recipe_steps.recipe_id
+                    FROM recipe_ingredients
+                             INNER JOIN recipe_steps ON recipe_ingredients.step_id = recipe_steps.id
+                    WHERE recipe_ingredients.ingredient_id = new.id);
+END;
+
+CREATE TRIGGER recipes_fts_recipe_tags_insert AFTER INSERT ON recipe_tags BEGIN
+    UPDATE recipes_fts
+    SET tags = (SELECT coalesce(group_concat(tags.name, ' '), '')
+                FROM recipe_tags
+                         INNER JOIN tags ON recipe_tags.tag_id = tags.id
+                WHERE recipe_tags.recipe_id = new.recipe_id)
+    WHERE rowid = new.recipe_id;
+END;
+
+CREATE TRIGGER recipes_fts_recipe_tags_delete AFTER DELETE ON recipe_tags BEGIN
+    UPDATE recipes_fts
+    SET tags = (SELECT coalesce(group_concat(tags.name, ' '), '')
+                FROM recipe_tags
+                         INNER JOIN tags ON recipe_tags.tag_id = tags.id
+                WHERE recipe_tags.recipe_id = old.recipe_id)
+    WHERE rowid = old.recipe_id;
+END;
+
+CREATE TRIGGER recipes_fts_tags_update AFTER UPDATE OF name ON tags BEGIN
+    UPDATE recipes_fts
+    SET tags = (SELECT coalesce(group_concat(tags.name, ' '), '')
+                FROM recipe_tags
+                         INNER JOIN tags ON recipe_tags.tag_id = tags.id
+                WHERE recipe_tags.recipe_id = recipes_fts.rowid)
+    WHERE rowid IN (SELECT recipe_id FROM recipe_tags WHERE tag_id = new.id);
+END;
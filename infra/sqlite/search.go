@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (s *Store) SearchRecipes(ctx context.Context, query domain.RecipeSearchQuery) ([]domain.RecipeSearchResult, error) {
+	match := toMatchExpression(query.Text)
+	if match == "" {
+		return []domain.RecipeSearchResult{}, nil
+	}
+
+	result, err := s.query().SearchRecipes(ctx, database.SearchRecipesParams{
+		Query:        match,
+		ResultLimit:  query.Limit,
+		ResultOffset: query.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]domain.Recipe, len(result))
+	for i, row := range result {
+		recipes[i] = s.mapper.ToRecipe(row.Recipe)
+	}
+
+	populatedRecipes, err := s.populateRecipeRelations(ctx, nil, recipes)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.RecipeSearchResult, len(result))
+	for i, row := range result {
+		results[i] = domain.RecipeSearchResult{
+			Recipe:          populatedRecipes[i],
+			HighlightedName: row.HighlightedName,
+			Snippet:         row.Snippet,
+			Rank:            row.Rank,
+		}
+	}
+	return results, nil
+}
+
+// toMatchExpression turns free text into an FTS5 query in which every word
+// must match as a prefix, so user input can never be parsed as FTS5 syntax.
+func toMatchExpression(text string) string {
+	terms := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, term := range terms {
+		terms[i] = `"` + term + `"*`
+	}
+	return strings.Join(terms, " ")
+}
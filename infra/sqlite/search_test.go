@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+)
+
+func TestSearchRecipes(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	user := registerTestUser(t, store, "user@example.com")
+	units, err := store.GetUnits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basil, err := store.CreateIngredient(ctx, domain.Ingredient{Name: "Basil", ReferenceAmount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	soup, err := store.CreateRecipe(ctx, domain.Recipe{
+		HouseholdID: user.Membership.HouseholdID,
+		RecipeDetails: domain.RecipeDetails{
+			Name:        "Tomato Soup",
+			Description: "Served with crème fraîche",
+			CreatedBy:   user,
+			Servings:    2,
+		},
+		Steps: []domain.RecipeStep{{
+			Instructions: "Simmer the tomatoes",
+			Ingredients:  []domain.StepIngredient{{Unit: units[0], Amount: 10, Ingredient: basil}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.CreateRecipe(ctx, domain.Recipe{
+		HouseholdID:   user.Membership.HouseholdID,
+		RecipeDetails: domain.RecipeDetails{Name: "Pancakes", Description: "Fluffy", CreatedBy: user, Servings: 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(text string) []domain.RecipeSearchResult {
+		t.Helper()
+		results, err := store.SearchRecipes(ctx, domain.RecipeSearchQuery{Text: text, Limit: 10})
+		if err != nil {
+			t.Fatalf("SearchRecipes(%q) error = %v", text, err)
+		}
+		return results
+	}
+	assertFound := func(text string, want ...int64) {
+		t.Helper()
+		results := search(text)
+		if len(results) != len(want) {
+			t.Fatalf("SearchRecipes(%q) found %d recipes, want %v", text, len(results), want)
+		}
+		for i, result := range results {
+			if result.Recipe.ID != want[i] {
+				t.Errorf("SearchRecipes(%q)[%d] = recipe %d, want %d", text, i, result.Recipe.ID, want[i])
+			}
+		}
+	}
+
+	// Names, descriptions, instructions and ingredients are indexed, words
+	// match as prefixes and without diacritics
+	assertFound("tomat", soup.ID)
+	assertFound("creme", soup.ID)
+	assertFound("simmer", soup.ID)
+	assertFound("basil soup", soup.ID)
+	assertFound("basil pancakes")
+	if results := search("tomato"); results[0].HighlightedName != "<mark>Tomato</mark> Soup" {
+		t.Errorf("SearchRecipes() highlighted name = %q", results[0].HighlightedName)
+	}
+	// FTS5 syntax is searched for like any other text
+	assertFound(`soup" OR "pancakes`)
+	assertFound("NEAR(soup*")
+
+	soup.Name = "Gazpacho"
+	soup.Steps[0].Instructions = "Blend everything"
+	if _, err = store.UpdateRecipe(ctx, soup); err != nil {
+		t.Fatal(err)
+	}
+	assertFound("tomato")
+	assertFound("simmer")
+	assertFound("gazpacho blend basil", soup.ID)
+
+	basil.Name = "Parsley"
+	if _, err = store.UpdateIngredient(ctx, basil); err != nil {
+		t.Fatal(err)
+	}
+	assertFound("basil")
+	assertFound("parsley", soup.ID)
+
+	if err = store.DeleteRecipe(ctx, soup.ID); err != nil {
+		t.Fatal(err)
+	}
+	assertFound("gazpacho")
+	assertFound("parsley")
+}
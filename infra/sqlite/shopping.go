@@ -2,42 +2,28 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
 	_ "modernc.org/sqlite"
 )
 
-func (s *Store) GetShoppingListsByUser(ctx context.Context, userID int64) ([]domain.ShoppingList, error) {
-	result, err := s.query().GetShoppingListsByUserID(ctx, userID)
+func (s *Store) GetShoppingListsByHousehold(ctx context.Context, householdID int64) ([]domain.ShoppingList, error) {
+	result, err := s.query().GetShoppingListsByHouseholdID(ctx, &householdID)
 	if err != nil {
 		return nil, err
 	}
 
 	var lists []domain.ShoppingList
 	for _, row := range result {
-		list := domain.ShoppingList{
-			ID:     row.ID,
-			UserID: row.UserID,
-			Name:   row.Name,
-		}
-
-		items, err := s.query().GetShoppingListItemsByListID(ctx, row.ID)
+		list := s.mapper.ToShoppingList(row)
+		list.Items, err = s.getShoppingListItems(ctx, row.ID)
 		if err != nil {
 			return nil, err
 		}
-
-		for _, item := range items {
-			list.Items = append(list.Items, domain.ShoppingListItem{
-				ID:         item.ID,
-				Ingredient: item.Ingredient,
-				Quantity:   item.Quantity,
-				Unit:       item.Unit,
-				Done:       item.Done,
-				SortOrder:  item.SortOrder,
-			})
-		}
-
 		lists = append(lists, list)
 	}
 
@@ -50,48 +36,118 @@ func (s *Store) GetShoppingListByID(ctx context.Context, listID int64) (domain.S
 		return domain.ShoppingList{}, err
 	}
 
-	list := domain.ShoppingList{
-		ID:     row.ID,
-		UserID: row.UserID,
-		Name:   row.Name,
-	}
-
-	items, err := s.query().GetShoppingListItemsByListID(ctx, listID)
+	list := s.mapper.ToShoppingList(row)
+	list.Items, err = s.getShoppingListItems(ctx, listID)
 	if err != nil {
 		return domain.ShoppingList{}, err
 	}
 
-	for _, item := range items {
-		list.Items = append(list.Items, domain.ShoppingListItem{
-			ID:         item.ID,
-			Ingredient: item.Ingredient,
-			Quantity:   (item.Quantity),
-			Unit:       (item.Unit),
-			Done:       item.Done,
-			SortOrder:  item.SortOrder,
-		})
-	}
-
 	return list, nil
 }
 
-func (s *Store) CreateShoppingList(ctx context.Context, userID int64, name string) (domain.ShoppingList, error) {
-	row, err := s.query().CreateShoppingList(ctx, database.CreateShoppingListParams{
-		UserID: userID,
-		Name:   name,
+// GetShoppingListChangesSince returns the items changed and deleted after
+// the given revision of the list.
+func (s *Store) GetShoppingListChangesSince(ctx context.Context, listID int64, revision int64) (domain.ShoppingListDelta, error) {
+	list, err := s.query().GetShoppingListByID(ctx, listID)
+	if err != nil {
+		return domain.ShoppingListDelta{}, err
+	}
+
+	rows, err := s.query().GetShoppingListItemsChangedSince(ctx, database.GetShoppingListItemsChangedSinceParams{
+		ShoppingListID: listID,
+		Revision:       revision,
+	})
+	if err != nil {
+		return domain.ShoppingListDelta{}, err
+	}
+	items, err := s.toShoppingListItems(ctx, rows)
+	if err != nil {
+		return domain.ShoppingListDelta{}, err
+	}
+
+	deleted, err := s.query().GetShoppingListItemDeletionsSince(ctx, database.GetShoppingListItemDeletionsSinceParams{
+		ShoppingListID: listID,
+		Revision:       revision,
 	})
 	if err != nil {
-		return domain.ShoppingList{}, err
+		return domain.ShoppingListDelta{}, err
+	}
+	if deleted == nil {
+		deleted = []int64{}
 	}
 
-	return domain.ShoppingList{
-		ID:     row.ID,
-		UserID: row.UserID,
-		Name:   row.Name,
-		Items:  []domain.ShoppingListItem{},
+	return domain.ShoppingListDelta{
+		Revision:       list.Revision,
+		Items:          items,
+		DeletedItemIDs: deleted,
 	}, nil
 }
 
+func (s *Store) getShoppingListItems(ctx context.Context, listID int64) ([]domain.ShoppingListItem, error) {
+	rows, err := s.query().GetShoppingListItemsByListID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toShoppingListItems(ctx, rows)
+}
+
+func (s *Store) toShoppingListItems(ctx context.Context, rows []database.ShoppingListItem) ([]domain.ShoppingListItem, error) {
+	items := make([]domain.ShoppingListItem, len(rows))
+	itemIDs := make([]int64, len(rows))
+	for i, row := range rows {
+		items[i] = s.mapper.ToShoppingListItem(row)
+		itemIDs[i] = row.ID
+	}
+	if err := s.populateShoppingListItemRecipes(ctx, items, itemIDs); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) populateShoppingListItemRecipes(ctx context.Context, items []domain.ShoppingListItem, itemIDs []int64) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	recipes, err := s.query().GetRecipesForShoppingListItems(ctx, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	recipesByItem := make(map[int64][]domain.Recipe)
+	for _, row := range recipes {
+		recipesByItem[row.ShoppingListItemID] = append(recipesByItem[row.ShoppingListItemID], s.mapper.ToShoppingListItemRecipe(row))
+	}
+	for i := range items {
+		if recipes, ok := recipesByItem[items[i].ID]; ok {
+			items[i].Recipes = recipes
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateShoppingList(ctx context.Context, householdID int64, userID int64, list domain.ShoppingList) (result domain.ShoppingList, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		row, err := tx.query().CreateShoppingList(ctx, database.CreateShoppingListParams{
+			HouseholdID: &householdID,
+			UserID:      userID,
+			Name:        list.Name,
+		})
+		if err != nil {
+			return err
+		}
+		if len(list.Items) == 0 {
+			result = tx.mapper.ToShoppingList(row)
+			return nil
+		}
+		if err = tx.saveShoppingListItems(ctx, row.ID, list.Items); err != nil {
+			return err
+		}
+		result, err = tx.GetShoppingListByID(ctx, row.ID)
+		return err
+	})
+	return result, err
+}
+
 func (s *Store) UpdateShoppingList(ctx context.Context, listID int64, name string) (domain.ShoppingList, error) {
 	row, err := s.query().UpdateShoppingList(ctx, database.UpdateShoppingListParams{
 		Name: name,
@@ -108,51 +164,198 @@ func (s *Store) DeleteShoppingList(ctx context.Context, listID int64) error {
 	return s.query().DeleteShoppingList(ctx, listID)
 }
 
-func (s *Store) CreateShoppingListItem(ctx context.Context, listID int64, item domain.ShoppingListItem) (domain.ShoppingListItem, error) {
-	row, err := s.query().CreateShoppingListItem(ctx, database.CreateShoppingListItemParams{
-		ShoppingListID: listID,
-		Ingredient:     item.Ingredient,
-		Quantity:       item.Quantity,
-		Unit:           item.Unit,
-		Done:           item.Done,
-		SortOrder:      item.SortOrder,
+func (s *Store) CreateShoppingListItem(ctx context.Context, listID int64, item domain.ShoppingListItem) (result domain.ShoppingListItem, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		revision, err := tx.query().BumpShoppingListRevision(ctx, listID)
+		if err != nil {
+			return err
+		}
+		result, err = tx.createShoppingListItem(ctx, listID, revision, item)
+		return err
 	})
+	return result, err
+}
+
+func (s *Store) createShoppingListItem(ctx context.Context, listID int64, revision int64, item domain.ShoppingListItem) (domain.ShoppingListItem, error) {
+	if item.UpdatedAt.IsZero() {
+		item.UpdatedAt = time.Now()
+	}
+	row, err := s.query().CreateShoppingListItem(ctx, s.mapper.FromShoppingListItem(listID, revision, item))
 	if err != nil {
 		return domain.ShoppingListItem{}, err
 	}
-
-	return domain.ShoppingListItem{
-		ID:         row.ID,
-		Ingredient: row.Ingredient,
-		Quantity:   (row.Quantity),
-		Unit:       (row.Unit),
-		Done:       row.Done,
-		SortOrder:  row.SortOrder,
-	}, nil
+	return s.saveShoppingListItemRecipes(ctx, s.mapper.ToShoppingListItem(row), item.Recipes)
 }
 
-func (s *Store) UpdateShoppingListItem(ctx context.Context, itemID int64, item domain.ShoppingListItem) (domain.ShoppingListItem, error) {
-	row, err := s.query().UpdateShoppingListItem(ctx, database.UpdateShoppingListItemParams{
-		Ingredient: item.Ingredient,
-		Quantity:   item.Quantity,
-		Unit:       item.Unit,
-		Done:       item.Done,
-		ID:         itemID,
+func (s *Store) UpdateShoppingListItem(ctx context.Context, itemID int64, item domain.ShoppingListItem) (result domain.ShoppingListItem, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		revision, err := tx.query().BumpShoppingListRevisionByItemID(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		result, err = tx.updateShoppingListItem(ctx, itemID, revision, item)
+		return err
 	})
+	return result, err
+}
+
+func (s *Store) updateShoppingListItem(ctx context.Context, itemID int64, revision int64, item domain.ShoppingListItem) (domain.ShoppingListItem, error) {
+	if item.UpdatedAt.IsZero() {
+		item.UpdatedAt = time.Now()
+	}
+	row, err := s.query().UpdateShoppingListItem(ctx, s.mapper.FromShoppingListItemForUpdate(itemID, revision, item))
 	if err != nil {
 		return domain.ShoppingListItem{}, err
 	}
+	return s.saveShoppingListItemRecipes(ctx, s.mapper.ToShoppingListItem(row), item.Recipes)
+}
 
-	return domain.ShoppingListItem{
-		ID:         row.ID,
-		Ingredient: row.Ingredient,
-		Quantity:   (row.Quantity),
-		Unit:       (row.Unit),
-		Done:       row.Done,
-		SortOrder:  row.SortOrder,
-	}, nil
+// saveShoppingListItemRecipes links the item to the recipes it is needed for.
+// Links are only ever added, so editing an item keeps where it came from.
+func (s *Store) saveShoppingListItemRecipes(ctx context.Context, item domain.ShoppingListItem, recipes []domain.Recipe) (domain.ShoppingListItem, error) {
+	for _, recipe := range recipes {
+		err := s.query().AddShoppingListItemRecipe(ctx, database.AddShoppingListItemRecipeParams{
+			ShoppingListItemID: item.ID,
+			RecipeID:           recipe.ID,
+		})
+		if err != nil {
+			return domain.ShoppingListItem{}, err
+		}
+	}
+
+	items := []domain.ShoppingListItem{item}
+	if err := s.populateShoppingListItemRecipes(ctx, items, []int64{item.ID}); err != nil {
+		return domain.ShoppingListItem{}, err
+	}
+	return items[0], nil
+}
+
+// SaveShoppingListItems creates items without an ID and updates all others.
+func (s *Store) SaveShoppingListItems(ctx context.Context, listID int64, items []domain.ShoppingListItem) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		return tx.saveShoppingListItems(ctx, listID, items)
+	})
+}
+
+func (s *Store) saveShoppingListItems(ctx context.Context, listID int64, items []domain.ShoppingListItem) error {
+	revision, err := s.query().BumpShoppingListRevision(ctx, listID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.ID == 0 {
+			_, err = s.createShoppingListItem(ctx, listID, revision, item)
+		} else {
+			_, err = s.updateShoppingListItem(ctx, item.ID, revision, item)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *Store) DeleteShoppingListItem(ctx context.Context, itemID int64) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		revision, err := tx.query().BumpShoppingListRevisionByItemID(ctx, itemID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.deleteShoppingListItem(ctx, revision, itemID)
+	})
+}
+
+// deleteShoppingListItem remembers the deletion for clients that sync later.
+func (s *Store) deleteShoppingListItem(ctx context.Context, revision int64, itemID int64) error {
+	err := s.query().CreateShoppingListItemDeletion(ctx, database.CreateShoppingListItemDeletionParams{
+		Revision: revision,
+		ID:       itemID,
+	})
+	if err != nil {
+		return err
+	}
 	return s.query().DeleteShoppingListItem(ctx, itemID)
 }
+
+func (s *Store) SyncShoppingListItems(ctx context.Context, listID int64, apply func(domain.ShoppingList) (domain.ShoppingListChanges, error)) (result domain.ShoppingList, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		list, err := tx.GetShoppingListByID(ctx, listID)
+		if err != nil {
+			return err
+		}
+		changes, err := apply(list)
+		if err != nil {
+			return err
+		}
+		if err = tx.saveShoppingListChanges(ctx, listID, changes); err != nil {
+			return err
+		}
+		result, err = tx.GetShoppingListByID(ctx, listID)
+		return err
+	})
+	return result, err
+}
+
+func (s *Store) saveShoppingListChanges(ctx context.Context, listID int64, changes domain.ShoppingListChanges) error {
+	revision, err := s.query().BumpShoppingListRevision(ctx, listID)
+	if err != nil {
+		return err
+	}
+
+	for _, itemID := range changes.Deleted {
+		if err = s.deleteShoppingListItem(ctx, revision, itemID); err != nil {
+			return err
+		}
+	}
+
+	// Items that move are parked on a position of their own first, so they
+	// never take the place of an item that has not moved yet
+	moving := append(append([]domain.ShoppingListItem{}, changes.Updated...), changes.Moved...)
+	for _, item := range moving {
+		if err = s.updateShoppingListItemSortOrder(ctx, revision, item.ID, -item.ID); err != nil {
+			return err
+		}
+	}
+	for _, item := range changes.Updated {
+		if _, err = s.updateShoppingListItem(ctx, item.ID, revision, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range moving {
+		if err = s.updateShoppingListItemSortOrder(ctx, revision, item.ID, item.SortOrder); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range changes.Created {
+		// An item that was created and deleted before is not created again
+		// when a client sends the same operations twice
+		if item.ClientID != nil {
+			deleted, err := s.query().CountShoppingListItemDeletionsByClientID(ctx, database.CountShoppingListItemDeletionsByClientIDParams{
+				ShoppingListID: listID,
+				ClientID:       item.ClientID,
+			})
+			if err != nil {
+				return err
+			}
+			if deleted > 0 {
+				continue
+			}
+		}
+		if _, err = s.createShoppingListItem(ctx, listID, revision, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) updateShoppingListItemSortOrder(ctx context.Context, revision int64, itemID int64, sortOrder int64) error {
+	return s.query().UpdateShoppingListItemSortOrder(ctx, database.UpdateShoppingListItemSortOrderParams{
+		SortOrder: sortOrder,
+		Revision:  revision,
+		ID:        itemID,
+	})
+}
@@ -0,0 +1,226 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/events"
+)
+
+func TestCreateShoppingList(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	user := registerTestUser(t, store, "user@example.com")
+	householdID := user.Membership.HouseholdID
+	milk, err := store.CreateIngredient(ctx, domain.Ingredient{Name: "Milk", ReferenceAmount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := store.CreateShoppingList(ctx, householdID, user.ID, domain.ShoppingList{
+		Name: "Groceries",
+		Items: []domain.ShoppingListItem{
+			{Ingredient: "Milk", IngredientID: &milk.ID, Amount: ptr(500.0)},
+			{Ingredient: "Salt", SortOrder: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateShoppingList() error = %v", err)
+	}
+	if created.Name != "Groceries" || len(created.Items) != 2 || created.Items[0].IngredientID == nil || created.Items[1].Ingredient != "Salt" {
+		t.Errorf("CreateShoppingList() = %+v, want the groceries with milk and salt", created)
+	}
+
+	// A list whose items can't be saved isn't created either
+	_, err = store.CreateShoppingList(ctx, householdID, user.ID, domain.ShoppingList{
+		Name:  "Broken",
+		Items: []domain.ShoppingListItem{{Ingredient: "Unknown", IngredientID: ptr(milk.ID + 1)}},
+	})
+	if err == nil {
+		t.Fatal("CreateShoppingList() with an unknown ingredient succeeded")
+	}
+	lists, err := store.GetShoppingListsByHousehold(ctx, householdID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 1 || lists[0].ID != created.ID {
+		t.Errorf("GetShoppingListsByHousehold() = %+v, want only the groceries", lists)
+	}
+}
+
+func TestShoppingServiceAddItem(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	user := registerTestUser(t, store, "user@example.com")
+	shopping := domain.NewShoppingService(store, domain.NewRecipeService(nil, store), events.NewShoppingListBus())
+	flour, err := store.CreateIngredient(ctx, domain.Ingredient{Name: "Flour", ReferenceAmount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	units, err := store.GetUnits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unitIDs := make(map[string]int64)
+	for _, unit := range units {
+		unitIDs[unit.Name] = unit.ID
+	}
+	list, err := shopping.Create(ctx, user, "Groceries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(amount float64, unit string) domain.ShoppingListItem {
+		t.Helper()
+		item, err := shopping.AddItem(ctx, user, list.ID, domain.ShoppingListItem{
+			IngredientID: &flour.ID,
+			UnitID:       ptr(unitIDs[unit]),
+			Amount:       &amount,
+		})
+		if err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+		return item
+	}
+
+	first := add(500, "Gram")
+	// The same ingredient in a unit that converts is added to the open item
+	merged := add(1, "Kilogram")
+	if merged.ID != first.ID || merged.Quantity == nil || *merged.Quantity != "1.5" || *merged.UnitID != unitIDs["Kilogram"] {
+		t.Errorf("AddItem() = item %d with %v of unit %v, want item %d with 1.5 kg", merged.ID, merged.Quantity, *merged.UnitID, first.ID)
+	}
+	// A bag doesn't convert into grams, so it is listed on its own
+	if bag := add(1, "Bag"); bag.ID == first.ID {
+		t.Errorf("AddItem() of a bag = item %d, want a new item", bag.ID)
+	}
+
+	list, err = shopping.GetByID(ctx, user, list.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("GetByID() has %d items, want the flour in kilograms and in bags", len(list.Items))
+	}
+}
+
+func TestSyncShoppingListItems(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	user := registerTestUser(t, store, "user@example.com")
+	list, err := store.CreateShoppingList(ctx, user.Membership.HouseholdID, user.ID, domain.ShoppingList{
+		Name: "Groceries",
+		Items: []domain.ShoppingListItem{
+			{Ingredient: "Apples"},
+			{Ingredient: "Bread", SortOrder: 1},
+			{Ingredient: "Cheese", SortOrder: 2},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	find := func(list domain.ShoppingList, ingredient string) domain.ShoppingListItem {
+		t.Helper()
+		for _, item := range list.Items {
+			if item.Ingredient == ingredient {
+				return item
+			}
+		}
+		t.Fatalf("list has no %s", ingredient)
+		return domain.ShoppingListItem{}
+	}
+	assertOrder := func(list domain.ShoppingList, want ...string) {
+		t.Helper()
+		for i, ingredient := range want {
+			if item := find(list, ingredient); item.SortOrder != int64(i) {
+				t.Errorf("%s is at %d, want %d", ingredient, item.SortOrder, i)
+			}
+		}
+		if len(list.Items) != len(want) {
+			t.Errorf("list has %d items, want %d", len(list.Items), len(want))
+		}
+	}
+
+	// Every item takes the place of another one, which the unique index on
+	// the sort order only allows because they are parked first
+	apples, bread, cheese := find(list, "Apples"), find(list, "Bread"), find(list, "Cheese")
+	synced, err := store.SyncShoppingListItems(ctx, list.ID, func(current domain.ShoppingList) (domain.ShoppingListChanges, error) {
+		cheese.SortOrder, apples.SortOrder = 0, 1
+		bread.Ingredient, bread.SortOrder = "Butter", 2
+		return domain.ShoppingListChanges{
+			Updated: []domain.ShoppingListItem{bread},
+			Moved:   []domain.ShoppingListItem{cheese, apples},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("SyncShoppingListItems() rotating the items error = %v", err)
+	}
+	assertOrder(synced, "Cheese", "Apples", "Butter")
+	if butter := find(synced, "Butter"); butter.Version != bread.Version+1 {
+		t.Errorf("SyncShoppingListItems() updated item version = %d, want %d", butter.Version, bread.Version+1)
+	}
+	if moved := find(synced, "Apples"); moved.Version != apples.Version {
+		t.Errorf("SyncShoppingListItems() moved item version = %d, want %d", moved.Version, apples.Version)
+	}
+	if synced.Revision <= list.Revision {
+		t.Errorf("SyncShoppingListItems() revision = %d, want more than %d", synced.Revision, list.Revision)
+	}
+
+	// A created item takes the place a deleted one left
+	revision := synced.Revision
+	synced, err = store.SyncShoppingListItems(ctx, list.ID, func(current domain.ShoppingList) (domain.ShoppingListChanges, error) {
+		apples, butter := find(current, "Apples"), find(current, "Butter")
+		apples.SortOrder, butter.SortOrder = 0, 1
+		return domain.ShoppingListChanges{
+			Deleted: []int64{find(current, "Cheese").ID},
+			Moved:   []domain.ShoppingListItem{apples, butter},
+			Created: []domain.ShoppingListItem{{Ingredient: "Dates", SortOrder: 2}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("SyncShoppingListItems() replacing an item error = %v", err)
+	}
+	assertOrder(synced, "Apples", "Butter", "Dates")
+	delta, err := store.GetShoppingListChangesSince(ctx, list.ID, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delta.Items) != 3 || len(delta.DeletedItemIDs) != 1 || delta.DeletedItemIDs[0] != cheese.ID {
+		t.Errorf("GetShoppingListChangesSince() = %d items and deleted %v, want 3 items and the cheese deleted", len(delta.Items), delta.DeletedItemIDs)
+	}
+
+	// A change made to an older version of an item loses against the newer
+	// change, unless it was made later
+	shopping := domain.NewShoppingService(store, domain.NewRecipeService(nil, store), events.NewShoppingListBus())
+	butter := find(synced, "Butter")
+	toggle := func(version int64, timestamp time.Time) domain.ShoppingListOperationStatus {
+		t.Helper()
+		result, err := shopping.Sync(ctx, user, list.ID, domain.ShoppingListSync{Operations: []domain.ShoppingListOperation{{
+			Type:        domain.ShoppingListOperationToggle,
+			Item:        domain.ShoppingListItemRef{ID: &butter.ID},
+			BaseVersion: version,
+			Timestamp:   timestamp,
+			Done:        true,
+		}}})
+		if err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		return result.Results[0].Status
+	}
+	if status := toggle(butter.Version-1, butter.UpdatedAt.Add(-time.Minute)); status != domain.ShoppingListOperationConflict {
+		t.Errorf("Sync() of an outdated toggle = %s, want %s", status, domain.ShoppingListOperationConflict)
+	}
+	if synced, _ = store.GetShoppingListByID(ctx, list.ID); find(synced, "Butter").Done {
+		t.Error("Sync() applied the outdated toggle")
+	}
+	if status := toggle(butter.Version, butter.UpdatedAt); status != domain.ShoppingListOperationApplied {
+		t.Errorf("Sync() of a current toggle = %s, want %s", status, domain.ShoppingListOperationApplied)
+	}
+	synced, err = store.GetShoppingListByID(ctx, list.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := find(synced, "Butter"); !got.Done || got.Version != butter.Version+1 {
+		t.Errorf("Sync() toggled item = done %t version %d, want done with version %d", got.Done, got.Version, butter.Version+1)
+	}
+}
@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
+)
+
+func (s *Store) GetStoresByHousehold(ctx context.Context, householdID int64) ([]domain.Store, error) {
+	result, err := s.query().GetStoresByHouseholdID(ctx, &householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := make([]domain.Store, len(result))
+	for i, row := range result {
+		stores[i] = s.mapper.ToStore(row)
+		stores[i].Sections, err = s.getStoreSections(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stores, nil
+}
+
+func (s *Store) GetStoreByID(ctx context.Context, storeID int64) (domain.Store, error) {
+	row, err := s.query().GetStoreByID(ctx, storeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Store{}, domain.ErrStoreNotFound
+	} else if err != nil {
+		return domain.Store{}, err
+	}
+
+	store := s.mapper.ToStore(row)
+	store.Sections, err = s.getStoreSections(ctx, storeID)
+	if err != nil {
+		return domain.Store{}, err
+	}
+	return store, nil
+}
+
+func (s *Store) getStoreSections(ctx context.Context, storeID int64) ([]domain.StoreSection, error) {
+	rows, err := s.query().GetStoreSectionsByStoreID(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	ingredients, err := s.query().GetStoreSectionIngredientsByStoreID(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make([]domain.StoreSection, len(rows))
+	indexByID := make(map[int64]int, len(rows))
+	for i, row := range rows {
+		sections[i] = s.mapper.ToStoreSection(row)
+		indexByID[row.ID] = i
+	}
+	for _, ingredient := range ingredients {
+		if i, ok := indexByID[ingredient.SectionID]; ok {
+			sections[i].IngredientIDs = append(sections[i].IngredientIDs, ingredient.IngredientID)
+		}
+	}
+	return sections, nil
+}
+
+func (s *Store) CreateStore(ctx context.Context, householdID int64, userID int64, store domain.Store) (result domain.Store, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		row, err := tx.query().CreateStore(ctx, database.CreateStoreParams{
+			HouseholdID: &householdID,
+			UserID:      userID,
+			Name:        store.Name,
+		})
+		if err != nil {
+			return err
+		}
+		if err = tx.saveStoreSections(ctx, row.ID, store.Sections); err != nil {
+			return err
+		}
+		result, err = tx.GetStoreByID(ctx, row.ID)
+		return err
+	})
+	return result, err
+}
+
+func (s *Store) UpdateStore(ctx context.Context, storeID int64, store domain.Store) (result domain.Store, _ error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		_, err := tx.query().UpdateStore(ctx, database.UpdateStoreParams{
+			Name: store.Name,
+			ID:   storeID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrStoreNotFound
+		} else if err != nil {
+			return err
+		}
+		if err = tx.saveStoreSections(ctx, storeID, store.Sections); err != nil {
+			return err
+		}
+		result, err = tx.GetStoreByID(ctx, storeID)
+		return err
+	})
+	return result, err
+}
+
+// saveStoreSections makes the sections of the store match the given ones.
+// Sections with an ID are updated, new ones created and all others removed.
+func (s *Store) saveStoreSections(ctx context.Context, storeID int64, sections []domain.StoreSection) error {
+	existing, err := s.query().GetStoreSectionsByStoreID(ctx, storeID)
+	if err != nil {
+		return err
+	}
+	if err = s.validateStoreIngredients(ctx, sections); err != nil {
+		return err
+	}
+	if err = s.query().DeleteStoreSectionIngredientsByStoreID(ctx, storeID); err != nil {
+		return err
+	}
+
+	kept := make(map[int64]bool, len(sections))
+	for _, section := range sections {
+		var row database.StoreSection
+		if section.ID == 0 {
+			row, err = s.query().CreateStoreSection(ctx, s.mapper.FromStoreSection(storeID, section))
+		} else {
+			row, err = s.query().UpdateStoreSection(ctx, s.mapper.FromStoreSectionForUpdate(storeID, section))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			// The section belongs to another store
+			return domain.ErrInvalidStore
+		} else if err != nil {
+			return err
+		}
+		kept[row.ID] = true
+
+		for _, ingredientID := range section.IngredientIDs {
+			err = s.query().CreateStoreSectionIngredient(ctx, database.CreateStoreSectionIngredientParams{
+				StoreID:      storeID,
+				IngredientID: ingredientID,
+				SectionID:    row.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, section := range existing {
+		if kept[section.ID] {
+			continue
+		}
+		if err = s.query().DeleteStoreSection(ctx, section.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateStoreIngredients fails with ErrInvalidStore when a section refers
+// to an ingredient that doesn't exist.
+func (s *Store) validateStoreIngredients(ctx context.Context, sections []domain.StoreSection) error {
+	var ids []int64
+	for _, section := range sections {
+		ids = append(ids, section.IngredientIDs...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	existing, err := s.query().GetExistingIngredientIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	// The sections never list an ingredient twice, the service makes sure
+	if len(existing) != len(ids) {
+		return domain.ErrInvalidStore
+	}
+	return nil
+}
+
+func (s *Store) DeleteStore(ctx context.Context, storeID int64) error {
+	return s.query().DeleteStore(ctx, storeID)
+}
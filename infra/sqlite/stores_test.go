@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/infra/events"
+)
+
+func TestUpdateStore(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	user := registerTestUser(t, store, "user@example.com")
+	var ingredients []domain.Ingredient
+	for _, name := range []string{"Milk", "Bread"} {
+		ingredient, err := store.CreateIngredient(ctx, domain.Ingredient{Name: name, ReferenceAmount: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	milk, bread := ingredients[0], ingredients[1]
+
+	created, err := store.CreateStore(ctx, user.Membership.HouseholdID, user.ID, domain.Store{
+		Name: "Corner Shop",
+		Sections: []domain.StoreSection{
+			{Name: "Dairy", IngredientIDs: []int64{milk.ID}},
+			{Name: "Frozen", SortOrder: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keep the dairy section, drop the frozen one and add a bakery
+	dairy := created.Sections[0]
+	dairy.SortOrder = 1
+	updated, err := store.UpdateStore(ctx, created.ID, domain.Store{
+		Name: "Supermarket",
+		Sections: []domain.StoreSection{
+			{Name: "Bakery", IngredientIDs: []int64{bread.ID}},
+			dairy,
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateStore() error = %v", err)
+	}
+	if updated.ID != created.ID || updated.UserID != user.ID || updated.Name != "Supermarket" {
+		t.Errorf("UpdateStore() = %+v, want store %d of user %d named Supermarket", updated, created.ID, user.ID)
+	}
+
+	stored, err := store.GetStoreByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "Supermarket" {
+		t.Errorf("GetStoreByID() name = %q after the update", stored.Name)
+	}
+	if len(stored.Sections) != 2 {
+		t.Fatalf("GetStoreByID() has %d sections after the update, want 2", len(stored.Sections))
+	}
+	bakery := stored.Sections[0]
+	if bakery.Name != "Bakery" || !slices.Equal(bakery.IngredientIDs, []int64{bread.ID}) {
+		t.Errorf("GetStoreByID() first section = %+v, want the bakery with bread", bakery)
+	}
+	if stored.Sections[1].ID != dairy.ID || !slices.Equal(stored.Sections[1].IngredientIDs, []int64{milk.ID}) {
+		t.Errorf("GetStoreByID() second section = %+v, want the updated dairy section %d", stored.Sections[1], dairy.ID)
+	}
+
+	if _, err = store.UpdateStore(ctx, created.ID+1, domain.Store{Name: "Unknown"}); err != domain.ErrStoreNotFound {
+		t.Errorf("UpdateStore() of an unknown store error = %v, want %v", err, domain.ErrStoreNotFound)
+	}
+}
+
+func TestDeleteStore(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	// The migrations turn on foreign keys for their connection, closing it
+	// leaves only connections with the settings of the DSN
+	store.db.SetMaxIdleConns(0)
+	user := registerTestUser(t, store, "user@example.com")
+	milk, err := store.CreateIngredient(ctx, domain.Ingredient{Name: "Milk", ReferenceAmount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.CreateStore(ctx, user.Membership.HouseholdID, user.ID, domain.Store{
+		Name:     "Corner Shop",
+		Sections: []domain.StoreSection{{Name: "Dairy", IngredientIDs: []int64{milk.ID + 1}}},
+	}); err != domain.ErrInvalidStore {
+		t.Errorf("CreateStore() with an unknown ingredient error = %v, want %v", err, domain.ErrInvalidStore)
+	}
+
+	created, err := store.CreateStore(ctx, user.Membership.HouseholdID, user.ID, domain.Store{
+		Name:     "Corner Shop",
+		Sections: []domain.StoreSection{{Name: "Dairy", IngredientIDs: []int64{milk.ID}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.DeleteStore(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The sections go with the store
+	for _, table := range []string{"store_sections", "store_section_ingredients"} {
+		var count int
+		if err = store.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("DeleteStore() left %d rows in %s, want 0", count, table)
+		}
+	}
+}
+
+func TestShoppingServiceStores(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	shopping := domain.NewShoppingService(store, domain.NewRecipeService(nil, store), events.NewShoppingListBus())
+	owner := registerTestUser(t, store, "owner@example.com")
+	member := registerTestUser(t, store, "member@example.com")
+	outsider := registerTestUser(t, store, "outsider@example.com")
+
+	corner, err := shopping.CreateStore(ctx, owner, domain.Store{Name: "Corner Shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	market, err := shopping.CreateStore(ctx, member, domain.Store{Name: "Market"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The member's store moves along into the owner's household
+	err = store.AcceptHouseholdInvitation(ctx, domain.HouseholdInvitation{
+		Household: domain.Household{ID: owner.Membership.HouseholdID},
+		Role:      domain.HouseholdRoleViewer,
+	}, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined, err := store.GetUserById(ctx, member.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member = &joined
+
+	stores, err := shopping.GetStores(ctx, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stores) != 2 || stores[0].ID != corner.ID || stores[1].ID != market.ID {
+		t.Errorf("GetStores() of the owner = %+v, want the corner shop and the market", stores)
+	}
+	if _, err = shopping.GetStore(ctx, member, corner.ID); err != nil {
+		t.Errorf("GetStore() of a viewer error = %v", err)
+	}
+	if _, err = shopping.UpdateStore(ctx, member, corner.ID, domain.Store{Name: "Supermarket"}); err != domain.ErrAuthorization {
+		t.Errorf("UpdateStore() of a viewer error = %v, want %v", err, domain.ErrAuthorization)
+	}
+	if _, err = shopping.CreateStore(ctx, member, domain.Store{Name: "Bakery"}); err != domain.ErrAuthorization {
+		t.Errorf("CreateStore() of a viewer error = %v, want %v", err, domain.ErrAuthorization)
+	}
+
+	member.Membership.Role = domain.HouseholdRoleEditor
+	if _, err = shopping.UpdateStore(ctx, member, corner.ID, domain.Store{Name: "Supermarket"}); err != nil {
+		t.Errorf("UpdateStore() of an editor error = %v", err)
+	}
+
+	if stores, err = shopping.GetStores(ctx, outsider); err != nil || len(stores) != 0 {
+		t.Errorf("GetStores() of an outsider = %+v, %v, want none", stores, err)
+	}
+	if _, err = shopping.GetStore(ctx, outsider, corner.ID); err != domain.ErrAuthorization {
+		t.Errorf("GetStore() of an outsider error = %v, want %v", err, domain.ErrAuthorization)
+	}
+	if err = shopping.DeleteStore(ctx, outsider, corner.ID); err != domain.ErrAuthorization {
+		t.Errorf("DeleteStore() of an outsider error = %v, want %v", err, domain.ErrAuthorization)
+	}
+}
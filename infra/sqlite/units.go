@@ -22,25 +22,23 @@ func (s *Store) GetUnits(ctx context.Context) ([]domain.Unit, error) {
 }
 
 func (s *Store) CreateUnit(ctx context.Context, unit domain.Unit) (domain.Unit, error) {
-	id, err := s.query().CreateUnit(ctx, database.CreateUnitParams{
-		Name:   unit.Name,
-		Symbol: unit.Symbol,
-	})
+	id, err := s.query().CreateUnit(ctx, s.mapper.FromUnit(unit))
 	if err != nil {
 		return domain.Unit{}, err
 	}
-	return domain.Unit{
-		ID:     id,
-		Name:   unit.Name,
-		Symbol: unit.Symbol,
-	}, nil
+	unit.ID = id
+	return unit, nil
 }
 
 func (s *Store) UpdateUnit(ctx context.Context, unit domain.Unit) error {
+	params := s.mapper.FromUnit(unit)
 	return s.query().UpdateUnit(ctx, database.UpdateUnitParams{
-		Name:   unit.Name,
-		Symbol: unit.Symbol,
-		ID:     unit.ID,
+		Name:      params.Name,
+		Symbol:    params.Symbol,
+		Dimension: params.Dimension,
+		System:    params.System,
+		Factor:    params.Factor,
+		ID:        unit.ID,
 	})
 }
 
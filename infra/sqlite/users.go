@@ -2,11 +2,16 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/domain/roles"
 	"github.com/wolfsblu/recipe-manager/domain/security"
+	"github.com/wolfsblu/recipe-manager/infra/sqlite/database"
 )
 
 func (s *Store) CreatePasswordResetToken(ctx context.Context, user *domain.User) (token domain.PasswordResetToken, _ error) {
@@ -21,6 +26,373 @@ func (s *Store) CreatePasswordResetToken(ctx context.Context, user *domain.User)
 	return token, nil
 }
 
+func (s *Store) CreateCalendarToken(ctx context.Context, userID int64, hash string) (domain.CalendarToken, error) {
+	result, err := s.query().CreateCalendarToken(ctx, s.mapper.FromUserForCalendarToken(userID, hash))
+	if err != nil {
+		return domain.CalendarToken{}, err
+	}
+	return s.mapper.ToCalendarToken(result), nil
+}
+
+func (s *Store) DeleteCalendarToken(ctx context.Context, userID int64) error {
+	return s.query().DeleteCalendarTokenByUserId(ctx, userID)
+}
+
+func (s *Store) GetCalendarToken(ctx context.Context, userID int64) (domain.CalendarToken, error) {
+	result, err := s.query().GetCalendarTokenByUser(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.CalendarToken{}, domain.ErrCalendarTokenNotFound
+	} else if err != nil {
+		return domain.CalendarToken{}, err
+	}
+	return s.mapper.ToCalendarToken(result), nil
+}
+
+func (s *Store) GetUserByCalendarTokenHash(ctx context.Context, hash string) (domain.User, error) {
+	userID, err := s.query().GetUserIdByCalendarTokenHash(ctx, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, domain.ErrCalendarTokenNotFound
+	} else if err != nil {
+		return domain.User{}, err
+	}
+	return s.GetUserById(ctx, userID)
+}
+
+func (s *Store) CreateSession(ctx context.Context, userID int64, hash string, client domain.SessionClient) (domain.Session, error) {
+	result, err := s.query().CreateSession(ctx, s.mapper.FromSessionClient(userID, hash, client))
+	if err != nil {
+		return domain.Session{}, err
+	}
+	return s.mapper.ToSession(result), nil
+}
+
+func (s *Store) DeleteSession(ctx context.Context, userID int64, id int64) error {
+	deleted, err := s.query().DeleteSession(ctx, database.DeleteSessionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *Store) DeleteSessionByTokenHash(ctx context.Context, hash string) error {
+	return s.query().DeleteSessionByTokenHash(ctx, hash)
+}
+
+func (s *Store) DeleteSessions(ctx context.Context, userID int64, keepID int64) error {
+	return s.query().DeleteSessionsByUserId(ctx, database.DeleteSessionsByUserIdParams{
+		UserID: userID,
+		KeepID: keepID,
+	})
+}
+
+func (s *Store) DeleteSessionsBefore(ctx context.Context, before time.Time) error {
+	return s.query().DeleteSessionsBefore(ctx, before)
+}
+
+func (s *Store) GetSessionByTokenHash(ctx context.Context, hash string) (domain.Session, error) {
+	result, err := s.query().GetSessionByTokenHash(ctx, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	} else if err != nil {
+		return domain.Session{}, err
+	}
+	return s.mapper.ToSession(result), nil
+}
+
+func (s *Store) GetSessions(ctx context.Context, userID int64) ([]domain.Session, error) {
+	result, err := s.query().GetSessionsByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]domain.Session, len(result))
+	for i, row := range result {
+		sessions[i] = s.mapper.ToSession(row)
+	}
+	return sessions, nil
+}
+
+func (s *Store) UpdateSessionLastSeen(ctx context.Context, id int64) error {
+	return s.query().UpdateSessionLastSeen(ctx, id)
+}
+
+func (s *Store) ConfirmTOTP(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		err := tx.query().ConfirmUserTOTP(ctx, database.ConfirmUserTOTPParams{
+			LastUsedStep: step,
+			UserID:       userID,
+		})
+		if err != nil {
+			return err
+		}
+		if err = tx.query().DeleteRecoveryCodesByUserId(ctx, userID); err != nil {
+			return err
+		}
+		for _, hash := range recoveryCodeHashes {
+			err = tx.query().CreateRecoveryCode(ctx, database.CreateRecoveryCodeParams{
+				UserID:   userID,
+				CodeHash: hash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) DeleteTOTP(ctx context.Context, userID int64) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		if err := tx.query().DeleteRecoveryCodesByUserId(ctx, userID); err != nil {
+			return err
+		}
+		return tx.query().DeleteUserTOTP(ctx, userID)
+	})
+}
+
+func (s *Store) GetTOTP(ctx context.Context, userID int64) (domain.TOTP, error) {
+	result, err := s.query().GetUserTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.TOTP{}, domain.ErrTwoFactorNotEnabled
+	} else if err != nil {
+		return domain.TOTP{}, err
+	}
+	return s.mapper.ToTOTP(result), nil
+}
+
+func (s *Store) SaveTOTP(ctx context.Context, userID int64, secret string) error {
+	_, err := s.query().SaveUserTOTP(ctx, database.SaveUserTOTPParams{
+		UserID: userID,
+		Secret: secret,
+	})
+	return err
+}
+
+func (s *Store) UpdateTOTPLastUsedStep(ctx context.Context, userID int64, step int64) error {
+	updated, err := s.query().UpdateUserTOTPLastUsedStep(ctx, database.UpdateUserTOTPLastUsedStepParams{
+		Step:   step,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	} else if updated == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *Store) DeleteRecoveryCode(ctx context.Context, id int64) error {
+	deleted, err := s.query().DeleteRecoveryCode(ctx, id)
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *Store) GetRecoveryCodes(ctx context.Context, userID int64) ([]domain.RecoveryCode, error) {
+	result, err := s.query().GetRecoveryCodesByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]domain.RecoveryCode, len(result))
+	for i, row := range result {
+		codes[i] = s.mapper.ToRecoveryCode(row)
+	}
+	return codes, nil
+}
+
+func (s *Store) CreateLoginChallenge(ctx context.Context, userID int64) (domain.LoginChallenge, error) {
+	result, err := s.query().CreateLoginChallenge(ctx, database.CreateLoginChallengeParams{
+		UserID: userID,
+		Token:  security.GenerateToken(security.DefaultTokenLength),
+	})
+	if err != nil {
+		return domain.LoginChallenge{}, err
+	}
+	return s.mapper.ToLoginChallenge(result), nil
+}
+
+func (s *Store) DeleteLoginChallenge(ctx context.Context, id int64) error {
+	return s.query().DeleteLoginChallenge(ctx, id)
+}
+
+func (s *Store) DeleteLoginChallengesBefore(ctx context.Context, before time.Time) error {
+	return s.query().DeleteLoginChallengesBefore(ctx, before)
+}
+
+func (s *Store) GetLoginChallenge(ctx context.Context, token string) (domain.LoginChallenge, error) {
+	result, err := s.query().GetLoginChallenge(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.LoginChallenge{}, domain.ErrLoginChallengeNotFound
+	} else if err != nil {
+		return domain.LoginChallenge{}, err
+	}
+	return s.mapper.ToLoginChallenge(result), nil
+}
+
+// UpdateLoginChallengeAttempts counts an attempt on the challenge unless the
+// user already made maxAttempts on their challenges created since.
+func (s *Store) UpdateLoginChallengeAttempts(ctx context.Context, id int64, since time.Time, maxAttempts int64) error {
+	updated, err := s.query().UpdateLoginChallengeAttempts(ctx, database.UpdateLoginChallengeAttemptsParams{
+		ID:          id,
+		Since:       since.UTC(),
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		return err
+	} else if updated == 0 {
+		return domain.ErrLoginChallengeNotFound
+	}
+	return nil
+}
+
+func (s *Store) CreatePasskey(ctx context.Context, userID int64, name string, credential webauthn.Credential) (domain.Passkey, error) {
+	result, err := s.query().CreatePasskey(ctx, s.mapper.FromPasskeyCredential(userID, name, credential))
+	if err != nil {
+		return domain.Passkey{}, err
+	}
+	return s.mapper.ToPasskey(result), nil
+}
+
+func (s *Store) DeletePasskey(ctx context.Context, userID int64, id int64) error {
+	deleted, err := s.query().DeletePasskey(ctx, database.DeletePasskeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return domain.ErrPasskeyNotFound
+	}
+	return nil
+}
+
+func (s *Store) GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (domain.Passkey, error) {
+	result, err := s.query().GetPasskeyByCredentialId(ctx, credentialID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Passkey{}, domain.ErrPasskeyNotFound
+	} else if err != nil {
+		return domain.Passkey{}, err
+	}
+	return s.mapper.ToPasskey(result), nil
+}
+
+func (s *Store) GetPasskeys(ctx context.Context, userID int64) ([]domain.Passkey, error) {
+	result, err := s.query().GetPasskeysByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	passkeys := make([]domain.Passkey, len(result))
+	for i, row := range result {
+		passkeys[i] = s.mapper.ToPasskey(row)
+	}
+	return passkeys, nil
+}
+
+// UpdatePasskeyUsage only moves the counter forward, so that of two logins
+// with the same signature only one passes. Counters of authenticators that
+// don't count signatures stay at zero.
+func (s *Store) UpdatePasskeyUsage(ctx context.Context, id int64, credential webauthn.Credential) error {
+	flags := int64(credential.Flags.ProtocolValue())
+	updated, err := s.query().UpdatePasskeyUsage(ctx, database.UpdatePasskeyUsageParams{
+		SignCount: int64(credential.Authenticator.SignCount),
+		Flags:     &flags,
+		ID:        id,
+	})
+	if err != nil {
+		return err
+	} else if updated == 0 {
+		return domain.ErrInvalidCredentials
+	}
+	return nil
+}
+
+func (s *Store) CreatePasskeyChallenge(ctx context.Context, ceremony string, userID int64, session webauthn.SessionData) (domain.PasskeyChallenge, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+	params := database.CreatePasskeyChallengeParams{
+		Token:    security.GenerateToken(security.DefaultTokenLength),
+		Ceremony: ceremony,
+		Session:  string(data),
+	}
+	if userID != 0 {
+		params.UserID = &userID
+	}
+	result, err := s.query().CreatePasskeyChallenge(ctx, params)
+	if err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+	return s.mapper.ToPasskeyChallenge(result)
+}
+
+func (s *Store) DeletePasskeyChallengesBefore(ctx context.Context, before time.Time) error {
+	return s.query().DeletePasskeyChallengesBefore(ctx, before)
+}
+
+func (s *Store) TakePasskeyChallenge(ctx context.Context, token, ceremony string) (domain.PasskeyChallenge, error) {
+	result, err := s.query().TakePasskeyChallenge(ctx, database.TakePasskeyChallengeParams{
+		Token:    token,
+		Ceremony: ceremony,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PasskeyChallenge{}, domain.ErrPasskeyChallengeNotFound
+	} else if err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+	return s.mapper.ToPasskeyChallenge(result)
+}
+
+func (s *Store) CreateSSOLogin(ctx context.Context, nonce, codeVerifier string) (domain.SSOLogin, error) {
+	result, err := s.query().CreateSSOLogin(ctx, database.CreateSSOLoginParams{
+		State:        security.GenerateToken(security.DefaultTokenLength),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	})
+	if err != nil {
+		return domain.SSOLogin{}, err
+	}
+	return s.mapper.ToSSOLogin(result), nil
+}
+
+func (s *Store) DeleteSSOLoginsBefore(ctx context.Context, before time.Time) error {
+	return s.query().DeleteSSOLoginsBefore(ctx, before)
+}
+
+func (s *Store) TakeSSOLogin(ctx context.Context, state string) (domain.SSOLogin, error) {
+	result, err := s.query().TakeSSOLogin(ctx, state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.SSOLogin{}, domain.ErrSSOLoginNotFound
+	} else if err != nil {
+		return domain.SSOLogin{}, err
+	}
+	return s.mapper.ToSSOLogin(result), nil
+}
+
+func (s *Store) CreateUserIdentity(ctx context.Context, userID int64, identity domain.ExternalIdentity) error {
+	return s.query().CreateUserIdentity(ctx, s.mapper.FromUserIdentity(userID, identity))
+}
+
+func (s *Store) GetUserByIdentity(ctx context.Context, issuer, subject string) (domain.User, error) {
+	result, err := s.query().GetUserByIdentity(ctx, database.GetUserByIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, domain.ErrUserNotFound
+	} else if err != nil {
+		return domain.User{}, err
+	}
+	return s.mapper.ToUser(result), nil
+}
+
 func (s *Store) DeletePasswordResetsBefore(ctx context.Context, before time.Time) error {
 	return s.query().DeletePasswordResetsBefore(ctx, before)
 }
@@ -52,8 +424,10 @@ func (s *Store) GetRegistrationByToken(ctx context.Context, token string) (regis
 
 func (s *Store) GetUserByEmail(ctx context.Context, email string) (user domain.User, _ error) {
 	result, err := s.query().GetUserByEmail(ctx, email)
-	if err != nil {
-		return user, domain.WrapError(domain.ErrUserNotFound, err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, domain.ErrUserNotFound
+	} else if err != nil {
+		return user, err
 	}
 	return s.mapper.ToUser(result), nil
 }
@@ -72,6 +446,13 @@ func (s *Store) GetUserById(ctx context.Context, id int64) (user domain.User, _
 	}
 	user.Role.Permissions = permissions
 
+	membership, err := s.query().GetHouseholdMembership(ctx, id)
+	if err == nil {
+		user.Membership = s.mapper.ToMembership(membership)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return user, err
+	}
+
 	return user, nil
 }
 
@@ -87,7 +468,8 @@ func (s *Store) UpdatePasswordByToken(ctx context.Context, searchToken, hashedPa
 		if err = tx.query().DeletePasswordResetTokenByUserId(ctx, token.User.ID); err != nil {
 			return domain.WrapError(domain.ErrDeletingPasswordResetToken, err)
 		}
-		return nil
+		// Whoever knew the old password must not stay logged in
+		return tx.DeleteSessions(ctx, token.User.ID, 0)
 	})
 }
 
@@ -124,8 +506,151 @@ func (s *Store) RegisterUser(ctx context.Context, userDetails domain.UserDetails
 			return err
 		}
 
-		return nil
+		return tx.createHousehold(ctx, user.ID)
 	})
 
 	return user, registration, err
 }
+
+func (s *Store) ClaimUnconfirmedUser(ctx context.Context, user *domain.User, passwordHash string) error {
+	return s.WithTransaction(ctx, func(tx *TxStore) error {
+		if err := tx.query().UpdateUser(ctx, s.mapper.FromUserForUpdate(user)); err != nil {
+			return domain.WrapError(domain.ErrUpdatingUser, err)
+		}
+		if err := tx.query().DeleteRegistrationByUserId(ctx, user.ID); err != nil {
+			return domain.WrapError(domain.ErrDeletingRegistration, err)
+		}
+		if err := tx.query().UpdatePasswordByUserId(ctx, s.mapper.FromUserForPasswordUpdate(passwordHash, user.ID)); err != nil {
+			return domain.WrapError(domain.ErrUpdatingPassword, err)
+		}
+		return tx.DeleteSessions(ctx, user.ID, 0)
+	})
+}
+
+func (s *Store) ProvisionUser(ctx context.Context, userDetails domain.UserDetails, roleID roles.ID, identity domain.ExternalIdentity) (domain.User, error) {
+	var user domain.User
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		dbUser, err := tx.query().CreateUser(ctx, s.mapper.FromUserDetails(userDetails, int64(roleID)))
+		if err != nil {
+			return domain.WrapError(domain.ErrCreatingUser, err)
+		}
+		user = s.mapper.ToUser(dbUser)
+		user.Confirmed = true
+		if err = tx.query().UpdateUser(ctx, s.mapper.FromUserForUpdate(&user)); err != nil {
+			return domain.WrapError(domain.ErrCreatingUser, err)
+		}
+		if err = tx.query().CreateUserIdentity(ctx, s.mapper.FromUserIdentity(user.ID, identity)); err != nil {
+			return domain.WrapError(domain.ErrCreatingUser, err)
+		}
+		return tx.createHousehold(ctx, user.ID)
+	})
+	return user, err
+}
+
+func (s *Store) CreateAPIToken(ctx context.Context, token domain.APIToken, hash string) (domain.APIToken, error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		result, err := tx.query().CreateAPIToken(ctx, database.CreateAPITokenParams{
+			UserID:    token.UserID,
+			Name:      token.Name,
+			TokenHash: hash,
+			ExpiresAt: token.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		for _, scope := range token.Scopes {
+			err = tx.query().CreateAPITokenPermission(ctx, database.CreateAPITokenPermissionParams{
+				ApiTokenID: result.ID,
+				Slug:       string(scope),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	return s.GetAPITokenByHash(ctx, hash)
+}
+
+func (s *Store) DeleteAPIToken(ctx context.Context, userID int64, id int64) error {
+	deleted, err := s.query().DeleteAPIToken(ctx, database.DeleteAPITokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return domain.ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func (s *Store) GetAPITokenByHash(ctx context.Context, hash string) (domain.APIToken, error) {
+	result, err := s.query().GetAPITokenByHash(ctx, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIToken{}, domain.ErrAPITokenNotFound
+	} else if err != nil {
+		return domain.APIToken{}, err
+	}
+	return s.toAPIToken(ctx, result)
+}
+
+func (s *Store) GetAPITokens(ctx context.Context, userID int64) ([]domain.APIToken, error) {
+	result, err := s.query().GetAPITokensByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]domain.APIToken, len(result))
+	for i, row := range result {
+		if tokens[i], err = s.toAPIToken(ctx, row); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+func (s *Store) UpdateAPITokenLastUsed(ctx context.Context, id int64) error {
+	return s.query().UpdateAPITokenLastUsed(ctx, id)
+}
+
+func (s *Store) toAPIToken(ctx context.Context, token database.ApiToken) (domain.APIToken, error) {
+	scopes, err := s.query().GetAPITokenPermissions(ctx, token.ID)
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	return s.mapper.ToAPIToken(token, scopes), nil
+}
+
+func (s *Store) GetNutrientTargets(ctx context.Context, userID int64) ([]domain.NutrientTarget, error) {
+	result, err := s.query().GetNutrientTargetsByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]domain.NutrientTarget, len(result))
+	for i, row := range result {
+		targets[i] = s.mapper.ToNutrientTarget(row)
+	}
+	return targets, nil
+}
+
+func (s *Store) UpdateNutrientTargets(ctx context.Context, userID int64, targets []domain.NutrientTarget) ([]domain.NutrientTarget, error) {
+	err := s.WithTransaction(ctx, func(tx *TxStore) error {
+		if err := tx.query().DeleteNutrientTargetsByUserId(ctx, userID); err != nil {
+			return domain.WrapError(domain.ErrUpdatingUser, err)
+		}
+		for _, target := range targets {
+			if err := tx.query().CreateNutrientTarget(ctx, s.mapper.FromNutrientTarget(userID, target)); err != nil {
+				// Most likely an unknown nutrient
+				return domain.ErrInvalidNutrientTargets
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetNutrientTargets(ctx, userID)
+}
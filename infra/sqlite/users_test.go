@@ -0,0 +1,497 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wolfsblu/recipe-manager/domain"
+	"github.com/wolfsblu/recipe-manager/domain/permissions"
+	"github.com/wolfsblu/recipe-manager/domain/roles"
+	"github.com/wolfsblu/recipe-manager/domain/security"
+	"github.com/wolfsblu/recipe-manager/domain/security/webauthn/webauthntest"
+	"github.com/wolfsblu/recipe-manager/infra/oidc"
+	"github.com/wolfsblu/recipe-manager/infra/oidc/oidctest"
+)
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	users := domain.NewUserService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+	other := registerTestUser(t, store, "other@example.com")
+
+	newSession := func(user *domain.User) domain.Session {
+		t.Helper()
+		session, err := users.CreateSession(ctx, user, domain.SessionClient{UserAgent: "Firefox", IPAddress: "203.0.113.7"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return session
+	}
+	current, expired, stale, foreign := newSession(user), newSession(user), newSession(user), newSession(other)
+	execTestSQL(t, store, "UPDATE sessions SET created_at = datetime('now', '-8 days') WHERE id = ?", expired.ID)
+	execTestSQL(t, store, "UPDATE sessions SET last_seen_at = datetime('now', '-1 hour') WHERE id = ?", stale.ID)
+
+	if got, session, err := users.GetUserBySession(ctx, current.Token); err != nil || got.ID != user.ID || session.UserAgent != "Firefox" {
+		t.Errorf("GetUserBySession() = user %d, %+v, %v, want user %d", got.ID, session, err, user.ID)
+	}
+	if _, _, err := users.GetUserBySession(ctx, expired.Token); err != domain.ErrSessionNotFound {
+		t.Errorf("GetUserBySession() of expired session error = %v, want %v", err, domain.ErrSessionNotFound)
+	}
+	if _, session, err := users.GetUserBySession(ctx, stale.Token); err != nil || time.Since(session.LastSeenAt) > time.Minute {
+		t.Errorf("GetUserBySession() last seen at %v, %v, want it updated", session.LastSeenAt, err)
+	}
+	if err := users.DeleteSession(ctx, user, foreign.ID); err != domain.ErrSessionNotFound {
+		t.Errorf("DeleteSession() of another user's session error = %v, want %v", err, domain.ErrSessionNotFound)
+	}
+	var stored string
+	if err := store.db.QueryRow("SELECT token_hash FROM sessions WHERE id = ?", current.ID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != security.HashToken(current.Token) {
+		t.Errorf("sessions holds %q, want the hash of the token", stored)
+	}
+	if sessions, _ := users.GetSessions(ctx, user); len(sessions) == 0 || sessions[0].Token != "" {
+		t.Errorf("GetSessions() = %+v, want the sessions without their tokens", sessions)
+	}
+
+	if err := users.DeleteExpiredSessions(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, _ := users.GetSessions(ctx, user); len(sessions) != 2 {
+		t.Errorf("DeleteExpiredSessions() left %d sessions, want 2", len(sessions))
+	}
+	if err := users.DeleteOtherSessions(ctx, user, current); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, _ := users.GetSessions(ctx, user); len(sessions) != 1 || sessions[0].ID != current.ID {
+		t.Errorf("DeleteOtherSessions() left %+v, want only session %d", sessions, current.ID)
+	}
+	if err := users.DeleteSessionByToken(ctx, current.Token); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := users.GetUserBySession(ctx, current.Token); err != domain.ErrSessionNotFound {
+		t.Errorf("GetUserBySession() after logging out error = %v, want %v", err, domain.ErrSessionNotFound)
+	}
+
+	// Resetting the password logs the user out everywhere
+	token, err := store.CreatePasswordResetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = users.UpdatePasswordByToken(ctx, token.Token, "hash"); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, _ := users.GetSessions(ctx, user); len(sessions) != 0 {
+		t.Errorf("UpdatePasswordByToken() left %d sessions, want 0", len(sessions))
+	}
+	if sessions, _ := users.GetSessions(ctx, other); len(sessions) != 1 {
+		t.Errorf("UpdatePasswordByToken() left %d sessions of another user, want 1", len(sessions))
+	}
+}
+
+func TestCalendarTokens(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	users := domain.NewUserService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+
+	created, err := users.CreateCalendarToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Token == "" {
+		t.Fatal("CreateCalendarToken() returned no token")
+	}
+	if got, err := users.GetUserByCalendarToken(ctx, created.Token); err != nil || got.ID != user.ID {
+		t.Errorf("GetUserByCalendarToken() = user %d, %v, want user %d", got.ID, err, user.ID)
+	}
+	if token, err := users.GetCalendarToken(ctx, user); err != nil || token.Token != "" {
+		t.Errorf("GetCalendarToken() = %+v, %v, want the feed without its token", token, err)
+	}
+	var stored string
+	if err = store.db.QueryRow("SELECT token_hash FROM calendar_tokens").Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != security.HashToken(created.Token) {
+		t.Errorf("calendar_tokens holds %q, want the hash of the token", stored)
+	}
+
+	// A new address replaces the old one
+	if _, err = users.CreateCalendarToken(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = users.GetUserByCalendarToken(ctx, created.Token); err != domain.ErrCalendarTokenNotFound {
+		t.Errorf("GetUserByCalendarToken() with the old token error = %v, want %v", err, domain.ErrCalendarTokenNotFound)
+	}
+}
+
+func TestTwoFactorAuthentication(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	users := domain.NewUserService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+
+	enrollment, err := users.EnrollTOTP(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Codes of the periods around the current one are accepted too
+	code := func(offset time.Duration) string {
+		t.Helper()
+		code, err := security.TOTPCode(enrollment.Secret, security.TOTPStep(time.Now().Add(offset)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+	if enabled, _ := users.IsTwoFactorEnabled(ctx, user); enabled {
+		t.Error("IsTwoFactorEnabled() = true before confirming")
+	}
+	if _, err = users.ConfirmTOTP(ctx, user, "abcdef"); err != domain.ErrInvalidTwoFactorCode {
+		t.Errorf("ConfirmTOTP() with wrong code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+	recoveryCodes, err := users.ConfirmTOTP(ctx, user, code(-security.TOTPPeriod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled, _ := users.IsTwoFactorEnabled(ctx, user); !enabled {
+		t.Error("IsTwoFactorEnabled() = false after confirming")
+	}
+	if _, err = users.EnrollTOTP(ctx, user); err != domain.ErrTwoFactorAlreadyEnabled {
+		t.Errorf("EnrollTOTP() when enabled error = %v, want %v", err, domain.ErrTwoFactorAlreadyEnabled)
+	}
+
+	login := func(code string) (domain.User, error) {
+		t.Helper()
+		challenge, err := users.CreateLoginChallenge(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return users.CompleteLoginChallenge(ctx, challenge.Token, code)
+	}
+	current := code(0)
+	if got, err := login(current); err != nil || got.ID != user.ID {
+		t.Errorf("CompleteLoginChallenge() = user %d, %v, want user %d", got.ID, err, user.ID)
+	}
+	if _, err = login(current); err != domain.ErrInvalidTwoFactorCode {
+		t.Errorf("CompleteLoginChallenge() with used code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+	if _, err = login(recoveryCodes[0]); err != nil {
+		t.Errorf("CompleteLoginChallenge() with recovery code error = %v", err)
+	}
+	if _, err = login(recoveryCodes[0]); err != domain.ErrInvalidTwoFactorCode {
+		t.Errorf("CompleteLoginChallenge() with used recovery code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+
+	// The codes used twice above were failed attempts as well
+	expireLoginChallenges := func() {
+		t.Helper()
+		execTestSQL(t, store, "UPDATE login_challenges SET created_at = datetime('now', '-10 minutes')")
+	}
+	expireLoginChallenges()
+	challenge, err := users.CreateLoginChallenge(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 5 {
+		if _, err = users.CompleteLoginChallenge(ctx, challenge.Token, "not a code"); err != domain.ErrInvalidTwoFactorCode {
+			t.Fatalf("CompleteLoginChallenge() with wrong code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+		}
+	}
+	if _, err = users.CompleteLoginChallenge(ctx, challenge.Token, code(security.TOTPPeriod)); err != domain.ErrLoginChallengeNotFound {
+		t.Errorf("CompleteLoginChallenge() after too many attempts error = %v, want %v", err, domain.ErrLoginChallengeNotFound)
+	}
+	// Logging in again doesn't hand out new attempts
+	if _, err = login(code(security.TOTPPeriod)); err != domain.ErrLoginChallengeNotFound {
+		t.Errorf("CompleteLoginChallenge() of a new challenge after too many attempts error = %v, want %v", err, domain.ErrLoginChallengeNotFound)
+	}
+	// Until the failed challenges expired
+	expireLoginChallenges()
+	if got, err := login(code(security.TOTPPeriod)); err != nil || got.ID != user.ID {
+		t.Errorf("CompleteLoginChallenge() after the failed challenges expired = user %d, %v, want user %d", got.ID, err, user.ID)
+	}
+
+	if err = users.DisableTOTP(ctx, user, recoveryCodes[1]); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, _ := users.IsTwoFactorEnabled(ctx, user); enabled {
+		t.Error("IsTwoFactorEnabled() = true after disabling")
+	}
+}
+
+func TestPasskeys(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	users := domain.NewUserService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+	user.Confirmed = true
+	if err := store.ConfirmRegistration(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	wa, err := domain.NewWebAuthn("https://recipes.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := webauthntest.NewAuthenticator("https://recipes.example.com")
+
+	creation, err := users.BeginPasskeyRegistration(ctx, wa, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestation, err := authenticator.Create(creation.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkey, err := users.FinishPasskeyRegistration(ctx, wa, user, creation.Token, " ", attestation)
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration() error = %v", err)
+	}
+	if passkey.Name != "Passkey" || passkey.UserID != user.ID {
+		t.Errorf("FinishPasskeyRegistration() = %+v", passkey)
+	}
+	if _, err = users.FinishPasskeyRegistration(ctx, wa, user, creation.Token, "", attestation); err != domain.ErrPasskeyChallengeNotFound {
+		t.Errorf("FinishPasskeyRegistration() with used challenge error = %v, want %v", err, domain.ErrPasskeyChallengeNotFound)
+	}
+	if creation, err = users.BeginPasskeyRegistration(ctx, wa, user); err != nil || len(creation.PublicKey.CredentialExcludeList) != 1 {
+		t.Errorf("BeginPasskeyRegistration() excludes %d credentials, %v, want 1", len(creation.PublicKey.CredentialExcludeList), err)
+	}
+
+	login := func() (domain.User, error) {
+		t.Helper()
+		request, err := users.BeginPasskeyLogin(ctx, wa)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertion, err := authenticator.Get(request.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return users.FinishPasskeyLogin(ctx, wa, request.Token, assertion)
+	}
+	for range 2 {
+		if got, err := login(); err != nil || got.ID != user.ID {
+			t.Fatalf("FinishPasskeyLogin() = user %d, %v, want user %d", got.ID, err, user.ID)
+		}
+	}
+	if passkeys, _ := users.GetPasskeys(ctx, user); len(passkeys) != 1 || passkeys[0].Credential.Authenticator.SignCount != 2 || passkeys[0].LastUsedAt == nil {
+		t.Errorf("GetPasskeys() = %+v, want one passkey used twice", passkeys)
+	}
+	// A parallel login with the same counter loses the race
+	passkey.Credential.Authenticator.SignCount = 2
+	if err = store.UpdatePasskeyUsage(ctx, passkey.ID, passkey.Credential); err != domain.ErrInvalidCredentials {
+		t.Errorf("UpdatePasskeyUsage() with the stored counter error = %v, want %v", err, domain.ErrInvalidCredentials)
+	}
+	execTestSQL(t, store, "UPDATE passkeys SET sign_count = 0")
+	passkey.Credential.Authenticator.SignCount = 0
+	if err = store.UpdatePasskeyUsage(ctx, passkey.ID, passkey.Credential); err != nil {
+		t.Errorf("UpdatePasskeyUsage() of an authenticator without counter error = %v", err)
+	}
+
+	// Passkeys saved without their flags take them from the next login
+	execTestSQL(t, store, "UPDATE passkeys SET sign_count = 2, flags = NULL")
+	if _, err = login(); err != nil {
+		t.Errorf("FinishPasskeyLogin() with unknown flags error = %v", err)
+	}
+	if passkeys, _ := users.GetPasskeys(ctx, user); len(passkeys) != 1 || passkeys[0].UnknownFlags || !passkeys[0].Credential.Flags.UserVerified {
+		t.Errorf("GetPasskeys() = %+v, want the flags of the login", passkeys)
+	}
+
+	// Challenges can't be answered after they expired
+	request, err := users.BeginPasskeyLogin(ctx, wa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execTestSQL(t, store, "UPDATE passkey_challenges SET created_at = datetime('now', '-10 minutes') WHERE token = ?", request.Token)
+	assertion, err := authenticator.Get(request.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = users.FinishPasskeyLogin(ctx, wa, request.Token, assertion); err != domain.ErrPasskeyChallengeNotFound {
+		t.Errorf("FinishPasskeyLogin() with expired challenge error = %v, want %v", err, domain.ErrPasskeyChallengeNotFound)
+	}
+
+	if err = users.DeletePasskey(ctx, user, passkey.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = login(); err != domain.ErrInvalidCredentials {
+		t.Errorf("FinishPasskeyLogin() with deleted passkey error = %v, want %v", err, domain.ErrInvalidCredentials)
+	}
+}
+
+func TestSingleSignOn(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	users := domain.NewUserService(nil, store)
+	issuer := oidctest.NewIssuer(t)
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       issuer.URL,
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  oidctest.RedirectURL,
+	}, nil)
+	alice := registerTestUser(t, store, "alice@example.com")
+	registerTestUser(t, store, "bob@example.com")
+
+	authorize := func(user oidctest.User) (state, code string) {
+		t.Helper()
+		redirectURL, state, err := users.BeginSSOLogin(ctx, provider)
+		if err != nil {
+			t.Fatal(err)
+		}
+		redirect, err := issuer.Authorize(redirectURL, user, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := redirect.Query().Get("state"); got != state {
+			t.Fatalf("Authorize() state = %q, want %q", got, state)
+		}
+		return state, redirect.Query().Get("code")
+	}
+	login := func(user oidctest.User, provisioning domain.SSOProvisioning) (domain.User, error) {
+		t.Helper()
+		state, code := authorize(user)
+		return users.FinishSSOLogin(ctx, provider, provisioning, state, code)
+	}
+
+	// Someone else registered with Alice's email before she ever logged in
+	squatterHash, err := security.CreateHash("squatter's password", security.DefaultHashParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execTestSQL(t, store, "UPDATE users SET password_hash = ? WHERE id = ?", squatterHash, alice.ID)
+	if _, err = store.CreateSession(ctx, alice.ID, security.HashToken("squatter's session"), domain.SessionClient{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Accounts are linked by the verified email, which also confirms them and
+	// locks out whoever registered them
+	got, err := login(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true}, domain.SSOProvisioning{})
+	if err != nil || got.ID != alice.ID {
+		t.Fatalf("FinishSSOLogin() = user %d, %v, want user %d", got.ID, err, alice.ID)
+	}
+	if got, _ = store.GetUserById(ctx, alice.ID); !got.Confirmed {
+		t.Error("FinishSSOLogin() did not confirm the linked user")
+	}
+	if err = users.VerifyPassword(got, "squatter's password"); err != domain.ErrInvalidCredentials {
+		t.Errorf("VerifyPassword() with the password of the unconfirmed account error = %v, want %v", err, domain.ErrInvalidCredentials)
+	}
+	if sessions, _ := store.GetSessions(ctx, alice.ID); len(sessions) != 0 {
+		t.Errorf("FinishSSOLogin() left %d sessions of the unconfirmed account, want 0", len(sessions))
+	}
+	// Later logins find the user by the identity, whatever the email is by now
+	if got, err = login(oidctest.User{Subject: "alice", Email: "alice@new.example.com"}, domain.SSOProvisioning{}); err != nil || got.ID != alice.ID {
+		t.Errorf("FinishSSOLogin() with changed email = user %d, %v, want user %d", got.ID, err, alice.ID)
+	}
+
+	if _, err = login(oidctest.User{Subject: "bob", Email: "bob@example.com"}, domain.SSOProvisioning{}); err != domain.ErrSSOEmailNotVerified {
+		t.Errorf("FinishSSOLogin() with unverified email error = %v, want %v", err, domain.ErrSSOEmailNotVerified)
+	}
+
+	carol := oidctest.User{Subject: "carol", Email: "carol@example.com", EmailVerified: true}
+	if _, err = login(carol, domain.SSOProvisioning{}); err != domain.ErrSSOAccountNotFound {
+		t.Errorf("FinishSSOLogin() without provisioning error = %v, want %v", err, domain.ErrSSOAccountNotFound)
+	}
+	provisioned, err := login(carol, domain.SSOProvisioning{Enabled: true, Role: roles.Moderator})
+	if err != nil {
+		t.Fatalf("FinishSSOLogin() with provisioning error = %v", err)
+	}
+	if got, err = store.GetUserById(ctx, provisioned.ID); err != nil || got.Email != carol.Email || !got.Confirmed || got.Role.Name != "Moderator" || got.Membership.HouseholdID == 0 {
+		t.Errorf("GetUserById() = %+v, %v, want a confirmed moderator with a household", got, err)
+	}
+	if got, err = login(carol, domain.SSOProvisioning{Enabled: true, Role: roles.Moderator}); err != nil || got.ID != provisioned.ID {
+		t.Errorf("FinishSSOLogin() of provisioned user = user %d, %v, want user %d", got.ID, err, provisioned.ID)
+	}
+
+	// Logins can only be finished once and before they expire
+	state, code := authorize(carol)
+	execTestSQL(t, store, "UPDATE sso_logins SET created_at = datetime('now', '-11 minutes') WHERE state = ?", state)
+	if _, err = users.FinishSSOLogin(ctx, provider, domain.SSOProvisioning{}, state, code); err != domain.ErrSSOLoginNotFound {
+		t.Errorf("FinishSSOLogin() with expired login error = %v, want %v", err, domain.ErrSSOLoginNotFound)
+	}
+	if _, err = users.FinishSSOLogin(ctx, provider, domain.SSOProvisioning{}, state, code); err != domain.ErrSSOLoginNotFound {
+		t.Errorf("FinishSSOLogin() with used login error = %v, want %v", err, domain.ErrSSOLoginNotFound)
+	}
+
+	if _, _, err = users.BeginSSOLogin(ctx, nil); err != domain.ErrSSONotConfigured {
+		t.Errorf("BeginSSOLogin() without provider error = %v, want %v", err, domain.ErrSSONotConfigured)
+	}
+}
+
+func TestAPITokens(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "")
+	users := domain.NewUserService(nil, store)
+	user := registerTestUser(t, store, "user@example.com")
+	other := registerTestUser(t, store, "other@example.com")
+	scopes := []permissions.Slug{permissions.ListRecipes, permissions.ViewRecipe, permissions.ListRecipes}
+
+	for name, tc := range map[string]struct {
+		name      string
+		scopes    []permissions.Slug
+		expiresAt time.Time
+	}{
+		"Without name":         {name: " ", scopes: scopes},
+		"Without scopes":       {name: "Script"},
+		"Unknown scope":        {name: "Script", scopes: []permissions.Slug{"can_do_anything"}},
+		"Expiring in the past": {name: "Script", scopes: scopes, expiresAt: time.Now().Add(-time.Hour)},
+	} {
+		var expiresAt *time.Time
+		if !tc.expiresAt.IsZero() {
+			expiresAt = &tc.expiresAt
+		}
+		if _, _, err := users.CreateAPIToken(ctx, user, tc.name, tc.scopes, expiresAt); err != domain.ErrInvalidAPIToken {
+			t.Errorf("CreateAPIToken() %s error = %v, want %v", name, err, domain.ErrInvalidAPIToken)
+		}
+	}
+
+	token, secret, err := users.CreateAPIToken(ctx, user, " Script ", scopes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Name != "Script" || len(token.Scopes) != 2 || token.ExpiresAt != nil || token.LastUsedAt != nil {
+		t.Errorf("CreateAPIToken() = %+v, want trimmed name and 2 scopes", token)
+	}
+	if !strings.HasPrefix(secret, domain.APITokenPrefix) {
+		t.Errorf("CreateAPIToken() secret = %q, want prefix %q", secret, domain.APITokenPrefix)
+	}
+
+	got, used, err := users.GetUserByAPIToken(ctx, secret)
+	if err != nil || got.ID != user.ID || used.ID != token.ID {
+		t.Fatalf("GetUserByAPIToken() = user %d, token %d, %v, want user %d, token %d", got.ID, used.ID, err, user.ID, token.ID)
+	}
+	if len(got.Role.Permissions) != 2 {
+		t.Errorf("GetUserByAPIToken() permissions = %+v, want only the scopes", got.Role.Permissions)
+	}
+	if used.LastUsedAt == nil {
+		t.Error("GetUserByAPIToken() didn't set last used at")
+	}
+	if _, _, err = users.GetUserByAPIToken(ctx, secret+"0"); err != domain.ErrAPITokenNotFound {
+		t.Errorf("GetUserByAPIToken() of unknown token error = %v, want %v", err, domain.ErrAPITokenNotFound)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	expired, expiredSecret, err := users.CreateAPIToken(ctx, user, "Expired", scopes, &expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execTestSQL(t, store, "UPDATE api_tokens SET expires_at = datetime('now', '-1 minute') WHERE id = ?", expired.ID)
+	if _, _, err = users.GetUserByAPIToken(ctx, expiredSecret); err != domain.ErrAPITokenNotFound {
+		t.Errorf("GetUserByAPIToken() of expired token error = %v, want %v", err, domain.ErrAPITokenNotFound)
+	}
+
+	if tokens, _ := users.GetAPITokens(ctx, user); len(tokens) != 2 || tokens[0].LastUsedAt == nil {
+		t.Errorf("GetAPITokens() = %+v, want 2 tokens with the first one used", tokens)
+	}
+	if err = users.DeleteAPIToken(ctx, other, token.ID); err != domain.ErrAPITokenNotFound {
+		t.Errorf("DeleteAPIToken() of another user's token error = %v, want %v", err, domain.ErrAPITokenNotFound)
+	}
+	if err = users.DeleteAPIToken(ctx, user, token.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = users.GetUserByAPIToken(ctx, secret); err != domain.ErrAPITokenNotFound {
+		t.Errorf("GetUserByAPIToken() of deleted token error = %v, want %v", err, domain.ErrAPITokenNotFound)
+	}
+}
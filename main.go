@@ -7,9 +7,11 @@ import (
 	"github.com/wolfsblu/recipe-manager/api"
 	"github.com/wolfsblu/recipe-manager/domain"
 	"github.com/wolfsblu/recipe-manager/infra/env"
+	"github.com/wolfsblu/recipe-manager/infra/events"
 	"github.com/wolfsblu/recipe-manager/infra/handler"
 	"github.com/wolfsblu/recipe-manager/infra/job"
 	"github.com/wolfsblu/recipe-manager/infra/routing"
+	"github.com/wolfsblu/recipe-manager/infra/schemaorg"
 	"github.com/wolfsblu/recipe-manager/infra/smtp"
 	"github.com/wolfsblu/recipe-manager/infra/sqlite"
 )
@@ -26,10 +28,12 @@ func main() {
 
 	recipeService := domain.NewRecipeService(mailer, sqliteStore)
 	userService := domain.NewUserService(mailer, sqliteStore)
-	shoppingService := domain.NewShoppingService(sqliteStore)
+	shoppingService := domain.NewShoppingService(sqliteStore, recipeService, events.NewShoppingListBus())
+	householdService := domain.NewHouseholdService(mailer, sqliteStore)
+	importService := domain.NewImportService(schemaorg.NewScraper(schemaorg.NewHTTPFetcher()), sqliteStore)
 
 	securityHandler := handler.NewSecurityHandler(userService)
-	apiHandler := handler.NewAPIHandler(recipeService, userService, shoppingService)
+	apiHandler := handler.NewAPIHandler(recipeService, userService, shoppingService, importService, householdService)
 	uploadHandler, err := handler.NewUploadHandler(userService)
 	if err != nil {
 		log.Fatal("failed to initialize upload handler: ", err)
@@ -39,8 +43,11 @@ func main() {
 		log.Fatal("failed to initialize API server: ", err)
 	}
 
-	mux := routing.NewServeMux(apiServer, uploadHandler)
-	scheduler := job.NewScheduler(userService)
+	eventHandler := handler.NewShoppingListEventHandler(shoppingService, userService)
+	calendarHandler := handler.NewCalendarHandler(recipeService, userService)
+	ssoHandler := handler.NewSSOHandler(userService)
+	mux := routing.NewServeMux(apiServer, uploadHandler, eventHandler, calendarHandler, ssoHandler)
+	scheduler := job.NewScheduler(userService, householdService, recipeService)
 	defer scheduler.Quit()
 
 	host := env.MustGet("HOST")